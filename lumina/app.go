@@ -2,30 +2,85 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"os"
 	"path/filepath"
+	"sync"
+	"time"
 
 	"github.com/joho/godotenv"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
 
 	chatdomain "lumina/backend/chat/domain"
 	chatinfra "lumina/backend/chat/infrastructure"
+	patchdomain "lumina/backend/patch/domain"
+	patchinfra "lumina/backend/patch/infrastructure"
 	treedomain "lumina/backend/projecttree/domain"
 	treeinfra "lumina/backend/projecttree/infrastructure"
+	retrievaldomain "lumina/backend/retrieval/domain"
+	retrievalinfra "lumina/backend/retrieval/infrastructure"
 	tooldomain "lumina/backend/tool/domain"
 	toolinfra "lumina/backend/tool/infrastructure"
 	typescriptdomain "lumina/backend/typescript_execution/domain"
 	typescriptinfra "lumina/backend/typescript_execution/infrastructure"
 )
 
+// Codebase context modes for chat messages
+const (
+	// contextModeFull sends the whole codebase (repomix) with every message
+	contextModeFull = "full"
+	// contextModeRetrieval sends only the chunks most relevant to the question
+	contextModeRetrieval = "retrieval"
+)
+
+// toolTriggerEvent is emitted with a TriggerResult when a triggered tool
+// prints something different from its previous run
+const toolTriggerEvent = "tool:trigger-output-changed"
+
+// toolCapabilityEvent is emitted with the new CapabilityEscalations when a
+// tool was refused an access it does not declare
+const toolCapabilityEvent = "tool:capability-requested"
+
+// triggerPollInterval is how often interval triggers and watched files are checked
+const triggerPollInterval = 2 * time.Second
+
 // App struct
 type App struct {
-	ctx                   context.Context
-	chat                  *chatdomain.Chat
-	persistence           *chatinfra.SQLitePersistence
-	toolRepository        tooldomain.ToolRepository
-	typescriptExecutor    typescriptdomain.TypeScriptExecutor
+	ctx                 context.Context
+	chat                *chatdomain.Chat
+	persistence         *chatinfra.SQLitePersistence
+	llmCallRepository   *chatinfra.SQLiteLLMCallRepository
+	changeSetRepository *patchinfra.SQLiteChangeSetRepository
+	patchService        *patchdomain.PatchService
+	indexRepository     *retrievalinfra.SQLiteIndexRepository
+	indexService        *retrievaldomain.IndexService
+	contextProviders    map[string]chatdomain.CodebaseContextProvider
+	contextMu           sync.Mutex
+	contextMode         string
+	toolRepository      tooldomain.ToolRepository
+	scopedTools         *tooldomain.ScopedToolRepository
+	toolSyncStates      *toolinfra.SQLiteToolSyncStateRepository
+	toolSyncService     *tooldomain.ToolSyncService
+	toolRunner          tooldomain.ToolRunner
+	toolTypeChecker     tooldomain.ToolTypeChecker
+	typeCheckMu         sync.Mutex
+	typeCheckPolicy     tooldomain.TypeCheckPolicy
+	toolRuns            *toolinfra.SQLiteToolRunRepository
+	toolExamples        *toolinfra.SQLiteToolExampleRepository
+	exampleService      *tooldomain.ExampleService
+	generationService   *tooldomain.ToolGenerationService
+	bundleService       *tooldomain.ToolBundleService
+	escalations         *toolinfra.SQLiteCapabilityEscalationRepository
+	capabilityService   *tooldomain.CapabilityService
+	pipelines           *toolinfra.SQLitePipelineRepository
+	pipelineService     *tooldomain.PipelineService
+	toolTriggers        *toolinfra.SQLiteToolTriggerRepository
+	triggerService      *tooldomain.TriggerService
+	stopTriggers        context.CancelFunc
+	typescriptExecutor  typescriptdomain.TypeScriptExecutor
+	executors           *typescriptdomain.ExecutorRegistry
 }
 
 // NewApp creates a new App application struct
@@ -41,9 +96,6 @@ func NewApp() *App {
 		log.Println("Warning: OPENAI_API_KEY not set in environment")
 	}
 
-	// Create OpenAI service
-	openAIService := chatinfra.NewOpenAIService(apiKey)
-
 	// Create repomix service (working directory is current directory)
 	workingDir, err := os.Getwd()
 	if err != nil {
@@ -63,33 +115,230 @@ func NewApp() *App {
 		log.Printf("Chat persistence initialized at: %s", dbPath)
 	}
 
-	// Create tool repository using the same database
+	// Create LLM call repository using the same database so every provider
+	// call can be inspected. callRecorder stays a true nil interface when
+	// initialization fails.
+	var llmCallRepository *chatinfra.SQLiteLLMCallRepository
+	var callRecorder chatdomain.LLMCallRepository
+	if persistence != nil {
+		llmCallRepo, err := chatinfra.NewSQLiteLLMCallRepository(dbPath)
+		if err != nil {
+			log.Printf("Warning: Could not initialize LLM call repository: %v", err)
+		} else {
+			llmCallRepository = llmCallRepo
+			callRecorder = llmCallRepo
+			log.Printf("LLM call repository initialized at: %s", dbPath)
+		}
+	}
+
+	// Create OpenAI service, recording calls when the repository is available
+	openAIService := chatinfra.NewOpenAIService(apiKey, callRecorder)
+
+	// Create tool repository using the same database. Names resolve as seen
+	// from the working directory: its project tools first, then team tools,
+	// then global ones.
 	var toolRepository tooldomain.ToolRepository
+	var scopedTools *tooldomain.ScopedToolRepository
 	if persistence != nil {
 		toolRepo, err := toolinfra.NewSQLiteToolRepository(dbPath)
 		if err != nil {
 			log.Printf("Warning: Could not initialize tool repository: %v", err)
 			toolRepository = nil
 		} else {
-			toolRepository = toolRepo
+			scopedTools = tooldomain.NewScopedToolRepository(toolRepo, tooldomain.ToolScope{ProjectRoot: workingDir})
+			toolRepository = scopedTools
 			log.Printf("Tool repository initialized at: %s", dbPath)
 		}
 	} else {
 		toolRepository = nil
 	}
 
+	// Create TypeScript executor with a shared cache for the npm packages of tools
+	packageCache := newPackageCache(dbPath)
+	typescriptExecutor := typescriptinfra.NewNodeTypeScriptExecutorWithPackages(packageCache)
+	executors := newExecutorRegistry(typescriptExecutor)
+
+	// Create the tool runner, recording every run in the same database.
+	// runRecorder stays a true nil interface when initialization fails.
+	var toolRuns *toolinfra.SQLiteToolRunRepository
+	var runRecorder tooldomain.ToolRunRepository
+	var toolRunner tooldomain.ToolRunner
+	if toolRepository != nil {
+		runRepo, err := toolinfra.NewSQLiteToolRunRepository(dbPath)
+		if err != nil {
+			log.Printf("Warning: Could not initialize tool run history: %v", err)
+		} else {
+			toolRuns = runRepo
+			runRecorder = runRepo
+		}
+		toolRunner = toolinfra.NewTypeScriptToolRunnerWithExecutors(typescriptExecutor, executors, toolRepository, runRecorder)
+	}
+
+	// Record the accesses tools are refused beyond their declared
+	// capabilities and ask the user about them in the window
+	app := &App{}
+	var escalations *toolinfra.SQLiteCapabilityEscalationRepository
+	var capabilityService *tooldomain.CapabilityService
+	if toolRunner != nil {
+		escalationRepo, err := toolinfra.NewSQLiteCapabilityEscalationRepository(dbPath)
+		if err != nil {
+			log.Printf("Warning: Could not initialize capability escalations: %v", err)
+		} else {
+			escalations = escalationRepo
+			capabilityService = tooldomain.NewCapabilityService(toolRepository, escalationRepo)
+			toolRunner = tooldomain.NewCapabilityGuardedRunner(toolRunner, capabilityService, app.capabilityRequested)
+		}
+	}
+
+	// Create the sync between the tool store and the project's tool files
+	var toolSyncStates *toolinfra.SQLiteToolSyncStateRepository
+	var toolSyncService *tooldomain.ToolSyncService
+	if toolRepository != nil {
+		toolsDir := filepath.Join(workingDir, toolinfra.ToolsDirName)
+		syncStates, err := toolinfra.NewSQLiteToolSyncStateRepository(dbPath, toolsDir)
+		if err != nil {
+			log.Printf("Warning: Could not initialize tool sync: %v", err)
+		} else {
+			toolSyncStates = syncStates
+			toolSyncService = tooldomain.NewToolSyncService(toolRepository, toolinfra.NewFileToolDirectory(toolsDir), syncStates, tooldomain.ProjectNamespace(workingDir), capabilityService)
+		}
+	}
+
+	// Type-check tools before saving them, with the compiler installed into
+	// the same package cache. LUMINA_TYPECHECK_POLICY=block refuses saves with
+	// type errors instead of keeping the tool as a draft.
+	var toolTypeChecker tooldomain.ToolTypeChecker
+	if toolRepository != nil {
+		toolTypeChecker = toolinfra.NewTypeScriptToolTypeChecker(typescriptinfra.NewTscTypeChecker(packageCache), toolRepository)
+	}
+	typeCheckPolicy := tooldomain.TypeCheckDraft
+	if value := os.Getenv("LUMINA_TYPECHECK_POLICY"); value != "" {
+		policy, err := tooldomain.ParseTypeCheckPolicy(value)
+		if err != nil {
+			log.Printf("Warning: Ignoring LUMINA_TYPECHECK_POLICY: %v", err)
+		} else {
+			typeCheckPolicy = policy
+		}
+	}
+
+	// Create the examples that act as tool regression tests
+	var toolExamples *toolinfra.SQLiteToolExampleRepository
+	var exampleService *tooldomain.ExampleService
+	var generationService *tooldomain.ToolGenerationService
+	var bundleService *tooldomain.ToolBundleService
+	if toolRepository != nil {
+		exampleRepo, err := toolinfra.NewSQLiteToolExampleRepository(dbPath)
+		if err != nil {
+			log.Printf("Warning: Could not initialize tool examples: %v", err)
+		} else {
+			toolExamples = exampleRepo
+			exampleService = tooldomain.NewExampleService(toolRepository, exampleRepo, toolRunner)
+			// Generated tools are checked by running the example they come with.
+			// Candidates are not saved yet, so their runs are neither recorded
+			// nor turned into capability escalations.
+			candidateRunner := toolinfra.NewTypeScriptToolRunnerWithExecutors(typescriptExecutor, executors, toolRepository, nil)
+			generationService = tooldomain.NewToolGenerationService(toolRepository, exampleRepo, candidateRunner, openAIService)
+			bundleService = tooldomain.NewToolBundleService(toolRepository, exampleRepo, toolinfra.NewGzipToolBundleArchive(), capabilityService)
+		}
+	}
+
+	// Create the pipelines that chain tools
+	var pipelines *toolinfra.SQLitePipelineRepository
+	var pipelineService *tooldomain.PipelineService
+	if toolRepository != nil {
+		pipelineRepo, err := toolinfra.NewSQLitePipelineRepository(dbPath)
+		if err != nil {
+			log.Printf("Warning: Could not initialize pipelines: %v", err)
+		} else {
+			pipelines = pipelineRepo
+			pipelineService = tooldomain.NewPipelineService(toolRepository, pipelineRepo, toolRunner)
+		}
+	}
+
+	// Create the triggers that re-run tools on file changes or on a schedule
+	var toolTriggers *toolinfra.SQLiteToolTriggerRepository
+	var triggerService *tooldomain.TriggerService
+	if toolRepository != nil {
+		triggerRepo, err := toolinfra.NewSQLiteToolTriggerRepository(dbPath)
+		if err != nil {
+			log.Printf("Warning: Could not initialize tool triggers: %v", err)
+		} else {
+			toolTriggers = triggerRepo
+			triggerService = tooldomain.NewTriggerService(toolRepository, triggerRepo, toolRunner, toolinfra.NewFileProjectWatcher(workingDir))
+		}
+	}
+
+	// Create patch service so code changes proposed by the assistant can be
+	// reviewed and applied to the working directory
+	var changeSetRepository *patchinfra.SQLiteChangeSetRepository
+	var patchService *patchdomain.PatchService
+	if persistence != nil {
+		changeSetRepo, err := patchinfra.NewSQLiteChangeSetRepository(dbPath)
+		if err != nil {
+			log.Printf("Warning: Could not initialize change set repository: %v", err)
+		} else {
+			changeSetRepository = changeSetRepo
+			patchService = patchdomain.NewPatchService(patchinfra.NewFileWorkspace(workingDir), changeSetRepo)
+			log.Printf("Change set repository initialized at: %s", dbPath)
+		}
+	}
+
+	// Create the codebase index used for retrieval-based context
+	contextProviders := map[string]chatdomain.CodebaseContextProvider{
+		contextModeFull: chatdomain.NewRepomixContextProvider(repomixService),
+	}
+	var indexRepository *retrievalinfra.SQLiteIndexRepository
+	var indexService *retrievaldomain.IndexService
+	if persistence != nil {
+		indexRepo, err := retrievalinfra.NewSQLiteIndexRepository(dbPath, workingDir)
+		if err != nil {
+			log.Printf("Warning: Could not initialize codebase index: %v", err)
+		} else {
+			indexRepository = indexRepo
+			indexService = retrievaldomain.NewIndexService(retrievalinfra.NewFileSourceReader(workingDir), indexRepo)
+			contextProviders[contextModeRetrieval] = chatinfra.NewRetrievalContextProvider(indexService, chatinfra.DefaultRetrievedChunks)
+			log.Printf("Codebase index initialized at: %s", dbPath)
+		}
+	}
+
 	// Create chat instance with repomix integration and persistence
 	chat := chatdomain.NewChat(openAIService, repomixService, persistence)
 
-	// Create TypeScript executor
-	typescriptExecutor := typescriptinfra.NewNodeTypeScriptExecutor()
+	// Git context is opt-in; the service is ready for when it gets enabled
+	chat.SetGitService(chatinfra.NewGitService(workingDir))
 
-	return &App{
-		chat:            chat,
-		persistence:     persistence,
-		toolRepository:  toolRepository,
-		typescriptExecutor: typescriptExecutor,
+	*app = App{
+		chat:                chat,
+		persistence:         persistence,
+		llmCallRepository:   llmCallRepository,
+		changeSetRepository: changeSetRepository,
+		patchService:        patchService,
+		indexRepository:     indexRepository,
+		indexService:        indexService,
+		contextProviders:    contextProviders,
+		contextMode:         contextModeFull,
+		toolRepository:      toolRepository,
+		scopedTools:         scopedTools,
+		toolSyncStates:      toolSyncStates,
+		toolSyncService:     toolSyncService,
+		toolRunner:          toolRunner,
+		toolTypeChecker:     toolTypeChecker,
+		typeCheckPolicy:     typeCheckPolicy,
+		toolRuns:            toolRuns,
+		toolExamples:        toolExamples,
+		exampleService:      exampleService,
+		generationService:   generationService,
+		bundleService:       bundleService,
+		escalations:         escalations,
+		capabilityService:   capabilityService,
+		pipelines:           pipelines,
+		pipelineService:     pipelineService,
+		toolTriggers:        toolTriggers,
+		triggerService:      triggerService,
+		typescriptExecutor:  typescriptExecutor,
+		executors:           executors,
 	}
+	return app
 }
 
 // getDBPath returns the path for the SQLite database
@@ -107,20 +356,107 @@ func getDBPath() string {
 	return "chat.db"
 }
 
+// newPackageCache creates the cache for the npm packages of tools next to
+// the database. LUMINA_NPM_REGISTRY points installs at a local mirror and
+// LUMINA_NPM_OFFLINE=1 installs only from the cache or tarballs.
+func newPackageCache(dbPath string) *typescriptinfra.NpmPackageCache {
+	return typescriptinfra.NewNpmPackageCache(
+		filepath.Join(filepath.Dir(dbPath), "npm"),
+		os.Getenv("LUMINA_NPM_REGISTRY"),
+		os.Getenv("LUMINA_NPM_OFFLINE") == "1",
+	)
+}
+
+// newExecutorRegistry registers an executor for each language tools can be
+// written in
+func newExecutorRegistry(typescriptExecutor typescriptdomain.TypeScriptExecutor) *typescriptdomain.ExecutorRegistry {
+	executors := typescriptdomain.NewExecutorRegistry()
+	executors.Register(typescriptdomain.LanguageTypeScript, typescriptExecutor)
+	executors.Register(typescriptdomain.LanguageShell, typescriptinfra.NewShellExecutor())
+	executors.Register(typescriptdomain.LanguagePython, typescriptinfra.NewPythonExecutor())
+	executors.Register(typescriptdomain.LanguageGo, typescriptinfra.NewGoExecutor())
+	return executors
+}
+
 // startup is called when the app starts. The context is saved
 // so we can call the runtime methods
 func (a *App) startup(ctx context.Context) {
 	a.ctx = ctx
+
+	if a.triggerService != nil {
+		triggerCtx, cancel := context.WithCancel(ctx)
+		a.stopTriggers = cancel
+		a.triggerService.SetNotifier(triggerEvents{ctx: ctx})
+		a.triggerService.Start(triggerCtx, triggerPollInterval)
+	}
 }
 
 // shutdown is called when the app is closing
 func (a *App) shutdown(ctx context.Context) {
+	if a.stopTriggers != nil {
+		a.stopTriggers()
+	}
+
 	if a.persistence != nil {
 		if err := a.persistence.Close(); err != nil {
 			log.Printf("Warning: Failed to close persistence: %v", err)
 		}
 	}
 
+	if a.llmCallRepository != nil {
+		if err := a.llmCallRepository.Close(); err != nil {
+			log.Printf("Warning: Failed to close LLM call repository: %v", err)
+		}
+	}
+
+	if a.changeSetRepository != nil {
+		if err := a.changeSetRepository.Close(); err != nil {
+			log.Printf("Warning: Failed to close change set repository: %v", err)
+		}
+	}
+
+	if a.indexRepository != nil {
+		if err := a.indexRepository.Close(); err != nil {
+			log.Printf("Warning: Failed to close codebase index: %v", err)
+		}
+	}
+
+	if a.toolExamples != nil {
+		if err := a.toolExamples.Close(); err != nil {
+			log.Printf("Warning: Failed to close tool examples: %v", err)
+		}
+	}
+
+	if a.toolTriggers != nil {
+		if err := a.toolTriggers.Close(); err != nil {
+			log.Printf("Warning: Failed to close tool triggers: %v", err)
+		}
+	}
+
+	if a.pipelines != nil {
+		if err := a.pipelines.Close(); err != nil {
+			log.Printf("Warning: Failed to close pipelines: %v", err)
+		}
+	}
+
+	if a.escalations != nil {
+		if err := a.escalations.Close(); err != nil {
+			log.Printf("Warning: Failed to close capability escalations: %v", err)
+		}
+	}
+
+	if a.toolRuns != nil {
+		if err := a.toolRuns.Close(); err != nil {
+			log.Printf("Warning: Failed to close tool run history: %v", err)
+		}
+	}
+
+	if a.toolSyncStates != nil {
+		if err := a.toolSyncStates.Close(); err != nil {
+			log.Printf("Warning: Failed to close tool sync state: %v", err)
+		}
+	}
+
 	if a.toolRepository != nil {
 		if err := a.toolRepository.Close(); err != nil {
 			log.Printf("Warning: Failed to close tool repository: %v", err)
@@ -138,9 +474,23 @@ func (a *App) GetCurrentProjectTree() (treedomain.Node, error) {
 	return treedomain.ListDirectoryUseCase(lister, ".")
 }
 
-// SendChatMessage sends a message to the chat and returns the updated state
+// SendChatMessage sends a message to the chat and returns the updated state.
+// Code changes proposed in the reply are stored as a change set for review.
 func (a *App) SendChatMessage(message string) (chatdomain.ChatState, error) {
-	return a.chat.SendMessage(message)
+	state, err := a.chat.SendMessage(message)
+	if err != nil {
+		return state, err
+	}
+
+	if a.patchService != nil && len(state.Messages) > 0 {
+		lastIndex := len(state.Messages) - 1
+		_, err := a.patchService.Propose(state.Messages[lastIndex].Content, lastIndex)
+		if err != nil && !errors.Is(err, patchdomain.ErrNoChanges) {
+			log.Printf("Warning: Failed to extract proposed changes: %v", err)
+		}
+	}
+
+	return state, nil
 }
 
 // GetChatState returns the current chat state
@@ -148,34 +498,204 @@ func (a *App) GetChatState() chatdomain.ChatState {
 	return a.chat.GetState()
 }
 
-// ExecuteTypeScript executes TypeScript code and returns the result
+// ListLLMCalls returns the most recent LLM calls, newest first (all when limit <= 0)
+func (a *App) ListLLMCalls(limit int) ([]chatdomain.LLMCall, error) {
+	if a.llmCallRepository == nil {
+		return nil, fmt.Errorf("LLM call repository not available")
+	}
+
+	return a.llmCallRepository.List(limit)
+}
+
+// GetLLMCall retrieves a recorded LLM call by ID
+func (a *App) GetLLMCall(id string) (chatdomain.LLMCall, error) {
+	if a.llmCallRepository == nil {
+		return chatdomain.LLMCall{}, fmt.Errorf("LLM call repository not available")
+	}
+
+	return a.llmCallRepository.GetByID(id)
+}
+
+// GetCodebaseContextMode returns how the codebase context is chosen ("full" or "retrieval")
+func (a *App) GetCodebaseContextMode() string {
+	a.contextMu.Lock()
+	defer a.contextMu.Unlock()
+
+	return a.contextMode
+}
+
+// SetCodebaseContextMode switches between sending the whole codebase ("full")
+// and only the chunks most relevant to each question ("retrieval")
+func (a *App) SetCodebaseContextMode(mode string) error {
+	provider, ok := a.contextProviders[mode]
+	if !ok {
+		return fmt.Errorf("codebase context mode %q not available", mode)
+	}
+
+	a.contextMu.Lock()
+	defer a.contextMu.Unlock()
+
+	a.chat.SetContextProvider(provider)
+	a.contextMode = mode
+	return nil
+}
+
+// RebuildCodebaseIndex re-reads the project tree into the retrieval index
+func (a *App) RebuildCodebaseIndex() (retrievaldomain.IndexStats, error) {
+	if a.indexService == nil {
+		return retrievaldomain.IndexStats{}, fmt.Errorf("codebase index not available")
+	}
+
+	return a.indexService.Rebuild()
+}
+
+// SearchCodebase returns the chunks retrieval would pick for a question
+func (a *App) SearchCodebase(query string, limit int) ([]retrievaldomain.ScoredChunk, error) {
+	if a.indexService == nil {
+		return nil, fmt.Errorf("codebase index not available")
+	}
+
+	return a.indexService.Search(query, limit)
+}
+
+// GetGitContextOptions returns whether git state is sent with chat messages
+func (a *App) GetGitContextOptions() chatdomain.GitContextOptions {
+	return a.chat.GitContextOptions()
+}
+
+// SetGitContextOptions enables or disables sending the current branch, diffs
+// and recent commits with chat messages
+func (a *App) SetGitContextOptions(options chatdomain.GitContextOptions) chatdomain.GitContextOptions {
+	a.chat.SetGitContextOptions(options)
+	return a.chat.GitContextOptions()
+}
+
+// ListChangeSets returns the code changes proposed by the assistant, newest first
+func (a *App) ListChangeSets() ([]patchdomain.ChangeSet, error) {
+	if a.patchService == nil {
+		return nil, fmt.Errorf("patch service not available")
+	}
+
+	return a.patchService.ChangeSets()
+}
+
+// GetChangeSet retrieves a proposed change set by ID
+func (a *App) GetChangeSet(id string) (patchdomain.ChangeSet, error) {
+	if a.patchService == nil {
+		return patchdomain.ChangeSet{}, fmt.Errorf("patch service not available")
+	}
+
+	return a.patchService.ChangeSet(id)
+}
+
+// ApplyChanges applies the selected hunks of a change set to the working
+// directory and returns the record needed to undo them
+func (a *App) ApplyChanges(changeSetID string, selections []patchdomain.HunkSelection) (patchdomain.UndoRecord, error) {
+	if a.patchService == nil {
+		return patchdomain.UndoRecord{}, fmt.Errorf("patch service not available")
+	}
+
+	return a.patchService.Apply(changeSetID, selections)
+}
+
+// RejectChanges marks the selected hunks of a change set as rejected
+func (a *App) RejectChanges(changeSetID string, selections []patchdomain.HunkSelection) (patchdomain.ChangeSet, error) {
+	if a.patchService == nil {
+		return patchdomain.ChangeSet{}, fmt.Errorf("patch service not available")
+	}
+
+	return a.patchService.Reject(changeSetID, selections)
+}
+
+// UndoChanges reverts a previous apply
+func (a *App) UndoChanges(undoRecordID string) (patchdomain.ChangeSet, error) {
+	if a.patchService == nil {
+		return patchdomain.ChangeSet{}, fmt.Errorf("patch service not available")
+	}
+
+	return a.patchService.Undo(undoRecordID)
+}
+
+// ListUndoRecords returns the applies made from a change set, newest first
+func (a *App) ListUndoRecords(changeSetID string) ([]patchdomain.UndoRecord, error) {
+	if a.patchService == nil {
+		return nil, fmt.Errorf("patch service not available")
+	}
+
+	return a.patchService.UndoRecords(changeSetID)
+}
+
+// ExecuteTypeScript executes TypeScript code and returns the result. The run
+// is kept in the run history when the tool store is available.
 func (a *App) ExecuteTypeScript(code string) (typescriptdomain.ExecutionResult, error) {
-	result, err := a.typescriptExecutor.Execute(code)
+	if a.toolRunner == nil {
+		result, err := a.typescriptExecutor.Execute(code, typescriptdomain.ExecuteOptions{})
+		if err != nil {
+			return typescriptdomain.ExecutionResult{}, err
+		}
+		return *result, nil
+	}
+
+	output, err := a.toolRunner.RunCode(code)
 	if err != nil {
 		return typescriptdomain.ExecutionResult{}, err
 	}
-	return *result, nil
+	return executionResult(output), nil
 }
 
-// SaveTool saves a tool with the given name and code
-func (a *App) SaveTool(name, code string) (tooldomain.Tool, error) {
+// SaveTool creates a tool when id is empty, and otherwise updates the name
+// and code of tool id. version is the version the caller loaded; an update
+// fails with ErrToolVersionConflict when the tool was saved since.
+func (a *App) SaveTool(id string, version int, name, code string) (tooldomain.Tool, error) {
 	if a.toolRepository == nil {
 		return tooldomain.Tool{}, fmt.Errorf("tool repository not available")
 	}
 
-	// Validate and create the tool
-	tool, err := tooldomain.NewToolWithValidation(name, code)
+	if id == "" {
+		// Validate and create the tool
+		created, err := tooldomain.NewToolWithValidation(name, code)
+		if err != nil {
+			return tooldomain.Tool{}, fmt.Errorf("validation failed: %w", err)
+		}
+
+		tool, err := a.typeCheck(*created)
+		if err != nil {
+			return tooldomain.Tool{}, fmt.Errorf("validation failed: %w", err)
+		}
+
+		saved, err := a.toolRepository.Save(tool)
+		if err != nil {
+			return tooldomain.Tool{}, fmt.Errorf("failed to save tool: %w", err)
+		}
+		return saved, nil
+	}
+
+	tool, err := a.toolRepository.GetByID(id)
+	if err != nil {
+		return tooldomain.Tool{}, err
+	}
+
+	// Keep the caller's version so a stale editor can't overwrite newer work
+	tool.Version = version
+	renamed, err := tool.WithName(name)
 	if err != nil {
 		return tooldomain.Tool{}, fmt.Errorf("validation failed: %w", err)
 	}
+	updated := renamed.WithUpdatedCode(code)
+	if updated.Code == "" {
+		return tooldomain.Tool{}, fmt.Errorf("validation failed: %w", tooldomain.ErrToolCodeEmpty)
+	}
 
-	// Save to repository
-	err = a.toolRepository.Save(*tool)
+	updated, err = a.typeCheck(updated)
 	if err != nil {
-		return tooldomain.Tool{}, fmt.Errorf("failed to save tool: %w", err)
+		return tooldomain.Tool{}, fmt.Errorf("validation failed: %w", err)
 	}
 
-	return *tool, nil
+	saved, err := a.toolRepository.Save(updated)
+	if err != nil {
+		return tooldomain.Tool{}, fmt.Errorf("failed to save tool: %w", err)
+	}
+	return saved, nil
 }
 
 // GetTool retrieves a tool by ID
@@ -187,6 +707,73 @@ func (a *App) GetTool(id string) (tooldomain.Tool, error) {
 	return a.toolRepository.GetByID(id)
 }
 
+// typeCheck applies the type-check policy to a tool about to be saved. When
+// the compiler can't run, the tool is saved unchecked rather than blocked.
+func (a *App) typeCheck(tool tooldomain.Tool) (tooldomain.Tool, error) {
+	diagnostics, err := a.checkTypes(tool)
+	if err != nil {
+		log.Printf("Warning: Could not type-check tool %s: %v", tool.Name, err)
+		return tool, nil
+	}
+
+	return a.GetToolTypeCheckPolicy().Apply(tool, diagnostics)
+}
+
+func (a *App) checkTypes(tool tooldomain.Tool) ([]tooldomain.ToolDiagnostic, error) {
+	if a.toolTypeChecker == nil {
+		return nil, nil
+	}
+	return a.toolTypeChecker.Check(tool)
+}
+
+// CheckToolTypes returns the type errors in the code of a saved tool
+func (a *App) CheckToolTypes(id string) ([]tooldomain.ToolDiagnostic, error) {
+	if a.toolRepository == nil || a.toolTypeChecker == nil {
+		return nil, fmt.Errorf("tool type checking not available")
+	}
+
+	tool, err := a.toolRepository.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.toolTypeChecker.Check(tool)
+}
+
+// CheckToolCode returns the type errors in code that is not saved yet; it may
+// import saved tools like any tool
+func (a *App) CheckToolCode(code string) ([]tooldomain.ToolDiagnostic, error) {
+	if a.toolTypeChecker == nil {
+		return nil, fmt.Errorf("tool type checking not available")
+	}
+
+	return a.toolTypeChecker.Check(tooldomain.NewTool("unsaved", code))
+}
+
+// GetToolTypeCheckPolicy returns what happens to tools saved with type errors
+// ("draft" or "block")
+func (a *App) GetToolTypeCheckPolicy() tooldomain.TypeCheckPolicy {
+	a.typeCheckMu.Lock()
+	defer a.typeCheckMu.Unlock()
+
+	return a.typeCheckPolicy
+}
+
+// SetToolTypeCheckPolicy chooses between saving tools with type errors as
+// drafts ("draft") and refusing to save them ("block")
+func (a *App) SetToolTypeCheckPolicy(policy string) error {
+	parsed, err := tooldomain.ParseTypeCheckPolicy(policy)
+	if err != nil {
+		return err
+	}
+
+	a.typeCheckMu.Lock()
+	defer a.typeCheckMu.Unlock()
+
+	a.typeCheckPolicy = parsed
+	return nil
+}
+
 // GetToolByName retrieves a tool by name
 func (a *App) GetToolByName(name string) (tooldomain.Tool, error) {
 	if a.toolRepository == nil {
@@ -196,11 +783,863 @@ func (a *App) GetToolByName(name string) (tooldomain.Tool, error) {
 	return a.toolRepository.GetByName(name)
 }
 
-// ListTools returns all saved tools
+// ListTools returns the tools visible in the current project: its own, the
+// teams' and the global ones, without those shadowed by a tool of the same name
 func (a *App) ListTools() ([]tooldomain.Tool, error) {
 	if a.toolRepository == nil {
 		return nil, fmt.Errorf("tool repository not available")
 	}
 
 	return a.toolRepository.List()
-}
\ No newline at end of file
+}
+
+// GetProjectNamespace returns the namespace of the current project's tools
+func (a *App) GetProjectNamespace() (string, error) {
+	if a.scopedTools == nil {
+		return "", fmt.Errorf("tool repository not available")
+	}
+
+	return tooldomain.ProjectNamespace(a.scopedTools.Scope().ProjectRoot), nil
+}
+
+// ListToolNamespaces returns the namespaces visible in the current project
+// that hold tools, the one winning name clashes first
+func (a *App) ListToolNamespaces() ([]string, error) {
+	if a.scopedTools == nil {
+		return nil, fmt.Errorf("tool repository not available")
+	}
+
+	return a.scopedTools.Namespaces()
+}
+
+// ListToolsInNamespace returns every tool of a namespace, including those
+// shadowed in the current project
+func (a *App) ListToolsInNamespace(namespace string) ([]tooldomain.Tool, error) {
+	if a.scopedTools == nil {
+		return nil, fmt.Errorf("tool repository not available")
+	}
+
+	return a.scopedTools.ListNamespace(namespace)
+}
+
+// MoveToolToNamespace moves a tool to "global", "project:<root>" or
+// "team:<name>"; it fails when the namespace has a tool with the same name
+func (a *App) MoveToolToNamespace(id string, version int, namespace string) (tooldomain.Tool, error) {
+	if a.toolRepository == nil {
+		return tooldomain.Tool{}, fmt.Errorf("tool repository not available")
+	}
+
+	tool, err := a.toolRepository.GetByID(id)
+	if err != nil {
+		return tooldomain.Tool{}, err
+	}
+
+	tool.Version = version
+	moved, err := tool.WithNamespace(namespace)
+	if err != nil {
+		return tooldomain.Tool{}, fmt.Errorf("validation failed: %w", err)
+	}
+
+	saved, err := a.toolRepository.SaveWithMessage(moved, fmt.Sprintf("Move from %s", tooldomain.NormalizeNamespace(tool.Namespace)))
+	if err != nil {
+		return tooldomain.Tool{}, fmt.Errorf("failed to move tool: %w", err)
+	}
+	return saved, nil
+}
+
+// UpdateTool saves new code for an existing tool as a new revision; like
+// SaveTool it fails when the tool was saved since version was loaded
+func (a *App) UpdateTool(id string, version int, code, message string) (tooldomain.Tool, error) {
+	if a.toolRepository == nil {
+		return tooldomain.Tool{}, fmt.Errorf("tool repository not available")
+	}
+
+	tool, err := a.toolRepository.GetByID(id)
+	if err != nil {
+		return tooldomain.Tool{}, err
+	}
+
+	tool.Version = version
+	updated := tool.WithUpdatedCode(code)
+	if updated.Code == "" {
+		return tooldomain.Tool{}, fmt.Errorf("validation failed: %w", tooldomain.ErrToolCodeEmpty)
+	}
+
+	updated, err = a.typeCheck(updated)
+	if err != nil {
+		return tooldomain.Tool{}, fmt.Errorf("validation failed: %w", err)
+	}
+
+	saved, err := a.toolRepository.SaveWithMessage(updated, message)
+	if err != nil {
+		return tooldomain.Tool{}, fmt.Errorf("failed to save tool: %w", err)
+	}
+	return saved, nil
+}
+
+// ListToolRevisions returns the saved revisions of a tool, newest first
+func (a *App) ListToolRevisions(toolID string) ([]tooldomain.ToolRevision, error) {
+	if a.toolRepository == nil {
+		return nil, fmt.Errorf("tool repository not available")
+	}
+
+	return a.toolRepository.ListRevisions(toolID)
+}
+
+// GetToolRevision returns one revision of a tool
+func (a *App) GetToolRevision(toolID string, revision int) (tooldomain.ToolRevision, error) {
+	if a.toolRepository == nil {
+		return tooldomain.ToolRevision{}, fmt.Errorf("tool repository not available")
+	}
+
+	return a.toolRepository.GetRevision(toolID, revision)
+}
+
+// DiffToolRevisions returns the line diff between two revisions of a tool
+func (a *App) DiffToolRevisions(toolID string, from, to int) (tooldomain.RevisionDiff, error) {
+	if a.toolRepository == nil {
+		return tooldomain.RevisionDiff{}, fmt.Errorf("tool repository not available")
+	}
+
+	return a.toolRepository.DiffRevisions(toolID, from, to)
+}
+
+// RevertTool restores the code of an earlier revision as a new revision
+func (a *App) RevertTool(toolID string, revision int) (tooldomain.Tool, error) {
+	if a.toolRepository == nil {
+		return tooldomain.Tool{}, fmt.Errorf("tool repository not available")
+	}
+
+	return a.toolRepository.Revert(toolID, revision)
+}
+
+// RenameTool gives a tool a new name, which must not be used by another
+// tool; version is the version the caller loaded
+func (a *App) RenameTool(id string, version int, newName string) (tooldomain.Tool, error) {
+	if a.toolRepository == nil {
+		return tooldomain.Tool{}, fmt.Errorf("tool repository not available")
+	}
+
+	tool, err := a.toolRepository.GetByID(id)
+	if err != nil {
+		return tooldomain.Tool{}, err
+	}
+
+	tool.Version = version
+	renamed, err := tool.WithName(newName)
+	if err != nil {
+		return tooldomain.Tool{}, fmt.Errorf("validation failed: %w", err)
+	}
+
+	saved, err := a.toolRepository.SaveWithMessage(renamed, fmt.Sprintf("Rename from %s", tool.Name))
+	if err != nil {
+		return tooldomain.Tool{}, fmt.Errorf("failed to rename tool: %w", err)
+	}
+	return saved, nil
+}
+
+// DuplicateTool saves a copy of a tool under a new name; an empty name
+// falls back to "<name> copy". version is the version the caller loaded,
+// so the copy is of the code the caller sees.
+func (a *App) DuplicateTool(id string, version int, newName string) (tooldomain.Tool, error) {
+	if a.toolRepository == nil {
+		return tooldomain.Tool{}, fmt.Errorf("tool repository not available")
+	}
+
+	tool, err := a.toolRepository.GetByID(id)
+	if err != nil {
+		return tooldomain.Tool{}, err
+	}
+	if tool.Version != version {
+		return tooldomain.Tool{}, fmt.Errorf("failed to duplicate tool: %w", tooldomain.ErrToolVersionConflict)
+	}
+
+	duplicate, err := tool.Duplicate(newName)
+	if err != nil {
+		return tooldomain.Tool{}, fmt.Errorf("validation failed: %w", err)
+	}
+
+	saved, err := a.toolRepository.SaveWithMessage(duplicate, fmt.Sprintf("Duplicated from %s", tool.Name))
+	if err != nil {
+		return tooldomain.Tool{}, fmt.Errorf("failed to duplicate tool: %w", err)
+	}
+	return saved, nil
+}
+
+// DeleteTool moves a tool to the trash
+func (a *App) DeleteTool(id string) error {
+	if a.toolRepository == nil {
+		return fmt.Errorf("tool repository not available")
+	}
+
+	return a.toolRepository.Delete(id)
+}
+
+// ListTrashedTools returns the tools in the trash, most recently deleted first
+func (a *App) ListTrashedTools() ([]tooldomain.Tool, error) {
+	if a.toolRepository == nil {
+		return nil, fmt.Errorf("tool repository not available")
+	}
+
+	return a.toolRepository.ListTrash()
+}
+
+// RestoreTool takes a tool out of the trash
+func (a *App) RestoreTool(id string) (tooldomain.Tool, error) {
+	if a.toolRepository == nil {
+		return tooldomain.Tool{}, fmt.Errorf("tool repository not available")
+	}
+
+	return a.toolRepository.Restore(id)
+}
+
+// PurgeTool permanently removes a tool from the trash
+func (a *App) PurgeTool(id string) error {
+	if a.toolRepository == nil {
+		return fmt.Errorf("tool repository not available")
+	}
+
+	if err := a.toolRepository.Purge(id); err != nil {
+		return err
+	}
+
+	if a.toolExamples != nil {
+		if err := a.toolExamples.DeleteByTool(id); err != nil {
+			log.Printf("Warning: Failed to delete examples of purged tool: %v", err)
+		}
+	}
+	return nil
+}
+
+// SetToolParameters declares the arguments a tool accepts; version is the
+// version the caller loaded
+func (a *App) SetToolParameters(id string, version int, parameters []tooldomain.ToolParameter) (tooldomain.Tool, error) {
+	if a.toolRepository == nil {
+		return tooldomain.Tool{}, fmt.Errorf("tool repository not available")
+	}
+
+	tool, err := a.toolRepository.GetByID(id)
+	if err != nil {
+		return tooldomain.Tool{}, err
+	}
+
+	tool.Version = version
+	updated, err := tool.WithParameters(parameters)
+	if err != nil {
+		return tooldomain.Tool{}, fmt.Errorf("validation failed: %w", err)
+	}
+
+	saved, err := a.toolRepository.SaveWithMessage(updated, "Update parameters")
+	if err != nil {
+		return tooldomain.Tool{}, fmt.Errorf("failed to save tool: %w", err)
+	}
+	return saved, nil
+}
+
+// SetToolDependencies declares the npm packages a tool imports; they are
+// installed into the shared package cache on the next run. version is the
+// version the caller loaded.
+func (a *App) SetToolDependencies(id string, version int, dependencies []tooldomain.NpmDependency) (tooldomain.Tool, error) {
+	if a.toolRepository == nil {
+		return tooldomain.Tool{}, fmt.Errorf("tool repository not available")
+	}
+
+	tool, err := a.toolRepository.GetByID(id)
+	if err != nil {
+		return tooldomain.Tool{}, err
+	}
+
+	tool.Version = version
+	updated, err := tool.WithDependencies(dependencies)
+	if err != nil {
+		return tooldomain.Tool{}, fmt.Errorf("validation failed: %w", err)
+	}
+
+	// The code may only type-check with the packages it declared before
+	updated, err = a.typeCheck(updated)
+	if err != nil {
+		return tooldomain.Tool{}, fmt.Errorf("validation failed: %w", err)
+	}
+
+	saved, err := a.toolRepository.SaveWithMessage(updated, "Update dependencies")
+	if err != nil {
+		return tooldomain.Tool{}, fmt.Errorf("failed to save tool: %w", err)
+	}
+	return saved, nil
+}
+
+// RunTool runs a saved tool by name after validating args against its parameters
+func (a *App) RunTool(name string, args map[string]interface{}) (typescriptdomain.ExecutionResult, error) {
+	if a.toolRepository == nil {
+		return typescriptdomain.ExecutionResult{}, fmt.Errorf("tool repository not available")
+	}
+
+	tool, err := a.toolRepository.GetByName(name)
+	if err != nil {
+		return typescriptdomain.ExecutionResult{}, err
+	}
+
+	resolved, err := tool.ResolveArguments(args)
+	if err != nil {
+		return typescriptdomain.ExecutionResult{}, err
+	}
+
+	output, err := a.toolRunner.Run(tool, resolved)
+	if err != nil {
+		return typescriptdomain.ExecutionResult{}, err
+	}
+	return executionResult(output), nil
+}
+
+// executionResult presents the output of a tool run like a direct execution
+func executionResult(output tooldomain.ToolRunOutput) typescriptdomain.ExecutionResult {
+	return typescriptdomain.ExecutionResult{
+		Output:   output.Output,
+		Error:    output.Error,
+		Success:  output.Success,
+		ExitCode: output.ExitCode,
+	}
+}
+
+// GetToolDependencies returns the tools a tool imports, transitively, in
+// dependency order
+func (a *App) GetToolDependencies(id string) (tooldomain.ToolDependencyGraph, error) {
+	if a.toolRepository == nil {
+		return tooldomain.ToolDependencyGraph{}, fmt.Errorf("tool repository not available")
+	}
+
+	tool, err := a.toolRepository.GetByID(id)
+	if err != nil {
+		return tooldomain.ToolDependencyGraph{}, err
+	}
+
+	return tooldomain.ResolveDependencies(tool, a.toolRepository)
+}
+
+// SetToolMetadata sets the description and tags of a tool; version is the
+// version the caller loaded
+func (a *App) SetToolMetadata(id string, version int, description string, tags []string) (tooldomain.Tool, error) {
+	if a.toolRepository == nil {
+		return tooldomain.Tool{}, fmt.Errorf("tool repository not available")
+	}
+
+	tool, err := a.toolRepository.GetByID(id)
+	if err != nil {
+		return tooldomain.Tool{}, err
+	}
+
+	tool.Version = version
+	updated := tool.WithMetadata(description, tags)
+	saved, err := a.toolRepository.SaveWithMessage(updated, "Update description and tags")
+	if err != nil {
+		return tooldomain.Tool{}, fmt.Errorf("failed to save tool: %w", err)
+	}
+	return saved, nil
+}
+
+// PublishTool marks a draft tool as reviewed; version is the version the
+// caller loaded
+func (a *App) PublishTool(id string, version int) (tooldomain.Tool, error) {
+	if a.toolRepository == nil {
+		return tooldomain.Tool{}, fmt.Errorf("tool repository not available")
+	}
+
+	tool, err := a.toolRepository.GetByID(id)
+	if err != nil {
+		return tooldomain.Tool{}, err
+	}
+
+	// A draft with type errors can't be published, whatever the policy
+	diagnostics, err := a.checkTypes(tool)
+	if err != nil {
+		log.Printf("Warning: Could not type-check tool %s: %v", tool.Name, err)
+	}
+	if _, err := tooldomain.TypeCheckBlock.Apply(tool, diagnostics); err != nil {
+		return tooldomain.Tool{}, fmt.Errorf("validation failed: %w", err)
+	}
+
+	tool.Version = version
+	published := tool.Published()
+	saved, err := a.toolRepository.SaveWithMessage(published, "Publish draft")
+	if err != nil {
+		return tooldomain.Tool{}, fmt.Errorf("failed to save tool: %w", err)
+	}
+	return saved, nil
+}
+
+// ListToolLanguages returns the languages tools can be written in
+func (a *App) ListToolLanguages() []string {
+	languages := []string{}
+	if a.executors == nil {
+		return languages
+	}
+	for _, language := range a.executors.Languages() {
+		languages = append(languages, string(language))
+	}
+	return languages
+}
+
+// SetToolLanguage sets the language the code of a tool is run as;
+// version is the version the caller loaded
+func (a *App) SetToolLanguage(id string, version int, language string) (tooldomain.Tool, error) {
+	if a.toolRepository == nil {
+		return tooldomain.Tool{}, fmt.Errorf("tool repository not available")
+	}
+
+	tool, err := a.toolRepository.GetByID(id)
+	if err != nil {
+		return tooldomain.Tool{}, err
+	}
+
+	tool.Version = version
+	updated, err := tool.WithLanguage(language)
+	if err != nil {
+		return tooldomain.Tool{}, fmt.Errorf("validation failed: %w", err)
+	}
+
+	// Only TypeScript is type-checked, so switching to it may find errors
+	updated, err = a.typeCheck(updated)
+	if err != nil {
+		return tooldomain.Tool{}, fmt.Errorf("validation failed: %w", err)
+	}
+
+	saved, err := a.toolRepository.SaveWithMessage(updated, fmt.Sprintf("Change language to %s", updated.Language))
+	if err != nil {
+		return tooldomain.Tool{}, fmt.Errorf("failed to save tool: %w", err)
+	}
+	return saved, nil
+}
+
+// SetToolCapabilities replaces what a tool may access when it runs;
+// version is the version the caller loaded
+func (a *App) SetToolCapabilities(id string, version int, capabilities tooldomain.ToolCapabilities) (tooldomain.Tool, error) {
+	if a.toolRepository == nil {
+		return tooldomain.Tool{}, fmt.Errorf("tool repository not available")
+	}
+
+	tool, err := a.toolRepository.GetByID(id)
+	if err != nil {
+		return tooldomain.Tool{}, err
+	}
+
+	tool.Version = version
+	updated, err := tool.WithCapabilities(capabilities)
+	if err != nil {
+		return tooldomain.Tool{}, fmt.Errorf("validation failed: %w", err)
+	}
+
+	saved, err := a.toolRepository.SaveWithMessage(updated, "Change capabilities")
+	if err != nil {
+		return tooldomain.Tool{}, fmt.Errorf("failed to save tool: %w", err)
+	}
+	return saved, nil
+}
+
+// ListCapabilityEscalations returns the accesses tools were refused with the
+// given status ("pending", "granted" or "denied"; empty for all), newest first
+func (a *App) ListCapabilityEscalations(status string) ([]tooldomain.CapabilityEscalation, error) {
+	if a.escalations == nil {
+		return nil, fmt.Errorf("capability escalations not available")
+	}
+
+	return a.escalations.List(tooldomain.EscalationStatus(status))
+}
+
+// GrantCapabilityEscalation adds what a pending escalation asked for to the
+// capabilities of its tool and returns the saved tool
+func (a *App) GrantCapabilityEscalation(id string) (tooldomain.Tool, error) {
+	if a.capabilityService == nil {
+		return tooldomain.Tool{}, fmt.Errorf("capability escalations not available")
+	}
+
+	return a.capabilityService.Grant(id)
+}
+
+// DenyCapabilityEscalation settles a pending escalation; the tool keeps
+// being refused the access
+func (a *App) DenyCapabilityEscalation(id string) (tooldomain.CapabilityEscalation, error) {
+	if a.capabilityService == nil {
+		return tooldomain.CapabilityEscalation{}, fmt.Errorf("capability escalations not available")
+	}
+
+	return a.capabilityService.Deny(id)
+}
+
+// SearchTools returns the tools matching the text and tag of query
+func (a *App) SearchTools(query tooldomain.ToolQuery) ([]tooldomain.Tool, error) {
+	if a.toolRepository == nil {
+		return nil, fmt.Errorf("tool repository not available")
+	}
+
+	return a.toolRepository.Search(query)
+}
+
+// ListToolsByFrecency returns all saved tools, most used and most recently
+// used first
+func (a *App) ListToolsByFrecency() ([]tooldomain.Tool, error) {
+	tools, err := a.ListTools()
+	if err != nil {
+		return nil, err
+	}
+
+	return a.rankByFrecency(tools)
+}
+
+// SearchToolsByFrecency is SearchTools with the results ranked by frecency
+func (a *App) SearchToolsByFrecency(query tooldomain.ToolQuery) ([]tooldomain.Tool, error) {
+	tools, err := a.SearchTools(query)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.rankByFrecency(tools)
+}
+
+func (a *App) rankByFrecency(tools []tooldomain.Tool) ([]tooldomain.Tool, error) {
+	if a.toolRuns == nil {
+		return nil, fmt.Errorf("tool run history not available")
+	}
+
+	usage, err := a.toolRuns.UsageStats()
+	if err != nil {
+		return nil, err
+	}
+
+	return tooldomain.RankByFrecency(tools, usage, time.Now()), nil
+}
+
+// GetToolUsage returns the run count, last run, average duration and failure
+// rate of a tool
+func (a *App) GetToolUsage(id string) (tooldomain.ToolUsage, error) {
+	usage, err := a.ListToolUsage()
+	if err != nil {
+		return tooldomain.ToolUsage{}, err
+	}
+
+	if toolUsage, ok := usage[id]; ok {
+		return toolUsage, nil
+	}
+	return tooldomain.NewToolUsage(id, 0, 0, 0, nil), nil
+}
+
+// ListToolUsage returns the usage of every tool that ran, keyed by tool ID
+func (a *App) ListToolUsage() (map[string]tooldomain.ToolUsage, error) {
+	if a.toolRuns == nil {
+		return nil, fmt.Errorf("tool run history not available")
+	}
+
+	return a.toolRuns.UsageStats()
+}
+
+// ListToolTags returns every tag used by a tool
+func (a *App) ListToolTags() ([]string, error) {
+	if a.toolRepository == nil {
+		return nil, fmt.Errorf("tool repository not available")
+	}
+
+	return a.toolRepository.ListTags()
+}
+
+// SyncTools runs a two-way sync between the tool store and the project's
+// .lumina/tools directory; tools changed on both sides are reported as conflicts
+func (a *App) SyncTools() (tooldomain.ToolSyncReport, error) {
+	if a.toolSyncService == nil {
+		return tooldomain.ToolSyncReport{}, fmt.Errorf("tool sync not available")
+	}
+
+	return a.toolSyncService.Sync()
+}
+
+// ResolveToolSyncConflict keeps either the "file" or the "database" copy of a tool
+func (a *App) ResolveToolSyncConflict(toolID string, keep string) (tooldomain.ToolSyncReport, error) {
+	if a.toolSyncService == nil {
+		return tooldomain.ToolSyncReport{}, fmt.Errorf("tool sync not available")
+	}
+
+	return a.toolSyncService.Resolve(toolID, tooldomain.SyncSide(keep))
+}
+
+// SaveToolExample adds an example to a tool, or updates it when exampleID is
+// set. Arguments are checked against the tool's parameters.
+func (a *App) SaveToolExample(toolID, exampleID, name string, args map[string]interface{}, expectedOutput string, assertions []tooldomain.ExampleAssertion) (tooldomain.ToolExample, error) {
+	if a.toolRepository == nil || a.toolExamples == nil {
+		return tooldomain.ToolExample{}, fmt.Errorf("tool examples not available")
+	}
+
+	tool, err := a.toolRepository.GetByID(toolID)
+	if err != nil {
+		return tooldomain.ToolExample{}, err
+	}
+
+	example, err := tooldomain.NewToolExample(tool, name, args, expectedOutput, assertions)
+	if err != nil {
+		return tooldomain.ToolExample{}, err
+	}
+
+	if exampleID != "" {
+		existing, err := a.toolExamples.GetByID(exampleID)
+		if err != nil {
+			return tooldomain.ToolExample{}, err
+		}
+		example.ID = existing.ID
+		example.CreatedAt = existing.CreatedAt
+	}
+
+	if err := a.toolExamples.Save(example); err != nil {
+		return tooldomain.ToolExample{}, err
+	}
+	return example, nil
+}
+
+// ListToolExamples returns the examples of a tool ordered by name
+func (a *App) ListToolExamples(toolID string) ([]tooldomain.ToolExample, error) {
+	if a.toolExamples == nil {
+		return nil, fmt.Errorf("tool examples not available")
+	}
+
+	return a.toolExamples.ListByTool(toolID)
+}
+
+// DeleteToolExample removes an example
+func (a *App) DeleteToolExample(id string) error {
+	if a.toolExamples == nil {
+		return fmt.Errorf("tool examples not available")
+	}
+
+	return a.toolExamples.Delete(id)
+}
+
+// RunToolExamples runs the examples of one tool and reports pass/fail per example
+func (a *App) RunToolExamples(toolID string) (tooldomain.ExampleSuiteReport, error) {
+	if a.exampleService == nil {
+		return tooldomain.ExampleSuiteReport{}, fmt.Errorf("tool examples not available")
+	}
+
+	return a.exampleService.RunTool(toolID)
+}
+
+// RunAllToolExamples runs the examples of every tool in the library as a
+// regression suite
+func (a *App) RunAllToolExamples() (tooldomain.ExampleSuiteReport, error) {
+	if a.exampleService == nil {
+		return tooldomain.ExampleSuiteReport{}, fmt.Errorf("tool examples not available")
+	}
+
+	return a.exampleService.RunAll()
+}
+
+// GenerateTool asks the assistant for a tool implementing description and
+// saves it as a draft once the example it proposed passes
+func (a *App) GenerateTool(description string) (tooldomain.ToolGenerationResult, error) {
+	if a.generationService == nil {
+		return tooldomain.ToolGenerationResult{}, fmt.Errorf("tool generation not available")
+	}
+
+	return a.generationService.Generate(description)
+}
+
+// ExportTools writes the tools with the given IDs, with their revisions and
+// examples, to a bundle file at path
+func (a *App) ExportTools(toolIDs []string, path string) (tooldomain.ToolBundleExport, error) {
+	if a.bundleService == nil {
+		return tooldomain.ToolBundleExport{}, fmt.Errorf("tool bundles not available")
+	}
+
+	return a.bundleService.Export(toolIDs, path)
+}
+
+// ImportTools adds the tools of the bundle at path to namespace. strategy
+// is "skip", "rename" or "overwrite" and decides what happens to a tool
+// whose name is taken in the namespace.
+func (a *App) ImportTools(path, strategy, namespace string) (tooldomain.ToolBundleImport, error) {
+	if a.bundleService == nil {
+		return tooldomain.ToolBundleImport{}, fmt.Errorf("tool bundles not available")
+	}
+
+	conflictStrategy, err := tooldomain.ParseImportConflictStrategy(strategy)
+	if err != nil {
+		return tooldomain.ToolBundleImport{}, err
+	}
+	return a.bundleService.Import(path, conflictStrategy, namespace)
+}
+
+// ListToolRuns returns the recorded runs matching query, most recent first
+func (a *App) ListToolRuns(query tooldomain.ToolRunQuery) ([]tooldomain.ToolRun, error) {
+	if a.toolRuns == nil {
+		return nil, fmt.Errorf("tool run history not available")
+	}
+
+	return a.toolRuns.List(query)
+}
+
+// GetToolRun retrieves a recorded run by ID
+func (a *App) GetToolRun(id string) (tooldomain.ToolRun, error) {
+	if a.toolRuns == nil {
+		return tooldomain.ToolRun{}, fmt.Errorf("tool run history not available")
+	}
+
+	return a.toolRuns.GetByID(id)
+}
+
+// DiffToolRuns compares the output of two recorded runs line by line
+func (a *App) DiffToolRuns(fromID, toID string) (tooldomain.ToolRunDiff, error) {
+	if a.toolRuns == nil {
+		return tooldomain.ToolRunDiff{}, fmt.Errorf("tool run history not available")
+	}
+
+	from, err := a.toolRuns.GetByID(fromID)
+	if err != nil {
+		return tooldomain.ToolRunDiff{}, err
+	}
+
+	to, err := a.toolRuns.GetByID(toID)
+	if err != nil {
+		return tooldomain.ToolRunDiff{}, err
+	}
+
+	return tooldomain.DiffToolRuns(from, to), nil
+}
+
+// SavePipeline creates a pipeline, or replaces the steps of the pipeline with
+// the given id when it is set
+func (a *App) SavePipeline(id, name string, steps []tooldomain.PipelineStep) (tooldomain.Pipeline, error) {
+	if a.pipelineService == nil {
+		return tooldomain.Pipeline{}, fmt.Errorf("pipelines not available")
+	}
+
+	pipeline, err := tooldomain.NewPipeline(name, steps)
+	if err != nil {
+		return tooldomain.Pipeline{}, err
+	}
+
+	if id != "" {
+		existing, err := a.pipelines.GetByID(id)
+		if err != nil {
+			return tooldomain.Pipeline{}, err
+		}
+		pipeline.ID = existing.ID
+		pipeline.CreatedAt = existing.CreatedAt
+	}
+
+	if err := a.pipelineService.Save(pipeline); err != nil {
+		return tooldomain.Pipeline{}, err
+	}
+	return pipeline, nil
+}
+
+// GetPipeline retrieves a pipeline by ID
+func (a *App) GetPipeline(id string) (tooldomain.Pipeline, error) {
+	if a.pipelines == nil {
+		return tooldomain.Pipeline{}, fmt.Errorf("pipelines not available")
+	}
+
+	return a.pipelines.GetByID(id)
+}
+
+// ListPipelines returns every pipeline ordered by name
+func (a *App) ListPipelines() ([]tooldomain.Pipeline, error) {
+	if a.pipelines == nil {
+		return nil, fmt.Errorf("pipelines not available")
+	}
+
+	return a.pipelines.List()
+}
+
+// DeletePipeline removes a pipeline
+func (a *App) DeletePipeline(id string) error {
+	if a.pipelines == nil {
+		return fmt.Errorf("pipelines not available")
+	}
+
+	return a.pipelines.Delete(id)
+}
+
+// RunPipeline runs the steps of a pipeline in order, piping input into the
+// first step, and reports the result of every step
+func (a *App) RunPipeline(id string, input map[string]interface{}) (tooldomain.PipelineRunResult, error) {
+	if a.pipelineService == nil {
+		return tooldomain.PipelineRunResult{}, fmt.Errorf("pipelines not available")
+	}
+
+	return a.pipelineService.Run(id, input)
+}
+
+// SaveToolTrigger adds a trigger to a tool, or updates it when triggerID is
+// set. kind is "file_change" (with a glob) or "interval" (with a period).
+func (a *App) SaveToolTrigger(toolID, triggerID, kind, glob string, intervalSeconds int, args map[string]interface{}, enabled bool) (tooldomain.ToolTrigger, error) {
+	if a.triggerService == nil {
+		return tooldomain.ToolTrigger{}, fmt.Errorf("tool triggers not available")
+	}
+
+	tool, err := a.toolRepository.GetByID(toolID)
+	if err != nil {
+		return tooldomain.ToolTrigger{}, err
+	}
+
+	trigger, err := tooldomain.NewToolTrigger(tool, tooldomain.TriggerKind(kind), glob, intervalSeconds, args)
+	if err != nil {
+		return tooldomain.ToolTrigger{}, err
+	}
+	trigger.Enabled = enabled
+
+	if triggerID != "" {
+		existing, err := a.toolTriggers.GetByID(triggerID)
+		if err != nil {
+			return tooldomain.ToolTrigger{}, err
+		}
+		trigger.ID = existing.ID
+		trigger.CreatedAt = existing.CreatedAt
+	}
+
+	if err := a.triggerService.Save(trigger); err != nil {
+		return tooldomain.ToolTrigger{}, err
+	}
+	return trigger, nil
+}
+
+// ListToolTriggers returns the triggers of a tool with their last output
+func (a *App) ListToolTriggers(toolID string) ([]tooldomain.ToolTrigger, error) {
+	if a.toolTriggers == nil {
+		return nil, fmt.Errorf("tool triggers not available")
+	}
+
+	return a.toolTriggers.ListByTool(toolID)
+}
+
+// DeleteToolTrigger removes a trigger
+func (a *App) DeleteToolTrigger(id string) error {
+	if a.triggerService == nil {
+		return fmt.Errorf("tool triggers not available")
+	}
+
+	return a.triggerService.Delete(id)
+}
+
+// RunToolTrigger runs the tool of a trigger now
+func (a *App) RunToolTrigger(id string) (tooldomain.TriggerResult, error) {
+	if a.triggerService == nil {
+		return tooldomain.TriggerResult{}, fmt.Errorf("tool triggers not available")
+	}
+
+	return a.triggerService.Fire(id)
+}
+
+// triggerEvents forwards trigger results to the frontend as Wails events
+type triggerEvents struct {
+	ctx context.Context
+}
+
+func (e triggerEvents) TriggerOutputChanged(result tooldomain.TriggerResult) {
+	runtime.EventsEmit(e.ctx, toolTriggerEvent, result)
+}
+
+// capabilityRequested asks the user in the window about the accesses a tool
+// was refused
+func (a *App) capabilityRequested(tool tooldomain.Tool, escalations []tooldomain.CapabilityEscalation) {
+	for _, escalation := range escalations {
+		log.Printf("Tool %s was refused %s, which it does not declare", tool.Name, escalation.Request())
+	}
+	if a.ctx != nil {
+		runtime.EventsEmit(a.ctx, toolCapabilityEvent, escalations)
+	}
+}
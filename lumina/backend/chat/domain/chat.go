@@ -4,29 +4,42 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"sync"
 )
 
+// Chat is a single conversation and is safe for concurrent use. Sends are
+// serialized: a SendMessage that overlaps another one waits for it to finish,
+// so turns never interleave. Reading the state never waits on the model.
 type Chat struct {
 	service            ChatService
-	repomixService     RepomixService
 	persistenceService PersistenceService
-	messages           []Message
+
+	// sendMu is held for the whole of a send, queueing overlapping sends
+	sendMu sync.Mutex
+
+	// mu guards the fields below
+	mu              sync.RWMutex
+	contextProvider CodebaseContextProvider
+	gitService      GitService
+	gitOptions      GitContextOptions
+	messages        []Message
 }
 
 func NewChat(service ChatService, repomixService RepomixService, persistenceService PersistenceService) *Chat {
 	chat := &Chat{
 		service:            service,
-		repomixService:     repomixService,
+		contextProvider:    NewRepomixContextProvider(repomixService),
 		persistenceService: persistenceService,
+		gitOptions:         GitContextOptions{CommitCount: defaultGitCommitCount},
 		messages:           make([]Message, 0),
 	}
 
-    messages, err := persistenceService.Load()
-    if err != nil {
-        log.Printf("Warning: Failed to load persisted messages: %v", err)
-    } else {
-        chat.messages = messages
-    }
+	messages, err := persistenceService.Load()
+	if err != nil {
+		log.Printf("Warning: Failed to load persisted messages: %v", err)
+	} else {
+		chat.messages = messages
+	}
 
 	return chat
 }
@@ -36,28 +49,23 @@ func (c *Chat) SendMessage(message string) (ChatState, error) {
 		return c.GetState(), errors.New("message cannot be empty")
 	}
 
-	messageToSend := message
+	c.sendMu.Lock()
+	defer c.sendMu.Unlock()
 
-	codebaseContext, err := c.repomixService.GenerateOutput()
+	messageToSend, contextChunks, err := c.buildPrompt(message)
 	if err != nil {
-		return c.GetState(), fmt.Errorf("failed to generate codebase context: %w", err)
+		return c.GetState(), err
 	}
 
-	messageToSend = fmt.Sprintf(
-		"Here is the current state of the codebase:\n\n%s\n\nUser question: %s",
-		codebaseContext,
-		message,
-	)
-
 	userMessage := Message{
-		Role:    "user",
-		Content: message,
+		Role:          "user",
+		Content:       message,
+		ContextChunks: contextChunks,
 	}
-	c.messages = append(c.messages, userMessage)
 
-    if err := c.persistenceService.Save(c.messages); err != nil {
-        log.Printf("Warning: Failed to persist user message: %v", err)
-    }
+	if err := c.persistenceService.Save(c.appendMessage(userMessage)); err != nil {
+		log.Printf("Warning: Failed to persist user message: %v", err)
+	}
 
 	response, err := c.service.SendMessage(messageToSend)
 	if err != nil {
@@ -68,17 +76,110 @@ func (c *Chat) SendMessage(message string) (ChatState, error) {
 		Role:    "assistant",
 		Content: response,
 	}
-	c.messages = append(c.messages, assistantMessage)
 
-    if err := c.persistenceService.Save(c.messages); err != nil {
-        log.Printf("Warning: Failed to persist assistant message: %v", err)
-    }
+	if err := c.persistenceService.Save(c.appendMessage(assistantMessage)); err != nil {
+		log.Printf("Warning: Failed to persist assistant message: %v", err)
+	}
 
 	return c.GetState(), nil
 }
 
+// appendMessage adds a message and returns a copy of the resulting history
+func (c *Chat) appendMessage(message Message) []Message {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.messages = append(c.messages, message)
+	return copyMessages(c.messages)
+}
+
+// SetContextProvider changes how the codebase context is chosen for following messages
+func (c *Chat) SetContextProvider(provider CodebaseContextProvider) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.contextProvider = provider
+}
+
+// SetGitService sets the service used to read git state; it is only
+// consulted while git context is enabled
+func (c *Chat) SetGitService(service GitService) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.gitService = service
+}
+
+// SetGitContextOptions turns the git context on or off for following messages
+func (c *Chat) SetGitContextOptions(options GitContextOptions) {
+	if options.CommitCount <= 0 {
+		options.CommitCount = defaultGitCommitCount
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.gitOptions = options
+}
+
+func (c *Chat) GitContextOptions() GitContextOptions {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.gitOptions
+}
+
+// buildPrompt wraps the user message with the codebase (and optionally git)
+// context and returns the codebase chunks it chose, if any
+func (c *Chat) buildPrompt(message string) (string, []ContextChunk, error) {
+	c.mu.RLock()
+	contextProvider, gitService, gitOptions := c.contextProvider, c.gitService, c.gitOptions
+	c.mu.RUnlock()
+
+	codebaseContext, err := contextProvider.ContextFor(message)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to generate codebase context: %w", err)
+	}
+
+	withoutGit := fmt.Sprintf(
+		"Here is the current state of the codebase:\n\n%s\n\nUser question: %s",
+		codebaseContext.Text,
+		message,
+	)
+	if !gitOptions.Enabled || gitService == nil {
+		return withoutGit, codebaseContext.Chunks, nil
+	}
+
+	// Git context is extra; e.g. outside a repository the message is sent without it
+	gitContext, err := gitService.CurrentContext(gitOptions.CommitCount)
+	if err != nil {
+		log.Printf("Warning: Failed to generate git context: %v", err)
+		return withoutGit, codebaseContext.Chunks, nil
+	}
+
+	return fmt.Sprintf(
+		"Here is the current state of the codebase:\n\n%s\n\nHere is the current git state:\n\n%s\nUser question: %s",
+		codebaseContext.Text,
+		gitContext.Format(),
+		message,
+	), codebaseContext.Chunks, nil
+}
+
+// GetState returns a snapshot of the conversation; callers may modify it freely
 func (c *Chat) GetState() ChatState {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
 	return ChatState{
-		Messages: c.messages,
+		Messages: copyMessages(c.messages),
+	}
+}
+
+func copyMessages(messages []Message) []Message {
+	copied := make([]Message, len(messages))
+	copy(copied, messages)
+	for i := range copied {
+		copied[i].ContextChunks = append([]ContextChunk(nil), messages[i].ContextChunks...)
 	}
-}
\ No newline at end of file
+	return copied
+}
@@ -0,0 +1,157 @@
+package domain_test
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"lumina/backend/chat/domain"
+)
+
+// ConcurrentChatService records how many sends are in flight at once and can
+// hold a send until released
+type ConcurrentChatService struct {
+	inFlight    atomic.Int32
+	maxInFlight atomic.Int32
+	started     chan struct{}
+	release     chan struct{}
+}
+
+func NewConcurrentChatService(blocking bool) *ConcurrentChatService {
+	service := &ConcurrentChatService{started: make(chan struct{}, 100)}
+	if blocking {
+		service.release = make(chan struct{})
+	}
+	return service
+}
+
+func (m *ConcurrentChatService) SendMessage(message string) (string, error) {
+	current := m.inFlight.Add(1)
+	defer m.inFlight.Add(-1)
+
+	for {
+		max := m.maxInFlight.Load()
+		if current <= max || m.maxInFlight.CompareAndSwap(max, current) {
+			break
+		}
+	}
+
+	m.started <- struct{}{}
+	if m.release != nil {
+		<-m.release
+	}
+	time.Sleep(time.Millisecond)
+
+	question := message[strings.LastIndex(message, "User question: ")+len("User question: "):]
+	return "reply to " + question, nil
+}
+
+func TestChat_ConcurrentSendsAreSerialized(t *testing.T) {
+	// Given a chat
+	service := NewConcurrentChatService(false)
+	chat := domain.NewChat(service, mockRepomix, &MockPersistenceService{})
+
+	// When many messages are sent and read concurrently
+	const senders = 10
+	var wg sync.WaitGroup
+	for i := 0; i < senders; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			_, err := chat.SendMessage(fmt.Sprintf("message-%02d", i))
+			assert.NoError(t, err)
+		}(i)
+		go func() {
+			defer wg.Done()
+			_ = chat.GetState()
+		}()
+	}
+	wg.Wait()
+
+	// Then the model should never have seen two sends at once
+	assert.Equal(t, int32(1), service.maxInFlight.Load())
+
+	// And every answer should directly follow its question
+	messages := chat.GetState().Messages
+	require.Len(t, messages, 2*senders)
+	for i := 0; i < len(messages); i += 2 {
+		assert.Equal(t, "user", messages[i].Role)
+		assert.Equal(t, "assistant", messages[i+1].Role)
+		assert.Equal(t, "reply to "+messages[i].Content, messages[i+1].Content)
+	}
+}
+
+func TestChat_GetStateDoesNotWaitForPendingSend(t *testing.T) {
+	// Given a send that is waiting on the model
+	service := NewConcurrentChatService(true)
+	chat := domain.NewChat(service, mockRepomix, &MockPersistenceService{})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _ = chat.SendMessage("message-01")
+	}()
+	<-service.started
+
+	// When reading the state meanwhile
+	state := chat.GetState()
+
+	// Then it should already contain the user message
+	require.Len(t, state.Messages, 1)
+	assert.Equal(t, "message-01", state.Messages[0].Content)
+
+	close(service.release)
+	<-done
+	assert.Len(t, chat.GetState().Messages, 2)
+}
+
+func TestChat_QueuedSendWaitsForPreviousOne(t *testing.T) {
+	// Given a send that is waiting on the model
+	service := NewConcurrentChatService(true)
+	chat := domain.NewChat(service, mockRepomix, &MockPersistenceService{})
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		_, _ = chat.SendMessage("message-01")
+	}()
+	<-service.started
+
+	// When a second message is sent
+	go func() {
+		defer wg.Done()
+		_, _ = chat.SendMessage("message-02")
+	}()
+
+	// Then it should be queued rather than reaching the model or the history
+	time.Sleep(20 * time.Millisecond)
+	assert.Len(t, chat.GetState().Messages, 1)
+	assert.Equal(t, int32(1), service.inFlight.Load())
+
+	close(service.release)
+	wg.Wait()
+	assert.Len(t, chat.GetState().Messages, 4)
+}
+
+func TestGetChatState_ReturnsDefensiveCopy(t *testing.T) {
+	// Given a chat with messages
+	chat := domain.NewChat(&MockChatService{response: "AI response"}, mockRepomix, &MockPersistenceService{})
+	_, err := chat.SendMessage("User message")
+	require.NoError(t, err)
+
+	// When a caller modifies the returned state
+	state := chat.GetState()
+	state.Messages[0].Content = "tampered"
+	_ = append(state.Messages[:1], domain.Message{Role: "user", Content: "injected"})
+
+	// Then the chat should be unaffected
+	fresh := chat.GetState()
+	assert.Equal(t, "User message", fresh.Messages[0].Content)
+	assert.Equal(t, "AI response", fresh.Messages[1].Content)
+}
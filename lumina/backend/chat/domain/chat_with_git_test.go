@@ -0,0 +1,98 @@
+package domain_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"lumina/backend/chat/domain"
+)
+
+// Mock GitService for testing
+type MockGitService struct {
+	context         domain.GitContext
+	err             error
+	lastCommitCount int
+}
+
+func (m *MockGitService) CurrentContext(commitCount int) (domain.GitContext, error) {
+	m.lastCommitCount = commitCount
+	return m.context, m.err
+}
+
+var mockGitContext = domain.GitContext{
+	Branch:       "feature/git-context",
+	UnstagedDiff: "--- a/app.go\n+++ b/app.go\n@@ -1 +1 @@\n-old\n+new\n",
+	RecentCommits: []domain.GitCommit{
+		{Hash: "0123456789abcdef", Author: "Ada", Subject: "Add git context"},
+	},
+}
+
+func TestSendChatMessage_WithoutGitContextByDefault(t *testing.T) {
+	// Given a chat with a git service that has not been enabled
+	mockChat := &MockChatService{response: "ok"}
+	mockGit := &MockGitService{context: mockGitContext}
+
+	chat := domain.NewChat(mockChat, mockRepomix, &MockPersistenceService{})
+	chat.SetGitService(mockGit)
+
+	// When sending a message
+	_, err := chat.SendMessage("What am I changing?")
+
+	// Then the git state should not be included
+	assert.NoError(t, err)
+	assert.NotContains(t, mockChat.lastMessage, "feature/git-context")
+}
+
+func TestSendChatMessage_WithGitContext(t *testing.T) {
+	// Given a chat with git context enabled
+	mockChat := &MockChatService{response: "ok"}
+	mockGit := &MockGitService{context: mockGitContext}
+
+	chat := domain.NewChat(mockChat, mockRepomix, &MockPersistenceService{})
+	chat.SetGitService(mockGit)
+	chat.SetGitContextOptions(domain.GitContextOptions{Enabled: true, CommitCount: 3})
+
+	// When sending a message
+	_, err := chat.SendMessage("What am I changing?")
+
+	// Then the branch, diff and commits should be sent alongside the codebase
+	assert.NoError(t, err)
+	assert.Equal(t, 3, mockGit.lastCommitCount)
+	assert.Contains(t, mockChat.lastMessage, "Current branch: feature/git-context")
+	assert.Contains(t, mockChat.lastMessage, "+new")
+	assert.Contains(t, mockChat.lastMessage, "0123456 Add git context (Ada)")
+	assert.Contains(t, mockChat.lastMessage, "Staged changes: none")
+	assert.Contains(t, mockChat.lastMessage, "User question: What am I changing?")
+}
+
+func TestSendChatMessage_GitContextError(t *testing.T) {
+	// Given a git service that fails
+	mockChat := &MockChatService{response: "ok"}
+	mockGit := &MockGitService{err: errors.New("not a git repository")}
+
+	chat := domain.NewChat(mockChat, mockRepomix, &MockPersistenceService{})
+	chat.SetGitService(mockGit)
+	chat.SetGitContextOptions(domain.GitContextOptions{Enabled: true})
+
+	// When sending a message
+	result, err := chat.SendMessage("Hello")
+
+	// Then the message should be sent without git context
+	assert.NoError(t, err)
+	assert.Len(t, result.Messages, 2)
+	assert.NotContains(t, mockChat.lastMessage, "current git state")
+	assert.Contains(t, mockChat.lastMessage, "User question: Hello")
+}
+
+func TestSetGitContextOptions_DefaultsCommitCount(t *testing.T) {
+	// Given a chat
+	chat := domain.NewChat(&MockChatService{}, mockRepomix, &MockPersistenceService{})
+
+	// When enabling git context without a commit count
+	chat.SetGitContextOptions(domain.GitContextOptions{Enabled: true})
+
+	// Then a default number of commits should be used
+	assert.True(t, chat.GitContextOptions().Enabled)
+	assert.Positive(t, chat.GitContextOptions().CommitCount)
+}
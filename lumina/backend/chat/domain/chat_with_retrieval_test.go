@@ -0,0 +1,88 @@
+package domain_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"lumina/backend/chat/domain"
+)
+
+// Mock CodebaseContextProvider for testing
+type MockContextProvider struct {
+	context      domain.CodebaseContext
+	err          error
+	lastQuestion string
+}
+
+func (m *MockContextProvider) ContextFor(question string) (domain.CodebaseContext, error) {
+	m.lastQuestion = question
+	return m.context, m.err
+}
+
+func TestSendChatMessage_WithRetrievedContext(t *testing.T) {
+	// Given a chat using a retrieval-based context provider
+	mockChat := &MockChatService{response: "It is saved with INSERT OR REPLACE"}
+	provider := &MockContextProvider{
+		context: domain.CodebaseContext{
+			Text: "<file path=\"tool.go\">func Save()</file>",
+			Chunks: []domain.ContextChunk{
+				{Path: "tool.go", StartLine: 1, EndLine: 40, Score: 3.2},
+			},
+		},
+	}
+
+	chat := domain.NewChat(mockChat, mockRepomix, &MockPersistenceService{})
+	chat.SetContextProvider(provider)
+
+	// When sending a message
+	result, err := chat.SendMessage("How are tools saved?")
+
+	// Then only the retrieved context should be sent for that question
+	require.NoError(t, err)
+	assert.Equal(t, "How are tools saved?", provider.lastQuestion)
+	assert.Contains(t, mockChat.lastMessage, "func Save()")
+	assert.NotContains(t, mockChat.lastMessage, mockRepomix.output)
+
+	// And the chosen chunks should be visible on the user message
+	require.Len(t, result.Messages, 2)
+	assert.Equal(t, provider.context.Chunks, result.Messages[0].ContextChunks)
+	assert.Empty(t, result.Messages[1].ContextChunks)
+}
+
+func TestGetState_CopiesContextChunks(t *testing.T) {
+	// Given a chat whose first message has retrieved chunks
+	provider := &MockContextProvider{
+		context: domain.CodebaseContext{
+			Text:   "<file path=\"tool.go\">func Save()</file>",
+			Chunks: []domain.ContextChunk{{Path: "tool.go", StartLine: 1, EndLine: 40, Score: 3.2}},
+		},
+	}
+	chat := domain.NewChat(&MockChatService{response: "ok"}, mockRepomix, &MockPersistenceService{})
+	chat.SetContextProvider(provider)
+	_, err := chat.SendMessage("How are tools saved?")
+	require.NoError(t, err)
+
+	// When a caller modifies the chunks of its snapshot
+	state := chat.GetState()
+	state.Messages[0].ContextChunks[0].Path = "changed.go"
+
+	// Then the conversation is unchanged
+	assert.Equal(t, "tool.go", chat.GetState().Messages[0].ContextChunks[0].Path)
+}
+
+func TestSendChatMessage_RetrievalError(t *testing.T) {
+	// Given a context provider that fails
+	provider := &MockContextProvider{err: errors.New("index unavailable")}
+
+	chat := domain.NewChat(&MockChatService{}, mockRepomix, &MockPersistenceService{})
+	chat.SetContextProvider(provider)
+
+	// When sending a message
+	result, err := chat.SendMessage("Hello")
+
+	// Then it should return the error like any codebase context failure
+	assert.EqualError(t, err, "failed to generate codebase context: index unavailable")
+	assert.Empty(t, result.Messages)
+}
@@ -0,0 +1,39 @@
+package domain
+
+// ContextChunk is a piece of the codebase that was sent with a question
+type ContextChunk struct {
+	Path      string  `json:"path"`
+	StartLine int     `json:"startLine"`
+	EndLine   int     `json:"endLine"`
+	Score     float64 `json:"score"`
+}
+
+// CodebaseContext is the codebase representation sent along with a question.
+// Chunks is empty when the whole codebase was sent.
+type CodebaseContext struct {
+	Text   string
+	Chunks []ContextChunk
+}
+
+// CodebaseContextProvider supplies the codebase context for a question
+type CodebaseContextProvider interface {
+	ContextFor(question string) (CodebaseContext, error)
+}
+
+// repomixContextProvider sends the whole codebase regardless of the question
+type repomixContextProvider struct {
+	repomixService RepomixService
+}
+
+// NewRepomixContextProvider adapts a RepomixService to the context port
+func NewRepomixContextProvider(repomixService RepomixService) CodebaseContextProvider {
+	return &repomixContextProvider{repomixService: repomixService}
+}
+
+func (p *repomixContextProvider) ContextFor(question string) (CodebaseContext, error) {
+	output, err := p.repomixService.GenerateOutput()
+	if err != nil {
+		return CodebaseContext{}, err
+	}
+	return CodebaseContext{Text: output}, nil
+}
@@ -0,0 +1,62 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+const defaultGitCommitCount = 5
+
+// GitContextOptions controls whether and how much git state is sent with each message
+type GitContextOptions struct {
+	Enabled     bool `json:"enabled"`
+	CommitCount int  `json:"commitCount"`
+}
+
+type GitCommit struct {
+	Hash    string `json:"hash"`
+	Author  string `json:"author"`
+	Subject string `json:"subject"`
+}
+
+// GitContext is the work in progress of a repository
+type GitContext struct {
+	Branch        string      `json:"branch"`
+	StagedDiff    string      `json:"stagedDiff"`
+	UnstagedDiff  string      `json:"unstagedDiff"`
+	RecentCommits []GitCommit `json:"recentCommits"`
+}
+
+// Format renders the git context as a prompt section
+func (g GitContext) Format() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Current branch: %s\n", g.Branch)
+
+	if len(g.RecentCommits) > 0 {
+		b.WriteString("\nRecent commits:\n")
+		for _, commit := range g.RecentCommits {
+			fmt.Fprintf(&b, "- %s %s (%s)\n", shortHash(commit.Hash), commit.Subject, commit.Author)
+		}
+	}
+
+	writeDiff(&b, "Staged changes", g.StagedDiff)
+	writeDiff(&b, "Unstaged changes", g.UnstagedDiff)
+
+	return b.String()
+}
+
+func writeDiff(b *strings.Builder, title, diff string) {
+	if strings.TrimSpace(diff) == "" {
+		fmt.Fprintf(b, "\n%s: none\n", title)
+		return
+	}
+	fmt.Fprintf(b, "\n%s:\n```diff\n%s\n```\n", title, strings.TrimRight(diff, "\n"))
+}
+
+func shortHash(hash string) string {
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
@@ -0,0 +1,6 @@
+package domain
+
+// GitService reads the state of the git repository the chat is about
+type GitService interface {
+	CurrentContext(commitCount int) (GitContext, error)
+}
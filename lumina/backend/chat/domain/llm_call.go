@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"errors"
+	"net/textproto"
+	"strings"
+	"time"
+)
+
+var ErrLLMCallNotFound = errors.New("llm call not found")
+
+// LLMCall is a recorded request/response exchange with a language model provider
+type LLMCall struct {
+	ID             string            `json:"id"`
+	Provider       string            `json:"provider"`
+	Model          string            `json:"model"`
+	Method         string            `json:"method"`
+	URL            string            `json:"url"`
+	RequestHeaders map[string]string `json:"requestHeaders"` // Secrets are redacted
+	RequestBody    string            `json:"requestBody"`    // The exact outgoing payload
+	StatusCode     int               `json:"statusCode"`     // 0 when no response was received
+	ResponseBody   string            `json:"responseBody"`   // The raw response payload
+	Error          string            `json:"error"`
+	LatencyMs      int64             `json:"latencyMs"`
+	StartedAt      time.Time         `json:"startedAt"`
+}
+
+const redactedHeaderValue = "[REDACTED]"
+
+// sensitiveHeaders lists the (canonicalized) headers that carry credentials
+var sensitiveHeaders = map[string]bool{
+	"Authorization":       true,
+	"Proxy-Authorization": true,
+	"Api-Key":             true,
+	"X-Api-Key":           true,
+	"Cookie":              true,
+}
+
+// RedactHeaders flattens request headers and masks the ones carrying secrets
+func RedactHeaders(headers map[string][]string) map[string]string {
+	redacted := make(map[string]string, len(headers))
+	for name, values := range headers {
+		if sensitiveHeaders[textproto.CanonicalMIMEHeaderKey(name)] {
+			redacted[name] = redactedHeaderValue
+			continue
+		}
+		redacted[name] = strings.Join(values, ", ")
+	}
+	return redacted
+}
@@ -0,0 +1,9 @@
+package domain
+
+// LLMCallRepository stores every call made to a language model provider
+type LLMCallRepository interface {
+	Save(call LLMCall) error
+	GetByID(id string) (LLMCall, error)
+	// List returns the most recent calls first, at most limit of them (all when limit <= 0)
+	List(limit int) ([]LLMCall, error)
+}
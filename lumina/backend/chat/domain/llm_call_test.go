@@ -0,0 +1,41 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"lumina/backend/chat/domain"
+)
+
+func TestRedactHeaders_MasksCredentials(t *testing.T) {
+	// Given request headers carrying an API key
+	headers := map[string][]string{
+		"Authorization": {"Bearer sk-secret"},
+		"x-api-key":     {"another-secret"},
+		"Content-Type":  {"application/json"},
+	}
+
+	// When redacting them
+	redacted := domain.RedactHeaders(headers)
+
+	// Then secrets should be masked and other headers kept
+	assert.Equal(t, "[REDACTED]", redacted["Authorization"])
+	assert.Equal(t, "[REDACTED]", redacted["x-api-key"])
+	assert.Equal(t, "application/json", redacted["Content-Type"])
+	for _, value := range redacted {
+		assert.NotContains(t, value, "secret")
+	}
+}
+
+func TestRedactHeaders_JoinsMultipleValues(t *testing.T) {
+	// Given a header with several values
+	headers := map[string][]string{
+		"Accept": {"application/json", "text/plain"},
+	}
+
+	// When redacting them
+	redacted := domain.RedactHeaders(headers)
+
+	// Then the values should be flattened into one string
+	assert.Equal(t, "application/json, text/plain", redacted["Accept"])
+}
@@ -1,8 +1,9 @@
 package domain
 
 type Message struct {
-	Role    string `json:"role"`    // "user" or "assistant"
-	Content string `json:"content"` // The message content
+	Role          string         `json:"role"`                    // "user" or "assistant"
+	Content       string         `json:"content"`                 // The message content
+	ContextChunks []ContextChunk `json:"contextChunks,omitempty"` // Codebase chunks retrieved for a user message
 }
 
 type ChatState struct {
@@ -0,0 +1,111 @@
+package infrastructure
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"lumina/backend/chat/domain"
+)
+
+const (
+	gitFieldSeparator  = "\x1f"
+	gitRecordSeparator = "\x1e"
+)
+
+// GitService implements the GitService port using the local git CLI
+type GitService struct {
+	workingDir string
+}
+
+// NewGitService creates a git service for the repository containing workingDir
+func NewGitService(workingDir string) *GitService {
+	return &GitService{workingDir: workingDir}
+}
+
+// CurrentContext returns the branch, staged and unstaged diffs and the last commits
+func (g *GitService) CurrentContext(commitCount int) (domain.GitContext, error) {
+	branch, err := g.currentBranch()
+	if err != nil {
+		return domain.GitContext{}, err
+	}
+
+	stagedDiff, err := g.run("diff", "--cached")
+	if err != nil {
+		return domain.GitContext{}, err
+	}
+
+	unstagedDiff, err := g.run("diff")
+	if err != nil {
+		return domain.GitContext{}, err
+	}
+
+	commits, err := g.recentCommits(commitCount)
+	if err != nil {
+		return domain.GitContext{}, err
+	}
+
+	return domain.GitContext{
+		Branch:        strings.TrimSpace(branch),
+		StagedDiff:    stagedDiff,
+		UnstagedDiff:  unstagedDiff,
+		RecentCommits: commits,
+	}, nil
+}
+
+// currentBranch also names the branch of a repository without commits,
+// where HEAD does not resolve yet; a detached HEAD is reported as "HEAD"
+func (g *GitService) currentBranch() (string, error) {
+	if branch, err := g.run("symbolic-ref", "--short", "-q", "HEAD"); err == nil {
+		return branch, nil
+	}
+	return g.run("rev-parse", "--abbrev-ref", "HEAD")
+}
+
+func (g *GitService) recentCommits(count int) ([]domain.GitCommit, error) {
+	if count <= 0 {
+		return nil, nil
+	}
+
+	format := "--format=%H" + gitFieldSeparator + "%an" + gitFieldSeparator + "%s" + gitRecordSeparator
+	output, err := g.run("log", "-n", strconv.Itoa(count), format)
+	if err != nil {
+		// A repository without commits has no log yet
+		if strings.Contains(err.Error(), "does not have any commits") {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var commits []domain.GitCommit
+	for _, record := range strings.Split(output, gitRecordSeparator) {
+		fields := strings.Split(strings.TrimSpace(record), gitFieldSeparator)
+		if len(fields) != 3 {
+			continue
+		}
+		commits = append(commits, domain.GitCommit{
+			Hash:    fields[0],
+			Author:  fields[1],
+			Subject: fields[2],
+		})
+	}
+
+	return commits, nil
+}
+
+func (g *GitService) run(args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = g.workingDir
+
+	// Keep stderr apart so warnings never end up inside a diff
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to run git %s: %w (output: %s)", strings.Join(args, " "), err, stderr.String())
+	}
+
+	return string(output), nil
+}
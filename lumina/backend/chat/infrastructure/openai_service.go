@@ -6,12 +6,23 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"log"
+	"lumina/backend/chat/domain"
 	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	openAIModel    = "gpt-4.1"
+	openAIEndpoint = "https://api.openai.com/v1/chat/completions"
 )
 
 type OpenAIService struct {
-	apiKey     string
-	httpClient *http.Client
+	apiKey         string
+	httpClient     *http.Client
+	callRepository domain.LLMCallRepository
 }
 
 type openAIRequest struct {
@@ -34,10 +45,13 @@ type openAIResponse struct {
 	} `json:"error"`
 }
 
-func NewOpenAIService(apiKey string) *OpenAIService {
+// NewOpenAIService creates an OpenAI-backed chat service. Every request is
+// recorded in callRepository when it is not nil.
+func NewOpenAIService(apiKey string, callRepository domain.LLMCallRepository) *OpenAIService {
 	return &OpenAIService{
-		apiKey:     apiKey,
-		httpClient: &http.Client{},
+		apiKey:         apiKey,
+		httpClient:     &http.Client{},
+		callRepository: callRepository,
 	}
 }
 
@@ -47,7 +61,7 @@ func (s *OpenAIService) SendMessage(message string) (string, error) {
 	}
 
 	reqBody := openAIRequest{
-		Model: "gpt-4.1",
+		Model: openAIModel,
 		Messages: []openAIMessage{
 			{
 				Role:    "user",
@@ -61,7 +75,7 @@ func (s *OpenAIService) SendMessage(message string) (string, error) {
 		return "", fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	req, err := http.NewRequest("POST", "https://api.openai.com/v1/chat/completions", bytes.NewBuffer(jsonData))
+	req, err := http.NewRequest("POST", openAIEndpoint, bytes.NewBuffer(jsonData))
 	if err != nil {
 		return "", fmt.Errorf("failed to create request: %w", err)
 	}
@@ -69,17 +83,45 @@ func (s *OpenAIService) SendMessage(message string) (string, error) {
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Authorization", "Bearer "+s.apiKey)
 
+	call := domain.LLMCall{
+		ID:             uuid.New().String(),
+		Provider:       "openai",
+		Model:          openAIModel,
+		Method:         req.Method,
+		URL:            req.URL.String(),
+		RequestHeaders: domain.RedactHeaders(req.Header),
+		RequestBody:    string(jsonData),
+		StartedAt:      time.Now(),
+	}
+
+	content, err := s.send(req, &call)
+
+	call.LatencyMs = time.Since(call.StartedAt).Milliseconds()
+	if err != nil {
+		call.Error = err.Error()
+	}
+	s.record(call)
+
+	return content, err
+}
+
+// send performs the request and fills in the response side of call
+func (s *OpenAIService) send(req *http.Request, call *domain.LLMCall) (string, error) {
 	resp, err := s.httpClient.Do(req)
 	if err != nil {
 		return "", fmt.Errorf("failed to send request: %w", err)
 	}
 	defer resp.Body.Close()
 
+	call.StatusCode = resp.StatusCode
+
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return "", fmt.Errorf("failed to read response: %w", err)
 	}
 
+	call.ResponseBody = string(body)
+
 	var openAIResp openAIResponse
 	if err := json.Unmarshal(body, &openAIResp); err != nil {
 		return "", fmt.Errorf("failed to unmarshal response: %w", err)
@@ -94,4 +136,15 @@ func (s *OpenAIService) SendMessage(message string) (string, error) {
 	}
 
 	return openAIResp.Choices[0].Message.Content, nil
-}
\ No newline at end of file
+}
+
+// record stores the call; a failure to record never fails the chat
+func (s *OpenAIService) record(call domain.LLMCall) {
+	if s.callRepository == nil {
+		return
+	}
+
+	if err := s.callRepository.Save(call); err != nil {
+		log.Printf("Warning: Failed to record LLM call: %v", err)
+	}
+}
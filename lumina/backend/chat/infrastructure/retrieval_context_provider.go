@@ -0,0 +1,60 @@
+package infrastructure
+
+import (
+	"fmt"
+	"strings"
+
+	"lumina/backend/chat/domain"
+	retrievaldomain "lumina/backend/retrieval/domain"
+)
+
+// DefaultRetrievedChunks is how many chunks are sent with each question
+const DefaultRetrievedChunks = 8
+
+// ChunkSearcher ranks codebase chunks against a question
+type ChunkSearcher interface {
+	Search(query string, limit int) ([]retrievaldomain.ScoredChunk, error)
+}
+
+// RetrievalContextProvider implements the CodebaseContextProvider port by
+// sending only the chunks most relevant to the question
+type RetrievalContextProvider struct {
+	searcher ChunkSearcher
+	limit    int
+}
+
+// NewRetrievalContextProvider creates a provider sending at most limit chunks
+func NewRetrievalContextProvider(searcher ChunkSearcher, limit int) *RetrievalContextProvider {
+	if limit <= 0 {
+		limit = DefaultRetrievedChunks
+	}
+	return &RetrievalContextProvider{searcher: searcher, limit: limit}
+}
+
+// ContextFor returns the best matching chunks formatted for the prompt
+func (p *RetrievalContextProvider) ContextFor(question string) (domain.CodebaseContext, error) {
+	results, err := p.searcher.Search(question, p.limit)
+	if err != nil {
+		return domain.CodebaseContext{}, fmt.Errorf("failed to retrieve relevant code: %w", err)
+	}
+
+	if len(results) == 0 {
+		return domain.CodebaseContext{Text: "(no code matched the question)"}, nil
+	}
+
+	var b strings.Builder
+	chunks := make([]domain.ContextChunk, 0, len(results))
+	for _, result := range results {
+		fmt.Fprintf(&b, "<file path=%q lines=\"%d-%d\">\n%s\n</file>\n",
+			result.Path, result.StartLine, result.EndLine, result.Content)
+
+		chunks = append(chunks, domain.ContextChunk{
+			Path:      result.Path,
+			StartLine: result.StartLine,
+			EndLine:   result.EndLine,
+			Score:     result.Score,
+		})
+	}
+
+	return domain.CodebaseContext{Text: b.String(), Chunks: chunks}, nil
+}
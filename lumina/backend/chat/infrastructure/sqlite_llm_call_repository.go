@@ -0,0 +1,174 @@
+package infrastructure
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"lumina/backend/chat/domain"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+type SQLiteLLMCallRepository struct {
+	db *sql.DB
+}
+
+func NewSQLiteLLMCallRepository(dbPath string) (*SQLiteLLMCallRepository, error) {
+	db, err := sql.Open("sqlite3", dbPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	// Create llm_calls table if it doesn't exist
+	createTableSQL := `
+	CREATE TABLE IF NOT EXISTS llm_calls (
+		id TEXT PRIMARY KEY,
+		provider TEXT NOT NULL,
+		model TEXT NOT NULL,
+		method TEXT NOT NULL,
+		url TEXT NOT NULL,
+		request_headers TEXT NOT NULL,
+		request_body TEXT NOT NULL,
+		status_code INTEGER NOT NULL,
+		response_body TEXT NOT NULL,
+		error TEXT NOT NULL,
+		latency_ms INTEGER NOT NULL,
+		started_at DATETIME NOT NULL
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_llm_calls_started_at ON llm_calls(started_at);
+	`
+
+	if _, err := db.Exec(createTableSQL); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create llm_calls table: %w", err)
+	}
+
+	return &SQLiteLLMCallRepository{db: db}, nil
+}
+
+func (r *SQLiteLLMCallRepository) Save(call domain.LLMCall) error {
+	headers, err := json.Marshal(call.RequestHeaders)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request headers: %w", err)
+	}
+
+	insertSQL := `
+	INSERT INTO llm_calls (id, provider, model, method, url, request_headers, request_body,
+		status_code, response_body, error, latency_ms, started_at)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	_, err = r.db.Exec(insertSQL,
+		call.ID,
+		call.Provider,
+		call.Model,
+		call.Method,
+		call.URL,
+		string(headers),
+		call.RequestBody,
+		call.StatusCode,
+		call.ResponseBody,
+		call.Error,
+		call.LatencyMs,
+		call.StartedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save llm call: %w", err)
+	}
+
+	return nil
+}
+
+func (r *SQLiteLLMCallRepository) GetByID(id string) (domain.LLMCall, error) {
+	query := `
+	SELECT id, provider, model, method, url, request_headers, request_body,
+		status_code, response_body, error, latency_ms, started_at
+	FROM llm_calls
+	WHERE id = ?
+	`
+
+	call, err := scanLLMCall(r.db.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.LLMCall{}, domain.ErrLLMCallNotFound
+		}
+		return domain.LLMCall{}, fmt.Errorf("failed to get llm call by ID: %w", err)
+	}
+
+	return call, nil
+}
+
+func (r *SQLiteLLMCallRepository) List(limit int) ([]domain.LLMCall, error) {
+	query := `
+	SELECT id, provider, model, method, url, request_headers, request_body,
+		status_code, response_body, error, latency_ms, started_at
+	FROM llm_calls
+	ORDER BY started_at DESC
+	LIMIT ?
+	`
+
+	// SQLite treats a negative LIMIT as "no limit"
+	if limit <= 0 {
+		limit = -1
+	}
+
+	rows, err := r.db.Query(query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list llm calls: %w", err)
+	}
+	defer rows.Close()
+
+	var calls []domain.LLMCall
+	for rows.Next() {
+		call, err := scanLLMCall(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan llm call: %w", err)
+		}
+		calls = append(calls, call)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating llm calls: %w", err)
+	}
+
+	return calls, nil
+}
+
+func (r *SQLiteLLMCallRepository) Close() error {
+	return r.db.Close()
+}
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanLLMCall(row rowScanner) (domain.LLMCall, error) {
+	var call domain.LLMCall
+	var headers string
+
+	err := row.Scan(
+		&call.ID,
+		&call.Provider,
+		&call.Model,
+		&call.Method,
+		&call.URL,
+		&headers,
+		&call.RequestBody,
+		&call.StatusCode,
+		&call.ResponseBody,
+		&call.Error,
+		&call.LatencyMs,
+		&call.StartedAt,
+	)
+	if err != nil {
+		return domain.LLMCall{}, err
+	}
+
+	if err := json.Unmarshal([]byte(headers), &call.RequestHeaders); err != nil {
+		return domain.LLMCall{}, fmt.Errorf("failed to unmarshal request headers: %w", err)
+	}
+
+	return call, nil
+}
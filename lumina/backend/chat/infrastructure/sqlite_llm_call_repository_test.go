@@ -0,0 +1,102 @@
+package infrastructure_test
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"lumina/backend/chat/domain"
+	"lumina/backend/chat/infrastructure"
+)
+
+func newLLMCall(id string, startedAt time.Time) domain.LLMCall {
+	return domain.LLMCall{
+		ID:             id,
+		Provider:       "openai",
+		Model:          "gpt-4.1",
+		Method:         "POST",
+		URL:            "https://api.openai.com/v1/chat/completions",
+		RequestHeaders: map[string]string{"Authorization": "[REDACTED]"},
+		RequestBody:    `{"messages":[{"role":"user","content":"User question: hi"}]}`,
+		StatusCode:     200,
+		ResponseBody:   `{"choices":[]}`,
+		LatencyMs:      42,
+		StartedAt:      startedAt,
+	}
+}
+
+func TestSQLiteLLMCallRepository_SaveAndRetrieve(t *testing.T) {
+	// Given a temporary database
+	dbFile := "test_llm_calls.db"
+	defer cleanupDatabase(dbFile)
+
+	repo, err := infrastructure.NewSQLiteLLMCallRepository(dbFile)
+	require.NoError(t, err)
+	defer repo.Close()
+
+	// When saving a call
+	call := newLLMCall("call-1", time.Now())
+	err = repo.Save(call)
+	require.NoError(t, err)
+
+	// Then it should be retrievable with the full payload
+	retrieved, err := repo.GetByID("call-1")
+	require.NoError(t, err)
+	assert.Equal(t, call.RequestBody, retrieved.RequestBody)
+	assert.Equal(t, call.ResponseBody, retrieved.ResponseBody)
+	assert.Equal(t, call.RequestHeaders, retrieved.RequestHeaders)
+	assert.Equal(t, 200, retrieved.StatusCode)
+	assert.Equal(t, int64(42), retrieved.LatencyMs)
+	assert.False(t, retrieved.StartedAt.IsZero())
+}
+
+func TestSQLiteLLMCallRepository_GetByID_NotFound(t *testing.T) {
+	// Given a temporary database
+	dbFile := "test_llm_calls_notfound.db"
+	defer cleanupDatabase(dbFile)
+
+	repo, err := infrastructure.NewSQLiteLLMCallRepository(dbFile)
+	require.NoError(t, err)
+	defer repo.Close()
+
+	// When getting a non-existent call
+	_, err = repo.GetByID("missing")
+
+	// Then it should return not found error
+	assert.Equal(t, domain.ErrLLMCallNotFound, err)
+}
+
+func TestSQLiteLLMCallRepository_ListNewestFirstWithLimit(t *testing.T) {
+	// Given a temporary database with three calls
+	dbFile := "test_llm_calls_list.db"
+	defer cleanupDatabase(dbFile)
+
+	repo, err := infrastructure.NewSQLiteLLMCallRepository(dbFile)
+	require.NoError(t, err)
+	defer repo.Close()
+
+	now := time.Now()
+	require.NoError(t, repo.Save(newLLMCall("oldest", now.Add(-2*time.Minute))))
+	require.NoError(t, repo.Save(newLLMCall("newest", now)))
+	require.NoError(t, repo.Save(newLLMCall("middle", now.Add(-time.Minute))))
+
+	// When listing with a limit
+	calls, err := repo.List(2)
+
+	// Then the most recent calls should come first
+	require.NoError(t, err)
+	require.Len(t, calls, 2)
+	assert.Equal(t, "newest", calls[0].ID)
+	assert.Equal(t, "middle", calls[1].ID)
+
+	// And no limit should return everything
+	all, err := repo.List(0)
+	require.NoError(t, err)
+	assert.Len(t, all, 3)
+}
+
+func cleanupDatabase(dbFile string) {
+	os.Remove(dbFile)
+}
@@ -2,8 +2,10 @@ package infrastructure
 
 import (
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"lumina/backend/chat/domain"
+	"strings"
 
 	_ "github.com/mattn/go-sqlite3"
 )
@@ -33,11 +35,18 @@ func NewSQLitePersistence(dbPath string) (*SQLitePersistence, error) {
 		return nil, fmt.Errorf("failed to create table: %w", err)
 	}
 
+	// Databases created before retrieval lack the context_chunks column
+	if _, err := db.Exec("ALTER TABLE messages ADD COLUMN context_chunks TEXT"); err != nil &&
+		!strings.Contains(err.Error(), "duplicate column name") {
+		db.Close()
+		return nil, fmt.Errorf("failed to migrate messages table: %w", err)
+	}
+
 	return &SQLitePersistence{db: db}, nil
 }
 
 func (s *SQLitePersistence) Load() ([]domain.Message, error) {
-	rows, err := s.db.Query("SELECT role, content FROM messages ORDER BY id ASC")
+	rows, err := s.db.Query("SELECT role, content, context_chunks FROM messages ORDER BY id ASC")
 	if err != nil {
 		return nil, fmt.Errorf("failed to query messages: %w", err)
 	}
@@ -46,9 +55,15 @@ func (s *SQLitePersistence) Load() ([]domain.Message, error) {
 	var messages []domain.Message
 	for rows.Next() {
 		var msg domain.Message
-		if err := rows.Scan(&msg.Role, &msg.Content); err != nil {
+		var contextChunks sql.NullString
+		if err := rows.Scan(&msg.Role, &msg.Content, &contextChunks); err != nil {
 			return nil, fmt.Errorf("failed to scan message: %w", err)
 		}
+		if contextChunks.Valid && contextChunks.String != "" {
+			if err := json.Unmarshal([]byte(contextChunks.String), &msg.ContextChunks); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal context chunks: %w", err)
+			}
+		}
 		messages = append(messages, msg)
 	}
 
@@ -73,14 +88,23 @@ func (s *SQLitePersistence) Save(messages []domain.Message) error {
 	}
 
 	// Insert all messages
-	stmt, err := tx.Prepare("INSERT INTO messages (role, content) VALUES (?, ?)")
+	stmt, err := tx.Prepare("INSERT INTO messages (role, content, context_chunks) VALUES (?, ?, ?)")
 	if err != nil {
 		return fmt.Errorf("failed to prepare statement: %w", err)
 	}
 	defer stmt.Close()
 
 	for _, msg := range messages {
-		if _, err := stmt.Exec(msg.Role, msg.Content); err != nil {
+		var contextChunks any
+		if len(msg.ContextChunks) > 0 {
+			data, err := json.Marshal(msg.ContextChunks)
+			if err != nil {
+				return fmt.Errorf("failed to marshal context chunks: %w", err)
+			}
+			contextChunks = string(data)
+		}
+
+		if _, err := stmt.Exec(msg.Role, msg.Content, contextChunks); err != nil {
 			return fmt.Errorf("failed to insert message: %w", err)
 		}
 	}
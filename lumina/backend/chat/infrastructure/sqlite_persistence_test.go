@@ -0,0 +1,71 @@
+package infrastructure_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"lumina/backend/chat/domain"
+	"lumina/backend/chat/infrastructure"
+)
+
+func TestSQLitePersistence_SaveAndLoadWithContextChunks(t *testing.T) {
+	// Given a temporary database
+	dbFile := "test_messages.db"
+	defer cleanupDatabase(dbFile)
+
+	persistence, err := infrastructure.NewSQLitePersistence(dbFile)
+	require.NoError(t, err)
+	defer persistence.Close()
+
+	// When saving messages with retrieved chunks
+	messages := []domain.Message{
+		{
+			Role:    "user",
+			Content: "How are tools saved?",
+			ContextChunks: []domain.ContextChunk{
+				{Path: "tool.go", StartLine: 1, EndLine: 40, Score: 2.5},
+			},
+		},
+		{Role: "assistant", Content: "With INSERT OR REPLACE"},
+	}
+	require.NoError(t, persistence.Save(messages))
+
+	// Then they should load back unchanged
+	loaded, err := persistence.Load()
+	require.NoError(t, err)
+	assert.Equal(t, messages, loaded)
+}
+
+func TestSQLitePersistence_MigratesExistingDatabase(t *testing.T) {
+	// Given a database created before context chunks existed
+	dbFile := "test_messages_migration.db"
+	defer cleanupDatabase(dbFile)
+
+	db, err := sql.Open("sqlite3", dbFile)
+	require.NoError(t, err)
+	_, err = db.Exec(`
+	CREATE TABLE messages (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		role TEXT NOT NULL,
+		content TEXT NOT NULL,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+	INSERT INTO messages (role, content) VALUES ('user', 'old question');
+	`)
+	require.NoError(t, err)
+	db.Close()
+
+	// When opening it
+	persistence, err := infrastructure.NewSQLitePersistence(dbFile)
+	require.NoError(t, err)
+	defer persistence.Close()
+
+	// Then old messages should still load
+	loaded, err := persistence.Load()
+	require.NoError(t, err)
+	require.Len(t, loaded, 1)
+	assert.Equal(t, "old question", loaded[0].Content)
+	assert.Empty(t, loaded[0].ContextChunks)
+}
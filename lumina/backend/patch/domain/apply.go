@@ -0,0 +1,170 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// oldSide returns the lines a hunk expects to find in the file
+func (h Hunk) oldSide() []string {
+	var lines []string
+	for _, line := range h.Lines {
+		if line.Kind != DiffLineAdd {
+			lines = append(lines, line.Text)
+		}
+	}
+	return lines
+}
+
+// newSide returns the lines a hunk leaves in the file
+func (h Hunk) newSide() []string {
+	var lines []string
+	for _, line := range h.Lines {
+		if line.Kind != DiffLineRemove {
+			lines = append(lines, line.Text)
+		}
+	}
+	return lines
+}
+
+// applyResult is the outcome of applying hunks to one file
+type applyResult struct {
+	content string
+	exists  bool
+}
+
+// apply computes the new content of the file for the selected hunks. It
+// never touches the workspace; conflicts are reported as *ConflictError.
+func (f FileChange) apply(current string, exists bool, selected []int) (applyResult, error) {
+	switch f.Kind {
+	case FileChangeReplace:
+		if hashContent(current, exists) != f.BaseHash {
+			return applyResult{}, &ConflictError{Path: f.Path, Hunk: -1, Reason: "file changed since the replacement was proposed"}
+		}
+		return applyResult{content: f.NewContent, exists: true}, nil
+
+	case FileChangeCreate:
+		if exists {
+			return applyResult{}, &ConflictError{Path: f.Path, Hunk: -1, Reason: "file already exists"}
+		}
+		return applyResult{content: joinLines(f.Hunks[0].newSide(), true), exists: true}, nil
+
+	case FileChangeDelete:
+		if !exists {
+			return applyResult{}, &ConflictError{Path: f.Path, Hunk: -1, Reason: "file does not exist"}
+		}
+		lines, _ := splitLines(current)
+		if !equalLines(lines, f.Hunks[0].oldSide()) {
+			return applyResult{}, &ConflictError{Path: f.Path, Hunk: -1, Reason: "file content differs from the deleted content"}
+		}
+		return applyResult{exists: false}, nil
+	}
+
+	if !exists {
+		return applyResult{}, &ConflictError{Path: f.Path, Hunk: -1, Reason: "file does not exist"}
+	}
+
+	lines, trailingNewline := splitLines(current)
+
+	// Apply bottom-up so earlier hunks keep their line numbers, then re-locate
+	// each hunk by content to tolerate drift from unselected hunks or edits.
+	for n := len(selected) - 1; n >= 0; n-- {
+		index := selected[n]
+		hunk := f.Hunks[index]
+		oldLines := hunk.oldSide()
+
+		position, ok := locate(lines, oldLines, hunk.OldStart)
+		if !ok {
+			return applyResult{}, &ConflictError{Path: f.Path, Hunk: index, Reason: "expected lines not found"}
+		}
+
+		updated := make([]string, 0, len(lines)-len(oldLines)+len(hunk.newSide()))
+		updated = append(updated, lines[:position]...)
+		updated = append(updated, hunk.newSide()...)
+		updated = append(updated, lines[position+len(oldLines):]...)
+		lines = updated
+	}
+
+	return applyResult{content: joinLines(lines, trailingNewline), exists: true}, nil
+}
+
+// locate finds the position of want in lines, preferring the match closest
+// to the 1-based hint from the hunk header.
+func locate(lines, want []string, hint int) (int, bool) {
+	expected := hint - 1
+	if len(want) == 0 {
+		// Pure insertion: "-l,0" means after line l
+		expected = hint
+		if expected < 0 {
+			expected = 0
+		}
+		if expected > len(lines) {
+			expected = len(lines)
+		}
+		return expected, true
+	}
+	if expected < 0 {
+		expected = 0
+	}
+
+	best := -1
+	for start := 0; start+len(want) <= len(lines); start++ {
+		if !equalLines(lines[start:start+len(want)], want) {
+			continue
+		}
+		if best < 0 || distance(start, expected) < distance(best, expected) {
+			best = start
+		}
+	}
+
+	return best, best >= 0
+}
+
+func distance(a, b int) int {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
+
+func equalLines(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if strings.TrimRight(a[i], " \t\r") != strings.TrimRight(b[i], " \t\r") {
+			return false
+		}
+	}
+	return true
+}
+
+// splitLines splits content into lines and reports whether it ended with a newline
+func splitLines(content string) ([]string, bool) {
+	if content == "" {
+		return nil, false
+	}
+	trailingNewline := strings.HasSuffix(content, "\n")
+	return strings.Split(strings.TrimSuffix(content, "\n"), "\n"), trailingNewline
+}
+
+func joinLines(lines []string, trailingNewline bool) string {
+	if len(lines) == 0 {
+		return ""
+	}
+	content := strings.Join(lines, "\n")
+	if trailingNewline {
+		content += "\n"
+	}
+	return content
+}
+
+// hashContent fingerprints a file; a missing file hashes to ""
+func hashContent(content string, exists bool) string {
+	if !exists {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
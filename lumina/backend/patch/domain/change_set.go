@@ -0,0 +1,134 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	ErrNoChanges           = errors.New("no code changes found")
+	ErrChangeSetNotFound   = errors.New("change set not found")
+	ErrFileChangeNotFound  = errors.New("file change not found in change set")
+	ErrUndoRecordNotFound  = errors.New("undo record not found")
+	ErrInvalidPath         = errors.New("path must be relative to the working directory")
+	ErrHunkOutOfRange      = errors.New("hunk index out of range")
+	ErrHunkNotPending      = errors.New("hunk has already been applied or rejected")
+	ErrUndoAlreadyReverted = errors.New("changes have already been undone")
+)
+
+// FileChangeKind describes what a file change does to its file
+type FileChangeKind string
+
+const (
+	FileChangeModify  FileChangeKind = "modify"  // Unified diff against an existing file
+	FileChangeCreate  FileChangeKind = "create"  // Unified diff from /dev/null
+	FileChangeDelete  FileChangeKind = "delete"  // Unified diff to /dev/null
+	FileChangeReplace FileChangeKind = "replace" // Whole-file replacement block
+)
+
+// HunkStatus tracks the review decision for a single hunk
+type HunkStatus string
+
+const (
+	HunkPending  HunkStatus = "pending"
+	HunkApplied  HunkStatus = "applied"
+	HunkRejected HunkStatus = "rejected"
+)
+
+// DiffLineKind is the role of a line inside a hunk
+type DiffLineKind string
+
+const (
+	DiffLineContext DiffLineKind = "context"
+	DiffLineAdd     DiffLineKind = "add"
+	DiffLineRemove  DiffLineKind = "remove"
+)
+
+type DiffLine struct {
+	Kind DiffLineKind `json:"kind"`
+	Text string       `json:"text"`
+}
+
+// Hunk is one independently reviewable region of a file change
+type Hunk struct {
+	OldStart int        `json:"oldStart"` // 1-based; 0 when the diff did not say where
+	OldLines int        `json:"oldLines"`
+	NewStart int        `json:"newStart"`
+	NewLines int        `json:"newLines"`
+	Lines    []DiffLine `json:"lines"`
+	Status   HunkStatus `json:"status"`
+}
+
+// FileChange groups the hunks proposed for a single file
+type FileChange struct {
+	Path  string         `json:"path"`
+	Kind  FileChangeKind `json:"kind"`
+	Hunks []Hunk         `json:"hunks"`
+	// NewContent and BaseHash are only set for replacements. BaseHash is the
+	// hash of the file when the change was proposed ("" when it did not exist).
+	NewContent string `json:"newContent,omitempty"`
+	BaseHash   string `json:"baseHash,omitempty"`
+}
+
+// ChangeSet is the set of file changes proposed by one assistant reply
+type ChangeSet struct {
+	ID           string       `json:"id"`
+	MessageIndex int          `json:"messageIndex"` // Index of the assistant message in the chat
+	Files        []FileChange `json:"files"`
+	CreatedAt    time.Time    `json:"createdAt"`
+}
+
+// HunkSelection picks hunks of one file; no indexes means every pending hunk
+type HunkSelection struct {
+	Path  string `json:"path"`
+	Hunks []int  `json:"hunks"`
+}
+
+type HunkRef struct {
+	Path  string `json:"path"`
+	Index int    `json:"index"`
+}
+
+// FileSnapshot captures a file around an apply so it can be undone safely
+type FileSnapshot struct {
+	Path          string `json:"path"`
+	ExistedBefore bool   `json:"existedBefore"`
+	Before        string `json:"before"`
+	ExistsAfter   bool   `json:"existsAfter"`
+	After         string `json:"after"`
+}
+
+// UndoRecord is written every time hunks are applied to the working directory
+type UndoRecord struct {
+	ID          string         `json:"id"`
+	ChangeSetID string         `json:"changeSetId"`
+	Hunks       []HunkRef      `json:"hunks"`
+	Files       []FileSnapshot `json:"files"`
+	AppliedAt   time.Time      `json:"appliedAt"`
+	UndoneAt    *time.Time     `json:"undoneAt"`
+}
+
+// ConflictError reports that a file no longer matches what a change expects
+type ConflictError struct {
+	Path   string
+	Hunk   int // -1 when the conflict concerns the whole file
+	Reason string
+}
+
+func (e *ConflictError) Error() string {
+	if e.Hunk < 0 {
+		return fmt.Sprintf("conflict in %s: %s", e.Path, e.Reason)
+	}
+	return fmt.Sprintf("conflict in %s hunk %d: %s", e.Path, e.Hunk, e.Reason)
+}
+
+// File returns the change for path
+func (c *ChangeSet) File(path string) (*FileChange, error) {
+	for i := range c.Files {
+		if c.Files[i].Path == path {
+			return &c.Files[i], nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrFileChangeNotFound, path)
+}
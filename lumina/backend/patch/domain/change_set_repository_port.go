@@ -0,0 +1,12 @@
+package domain
+
+// ChangeSetRepository persists proposed change sets and their undo records
+type ChangeSetRepository interface {
+	Save(changeSet ChangeSet) error
+	GetByID(id string) (ChangeSet, error)
+	List() ([]ChangeSet, error)
+	SaveUndoRecord(record UndoRecord) error
+	GetUndoRecord(id string) (UndoRecord, error)
+	ListUndoRecords(changeSetID string) ([]UndoRecord, error)
+	Close() error
+}
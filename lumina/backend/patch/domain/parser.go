@@ -0,0 +1,284 @@
+package domain
+
+import (
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var hunkHeaderPattern = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+(\d+)(?:,(\d+))? @@`)
+
+// fencedBlock is a ``` delimited block from a markdown reply
+type fencedBlock struct {
+	info string
+	body []string
+}
+
+// ParseFileChanges extracts the file changes proposed in an assistant reply.
+// Two forms are recognised inside fenced code blocks:
+//   - unified diffs (```diff, ```patch or any block starting with "--- "/"diff --git")
+//   - whole-file replacements, marked with a file= or path= attribute on the
+//     fence, e.g. ```go file=backend/app.go
+//
+// Replacements come back with only Path, Kind and NewContent set; the caller
+// completes them against the current file.
+func ParseFileChanges(reply string) ([]FileChange, error) {
+	var changes []FileChange
+
+	for _, block := range extractFencedBlocks(reply) {
+		if target, ok := replacementTarget(block.info); ok {
+			cleaned, err := cleanPath(target)
+			if err != nil {
+				return nil, err
+			}
+			changes = append(changes, FileChange{
+				Path:       cleaned,
+				Kind:       FileChangeReplace,
+				NewContent: strings.Join(block.body, "\n") + "\n",
+			})
+			continue
+		}
+
+		if !isDiffBlock(block) {
+			continue
+		}
+
+		fileChanges, err := parseUnifiedDiff(block.body)
+		if err != nil {
+			return nil, err
+		}
+		changes = mergeFileChanges(changes, fileChanges)
+	}
+
+	if len(changes) == 0 {
+		return nil, ErrNoChanges
+	}
+
+	return changes, nil
+}
+
+// mergeFileChanges appends added to changes, folding further diffs of an
+// already modified file into its hunk list
+func mergeFileChanges(changes, added []FileChange) []FileChange {
+	for _, change := range added {
+		merged := false
+		for i := range changes {
+			if changes[i].Path == change.Path && changes[i].Kind == FileChangeModify && change.Kind == FileChangeModify {
+				changes[i].Hunks = append(changes[i].Hunks, change.Hunks...)
+				merged = true
+				break
+			}
+		}
+		if !merged {
+			changes = append(changes, change)
+		}
+	}
+	return changes
+}
+
+func extractFencedBlocks(text string) []fencedBlock {
+	var blocks []fencedBlock
+	var current *fencedBlock
+
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimRight(line, "\r")
+		trimmed := strings.TrimSpace(line)
+
+		if current == nil {
+			if strings.HasPrefix(trimmed, "```") {
+				current = &fencedBlock{info: strings.TrimSpace(strings.TrimPrefix(trimmed, "```"))}
+			}
+			continue
+		}
+
+		if trimmed == "```" {
+			blocks = append(blocks, *current)
+			current = nil
+			continue
+		}
+		current.body = append(current.body, line)
+	}
+
+	return blocks
+}
+
+// replacementTarget returns the file= or path= attribute of a fence info string
+func replacementTarget(info string) (string, bool) {
+	for _, field := range strings.Fields(info) {
+		for _, key := range []string{"file=", "path="} {
+			if strings.HasPrefix(field, key) {
+				target := strings.Trim(strings.TrimPrefix(field, key), `"'`)
+				return target, target != ""
+			}
+		}
+	}
+	return "", false
+}
+
+func isDiffBlock(block fencedBlock) bool {
+	lang := strings.ToLower(strings.Fields(block.info + " ")[0])
+	if lang == "diff" || lang == "patch" {
+		return true
+	}
+	for _, line := range block.body {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		return strings.HasPrefix(line, "--- ") || strings.HasPrefix(line, "diff --git")
+	}
+	return false
+}
+
+func parseUnifiedDiff(lines []string) ([]FileChange, error) {
+	var changes []FileChange
+
+	i := 0
+	for i < len(lines) {
+		if !isFileHeader(lines, i) {
+			i++
+			continue
+		}
+
+		oldPath := diffPath(lines[i][4:])
+		newPath := diffPath(lines[i+1][4:])
+		i += 2
+
+		change := FileChange{Kind: FileChangeModify}
+		target := newPath
+		switch {
+		case oldPath == "/dev/null":
+			change.Kind = FileChangeCreate
+		case newPath == "/dev/null":
+			change.Kind = FileChangeDelete
+			target = oldPath
+		}
+
+		cleaned, err := cleanPath(target)
+		if err != nil {
+			return nil, err
+		}
+		change.Path = cleaned
+
+		for i < len(lines) && strings.HasPrefix(lines[i], "@@") {
+			var hunk Hunk
+			hunk, i = parseHunk(lines, i)
+			change.Hunks = append(change.Hunks, hunk)
+		}
+
+		if len(change.Hunks) > 0 {
+			changes = append(changes, change)
+		}
+	}
+
+	return changes, nil
+}
+
+// parseHunk reads the hunk whose header is at lines[start] and returns it
+// together with the index of the first line after it. Line counts from the
+// header bound the hunk when present; headers without numbers (common in
+// model output) leave the hunk to be located by content alone.
+func parseHunk(lines []string, start int) (Hunk, int) {
+	hunk := Hunk{Status: HunkPending}
+	oldRemaining, newRemaining := -1, -1
+
+	if match := hunkHeaderPattern.FindStringSubmatch(lines[start]); match != nil {
+		hunk.OldStart = atoiOr(match[1], 0)
+		oldRemaining = atoiOr(match[2], 1)
+		hunk.NewStart = atoiOr(match[3], 0)
+		newRemaining = atoiOr(match[4], 1)
+	}
+
+	i := start + 1
+	for i < len(lines) {
+		if oldRemaining == 0 && newRemaining == 0 {
+			break
+		}
+
+		line := lines[i]
+		if strings.HasPrefix(line, "@@") || strings.HasPrefix(line, "diff ") || isFileHeader(lines, i) {
+			break
+		}
+
+		var diffLine DiffLine
+		switch {
+		case line == "":
+			diffLine = DiffLine{Kind: DiffLineContext}
+		case line[0] == ' ':
+			diffLine = DiffLine{Kind: DiffLineContext, Text: line[1:]}
+		case line[0] == '+':
+			diffLine = DiffLine{Kind: DiffLineAdd, Text: line[1:]}
+		case line[0] == '-':
+			diffLine = DiffLine{Kind: DiffLineRemove, Text: line[1:]}
+		case line[0] == '\\':
+			// "\ No newline at end of file"
+			i++
+			continue
+		default:
+			return finishHunk(hunk), i
+		}
+
+		if diffLine.Kind != DiffLineAdd {
+			oldRemaining--
+		}
+		if diffLine.Kind != DiffLineRemove {
+			newRemaining--
+		}
+		hunk.Lines = append(hunk.Lines, diffLine)
+		i++
+	}
+
+	return finishHunk(hunk), i
+}
+
+func isFileHeader(lines []string, i int) bool {
+	return strings.HasPrefix(lines[i], "--- ") && i+1 < len(lines) && strings.HasPrefix(lines[i+1], "+++ ")
+}
+
+// finishHunk makes the line counts agree with the lines actually present
+func finishHunk(hunk Hunk) Hunk {
+	hunk.OldLines = len(hunk.oldSide())
+	hunk.NewLines = len(hunk.newSide())
+	return hunk
+}
+
+func diffPath(raw string) string {
+	// Drop an optional tab-separated timestamp
+	if tab := strings.IndexByte(raw, '\t'); tab >= 0 {
+		raw = raw[:tab]
+	}
+	raw = strings.TrimSpace(raw)
+	if raw == "/dev/null" {
+		return raw
+	}
+	if strings.HasPrefix(raw, "a/") || strings.HasPrefix(raw, "b/") {
+		return raw[2:]
+	}
+	return raw
+}
+
+// cleanPath normalises a path and rejects anything escaping the workspace
+func cleanPath(p string) (string, error) {
+	p = strings.ReplaceAll(strings.TrimSpace(p), "\\", "/")
+	if p == "" || strings.HasPrefix(p, "/") || (len(p) > 1 && p[1] == ':') {
+		return "", ErrInvalidPath
+	}
+
+	cleaned := path.Clean(p)
+	if cleaned == "." || cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return "", ErrInvalidPath
+	}
+
+	return cleaned, nil
+}
+
+func atoiOr(s string, fallback int) int {
+	if s == "" {
+		return fallback
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return fallback
+	}
+	return n
+}
@@ -0,0 +1,103 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"lumina/backend/patch/domain"
+)
+
+func TestParseFileChanges_UnifiedDiff(t *testing.T) {
+	// Given a reply with a unified diff
+	reply := "Here is the fix:\n\n```diff\n" +
+		"--- a/app.go\n" +
+		"+++ b/app.go\n" +
+		"@@ -1,3 +1,3 @@\n" +
+		" package main\n" +
+		"-var x = 1\n" +
+		"+var x = 2\n" +
+		" \n" +
+		"```\n"
+
+	// When parsing it
+	changes, err := domain.ParseFileChanges(reply)
+
+	// Then it should produce one modified file with one pending hunk
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	assert.Equal(t, "app.go", changes[0].Path)
+	assert.Equal(t, domain.FileChangeModify, changes[0].Kind)
+	require.Len(t, changes[0].Hunks, 1)
+
+	hunk := changes[0].Hunks[0]
+	assert.Equal(t, domain.HunkPending, hunk.Status)
+	assert.Equal(t, 1, hunk.OldStart)
+	assert.Equal(t, 3, hunk.OldLines)
+	assert.Equal(t, 3, hunk.NewLines)
+	assert.Equal(t, domain.DiffLine{Kind: domain.DiffLineRemove, Text: "var x = 1"}, hunk.Lines[1])
+	assert.Equal(t, domain.DiffLine{Kind: domain.DiffLineAdd, Text: "var x = 2"}, hunk.Lines[2])
+}
+
+func TestParseFileChanges_CreateAndDelete(t *testing.T) {
+	// Given a diff creating one file and deleting another
+	reply := "```diff\n" +
+		"--- /dev/null\n" +
+		"+++ b/new.txt\n" +
+		"@@ -0,0 +1,1 @@\n" +
+		"+hello\n" +
+		"--- a/old.txt\n" +
+		"+++ /dev/null\n" +
+		"@@ -1,1 +0,0 @@\n" +
+		"-bye\n" +
+		"```"
+
+	// When parsing it
+	changes, err := domain.ParseFileChanges(reply)
+
+	// Then both kinds should be recognised
+	require.NoError(t, err)
+	require.Len(t, changes, 2)
+	assert.Equal(t, domain.FileChangeCreate, changes[0].Kind)
+	assert.Equal(t, "new.txt", changes[0].Path)
+	assert.Equal(t, domain.FileChangeDelete, changes[1].Kind)
+	assert.Equal(t, "old.txt", changes[1].Path)
+}
+
+func TestParseFileChanges_ReplacementBlock(t *testing.T) {
+	// Given a reply with a whole-file replacement block
+	reply := "```go file=backend/main.go\npackage main\n\nfunc main() {}\n```"
+
+	// When parsing it
+	changes, err := domain.ParseFileChanges(reply)
+
+	// Then it should produce a replacement with the block content
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	assert.Equal(t, domain.FileChangeReplace, changes[0].Kind)
+	assert.Equal(t, "backend/main.go", changes[0].Path)
+	assert.Equal(t, "package main\n\nfunc main() {}\n", changes[0].NewContent)
+}
+
+func TestParseFileChanges_IgnoresOrdinaryCodeBlocks(t *testing.T) {
+	// Given a reply with only an illustrative snippet
+	reply := "Try this:\n```go\nfmt.Println(\"hi\")\n```"
+
+	// When parsing it
+	_, err := domain.ParseFileChanges(reply)
+
+	// Then no changes should be found
+	assert.ErrorIs(t, err, domain.ErrNoChanges)
+}
+
+func TestParseFileChanges_RejectsPathsOutsideWorkspace(t *testing.T) {
+	testCases := []string{
+		"```diff\n--- a/../etc/passwd\n+++ b/../etc/passwd\n@@ -1 +1 @@\n-a\n+b\n```",
+		"```sh file=/etc/hosts\n127.0.0.1 evil\n```",
+	}
+
+	for _, reply := range testCases {
+		_, err := domain.ParseFileChanges(reply)
+		assert.ErrorIs(t, err, domain.ErrInvalidPath, "reply: %s", reply)
+	}
+}
@@ -0,0 +1,344 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PatchService turns assistant replies into reviewable change sets and
+// applies, rejects or undoes them against a workspace
+type PatchService struct {
+	workspace  Workspace
+	repository ChangeSetRepository
+}
+
+func NewPatchService(workspace Workspace, repository ChangeSetRepository) *PatchService {
+	return &PatchService{
+		workspace:  workspace,
+		repository: repository,
+	}
+}
+
+// Propose extracts the changes in an assistant reply and stores them as a
+// pending change set. It returns ErrNoChanges when the reply proposes none.
+func (s *PatchService) Propose(reply string, messageIndex int) (ChangeSet, error) {
+	files, err := ParseFileChanges(reply)
+	if err != nil {
+		return ChangeSet{}, err
+	}
+
+	for i := range files {
+		if files[i].Kind != FileChangeReplace {
+			continue
+		}
+		if err := s.completeReplacement(&files[i]); err != nil {
+			return ChangeSet{}, err
+		}
+	}
+
+	changeSet := ChangeSet{
+		ID:           uuid.New().String(),
+		MessageIndex: messageIndex,
+		Files:        files,
+		CreatedAt:    time.Now(),
+	}
+
+	if err := s.repository.Save(changeSet); err != nil {
+		return ChangeSet{}, fmt.Errorf("failed to save change set: %w", err)
+	}
+
+	return changeSet, nil
+}
+
+// completeReplacement records the base the replacement was proposed against
+// and builds a single whole-file hunk so it can be previewed like a diff
+func (s *PatchService) completeReplacement(change *FileChange) error {
+	current, exists, err := s.workspace.ReadFile(change.Path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", change.Path, err)
+	}
+
+	change.BaseHash = hashContent(current, exists)
+
+	oldLines, _ := splitLines(current)
+	newLines, _ := splitLines(change.NewContent)
+
+	hunk := Hunk{Status: HunkPending}
+	if len(oldLines) > 0 {
+		hunk.OldStart = 1
+	}
+	if len(newLines) > 0 {
+		hunk.NewStart = 1
+	}
+	for _, line := range oldLines {
+		hunk.Lines = append(hunk.Lines, DiffLine{Kind: DiffLineRemove, Text: line})
+	}
+	for _, line := range newLines {
+		hunk.Lines = append(hunk.Lines, DiffLine{Kind: DiffLineAdd, Text: line})
+	}
+	change.Hunks = []Hunk{finishHunk(hunk)}
+
+	return nil
+}
+
+// Apply writes the selected hunks to the workspace. Either every selected
+// file applies cleanly or nothing is written; a *ConflictError names the
+// first file or hunk that does not match.
+func (s *PatchService) Apply(changeSetID string, selections []HunkSelection) (UndoRecord, error) {
+	changeSet, err := s.repository.GetByID(changeSetID)
+	if err != nil {
+		return UndoRecord{}, err
+	}
+
+	refs, err := resolveSelections(&changeSet, selections)
+	if err != nil {
+		return UndoRecord{}, err
+	}
+
+	// Compute every file first so a conflict leaves the workspace untouched
+	var snapshots []FileSnapshot
+	for _, path := range refPaths(refs) {
+		file, _ := changeSet.File(path)
+
+		current, exists, err := s.workspace.ReadFile(path)
+		if err != nil {
+			return UndoRecord{}, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+
+		result, err := file.apply(current, exists, refIndexes(refs, path))
+		if err != nil {
+			return UndoRecord{}, err
+		}
+
+		snapshots = append(snapshots, FileSnapshot{
+			Path:          path,
+			ExistedBefore: exists,
+			Before:        current,
+			ExistsAfter:   result.exists,
+			After:         result.content,
+		})
+	}
+
+	for i, snapshot := range snapshots {
+		if err := s.writeSnapshot(snapshot.Path, snapshot.ExistsAfter, snapshot.After); err != nil {
+			s.restore(snapshots[:i])
+			return UndoRecord{}, err
+		}
+	}
+
+	record := UndoRecord{
+		ID:          uuid.New().String(),
+		ChangeSetID: changeSet.ID,
+		Hunks:       refs,
+		Files:       snapshots,
+		AppliedAt:   time.Now(),
+	}
+
+	// Changes that couldn't be undone are taken back
+	if err := s.repository.SaveUndoRecord(record); err != nil {
+		s.restore(snapshots)
+		return UndoRecord{}, fmt.Errorf("failed to save undo record: %w", err)
+	}
+
+	for _, ref := range refs {
+		file, _ := changeSet.File(ref.Path)
+		file.Hunks[ref.Index].Status = HunkApplied
+	}
+
+	if err := s.repository.Save(changeSet); err != nil {
+		return UndoRecord{}, fmt.Errorf("failed to save change set: %w", err)
+	}
+
+	return record, nil
+}
+
+// Reject marks the selected hunks as rejected without touching the workspace
+func (s *PatchService) Reject(changeSetID string, selections []HunkSelection) (ChangeSet, error) {
+	changeSet, err := s.repository.GetByID(changeSetID)
+	if err != nil {
+		return ChangeSet{}, err
+	}
+
+	refs, err := resolveSelections(&changeSet, selections)
+	if err != nil {
+		return ChangeSet{}, err
+	}
+
+	for _, ref := range refs {
+		file, _ := changeSet.File(ref.Path)
+		file.Hunks[ref.Index].Status = HunkRejected
+	}
+
+	if err := s.repository.Save(changeSet); err != nil {
+		return ChangeSet{}, fmt.Errorf("failed to save change set: %w", err)
+	}
+
+	return changeSet, nil
+}
+
+// Undo restores the files touched by an apply. It refuses with a
+// *ConflictError when a file was modified after the apply.
+func (s *PatchService) Undo(undoRecordID string) (ChangeSet, error) {
+	record, err := s.repository.GetUndoRecord(undoRecordID)
+	if err != nil {
+		return ChangeSet{}, err
+	}
+
+	if record.UndoneAt != nil {
+		return ChangeSet{}, ErrUndoAlreadyReverted
+	}
+
+	for _, snapshot := range record.Files {
+		current, exists, err := s.workspace.ReadFile(snapshot.Path)
+		if err != nil {
+			return ChangeSet{}, fmt.Errorf("failed to read %s: %w", snapshot.Path, err)
+		}
+		if exists != snapshot.ExistsAfter || (exists && current != snapshot.After) {
+			return ChangeSet{}, &ConflictError{Path: snapshot.Path, Hunk: -1, Reason: "file changed after the changes were applied"}
+		}
+	}
+
+	if err := s.restore(record.Files); err != nil {
+		return ChangeSet{}, err
+	}
+
+	now := time.Now()
+	record.UndoneAt = &now
+	if err := s.repository.SaveUndoRecord(record); err != nil {
+		return ChangeSet{}, fmt.Errorf("failed to save undo record: %w", err)
+	}
+
+	changeSet, err := s.repository.GetByID(record.ChangeSetID)
+	if err != nil {
+		return ChangeSet{}, err
+	}
+
+	for _, ref := range record.Hunks {
+		file, err := changeSet.File(ref.Path)
+		if err != nil || ref.Index >= len(file.Hunks) {
+			continue
+		}
+		file.Hunks[ref.Index].Status = HunkPending
+	}
+
+	if err := s.repository.Save(changeSet); err != nil {
+		return ChangeSet{}, fmt.Errorf("failed to save change set: %w", err)
+	}
+
+	return changeSet, nil
+}
+
+// ChangeSets lists stored change sets
+func (s *PatchService) ChangeSets() ([]ChangeSet, error) {
+	return s.repository.List()
+}
+
+// ChangeSet returns a stored change set
+func (s *PatchService) ChangeSet(id string) (ChangeSet, error) {
+	return s.repository.GetByID(id)
+}
+
+// UndoRecords lists the applies made from a change set, newest first
+func (s *PatchService) UndoRecords(changeSetID string) ([]UndoRecord, error) {
+	return s.repository.ListUndoRecords(changeSetID)
+}
+
+// restore puts the files back to their state before an apply
+func (s *PatchService) restore(snapshots []FileSnapshot) error {
+	for _, snapshot := range snapshots {
+		if err := s.writeSnapshot(snapshot.Path, snapshot.ExistedBefore, snapshot.Before); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *PatchService) writeSnapshot(path string, exists bool, content string) error {
+	if !exists {
+		if err := s.workspace.DeleteFile(path); err != nil {
+			return fmt.Errorf("failed to delete %s: %w", path, err)
+		}
+		return nil
+	}
+
+	if err := s.workspace.WriteFile(path, content); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// resolveSelections validates the selections and expands them to hunk
+// references sorted by path and index. Whole-file changes (create, delete,
+// replace) can only be taken as a whole.
+func resolveSelections(changeSet *ChangeSet, selections []HunkSelection) ([]HunkRef, error) {
+	seen := make(map[HunkRef]bool)
+	var refs []HunkRef
+
+	for _, selection := range selections {
+		file, err := changeSet.File(selection.Path)
+		if err != nil {
+			return nil, err
+		}
+
+		indexes := selection.Hunks
+		if len(indexes) == 0 || file.Kind != FileChangeModify {
+			indexes = nil
+			for i, hunk := range file.Hunks {
+				if hunk.Status == HunkPending {
+					indexes = append(indexes, i)
+				}
+			}
+		}
+
+		for _, index := range indexes {
+			if index < 0 || index >= len(file.Hunks) {
+				return nil, fmt.Errorf("%w: %s hunk %d", ErrHunkOutOfRange, file.Path, index)
+			}
+			if file.Hunks[index].Status != HunkPending {
+				return nil, fmt.Errorf("%w: %s hunk %d", ErrHunkNotPending, file.Path, index)
+			}
+
+			ref := HunkRef{Path: file.Path, Index: index}
+			if !seen[ref] {
+				seen[ref] = true
+				refs = append(refs, ref)
+			}
+		}
+	}
+
+	if len(refs) == 0 {
+		return nil, ErrNoChanges
+	}
+
+	sort.Slice(refs, func(i, j int) bool {
+		if refs[i].Path != refs[j].Path {
+			return refs[i].Path < refs[j].Path
+		}
+		return refs[i].Index < refs[j].Index
+	})
+
+	return refs, nil
+}
+
+func refPaths(refs []HunkRef) []string {
+	var paths []string
+	for _, ref := range refs {
+		if len(paths) == 0 || paths[len(paths)-1] != ref.Path {
+			paths = append(paths, ref.Path)
+		}
+	}
+	return paths
+}
+
+func refIndexes(refs []HunkRef, path string) []int {
+	var indexes []int
+	for _, ref := range refs {
+		if ref.Path == path {
+			indexes = append(indexes, ref.Index)
+		}
+	}
+	return indexes
+}
@@ -0,0 +1,280 @@
+package domain_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"lumina/backend/patch/domain"
+)
+
+// MockWorkspace keeps files in memory
+type MockWorkspace struct {
+	files map[string]string
+}
+
+func NewMockWorkspace(files map[string]string) *MockWorkspace {
+	return &MockWorkspace{files: files}
+}
+
+func (m *MockWorkspace) ReadFile(path string) (string, bool, error) {
+	content, ok := m.files[path]
+	return content, ok, nil
+}
+
+func (m *MockWorkspace) WriteFile(path string, content string) error {
+	m.files[path] = content
+	return nil
+}
+
+func (m *MockWorkspace) DeleteFile(path string) error {
+	delete(m.files, path)
+	return nil
+}
+
+// MockChangeSetRepository keeps change sets in memory; saving undo records
+// fails with undoErr when it is set
+type MockChangeSetRepository struct {
+	changeSets  map[string]domain.ChangeSet
+	undoRecords map[string]domain.UndoRecord
+	undoErr     error
+}
+
+func NewMockChangeSetRepository() *MockChangeSetRepository {
+	return &MockChangeSetRepository{
+		changeSets:  make(map[string]domain.ChangeSet),
+		undoRecords: make(map[string]domain.UndoRecord),
+	}
+}
+
+func (m *MockChangeSetRepository) Save(changeSet domain.ChangeSet) error {
+	m.changeSets[changeSet.ID] = changeSet
+	return nil
+}
+
+func (m *MockChangeSetRepository) GetByID(id string) (domain.ChangeSet, error) {
+	changeSet, ok := m.changeSets[id]
+	if !ok {
+		return domain.ChangeSet{}, domain.ErrChangeSetNotFound
+	}
+	return changeSet, nil
+}
+
+func (m *MockChangeSetRepository) List() ([]domain.ChangeSet, error) {
+	changeSets := make([]domain.ChangeSet, 0, len(m.changeSets))
+	for _, changeSet := range m.changeSets {
+		changeSets = append(changeSets, changeSet)
+	}
+	return changeSets, nil
+}
+
+func (m *MockChangeSetRepository) SaveUndoRecord(record domain.UndoRecord) error {
+	if m.undoErr != nil {
+		return m.undoErr
+	}
+	m.undoRecords[record.ID] = record
+	return nil
+}
+
+func (m *MockChangeSetRepository) GetUndoRecord(id string) (domain.UndoRecord, error) {
+	record, ok := m.undoRecords[id]
+	if !ok {
+		return domain.UndoRecord{}, domain.ErrUndoRecordNotFound
+	}
+	return record, nil
+}
+
+func (m *MockChangeSetRepository) ListUndoRecords(changeSetID string) ([]domain.UndoRecord, error) {
+	var records []domain.UndoRecord
+	for _, record := range m.undoRecords {
+		if record.ChangeSetID == changeSetID {
+			records = append(records, record)
+		}
+	}
+	return records, nil
+}
+
+func (m *MockChangeSetRepository) Close() error {
+	return nil
+}
+
+const twoHunkReply = "```diff\n" +
+	"--- a/main.go\n" +
+	"+++ b/main.go\n" +
+	"@@ -1,2 +1,2 @@\n" +
+	" line 1\n" +
+	"-line 2\n" +
+	"+line two\n" +
+	"@@ -4,2 +4,2 @@\n" +
+	" line 4\n" +
+	"-line 5\n" +
+	"+line five\n" +
+	"```"
+
+const mainGo = "line 1\nline 2\nline 3\nline 4\nline 5\n"
+
+func TestPatchService_ApplySelectedHunk(t *testing.T) {
+	// Given a proposed change set with two hunks
+	workspace := NewMockWorkspace(map[string]string{"main.go": mainGo})
+	service := domain.NewPatchService(workspace, NewMockChangeSetRepository())
+
+	changeSet, err := service.Propose(twoHunkReply, 1)
+	require.NoError(t, err)
+
+	// When applying only the second hunk
+	record, err := service.Apply(changeSet.ID, []domain.HunkSelection{{Path: "main.go", Hunks: []int{1}}})
+
+	// Then only that hunk should be written and marked applied
+	require.NoError(t, err)
+	assert.Equal(t, "line 1\nline 2\nline 3\nline 4\nline five\n", workspace.files["main.go"])
+	assert.Equal(t, []domain.HunkRef{{Path: "main.go", Index: 1}}, record.Hunks)
+
+	updated, err := service.ChangeSet(changeSet.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.HunkPending, updated.Files[0].Hunks[0].Status)
+	assert.Equal(t, domain.HunkApplied, updated.Files[0].Hunks[1].Status)
+}
+
+func TestPatchService_ApplyToleratesShiftedLines(t *testing.T) {
+	// Given a file that gained lines at the top since the diff was written
+	workspace := NewMockWorkspace(map[string]string{"main.go": "header\n" + mainGo})
+	service := domain.NewPatchService(workspace, NewMockChangeSetRepository())
+
+	changeSet, err := service.Propose(twoHunkReply, 1)
+	require.NoError(t, err)
+
+	// When applying every hunk
+	_, err = service.Apply(changeSet.ID, []domain.HunkSelection{{Path: "main.go"}})
+
+	// Then the hunks should be located by content
+	require.NoError(t, err)
+	assert.Equal(t, "header\nline 1\nline two\nline 3\nline 4\nline five\n", workspace.files["main.go"])
+}
+
+func TestPatchService_ApplyWithoutUndoRecord(t *testing.T) {
+	// Given a repository that can't save undo records
+	workspace := NewMockWorkspace(map[string]string{"main.go": mainGo})
+	repository := NewMockChangeSetRepository()
+	service := domain.NewPatchService(workspace, repository)
+
+	changeSet, err := service.Propose(twoHunkReply, 1)
+	require.NoError(t, err)
+	repository.undoErr = errors.New("disk full")
+
+	// When applying every hunk
+	_, err = service.Apply(changeSet.ID, []domain.HunkSelection{{Path: "main.go"}})
+
+	// Then the changes are taken back, since they could not be undone
+	require.Error(t, err)
+	assert.Equal(t, mainGo, workspace.files["main.go"])
+
+	unchanged, err := service.ChangeSet(changeSet.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.HunkPending, unchanged.Files[0].Hunks[0].Status)
+}
+
+func TestPatchService_ApplyDetectsConflict(t *testing.T) {
+	// Given a file that no longer contains the lines the diff expects
+	workspace := NewMockWorkspace(map[string]string{"main.go": "line 1\nchanged\nline 3\nline 4\nline 5\n"})
+	service := domain.NewPatchService(workspace, NewMockChangeSetRepository())
+
+	changeSet, err := service.Propose(twoHunkReply, 1)
+	require.NoError(t, err)
+
+	// When applying every hunk
+	_, err = service.Apply(changeSet.ID, []domain.HunkSelection{{Path: "main.go"}})
+
+	// Then it should report the conflicting hunk and leave the file untouched
+	var conflict *domain.ConflictError
+	require.ErrorAs(t, err, &conflict)
+	assert.Equal(t, "main.go", conflict.Path)
+	assert.Equal(t, 0, conflict.Hunk)
+	assert.Equal(t, "line 1\nchanged\nline 3\nline 4\nline 5\n", workspace.files["main.go"])
+}
+
+func TestPatchService_ReplacementConflictsWhenFileChanged(t *testing.T) {
+	// Given a replacement proposed against the current file
+	workspace := NewMockWorkspace(map[string]string{"notes.md": "old\n"})
+	service := domain.NewPatchService(workspace, NewMockChangeSetRepository())
+
+	changeSet, err := service.Propose("```md file=notes.md\nnew\n```", 1)
+	require.NoError(t, err)
+	require.Len(t, changeSet.Files[0].Hunks, 1)
+
+	// When the file changes before the replacement is applied
+	workspace.files["notes.md"] = "edited by hand\n"
+	_, err = service.Apply(changeSet.ID, []domain.HunkSelection{{Path: "notes.md"}})
+
+	// Then the apply should be refused
+	var conflict *domain.ConflictError
+	assert.ErrorAs(t, err, &conflict)
+	assert.Equal(t, "edited by hand\n", workspace.files["notes.md"])
+}
+
+func TestPatchService_RejectMarksHunks(t *testing.T) {
+	// Given a proposed change set
+	workspace := NewMockWorkspace(map[string]string{"main.go": mainGo})
+	service := domain.NewPatchService(workspace, NewMockChangeSetRepository())
+
+	changeSet, err := service.Propose(twoHunkReply, 1)
+	require.NoError(t, err)
+
+	// When rejecting the first hunk
+	updated, err := service.Reject(changeSet.ID, []domain.HunkSelection{{Path: "main.go", Hunks: []int{0}}})
+
+	// Then it should be rejected and no longer applicable
+	require.NoError(t, err)
+	assert.Equal(t, domain.HunkRejected, updated.Files[0].Hunks[0].Status)
+	assert.Equal(t, mainGo, workspace.files["main.go"])
+
+	_, err = service.Apply(changeSet.ID, []domain.HunkSelection{{Path: "main.go", Hunks: []int{0}}})
+	assert.ErrorIs(t, err, domain.ErrHunkNotPending)
+}
+
+func TestPatchService_UndoRestoresFiles(t *testing.T) {
+	// Given an applied change set that also created a file
+	workspace := NewMockWorkspace(map[string]string{"main.go": mainGo})
+	service := domain.NewPatchService(workspace, NewMockChangeSetRepository())
+
+	reply := twoHunkReply + "\n```diff\n--- /dev/null\n+++ b/new.go\n@@ -0,0 +1 @@\n+package main\n```"
+	changeSet, err := service.Propose(reply, 1)
+	require.NoError(t, err)
+
+	record, err := service.Apply(changeSet.ID, []domain.HunkSelection{{Path: "main.go"}, {Path: "new.go"}})
+	require.NoError(t, err)
+	assert.Equal(t, "package main\n", workspace.files["new.go"])
+
+	// When undoing the apply
+	updated, err := service.Undo(record.ID)
+
+	// Then the files should be back to their original state
+	require.NoError(t, err)
+	assert.Equal(t, mainGo, workspace.files["main.go"])
+	assert.NotContains(t, workspace.files, "new.go")
+	assert.Equal(t, domain.HunkPending, updated.Files[0].Hunks[0].Status)
+
+	// And undoing twice should fail
+	_, err = service.Undo(record.ID)
+	assert.ErrorIs(t, err, domain.ErrUndoAlreadyReverted)
+}
+
+func TestPatchService_UndoRefusesWhenFileEditedAfterApply(t *testing.T) {
+	// Given an applied change set
+	workspace := NewMockWorkspace(map[string]string{"main.go": mainGo})
+	service := domain.NewPatchService(workspace, NewMockChangeSetRepository())
+
+	changeSet, err := service.Propose(twoHunkReply, 1)
+	require.NoError(t, err)
+	record, err := service.Apply(changeSet.ID, []domain.HunkSelection{{Path: "main.go"}})
+	require.NoError(t, err)
+
+	// When the file is edited afterwards
+	workspace.files["main.go"] = "rewritten\n"
+	_, err = service.Undo(record.ID)
+
+	// Then undo should report a conflict and keep the edit
+	var conflict *domain.ConflictError
+	assert.ErrorAs(t, err, &conflict)
+	assert.Equal(t, "rewritten\n", workspace.files["main.go"])
+}
@@ -0,0 +1,10 @@
+package domain
+
+// Workspace gives access to the files that change sets are applied to.
+// Paths are slash-separated and relative to the workspace root.
+type Workspace interface {
+	// ReadFile returns the file content and whether the file exists
+	ReadFile(path string) (string, bool, error)
+	WriteFile(path string, content string) error
+	DeleteFile(path string) error
+}
@@ -0,0 +1,109 @@
+package infrastructure
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"lumina/backend/patch/domain"
+)
+
+// FileWorkspace implements the Workspace port on a directory of the OS filesystem
+type FileWorkspace struct {
+	root string
+}
+
+// NewFileWorkspace creates a workspace rooted at root
+func NewFileWorkspace(root string) *FileWorkspace {
+	return &FileWorkspace{root: root}
+}
+
+// ReadFile returns the content of the file and whether it exists
+func (w *FileWorkspace) ReadFile(path string) (string, bool, error) {
+	fullPath, err := w.resolve(path)
+	if err != nil {
+		return "", false, err
+	}
+
+	content, err := os.ReadFile(fullPath)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return "", false, nil
+		}
+		return "", false, err
+	}
+	return string(content), true, nil
+}
+
+// WriteFile writes the file, creating parent directories as needed
+func (w *FileWorkspace) WriteFile(path string, content string) error {
+	fullPath, err := w.resolve(path)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+		return err
+	}
+
+	// Keep the permissions of an existing file
+	mode := fs.FileMode(0644)
+	if info, err := os.Stat(fullPath); err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	return os.WriteFile(fullPath, []byte(content), mode)
+}
+
+// DeleteFile removes the file; deleting a missing file is not an error. A
+// symlink is removed itself, not what it points to.
+func (w *FileWorkspace) DeleteFile(path string) error {
+	dir, err := w.resolve(filepath.ToSlash(filepath.Dir(filepath.FromSlash(path))))
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(filepath.Join(dir, filepath.Base(filepath.FromSlash(path))))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// resolve returns where path is on disk, following symlinks as far as the
+// path exists, and refuses paths that lead outside the root that way
+func (w *FileWorkspace) resolve(path string) (string, error) {
+	root, err := filepath.EvalSymlinks(w.root)
+	if err != nil {
+		return "", err
+	}
+
+	// The part of the path that doesn't exist yet is created below the
+	// part that does
+	existing, missing := filepath.Join(root, filepath.FromSlash(path)), ""
+	for {
+		resolved, err := filepath.EvalSymlinks(existing)
+		if err == nil {
+			existing = resolved
+			break
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return "", err
+		}
+		// A dangling symlink would be followed when writing
+		if _, err := os.Lstat(existing); err == nil {
+			return "", fmt.Errorf("%w: %s is a broken symlink", domain.ErrInvalidPath, path)
+		}
+		missing = filepath.Join(filepath.Base(existing), missing)
+		existing = filepath.Dir(existing)
+	}
+	fullPath := filepath.Join(existing, missing)
+
+	relative, err := filepath.Rel(root, fullPath)
+	if err != nil || relative == ".." || strings.HasPrefix(relative, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("%w: %s leads outside the working directory", domain.ErrInvalidPath, path)
+	}
+	return fullPath, nil
+}
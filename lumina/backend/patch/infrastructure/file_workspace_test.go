@@ -0,0 +1,93 @@
+package infrastructure_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"lumina/backend/patch/domain"
+	"lumina/backend/patch/infrastructure"
+)
+
+func TestFileWorkspace_WriteAndRead(t *testing.T) {
+	// Given a workspace reached through a symlink
+	root := t.TempDir()
+	link := filepath.Join(t.TempDir(), "project")
+	require.NoError(t, os.Symlink(root, link))
+	workspace := infrastructure.NewFileWorkspace(link)
+
+	// When writing a file in a new directory
+	require.NoError(t, workspace.WriteFile("src/main.go", "package main\n"))
+
+	// Then it can be read back and is on disk below the root
+	content, exists, err := workspace.ReadFile("src/main.go")
+	require.NoError(t, err)
+	assert.True(t, exists)
+	assert.Equal(t, "package main\n", content)
+	assert.FileExists(t, filepath.Join(root, "src", "main.go"))
+
+	_, exists, err = workspace.ReadFile("src/missing.go")
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestFileWorkspace_SymlinkOutsideRoot(t *testing.T) {
+	// Given a workspace with a symlink to a directory outside it
+	root := t.TempDir()
+	outside := t.TempDir()
+	secret := filepath.Join(outside, "secret.txt")
+	require.NoError(t, os.WriteFile(secret, []byte("secret"), 0644))
+	require.NoError(t, os.Symlink(outside, filepath.Join(root, "escape")))
+	workspace := infrastructure.NewFileWorkspace(root)
+
+	// When changing files through the symlink
+	_, _, readErr := workspace.ReadFile("escape/secret.txt")
+	writeErr := workspace.WriteFile("escape/secret.txt", "overwritten")
+	newErr := workspace.WriteFile("escape/new/file.txt", "created")
+	deleteErr := workspace.DeleteFile("escape/secret.txt")
+
+	// Then every change is refused and the outside files are untouched
+	assert.ErrorIs(t, readErr, domain.ErrInvalidPath)
+	assert.ErrorIs(t, writeErr, domain.ErrInvalidPath)
+	assert.ErrorIs(t, newErr, domain.ErrInvalidPath)
+	assert.ErrorIs(t, deleteErr, domain.ErrInvalidPath)
+	content, err := os.ReadFile(secret)
+	require.NoError(t, err)
+	assert.Equal(t, "secret", string(content))
+	assert.NoDirExists(t, filepath.Join(outside, "new"))
+}
+
+func TestFileWorkspace_BrokenSymlink(t *testing.T) {
+	// Given a workspace with a symlink to a file that doesn't exist outside it
+	root := t.TempDir()
+	target := filepath.Join(t.TempDir(), "target.txt")
+	require.NoError(t, os.Symlink(target, filepath.Join(root, "dangling.txt")))
+	workspace := infrastructure.NewFileWorkspace(root)
+
+	// When writing through it
+	err := workspace.WriteFile("dangling.txt", "created")
+
+	// Then the write is refused
+	assert.ErrorIs(t, err, domain.ErrInvalidPath)
+	assert.NoFileExists(t, target)
+}
+
+func TestFileWorkspace_DeleteSymlink(t *testing.T) {
+	// Given a workspace with a symlink to a file outside it
+	root := t.TempDir()
+	target := filepath.Join(t.TempDir(), "target.txt")
+	require.NoError(t, os.WriteFile(target, []byte("kept"), 0644))
+	require.NoError(t, os.Symlink(target, filepath.Join(root, "link.txt")))
+	workspace := infrastructure.NewFileWorkspace(root)
+
+	// When deleting the symlink
+	require.NoError(t, workspace.DeleteFile("link.txt"))
+
+	// Then the symlink is gone and the file it pointed to is kept
+	_, err := os.Lstat(filepath.Join(root, "link.txt"))
+	assert.True(t, os.IsNotExist(err))
+	assert.FileExists(t, target)
+	require.NoError(t, workspace.DeleteFile("link.txt"))
+}
@@ -0,0 +1,188 @@
+package infrastructure
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"lumina/backend/patch/domain"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// SQLiteChangeSetRepository stores change sets and undo records as JSON documents
+type SQLiteChangeSetRepository struct {
+	db *sql.DB
+}
+
+func NewSQLiteChangeSetRepository(dbPath string) (*SQLiteChangeSetRepository, error) {
+	db, err := sql.Open("sqlite3", dbPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	// Create change set tables if they don't exist
+	createTableSQL := `
+	CREATE TABLE IF NOT EXISTS change_sets (
+		id TEXT PRIMARY KEY,
+		data TEXT NOT NULL,
+		created_at DATETIME NOT NULL
+	);
+
+	CREATE TABLE IF NOT EXISTS change_set_undo_records (
+		id TEXT PRIMARY KEY,
+		change_set_id TEXT NOT NULL,
+		data TEXT NOT NULL,
+		applied_at DATETIME NOT NULL
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_change_set_undo_records_change_set_id
+		ON change_set_undo_records(change_set_id);
+	`
+
+	if _, err := db.Exec(createTableSQL); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create change set tables: %w", err)
+	}
+
+	return &SQLiteChangeSetRepository{db: db}, nil
+}
+
+func (r *SQLiteChangeSetRepository) Save(changeSet domain.ChangeSet) error {
+	data, err := json.Marshal(changeSet)
+	if err != nil {
+		return fmt.Errorf("failed to marshal change set: %w", err)
+	}
+
+	insertSQL := `
+	INSERT OR REPLACE INTO change_sets (id, data, created_at)
+	VALUES (?, ?, ?)
+	`
+
+	if _, err := r.db.Exec(insertSQL, changeSet.ID, string(data), changeSet.CreatedAt); err != nil {
+		return fmt.Errorf("failed to save change set: %w", err)
+	}
+
+	return nil
+}
+
+func (r *SQLiteChangeSetRepository) GetByID(id string) (domain.ChangeSet, error) {
+	var data string
+	err := r.db.QueryRow("SELECT data FROM change_sets WHERE id = ?", id).Scan(&data)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.ChangeSet{}, domain.ErrChangeSetNotFound
+		}
+		return domain.ChangeSet{}, fmt.Errorf("failed to get change set by ID: %w", err)
+	}
+
+	var changeSet domain.ChangeSet
+	if err := json.Unmarshal([]byte(data), &changeSet); err != nil {
+		return domain.ChangeSet{}, fmt.Errorf("failed to unmarshal change set: %w", err)
+	}
+
+	return changeSet, nil
+}
+
+func (r *SQLiteChangeSetRepository) List() ([]domain.ChangeSet, error) {
+	rows, err := r.db.Query("SELECT data FROM change_sets ORDER BY created_at DESC")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list change sets: %w", err)
+	}
+	defer rows.Close()
+
+	var changeSets []domain.ChangeSet
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("failed to scan change set: %w", err)
+		}
+
+		var changeSet domain.ChangeSet
+		if err := json.Unmarshal([]byte(data), &changeSet); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal change set: %w", err)
+		}
+		changeSets = append(changeSets, changeSet)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating change sets: %w", err)
+	}
+
+	return changeSets, nil
+}
+
+func (r *SQLiteChangeSetRepository) SaveUndoRecord(record domain.UndoRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal undo record: %w", err)
+	}
+
+	insertSQL := `
+	INSERT OR REPLACE INTO change_set_undo_records (id, change_set_id, data, applied_at)
+	VALUES (?, ?, ?, ?)
+	`
+
+	if _, err := r.db.Exec(insertSQL, record.ID, record.ChangeSetID, string(data), record.AppliedAt); err != nil {
+		return fmt.Errorf("failed to save undo record: %w", err)
+	}
+
+	return nil
+}
+
+func (r *SQLiteChangeSetRepository) GetUndoRecord(id string) (domain.UndoRecord, error) {
+	var data string
+	err := r.db.QueryRow("SELECT data FROM change_set_undo_records WHERE id = ?", id).Scan(&data)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.UndoRecord{}, domain.ErrUndoRecordNotFound
+		}
+		return domain.UndoRecord{}, fmt.Errorf("failed to get undo record by ID: %w", err)
+	}
+
+	var record domain.UndoRecord
+	if err := json.Unmarshal([]byte(data), &record); err != nil {
+		return domain.UndoRecord{}, fmt.Errorf("failed to unmarshal undo record: %w", err)
+	}
+
+	return record, nil
+}
+
+func (r *SQLiteChangeSetRepository) ListUndoRecords(changeSetID string) ([]domain.UndoRecord, error) {
+	query := `
+	SELECT data
+	FROM change_set_undo_records
+	WHERE change_set_id = ?
+	ORDER BY applied_at DESC
+	`
+
+	rows, err := r.db.Query(query, changeSetID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list undo records: %w", err)
+	}
+	defer rows.Close()
+
+	var records []domain.UndoRecord
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("failed to scan undo record: %w", err)
+		}
+
+		var record domain.UndoRecord
+		if err := json.Unmarshal([]byte(data), &record); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal undo record: %w", err)
+		}
+		records = append(records, record)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating undo records: %w", err)
+	}
+
+	return records, nil
+}
+
+func (r *SQLiteChangeSetRepository) Close() error {
+	return r.db.Close()
+}
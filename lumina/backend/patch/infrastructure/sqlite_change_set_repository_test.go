@@ -0,0 +1,109 @@
+package infrastructure_test
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"lumina/backend/patch/domain"
+	"lumina/backend/patch/infrastructure"
+)
+
+func TestSQLiteChangeSetRepository_SaveAndRetrieve(t *testing.T) {
+	// Given a temporary database
+	dbFile := "test_change_sets.db"
+	defer cleanupDatabase(dbFile)
+
+	repo, err := infrastructure.NewSQLiteChangeSetRepository(dbFile)
+	require.NoError(t, err)
+	defer repo.Close()
+
+	// When saving a change set
+	changeSet := domain.ChangeSet{
+		ID:           "cs-1",
+		MessageIndex: 3,
+		Files: []domain.FileChange{{
+			Path: "main.go",
+			Kind: domain.FileChangeModify,
+			Hunks: []domain.Hunk{{
+				OldStart: 1, OldLines: 1, NewStart: 1, NewLines: 1,
+				Lines: []domain.DiffLine{
+					{Kind: domain.DiffLineRemove, Text: "a"},
+					{Kind: domain.DiffLineAdd, Text: "b"},
+				},
+				Status: domain.HunkPending,
+			}},
+		}},
+		CreatedAt: time.Now(),
+	}
+	require.NoError(t, repo.Save(changeSet))
+
+	// Then it should be retrievable with its hunks
+	retrieved, err := repo.GetByID("cs-1")
+	require.NoError(t, err)
+	assert.Equal(t, 3, retrieved.MessageIndex)
+	assert.Equal(t, changeSet.Files, retrieved.Files)
+
+	// And saving again should update it in place
+	changeSet.Files[0].Hunks[0].Status = domain.HunkApplied
+	require.NoError(t, repo.Save(changeSet))
+
+	all, err := repo.List()
+	require.NoError(t, err)
+	require.Len(t, all, 1)
+	assert.Equal(t, domain.HunkApplied, all[0].Files[0].Hunks[0].Status)
+}
+
+func TestSQLiteChangeSetRepository_NotFound(t *testing.T) {
+	// Given a temporary database
+	dbFile := "test_change_sets_notfound.db"
+	defer cleanupDatabase(dbFile)
+
+	repo, err := infrastructure.NewSQLiteChangeSetRepository(dbFile)
+	require.NoError(t, err)
+	defer repo.Close()
+
+	// When getting missing records
+	_, err = repo.GetByID("missing")
+	_, undoErr := repo.GetUndoRecord("missing")
+
+	// Then it should return not found errors
+	assert.Equal(t, domain.ErrChangeSetNotFound, err)
+	assert.Equal(t, domain.ErrUndoRecordNotFound, undoErr)
+}
+
+func TestSQLiteChangeSetRepository_UndoRecords(t *testing.T) {
+	// Given a temporary database
+	dbFile := "test_change_sets_undo.db"
+	defer cleanupDatabase(dbFile)
+
+	repo, err := infrastructure.NewSQLiteChangeSetRepository(dbFile)
+	require.NoError(t, err)
+	defer repo.Close()
+
+	// When saving undo records for two change sets
+	record := domain.UndoRecord{
+		ID:          "undo-1",
+		ChangeSetID: "cs-1",
+		Hunks:       []domain.HunkRef{{Path: "main.go", Index: 0}},
+		Files: []domain.FileSnapshot{{
+			Path: "main.go", ExistedBefore: true, Before: "a\n", ExistsAfter: true, After: "b\n",
+		}},
+		AppliedAt: time.Now(),
+	}
+	require.NoError(t, repo.SaveUndoRecord(record))
+	require.NoError(t, repo.SaveUndoRecord(domain.UndoRecord{ID: "undo-2", ChangeSetID: "cs-2", AppliedAt: time.Now()}))
+
+	// Then they should be listed per change set
+	records, err := repo.ListUndoRecords("cs-1")
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, record.Files, records[0].Files)
+	assert.Nil(t, records[0].UndoneAt)
+}
+
+func cleanupDatabase(dbFile string) {
+	os.Remove(dbFile)
+}
@@ -0,0 +1,71 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultChunkLines is the number of lines per indexed chunk
+const DefaultChunkLines = 40
+
+// SourceFile is a text file of the project to be indexed
+type SourceFile struct {
+	Path    string
+	Content string
+}
+
+// SourceStamp tells whether a source file changed since it was indexed
+// without reading it
+type SourceStamp struct {
+	Path    string
+	Size    int64
+	ModTime int64 // Unix nanoseconds
+}
+
+// Chunk is a contiguous range of lines of a source file
+type Chunk struct {
+	ID        string `json:"id"`
+	Path      string `json:"path"`
+	StartLine int    `json:"startLine"` // 1-based, inclusive
+	EndLine   int    `json:"endLine"`   // 1-based, inclusive
+	Content   string `json:"content"`
+}
+
+// ScoredChunk is a chunk with its relevance to a query
+type ScoredChunk struct {
+	Chunk
+	Score float64 `json:"score"`
+}
+
+// SplitIntoChunks cuts a file into chunks of at most linesPerChunk lines,
+// skipping chunks that contain only whitespace
+func SplitIntoChunks(file SourceFile, linesPerChunk int) []Chunk {
+	if linesPerChunk <= 0 {
+		linesPerChunk = DefaultChunkLines
+	}
+
+	lines := strings.Split(strings.TrimRight(file.Content, "\n"), "\n")
+
+	var chunks []Chunk
+	for start := 0; start < len(lines); start += linesPerChunk {
+		end := start + linesPerChunk
+		if end > len(lines) {
+			end = len(lines)
+		}
+
+		content := strings.Join(lines[start:end], "\n")
+		if strings.TrimSpace(content) == "" {
+			continue
+		}
+
+		chunks = append(chunks, Chunk{
+			ID:        fmt.Sprintf("%s#%d", file.Path, start+1),
+			Path:      file.Path,
+			StartLine: start + 1,
+			EndLine:   end,
+			Content:   content,
+		})
+	}
+
+	return chunks
+}
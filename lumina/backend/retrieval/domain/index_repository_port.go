@@ -0,0 +1,45 @@
+package domain
+
+// IndexedChunk is a chunk together with the term statistics BM25 needs
+type IndexedChunk struct {
+	Chunk
+	TermFrequencies map[string]int
+	Length          int // Number of terms in the chunk
+}
+
+// IndexedFile is a source file as it was indexed, with its chunks
+type IndexedFile struct {
+	SourceStamp
+	Chunks []IndexedChunk
+}
+
+// Posting records how often a term occurs in a chunk
+type Posting struct {
+	Term        string
+	ChunkID     string
+	Frequency   int
+	ChunkLength int
+}
+
+// IndexStats describes the whole index
+type IndexStats struct {
+	ChunkCount    int     `json:"chunkCount"`
+	FileCount     int     `json:"fileCount"`
+	AverageLength float64 `json:"averageLength"`
+}
+
+// IndexRepository stores the lexical index of one project
+type IndexRepository interface {
+	// ReplaceAll swaps the whole index for the given files
+	ReplaceAll(files []IndexedFile) error
+	// UpdateFiles drops the removed paths and replaces the chunks of the
+	// given files, keeping the rest of the index
+	UpdateFiles(removed []string, files []IndexedFile) error
+	// Files returns the stamps of the indexed files
+	Files() ([]SourceStamp, error)
+	Stats() (IndexStats, error)
+	// Postings returns every posting for any of the terms
+	Postings(terms []string) ([]Posting, error)
+	GetChunks(ids []string) ([]Chunk, error)
+	Close() error
+}
@@ -0,0 +1,224 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// BM25 parameters; the usual defaults for short documents
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// IndexService builds the lexical index of a project and ranks chunks
+// against free-text questions with BM25
+type IndexService struct {
+	reader        SourceReader
+	repository    IndexRepository
+	linesPerChunk int
+}
+
+func NewIndexService(reader SourceReader, repository IndexRepository) *IndexService {
+	return &IndexService{
+		reader:        reader,
+		repository:    repository,
+		linesPerChunk: DefaultChunkLines,
+	}
+}
+
+// Rebuild re-reads every source file and replaces the stored index
+func (s *IndexService) Rebuild() (IndexStats, error) {
+	stamps, err := s.reader.ListSources()
+	if err != nil {
+		return IndexStats{}, fmt.Errorf("failed to read sources: %w", err)
+	}
+
+	if err := s.repository.ReplaceAll(s.indexFiles(stamps)); err != nil {
+		return IndexStats{}, fmt.Errorf("failed to store index: %w", err)
+	}
+
+	return s.repository.Stats()
+}
+
+// Refresh re-indexes only the files added, changed or removed since they
+// were indexed, going by their size and modification time
+func (s *IndexService) Refresh() error {
+	stamps, err := s.reader.ListSources()
+	if err != nil {
+		return fmt.Errorf("failed to read sources: %w", err)
+	}
+
+	indexed, err := s.repository.Files()
+	if err != nil {
+		return fmt.Errorf("failed to read indexed files: %w", err)
+	}
+
+	known := make(map[string]SourceStamp, len(indexed))
+	for _, stamp := range indexed {
+		known[stamp.Path] = stamp
+	}
+
+	var changed []SourceStamp
+	for _, stamp := range stamps {
+		if previous, ok := known[stamp.Path]; !ok || previous != stamp {
+			changed = append(changed, stamp)
+		}
+		delete(known, stamp.Path)
+	}
+
+	removed := make([]string, 0, len(known))
+	for path := range known {
+		removed = append(removed, path)
+	}
+	sort.Strings(removed)
+
+	if len(changed) == 0 && len(removed) == 0 {
+		return nil
+	}
+
+	if err := s.repository.UpdateFiles(removed, s.indexFiles(changed)); err != nil {
+		return fmt.Errorf("failed to store index: %w", err)
+	}
+	return nil
+}
+
+// indexFiles reads and chunks the stamped files. A file that can't be read
+// is left out and tried again by the next refresh.
+func (s *IndexService) indexFiles(stamps []SourceStamp) []IndexedFile {
+	files := make([]IndexedFile, 0, len(stamps))
+	for _, stamp := range stamps {
+		file, err := s.reader.ReadSource(stamp.Path)
+		if err != nil {
+			continue
+		}
+
+		var chunks []IndexedChunk
+		for _, chunk := range SplitIntoChunks(file, s.linesPerChunk) {
+			// Paths are searchable too, so "tool repository" finds tool/infrastructure
+			terms := append(Tokenize(chunk.Path), Tokenize(chunk.Content)...)
+			if len(terms) == 0 {
+				continue
+			}
+
+			frequencies := make(map[string]int)
+			for _, term := range terms {
+				frequencies[term]++
+			}
+
+			chunks = append(chunks, IndexedChunk{
+				Chunk:           chunk,
+				TermFrequencies: frequencies,
+				Length:          len(terms),
+			})
+		}
+
+		files = append(files, IndexedFile{SourceStamp: stamp, Chunks: chunks})
+	}
+	return files
+}
+
+// Search returns the limit chunks that best match the query, best first.
+// Files changed since the last search are re-indexed first.
+func (s *IndexService) Search(query string, limit int) ([]ScoredChunk, error) {
+	if err := s.Refresh(); err != nil {
+		return nil, err
+	}
+
+	stats, err := s.repository.Stats()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read index stats: %w", err)
+	}
+	if stats.ChunkCount == 0 {
+		return nil, nil
+	}
+
+	terms := uniqueTerms(Tokenize(query))
+	if len(terms) == 0 {
+		return nil, nil
+	}
+
+	postings, err := s.repository.Postings(terms)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read postings: %w", err)
+	}
+
+	scores := scoreBM25(postings, stats)
+
+	ids := make([]string, 0, len(scores))
+	for id := range scores {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		if scores[ids[i]] != scores[ids[j]] {
+			return scores[ids[i]] > scores[ids[j]]
+		}
+		return ids[i] < ids[j]
+	})
+	if limit > 0 && len(ids) > limit {
+		ids = ids[:limit]
+	}
+
+	chunks, err := s.repository.GetChunks(ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read chunks: %w", err)
+	}
+
+	byID := make(map[string]Chunk, len(chunks))
+	for _, chunk := range chunks {
+		byID[chunk.ID] = chunk
+	}
+
+	results := make([]ScoredChunk, 0, len(ids))
+	for _, id := range ids {
+		if chunk, ok := byID[id]; ok {
+			results = append(results, ScoredChunk{Chunk: chunk, Score: scores[id]})
+		}
+	}
+
+	return results, nil
+}
+
+// Stats describes the stored index
+func (s *IndexService) Stats() (IndexStats, error) {
+	return s.repository.Stats()
+}
+
+// scoreBM25 sums the BM25 contribution of every matching term per chunk
+func scoreBM25(postings []Posting, stats IndexStats) map[string]float64 {
+	documentFrequency := make(map[string]int)
+	for _, posting := range postings {
+		documentFrequency[posting.Term]++
+	}
+
+	averageLength := stats.AverageLength
+	if averageLength <= 0 {
+		averageLength = 1
+	}
+
+	n := float64(stats.ChunkCount)
+	scores := make(map[string]float64)
+	for _, posting := range postings {
+		df := float64(documentFrequency[posting.Term])
+		idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+
+		tf := float64(posting.Frequency)
+		norm := bm25K1 * (1 - bm25B + bm25B*float64(posting.ChunkLength)/averageLength)
+		scores[posting.ChunkID] += idf * tf * (bm25K1 + 1) / (tf + norm)
+	}
+
+	return scores
+}
+
+func uniqueTerms(terms []string) []string {
+	seen := make(map[string]bool, len(terms))
+	var unique []string
+	for _, term := range terms {
+		if !seen[term] {
+			seen[term] = true
+			unique = append(unique, term)
+		}
+	}
+	return unique
+}
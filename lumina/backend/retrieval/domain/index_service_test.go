@@ -0,0 +1,225 @@
+package domain_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"lumina/backend/retrieval/domain"
+)
+
+// MockSourceReader returns a fixed set of files, stamped by their length
+type MockSourceReader struct {
+	files     []domain.SourceFile
+	err       error
+	readCalls int
+}
+
+func (m *MockSourceReader) ListSources() ([]domain.SourceStamp, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	stamps := make([]domain.SourceStamp, 0, len(m.files))
+	for _, file := range m.files {
+		stamps = append(stamps, domain.SourceStamp{Path: file.Path, Size: int64(len(file.Content))})
+	}
+	return stamps, nil
+}
+
+func (m *MockSourceReader) ReadSource(path string) (domain.SourceFile, error) {
+	m.readCalls++
+	for _, file := range m.files {
+		if file.Path == path {
+			return file, nil
+		}
+	}
+	return domain.SourceFile{}, errors.New("no such file")
+}
+
+// MockIndexRepository keeps the index in memory
+type MockIndexRepository struct {
+	files  []domain.SourceStamp
+	chunks []domain.IndexedChunk
+}
+
+func (m *MockIndexRepository) ReplaceAll(files []domain.IndexedFile) error {
+	m.files, m.chunks = nil, nil
+	return m.UpdateFiles(nil, files)
+}
+
+func (m *MockIndexRepository) UpdateFiles(removed []string, files []domain.IndexedFile) error {
+	dropped := make(map[string]bool)
+	for _, path := range removed {
+		dropped[path] = true
+	}
+	for _, file := range files {
+		dropped[file.Path] = true
+	}
+
+	var keptFiles []domain.SourceStamp
+	for _, stamp := range m.files {
+		if !dropped[stamp.Path] {
+			keptFiles = append(keptFiles, stamp)
+		}
+	}
+	var keptChunks []domain.IndexedChunk
+	for _, chunk := range m.chunks {
+		if !dropped[chunk.Path] {
+			keptChunks = append(keptChunks, chunk)
+		}
+	}
+
+	for _, file := range files {
+		keptFiles = append(keptFiles, file.SourceStamp)
+		keptChunks = append(keptChunks, file.Chunks...)
+	}
+	m.files, m.chunks = keptFiles, keptChunks
+	return nil
+}
+
+func (m *MockIndexRepository) Files() ([]domain.SourceStamp, error) {
+	return m.files, nil
+}
+
+func (m *MockIndexRepository) Stats() (domain.IndexStats, error) {
+	stats := domain.IndexStats{ChunkCount: len(m.chunks)}
+	files := make(map[string]bool)
+	total := 0
+	for _, chunk := range m.chunks {
+		files[chunk.Path] = true
+		total += chunk.Length
+	}
+	stats.FileCount = len(files)
+	if len(m.chunks) > 0 {
+		stats.AverageLength = float64(total) / float64(len(m.chunks))
+	}
+	return stats, nil
+}
+
+func (m *MockIndexRepository) Postings(terms []string) ([]domain.Posting, error) {
+	var postings []domain.Posting
+	for _, term := range terms {
+		for _, chunk := range m.chunks {
+			if frequency, ok := chunk.TermFrequencies[term]; ok {
+				postings = append(postings, domain.Posting{
+					Term: term, ChunkID: chunk.ID, Frequency: frequency, ChunkLength: chunk.Length,
+				})
+			}
+		}
+	}
+	return postings, nil
+}
+
+func (m *MockIndexRepository) GetChunks(ids []string) ([]domain.Chunk, error) {
+	var chunks []domain.Chunk
+	for _, id := range ids {
+		for _, chunk := range m.chunks {
+			if chunk.ID == id {
+				chunks = append(chunks, chunk.Chunk)
+			}
+		}
+	}
+	return chunks, nil
+}
+
+func (m *MockIndexRepository) Close() error {
+	return nil
+}
+
+var projectSources = []domain.SourceFile{
+	{Path: "backend/chat/domain/chat.go", Content: "type Chat struct {}\nfunc (c *Chat) SendMessage(message string) {}\n"},
+	{Path: "backend/tool/infrastructure/sqlite_tool_repository.go", Content: "func (r *SQLiteToolRepository) Save(tool Tool) error {\n\tINSERT OR REPLACE INTO tools\n}\n"},
+	{Path: "README.md", Content: "# Lumina\n\nA moldable development environment.\n"},
+}
+
+func TestIndexService_RanksRelevantChunkFirst(t *testing.T) {
+	// Given an indexed project
+	service := domain.NewIndexService(&MockSourceReader{files: projectSources}, &MockIndexRepository{})
+	stats, err := service.Rebuild()
+	require.NoError(t, err)
+	assert.Equal(t, 3, stats.FileCount)
+
+	// When searching for how tools are saved
+	results, err := service.Search("How does the tool repository save tools?", 2)
+
+	// Then the tool repository should come first with a positive score
+	require.NoError(t, err)
+	require.NotEmpty(t, results)
+	assert.Equal(t, "backend/tool/infrastructure/sqlite_tool_repository.go", results[0].Path)
+	assert.Positive(t, results[0].Score)
+	assert.LessOrEqual(t, len(results), 2)
+	for i := 1; i < len(results); i++ {
+		assert.GreaterOrEqual(t, results[i-1].Score, results[i].Score)
+	}
+}
+
+func TestIndexService_BuildsIndexOnFirstSearch(t *testing.T) {
+	// Given an empty index
+	reader := &MockSourceReader{files: projectSources}
+	service := domain.NewIndexService(reader, &MockIndexRepository{})
+
+	// When searching
+	results, err := service.Search("chat message", 5)
+
+	// Then every file should be indexed once and used
+	require.NoError(t, err)
+	assert.Equal(t, 3, reader.readCalls)
+	require.NotEmpty(t, results)
+	assert.Equal(t, "backend/chat/domain/chat.go", results[0].Path)
+
+	_, err = service.Search("chat", 5)
+	require.NoError(t, err)
+	assert.Equal(t, 3, reader.readCalls)
+}
+
+func TestIndexService_SearchReindexesChangedFiles(t *testing.T) {
+	// Given an indexed project
+	reader := &MockSourceReader{files: append([]domain.SourceFile{}, projectSources...)}
+	service := domain.NewIndexService(reader, &MockIndexRepository{})
+	_, err := service.Rebuild()
+	require.NoError(t, err)
+	reader.readCalls = 0
+
+	// When the README changes and the chat file is removed
+	reader.files = []domain.SourceFile{
+		projectSources[1],
+		{Path: "README.md", Content: "# Lumina\n\nNow with pipelines and triggers.\n"},
+	}
+	pipelines, err := service.Search("pipelines", 5)
+	require.NoError(t, err)
+	chat, err := service.Search("chat message", 5)
+	require.NoError(t, err)
+
+	// Then only the README is read again and the removed file is gone
+	assert.Equal(t, 1, reader.readCalls)
+	require.Len(t, pipelines, 1)
+	assert.Equal(t, "README.md", pipelines[0].Path)
+	assert.Empty(t, chat)
+}
+
+func TestIndexService_NoMatches(t *testing.T) {
+	// Given an indexed project
+	service := domain.NewIndexService(&MockSourceReader{files: projectSources}, &MockIndexRepository{})
+
+	// When searching for unrelated or empty queries
+	unrelated, err := service.Search("kubernetes", 5)
+	require.NoError(t, err)
+	empty, err := service.Search("the", 5)
+	require.NoError(t, err)
+
+	// Then nothing should be returned
+	assert.Empty(t, unrelated)
+	assert.Empty(t, empty)
+}
+
+func TestIndexService_RebuildReaderError(t *testing.T) {
+	// Given a reader that fails
+	service := domain.NewIndexService(&MockSourceReader{err: errors.New("permission denied")}, &MockIndexRepository{})
+
+	// When rebuilding
+	_, err := service.Rebuild()
+
+	// Then the error should be returned
+	assert.EqualError(t, err, "failed to read sources: permission denied")
+}
@@ -0,0 +1,9 @@
+package domain
+
+// SourceReader lists the text files of the project that should be searchable
+type SourceReader interface {
+	// ListSources stamps every searchable file without reading it
+	ListSources() ([]SourceStamp, error)
+	// ReadSource reads a listed file; binary files have no content
+	ReadSource(path string) (SourceFile, error)
+}
@@ -0,0 +1,74 @@
+package domain
+
+import (
+	"strings"
+	"unicode"
+)
+
+// stopWords are too common in questions and code to help ranking
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true, "be": true,
+	"by": true, "do": true, "does": true, "for": true, "from": true, "how": true, "i": true,
+	"if": true, "in": true, "is": true, "it": true, "of": true, "on": true, "or": true,
+	"the": true, "this": true, "to": true, "what": true, "when": true, "where": true,
+	"which": true, "why": true, "with": true,
+}
+
+// Tokenize splits text into lowercase search terms. Identifiers are kept
+// whole and also split into their camelCase and snake_case parts, so
+// "SendChatMessage" matches questions about "chat messages".
+func Tokenize(text string) []string {
+	var tokens []string
+
+	for _, word := range strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_'
+	}) {
+		parts := splitIdentifier(word)
+		if len(parts) > 1 {
+			tokens = appendTerm(tokens, strings.ToLower(strings.ReplaceAll(word, "_", "")))
+		}
+		for _, part := range parts {
+			tokens = appendTerm(tokens, strings.ToLower(part))
+		}
+	}
+
+	return tokens
+}
+
+func appendTerm(tokens []string, term string) []string {
+	if len(term) < 2 || stopWords[term] {
+		return tokens
+	}
+	return append(tokens, stem(term))
+}
+
+// stem strips a plural "s" so "messages" and "message" share a term
+func stem(term string) string {
+	if len(term) > 3 && strings.HasSuffix(term, "s") && !strings.HasSuffix(term, "ss") {
+		return term[:len(term)-1]
+	}
+	return term
+}
+
+// splitIdentifier splits on underscores and lower-to-upper case transitions
+func splitIdentifier(word string) []string {
+	var parts []string
+
+	for _, segment := range strings.Split(word, "_") {
+		runes := []rune(segment)
+		start := 0
+		for i := 1; i < len(runes); i++ {
+			lowerToUpper := unicode.IsLower(runes[i-1]) && unicode.IsUpper(runes[i])
+			acronymEnd := i+1 < len(runes) && unicode.IsUpper(runes[i-1]) && unicode.IsUpper(runes[i]) && unicode.IsLower(runes[i+1])
+			if lowerToUpper || acronymEnd {
+				parts = append(parts, string(runes[start:i]))
+				start = i
+			}
+		}
+		if start < len(runes) {
+			parts = append(parts, string(runes[start:]))
+		}
+	}
+
+	return parts
+}
@@ -0,0 +1,48 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"lumina/backend/retrieval/domain"
+)
+
+func TestTokenize_SplitsIdentifiers(t *testing.T) {
+	// When tokenizing code with camelCase and snake_case identifiers
+	tokens := domain.Tokenize("func SendChatMessage(user_id string) HTTPServer")
+
+	// Then identifiers should be kept whole and split into parts
+	assert.Contains(t, tokens, "sendchatmessage")
+	assert.Contains(t, tokens, "send")
+	assert.Contains(t, tokens, "chat")
+	assert.Contains(t, tokens, "message")
+	assert.Contains(t, tokens, "userid")
+	assert.Contains(t, tokens, "user")
+	assert.Contains(t, tokens, "http")
+	assert.Contains(t, tokens, "server")
+}
+
+func TestTokenize_DropsStopWordsAndPlurals(t *testing.T) {
+	// When tokenizing a question
+	tokens := domain.Tokenize("Where are the chat messages stored?")
+
+	// Then stop words should be dropped and plurals folded
+	assert.Equal(t, []string{"chat", "message", "stored"}, tokens)
+}
+
+func TestSplitIntoChunks(t *testing.T) {
+	// Given a file of five lines
+	file := domain.SourceFile{Path: "a.go", Content: "1\n2\n3\n4\n5\n"}
+
+	// When splitting it into chunks of two lines
+	chunks := domain.SplitIntoChunks(file, 2)
+
+	// Then it should produce three chunks with line ranges
+	assert.Len(t, chunks, 3)
+	assert.Equal(t, "a.go#1", chunks[0].ID)
+	assert.Equal(t, 1, chunks[0].StartLine)
+	assert.Equal(t, 2, chunks[0].EndLine)
+	assert.Equal(t, "1\n2", chunks[0].Content)
+	assert.Equal(t, 5, chunks[2].StartLine)
+	assert.Equal(t, 5, chunks[2].EndLine)
+}
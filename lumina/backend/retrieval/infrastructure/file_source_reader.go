@@ -0,0 +1,114 @@
+package infrastructure
+
+import (
+	"bytes"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"lumina/backend/retrieval/domain"
+)
+
+// maxSourceFileSize skips generated bundles, lock files and the like
+const maxSourceFileSize = 256 * 1024
+
+// skippedDirs are never worth searching
+var skippedDirs = map[string]bool{
+	".git":         true,
+	"node_modules": true,
+	"dist":         true,
+	"vendor":       true,
+	"bin":          true,
+	".lumina":      true,
+}
+
+// skippedFiles would only duplicate or pollute the index
+var skippedFiles = map[string]bool{
+	"repomix-output.xml": true,
+	"package-lock.json":  true,
+	"go.sum":             true,
+	".env":               true,
+}
+
+// FileSourceReader implements the SourceReader port by walking the project tree
+type FileSourceReader struct {
+	root string
+}
+
+// NewFileSourceReader creates a reader for the project rooted at root
+func NewFileSourceReader(root string) *FileSourceReader {
+	return &FileSourceReader{root: root}
+}
+
+// ListSources stamps every text file of the project with a slash-separated relative path
+func (r *FileSourceReader) ListSources() ([]domain.SourceStamp, error) {
+	var stamps []domain.SourceStamp
+
+	err := filepath.WalkDir(r.root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			// Skip entries we can't read (permissions, etc.)
+			if entry != nil && entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if entry.IsDir() {
+			if path != r.root && (skippedDirs[entry.Name()] || strings.HasPrefix(entry.Name(), ".")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if skippedFiles[entry.Name()] || !entry.Type().IsRegular() {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil || info.Size() > maxSourceFileSize {
+			return nil
+		}
+
+		relative, err := filepath.Rel(r.root, path)
+		if err != nil {
+			return nil
+		}
+
+		stamps = append(stamps, domain.SourceStamp{
+			Path:    filepath.ToSlash(relative),
+			Size:    info.Size(),
+			ModTime: info.ModTime().UnixNano(),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return stamps, nil
+}
+
+// ReadSource reads a file listed by ListSources
+func (r *FileSourceReader) ReadSource(path string) (domain.SourceFile, error) {
+	content, err := os.ReadFile(filepath.Join(r.root, filepath.FromSlash(path)))
+	if err != nil {
+		return domain.SourceFile{}, err
+	}
+
+	// Binary files are kept without content so they aren't read again
+	if isBinary(content) {
+		return domain.SourceFile{Path: path}, nil
+	}
+
+	return domain.SourceFile{Path: path, Content: string(content)}, nil
+}
+
+// isBinary uses the same heuristic as git: a NUL byte near the start
+func isBinary(content []byte) bool {
+	head := content
+	if len(head) > 8000 {
+		head = head[:8000]
+	}
+	return bytes.IndexByte(head, 0) >= 0
+}
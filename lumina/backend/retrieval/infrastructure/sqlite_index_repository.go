@@ -0,0 +1,310 @@
+package infrastructure
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"lumina/backend/retrieval/domain"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// SQLiteIndexRepository stores the lexical index of one project. Several
+// projects can share a database; rows are keyed by the project root.
+type SQLiteIndexRepository struct {
+	db          *sql.DB
+	projectRoot string
+}
+
+func NewSQLiteIndexRepository(dbPath string, projectRoot string) (*SQLiteIndexRepository, error) {
+	db, err := sql.Open("sqlite3", dbPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	// Create index tables if they don't exist
+	createTableSQL := `
+	CREATE TABLE IF NOT EXISTS retrieval_chunks (
+		project_root TEXT NOT NULL,
+		id TEXT NOT NULL,
+		path TEXT NOT NULL,
+		start_line INTEGER NOT NULL,
+		end_line INTEGER NOT NULL,
+		content TEXT NOT NULL,
+		length INTEGER NOT NULL,
+		PRIMARY KEY (project_root, id)
+	);
+
+	CREATE TABLE IF NOT EXISTS retrieval_postings (
+		project_root TEXT NOT NULL,
+		term TEXT NOT NULL,
+		chunk_id TEXT NOT NULL,
+		frequency INTEGER NOT NULL
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_retrieval_postings_term
+		ON retrieval_postings(project_root, term);
+
+	CREATE INDEX IF NOT EXISTS idx_retrieval_postings_chunk
+		ON retrieval_postings(project_root, chunk_id);
+
+	CREATE TABLE IF NOT EXISTS retrieval_files (
+		project_root TEXT NOT NULL,
+		path TEXT NOT NULL,
+		size INTEGER NOT NULL,
+		mod_time INTEGER NOT NULL,
+		PRIMARY KEY (project_root, path)
+	);
+	`
+
+	if _, err := db.Exec(createTableSQL); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create retrieval tables: %w", err)
+	}
+
+	return &SQLiteIndexRepository{db: db, projectRoot: projectRoot}, nil
+}
+
+func (r *SQLiteIndexRepository) ReplaceAll(files []domain.IndexedFile) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM retrieval_postings WHERE project_root = ?", r.projectRoot); err != nil {
+		return fmt.Errorf("failed to clear postings: %w", err)
+	}
+	if _, err := tx.Exec("DELETE FROM retrieval_chunks WHERE project_root = ?", r.projectRoot); err != nil {
+		return fmt.Errorf("failed to clear chunks: %w", err)
+	}
+	if _, err := tx.Exec("DELETE FROM retrieval_files WHERE project_root = ?", r.projectRoot); err != nil {
+		return fmt.Errorf("failed to clear files: %w", err)
+	}
+
+	if err := r.insertFiles(tx, files); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (r *SQLiteIndexRepository) UpdateFiles(removed []string, files []domain.IndexedFile) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	paths := append([]string{}, removed...)
+	for _, file := range files {
+		paths = append(paths, file.Path)
+	}
+
+	for _, path := range paths {
+		_, err := tx.Exec(`
+		DELETE FROM retrieval_postings
+		WHERE project_root = ? AND chunk_id IN (
+			SELECT id FROM retrieval_chunks WHERE project_root = ? AND path = ?
+		)`, r.projectRoot, r.projectRoot, path)
+		if err != nil {
+			return fmt.Errorf("failed to clear postings: %w", err)
+		}
+		if _, err := tx.Exec("DELETE FROM retrieval_chunks WHERE project_root = ? AND path = ?", r.projectRoot, path); err != nil {
+			return fmt.Errorf("failed to clear chunks: %w", err)
+		}
+		if _, err := tx.Exec("DELETE FROM retrieval_files WHERE project_root = ? AND path = ?", r.projectRoot, path); err != nil {
+			return fmt.Errorf("failed to clear files: %w", err)
+		}
+	}
+
+	if err := r.insertFiles(tx, files); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (r *SQLiteIndexRepository) insertFiles(tx *sql.Tx, files []domain.IndexedFile) error {
+	fileStmt, err := tx.Prepare(`
+	INSERT INTO retrieval_files (project_root, path, size, mod_time)
+	VALUES (?, ?, ?, ?)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare statement: %w", err)
+	}
+	defer fileStmt.Close()
+
+	chunkStmt, err := tx.Prepare(`
+	INSERT INTO retrieval_chunks (project_root, id, path, start_line, end_line, content, length)
+	VALUES (?, ?, ?, ?, ?, ?, ?)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare statement: %w", err)
+	}
+	defer chunkStmt.Close()
+
+	postingStmt, err := tx.Prepare(`
+	INSERT INTO retrieval_postings (project_root, term, chunk_id, frequency)
+	VALUES (?, ?, ?, ?)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare statement: %w", err)
+	}
+	defer postingStmt.Close()
+
+	for _, file := range files {
+		if _, err := fileStmt.Exec(r.projectRoot, file.Path, file.Size, file.ModTime); err != nil {
+			return fmt.Errorf("failed to insert file: %w", err)
+		}
+
+		for _, chunk := range file.Chunks {
+			_, err := chunkStmt.Exec(r.projectRoot, chunk.ID, chunk.Path, chunk.StartLine, chunk.EndLine, chunk.Content, chunk.Length)
+			if err != nil {
+				return fmt.Errorf("failed to insert chunk: %w", err)
+			}
+
+			for term, frequency := range chunk.TermFrequencies {
+				if _, err := postingStmt.Exec(r.projectRoot, term, chunk.ID, frequency); err != nil {
+					return fmt.Errorf("failed to insert posting: %w", err)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func (r *SQLiteIndexRepository) Files() ([]domain.SourceStamp, error) {
+	rows, err := r.db.Query("SELECT path, size, mod_time FROM retrieval_files WHERE project_root = ?", r.projectRoot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query files: %w", err)
+	}
+	defer rows.Close()
+
+	var stamps []domain.SourceStamp
+	for rows.Next() {
+		var stamp domain.SourceStamp
+		if err := rows.Scan(&stamp.Path, &stamp.Size, &stamp.ModTime); err != nil {
+			return nil, fmt.Errorf("failed to scan file: %w", err)
+		}
+		stamps = append(stamps, stamp)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating files: %w", err)
+	}
+
+	return stamps, nil
+}
+
+func (r *SQLiteIndexRepository) Stats() (domain.IndexStats, error) {
+	query := `
+	SELECT COUNT(*), COUNT(DISTINCT path), COALESCE(AVG(length), 0)
+	FROM retrieval_chunks
+	WHERE project_root = ?
+	`
+
+	var stats domain.IndexStats
+	err := r.db.QueryRow(query, r.projectRoot).Scan(&stats.ChunkCount, &stats.FileCount, &stats.AverageLength)
+	if err != nil {
+		return domain.IndexStats{}, fmt.Errorf("failed to read index stats: %w", err)
+	}
+
+	return stats, nil
+}
+
+func (r *SQLiteIndexRepository) Postings(terms []string) ([]domain.Posting, error) {
+	if len(terms) == 0 {
+		return nil, nil
+	}
+
+	query := fmt.Sprintf(`
+	SELECT p.term, p.chunk_id, p.frequency, c.length
+	FROM retrieval_postings p
+	JOIN retrieval_chunks c ON c.project_root = p.project_root AND c.id = p.chunk_id
+	WHERE p.project_root = ? AND p.term IN (%s)
+	`, placeholders(len(terms)))
+
+	args := []any{r.projectRoot}
+	for _, term := range terms {
+		args = append(args, term)
+	}
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query postings: %w", err)
+	}
+	defer rows.Close()
+
+	var postings []domain.Posting
+	for rows.Next() {
+		var posting domain.Posting
+		if err := rows.Scan(&posting.Term, &posting.ChunkID, &posting.Frequency, &posting.ChunkLength); err != nil {
+			return nil, fmt.Errorf("failed to scan posting: %w", err)
+		}
+		postings = append(postings, posting)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating postings: %w", err)
+	}
+
+	return postings, nil
+}
+
+func (r *SQLiteIndexRepository) GetChunks(ids []string) ([]domain.Chunk, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	query := fmt.Sprintf(`
+	SELECT id, path, start_line, end_line, content
+	FROM retrieval_chunks
+	WHERE project_root = ? AND id IN (%s)
+	`, placeholders(len(ids)))
+
+	args := []any{r.projectRoot}
+	for _, id := range ids {
+		args = append(args, id)
+	}
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query chunks: %w", err)
+	}
+	defer rows.Close()
+
+	var chunks []domain.Chunk
+	for rows.Next() {
+		var chunk domain.Chunk
+		if err := rows.Scan(&chunk.ID, &chunk.Path, &chunk.StartLine, &chunk.EndLine, &chunk.Content); err != nil {
+			return nil, fmt.Errorf("failed to scan chunk: %w", err)
+		}
+		chunks = append(chunks, chunk)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating chunks: %w", err)
+	}
+
+	return chunks, nil
+}
+
+func (r *SQLiteIndexRepository) Close() error {
+	return r.db.Close()
+}
+
+// placeholders returns "?, ?, ..." for n query arguments
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
@@ -0,0 +1,157 @@
+package infrastructure_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"lumina/backend/retrieval/domain"
+	"lumina/backend/retrieval/infrastructure"
+)
+
+func indexedChunk(id, path string, frequencies map[string]int) domain.IndexedChunk {
+	length := 0
+	for _, frequency := range frequencies {
+		length += frequency
+	}
+	return domain.IndexedChunk{
+		Chunk:           domain.Chunk{ID: id, Path: path, StartLine: 1, EndLine: 10, Content: "content of " + id},
+		TermFrequencies: frequencies,
+		Length:          length,
+	}
+}
+
+func indexedFile(path string, modTime int64, chunks ...domain.IndexedChunk) domain.IndexedFile {
+	return domain.IndexedFile{
+		SourceStamp: domain.SourceStamp{Path: path, Size: 100, ModTime: modTime},
+		Chunks:      chunks,
+	}
+}
+
+func TestSQLiteIndexRepository_ReplaceAllAndQuery(t *testing.T) {
+	// Given a temporary database
+	dbFile := "test_retrieval_index.db"
+	defer cleanupDatabase(dbFile)
+
+	repo, err := infrastructure.NewSQLiteIndexRepository(dbFile, "/project")
+	require.NoError(t, err)
+	defer repo.Close()
+
+	// When storing an index
+	err = repo.ReplaceAll([]domain.IndexedFile{
+		indexedFile("a.go", 1, indexedChunk("a.go#1", "a.go", map[string]int{"chat": 2, "message": 1})),
+		indexedFile("b.go", 1, indexedChunk("b.go#1", "b.go", map[string]int{"tool": 3})),
+	})
+	require.NoError(t, err)
+
+	// Then stats, postings and chunks should be queryable
+	stats, err := repo.Stats()
+	require.NoError(t, err)
+	assert.Equal(t, 2, stats.ChunkCount)
+	assert.Equal(t, 2, stats.FileCount)
+	assert.Equal(t, 3.0, stats.AverageLength)
+
+	postings, err := repo.Postings([]string{"chat", "tool"})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []domain.Posting{
+		{Term: "chat", ChunkID: "a.go#1", Frequency: 2, ChunkLength: 3},
+		{Term: "tool", ChunkID: "b.go#1", Frequency: 3, ChunkLength: 3},
+	}, postings)
+
+	chunks, err := repo.GetChunks([]string{"b.go#1"})
+	require.NoError(t, err)
+	require.Len(t, chunks, 1)
+	assert.Equal(t, "content of b.go#1", chunks[0].Content)
+}
+
+func TestSQLiteIndexRepository_ReplaceAllDropsOldIndex(t *testing.T) {
+	// Given a stored index
+	dbFile := "test_retrieval_replace.db"
+	defer cleanupDatabase(dbFile)
+
+	repo, err := infrastructure.NewSQLiteIndexRepository(dbFile, "/project")
+	require.NoError(t, err)
+	defer repo.Close()
+
+	require.NoError(t, repo.ReplaceAll([]domain.IndexedFile{
+		indexedFile("old.go", 1, indexedChunk("old.go#1", "old.go", map[string]int{"legacy": 1})),
+	}))
+
+	// When replacing it
+	require.NoError(t, repo.ReplaceAll([]domain.IndexedFile{
+		indexedFile("new.go", 1, indexedChunk("new.go#1", "new.go", map[string]int{"fresh": 1})),
+	}))
+
+	// Then only the new chunks should remain
+	postings, err := repo.Postings([]string{"legacy"})
+	require.NoError(t, err)
+	assert.Empty(t, postings)
+
+	stats, err := repo.Stats()
+	require.NoError(t, err)
+	assert.Equal(t, 1, stats.ChunkCount)
+}
+
+func TestSQLiteIndexRepository_UpdateFiles(t *testing.T) {
+	// Given an index of three files
+	dbFile := "test_retrieval_update.db"
+	defer cleanupDatabase(dbFile)
+
+	repo, err := infrastructure.NewSQLiteIndexRepository(dbFile, "/project")
+	require.NoError(t, err)
+	defer repo.Close()
+
+	require.NoError(t, repo.ReplaceAll([]domain.IndexedFile{
+		indexedFile("a.go", 1, indexedChunk("a.go#1", "a.go", map[string]int{"chat": 1})),
+		indexedFile("b.go", 1, indexedChunk("b.go#1", "b.go", map[string]int{"tool": 1})),
+		indexedFile("c.go", 1, indexedChunk("c.go#1", "c.go", map[string]int{"pipeline": 1})),
+	}))
+
+	// When a.go changed and c.go was removed
+	require.NoError(t, repo.UpdateFiles([]string{"c.go"}, []domain.IndexedFile{
+		indexedFile("a.go", 2, indexedChunk("a.go#1", "a.go", map[string]int{"trigger": 1})),
+	}))
+
+	// Then only b.go is untouched
+	postings, err := repo.Postings([]string{"chat", "tool", "pipeline", "trigger"})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []domain.Posting{
+		{Term: "tool", ChunkID: "b.go#1", Frequency: 1, ChunkLength: 1},
+		{Term: "trigger", ChunkID: "a.go#1", Frequency: 1, ChunkLength: 1},
+	}, postings)
+
+	files, err := repo.Files()
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []domain.SourceStamp{
+		{Path: "a.go", Size: 100, ModTime: 2},
+		{Path: "b.go", Size: 100, ModTime: 1},
+	}, files)
+}
+
+func TestSQLiteIndexRepository_ProjectsAreIsolated(t *testing.T) {
+	// Given two projects sharing a database
+	dbFile := "test_retrieval_projects.db"
+	defer cleanupDatabase(dbFile)
+
+	first, err := infrastructure.NewSQLiteIndexRepository(dbFile, "/first")
+	require.NoError(t, err)
+	defer first.Close()
+	second, err := infrastructure.NewSQLiteIndexRepository(dbFile, "/second")
+	require.NoError(t, err)
+	defer second.Close()
+
+	// When only the first project is indexed
+	require.NoError(t, first.ReplaceAll([]domain.IndexedFile{
+		indexedFile("a.go", 1, indexedChunk("a.go#1", "a.go", map[string]int{"chat": 1})),
+	}))
+
+	// Then the second project should still be empty
+	stats, err := second.Stats()
+	require.NoError(t, err)
+	assert.Equal(t, 0, stats.ChunkCount)
+}
+
+func cleanupDatabase(dbFile string) {
+	os.Remove(dbFile)
+}
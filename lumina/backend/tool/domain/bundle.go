@@ -0,0 +1,138 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidToolBundle     = errors.New("invalid tool bundle")
+	ErrInvalidImportStrategy = errors.New("invalid import conflict strategy")
+	ErrEmptyToolBundle       = errors.New("no tools selected for the bundle")
+)
+
+const (
+	// ToolBundleFormat identifies bundle files
+	ToolBundleFormat = "lumina-tool-bundle"
+	// ToolBundleVersion is the version of the bundle layout written by Export
+	ToolBundleVersion = 1
+)
+
+// ToolBundle is a set of tools with their history and examples, for handing
+// tools to another team without sharing a database
+type ToolBundle struct {
+	Format     string        `json:"format"`
+	Version    int           `json:"version"`
+	ExportedAt time.Time     `json:"exported_at"`
+	Tools      []BundledTool `json:"tools"`
+}
+
+// BundledTool is one tool of a bundle
+type BundledTool struct {
+	Tool Tool `json:"tool"`
+	// Revisions are the saved revisions of the tool, oldest first
+	Revisions []ToolRevision `json:"revisions"`
+	Examples  []ToolExample  `json:"examples"`
+}
+
+// NewToolBundle creates a bundle of the current layout
+func NewToolBundle(tools []BundledTool) ToolBundle {
+	return ToolBundle{
+		Format:     ToolBundleFormat,
+		Version:    ToolBundleVersion,
+		ExportedAt: time.Now(),
+		Tools:      tools,
+	}
+}
+
+// Validate checks that the bundle was written by a known layout and that
+// its tools can be saved
+func (b ToolBundle) Validate() error {
+	if b.Format != ToolBundleFormat {
+		return fmt.Errorf("%w: not a tool bundle", ErrInvalidToolBundle)
+	}
+	if b.Version < 1 || b.Version > ToolBundleVersion {
+		return fmt.Errorf("%w: unsupported version %d", ErrInvalidToolBundle, b.Version)
+	}
+
+	for i, bundled := range b.Tools {
+		if strings.TrimSpace(bundled.Tool.Name) == "" || strings.TrimSpace(bundled.Tool.Code) == "" {
+			return fmt.Errorf("%w: tool %d has no name or code", ErrInvalidToolBundle, i+1)
+		}
+		if err := bundled.validate(); err != nil {
+			return fmt.Errorf("%w: %s: %v", ErrInvalidToolBundle, bundled.Tool.Name, err)
+		}
+	}
+	return nil
+}
+
+// validate checks the fields of the tool and its revisions the way saving
+// them in the editor would
+func (b BundledTool) validate() error {
+	if _, err := ParseLanguage(b.Tool.Language); err != nil {
+		return err
+	}
+	if err := ValidateParameters(b.Tool.Parameters); err != nil {
+		return err
+	}
+	if err := ValidateDependencies(b.Tool.Dependencies); err != nil {
+		return err
+	}
+	if b.Tool.Capabilities != nil {
+		if err := b.Tool.Capabilities.Validate(); err != nil {
+			return err
+		}
+	}
+
+	for _, revision := range b.Revisions {
+		if err := ValidateParameters(revision.Parameters); err != nil {
+			return fmt.Errorf("revision %d: %w", revision.Revision, err)
+		}
+		if err := ValidateDependencies(revision.Dependencies); err != nil {
+			return fmt.Errorf("revision %d: %w", revision.Revision, err)
+		}
+	}
+	return nil
+}
+
+// ImportConflictStrategy decides what happens to a bundled tool whose name
+// is taken in the target namespace
+type ImportConflictStrategy string
+
+const (
+	// ImportSkip leaves the existing tool alone
+	ImportSkip ImportConflictStrategy = "skip"
+	// ImportRename imports the tool under a free name
+	ImportRename ImportConflictStrategy = "rename"
+	// ImportOverwrite saves the bundled tool over the existing one as a new
+	// revision and replaces its examples
+	ImportOverwrite ImportConflictStrategy = "overwrite"
+)
+
+// ParseImportConflictStrategy accepts "skip", "rename" and "overwrite"
+func ParseImportConflictStrategy(value string) (ImportConflictStrategy, error) {
+	switch strategy := ImportConflictStrategy(strings.TrimSpace(value)); strategy {
+	case ImportSkip, ImportRename, ImportOverwrite:
+		return strategy, nil
+	}
+	return "", fmt.Errorf("%w: %q", ErrInvalidImportStrategy, value)
+}
+
+// ToolBundleExport reports what Export wrote
+type ToolBundleExport struct {
+	Path  string   `json:"path"`
+	Tools []string `json:"tools"`
+}
+
+// ToolBundleImport reports what Import did with each tool of a bundle
+type ToolBundleImport struct {
+	Namespace string `json:"namespace"`
+	// Imported are the tools created, under their final names
+	Imported []string `json:"imported"`
+	// Renamed maps the bundled name of renamed tools to their new name
+	Renamed     map[string]string `json:"renamed"`
+	Overwritten []string          `json:"overwritten"`
+	Skipped     []string          `json:"skipped"`
+}
@@ -0,0 +1,70 @@
+package domain_test
+
+import (
+	"testing"
+
+	"lumina/backend/tool/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToolBundle_Validate(t *testing.T) {
+	valid := domain.NewToolBundle([]domain.BundledTool{{Tool: domain.NewTool("Greet", "console.log('hi')")}})
+
+	newer := valid
+	newer.Version = domain.ToolBundleVersion + 1
+
+	nameless := domain.NewToolBundle([]domain.BundledTool{{Tool: domain.NewTool(" ", "console.log('hi')")}})
+
+	cobol := domain.NewTool("Greet", "DISPLAY 'hi'")
+	cobol.Language = "cobol"
+	unknownLanguage := domain.NewToolBundle([]domain.BundledTool{{Tool: cobol}})
+
+	untyped := domain.NewTool("Greet", "console.log('hi')")
+	untyped.Parameters = []domain.ToolParameter{{Name: "city", Type: "place"}}
+	badParameter := domain.NewToolBundle([]domain.BundledTool{{Tool: untyped}})
+
+	relative := domain.NewTool("Greet", "console.log('hi')")
+	relative.Capabilities = &domain.ToolCapabilities{ReadPaths: []string{"notes.txt"}}
+	relativePath := domain.NewToolBundle([]domain.BundledTool{{Tool: relative}})
+
+	tests := []struct {
+		name   string
+		bundle domain.ToolBundle
+		valid  bool
+	}{
+		{"current layout", valid, true},
+		{"other format", domain.ToolBundle{Format: "zip", Version: 1}, false},
+		{"newer version", newer, false},
+		{"tool without a name", nameless, false},
+		{"unknown language", unknownLanguage, false},
+		{"invalid parameter", badParameter, false},
+		{"relative capability path", relativePath, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// When
+			err := tt.bundle.Validate()
+
+			// Then
+			if tt.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, domain.ErrInvalidToolBundle)
+			}
+		})
+	}
+}
+
+func TestParseImportConflictStrategy(t *testing.T) {
+	// When
+	strategy, err := domain.ParseImportConflictStrategy(" rename ")
+	_, invalidErr := domain.ParseImportConflictStrategy("merge")
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, domain.ImportRename, strategy)
+	assert.ErrorIs(t, invalidErr, domain.ErrInvalidImportStrategy)
+}
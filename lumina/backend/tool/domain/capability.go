@@ -0,0 +1,241 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidCapabilities      = errors.New("invalid tool capabilities")
+	ErrEscalationNotFound       = errors.New("capability escalation not found")
+	ErrEscalationAlreadyDecided = errors.New("capability escalation already decided")
+)
+
+// CapabilityKind is something a tool may need beyond computing and printing
+type CapabilityKind string
+
+const (
+	CapabilityFileRead  CapabilityKind = "fs-read"
+	CapabilityFileWrite CapabilityKind = "fs-write"
+	CapabilityNetwork   CapabilityKind = "network"
+	// CapabilitySubprocess covers child processes and worker threads
+	CapabilitySubprocess CapabilityKind = "subprocess"
+	// CapabilityUnrestricted lifts every restriction; tools in languages
+	// that can't be restricted need it to run at all
+	CapabilityUnrestricted CapabilityKind = "unrestricted"
+)
+
+// ToolCapabilities is what a tool declares it needs. A tool with declared
+// capabilities runs in a private working directory and is refused anything
+// else. Only TypeScript runs can be held to this, so tools in other
+// languages are refused restricted runs and must be granted Unrestricted.
+type ToolCapabilities struct {
+	// ReadPaths and WritePaths are absolute paths of files or directories;
+	// writing a path implies reading it
+	ReadPaths  []string `json:"read_paths"`
+	WritePaths []string `json:"write_paths"`
+	Network    bool     `json:"network"`
+	Subprocess bool     `json:"subprocess"`
+	// Unrestricted runs the tool without any restriction
+	Unrestricted bool `json:"unrestricted,omitempty"`
+}
+
+// Validate checks that every declared path is absolute
+func (c ToolCapabilities) Validate() error {
+	for _, path := range append(append([]string{}, c.ReadPaths...), c.WritePaths...) {
+		if !filepath.IsAbs(path) {
+			return fmt.Errorf("%w: %q is not an absolute path", ErrInvalidCapabilities, path)
+		}
+	}
+	return nil
+}
+
+// Allows reports whether the declaration covers request
+func (c ToolCapabilities) Allows(request CapabilityRequest) bool {
+	if c.Unrestricted {
+		return true
+	}
+	switch request.Kind {
+	case CapabilityFileRead:
+		return coversPath(c.ReadPaths, request.Resource) || coversPath(c.WritePaths, request.Resource)
+	case CapabilityFileWrite:
+		return coversPath(c.WritePaths, request.Resource)
+	case CapabilityNetwork:
+		return c.Network
+	case CapabilitySubprocess:
+		return c.Subprocess
+	}
+	return false
+}
+
+// Grant returns the declaration extended to cover request; a path is
+// granted on its own, not its directory
+func (c ToolCapabilities) Grant(request CapabilityRequest) ToolCapabilities {
+	if c.Allows(request) {
+		return c
+	}
+
+	granted := c
+	switch request.Kind {
+	case CapabilityFileRead:
+		granted.ReadPaths = append(append([]string{}, c.ReadPaths...), request.Resource)
+	case CapabilityFileWrite:
+		granted.WritePaths = append(append([]string{}, c.WritePaths...), request.Resource)
+	case CapabilityNetwork:
+		granted.Network = true
+	case CapabilitySubprocess:
+		granted.Subprocess = true
+	case CapabilityUnrestricted:
+		granted.Unrestricted = true
+	}
+	return granted
+}
+
+// Requests are the requests that, granted one by one, give the declaration
+func (c ToolCapabilities) Requests() []CapabilityRequest {
+	var requests []CapabilityRequest
+	for _, path := range c.ReadPaths {
+		requests = append(requests, CapabilityRequest{Kind: CapabilityFileRead, Resource: path})
+	}
+	for _, path := range c.WritePaths {
+		requests = append(requests, CapabilityRequest{Kind: CapabilityFileWrite, Resource: path})
+	}
+	if c.Network {
+		requests = append(requests, CapabilityRequest{Kind: CapabilityNetwork})
+	}
+	if c.Subprocess {
+		requests = append(requests, CapabilityRequest{Kind: CapabilitySubprocess})
+	}
+	if c.Unrestricted {
+		requests = append(requests, CapabilityRequest{Kind: CapabilityUnrestricted})
+	}
+	return requests
+}
+
+// Within returns the part of the declaration that granted allows
+func (c ToolCapabilities) Within(granted ToolCapabilities) ToolCapabilities {
+	var within ToolCapabilities
+	for _, request := range c.Requests() {
+		if granted.Allows(request) {
+			within = within.Grant(request)
+		}
+	}
+	return within
+}
+
+// importedCapabilities splits what a tool from a file or bundle declares
+// into what it may use, which is what its stored copy was granted, and the
+// requests left for the user to grant. A tool that is not stored yet has
+// been granted nothing; one stored without a declaration runs unrestricted.
+func importedCapabilities(declared *ToolCapabilities, stored *Tool) (*ToolCapabilities, []CapabilityRequest) {
+	granted := ToolCapabilities{}
+	if stored != nil {
+		granted = ToolCapabilities{Unrestricted: true}
+		if stored.Capabilities != nil {
+			granted = *stored.Capabilities
+		}
+	}
+	if declared == nil {
+		declared = &ToolCapabilities{}
+	}
+
+	within := declared.Within(granted)
+	var requests []CapabilityRequest
+	for _, request := range declared.Requests() {
+		if !within.Allows(request) {
+			requests = append(requests, request)
+		}
+	}
+	return &within, requests
+}
+
+// coversPath reports whether path is one of paths or inside one of them
+func coversPath(paths []string, path string) bool {
+	for _, allowed := range paths {
+		relative, err := filepath.Rel(allowed, path)
+		if err == nil && relative != ".." && !strings.HasPrefix(relative, ".."+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+// WithCapabilities returns a copy of the tool declaring capabilities
+func (t Tool) WithCapabilities(capabilities ToolCapabilities) (Tool, error) {
+	if err := capabilities.Validate(); err != nil {
+		return Tool{}, err
+	}
+
+	declared := t
+	declared.Capabilities = &capabilities
+	declared.UpdatedAt = time.Now()
+	return declared, nil
+}
+
+// CapabilityRequest is an access a run attempted and was refused
+type CapabilityRequest struct {
+	Kind CapabilityKind `json:"kind"`
+	// Resource is the path or host:port asked for; empty for subprocesses
+	Resource string `json:"resource"`
+}
+
+func (r CapabilityRequest) String() string {
+	if r.Resource == "" {
+		return string(r.Kind)
+	}
+	return fmt.Sprintf("%s %s", r.Kind, r.Resource)
+}
+
+// EscalationStatus is where the review of an escalation stands
+type EscalationStatus string
+
+const (
+	EscalationPending EscalationStatus = "pending"
+	EscalationGranted EscalationStatus = "granted"
+	EscalationDenied  EscalationStatus = "denied"
+)
+
+// CapabilityEscalation records a tool asking for more than it declared,
+// until the user grants or denies it
+type CapabilityEscalation struct {
+	ID        string           `json:"id"`
+	ToolID    string           `json:"tool_id"`
+	ToolName  string           `json:"tool_name"`
+	Kind      CapabilityKind   `json:"kind"`
+	Resource  string           `json:"resource"`
+	Status    EscalationStatus `json:"status"`
+	CreatedAt time.Time        `json:"created_at"`
+	DecidedAt *time.Time       `json:"decided_at,omitempty"`
+}
+
+// NewCapabilityEscalation creates a pending escalation of tool for request
+func NewCapabilityEscalation(tool Tool, request CapabilityRequest) CapabilityEscalation {
+	return CapabilityEscalation{
+		ID:        uuid.New().String(),
+		ToolID:    tool.ID,
+		ToolName:  tool.Name,
+		Kind:      request.Kind,
+		Resource:  request.Resource,
+		Status:    EscalationPending,
+		CreatedAt: time.Now(),
+	}
+}
+
+// Request is what the tool asked for
+func (e CapabilityEscalation) Request() CapabilityRequest {
+	return CapabilityRequest{Kind: e.Kind, Resource: e.Resource}
+}
+
+// Decided returns a copy of the escalation settled with status
+func (e CapabilityEscalation) Decided(status EscalationStatus) CapabilityEscalation {
+	now := time.Now()
+	decided := e
+	decided.Status = status
+	decided.DecidedAt = &now
+	return decided
+}
@@ -0,0 +1,12 @@
+package domain
+
+// CapabilityEscalationRepository defines the interface for capability
+// escalation persistence
+type CapabilityEscalationRepository interface {
+	Save(escalation CapabilityEscalation) error
+	GetByID(id string) (CapabilityEscalation, error)
+	// List returns the escalations with status, or all of them when status
+	// is empty, newest first
+	List(status EscalationStatus) ([]CapabilityEscalation, error)
+	Close() error
+}
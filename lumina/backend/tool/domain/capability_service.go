@@ -0,0 +1,158 @@
+package domain
+
+import (
+	"fmt"
+	"log"
+)
+
+// CapabilityService records the accesses tools were refused as escalations
+// and lets the user grant or deny them
+type CapabilityService struct {
+	tools       ToolRepository
+	escalations CapabilityEscalationRepository
+}
+
+func NewCapabilityService(tools ToolRepository, escalations CapabilityEscalationRepository) *CapabilityService {
+	return &CapabilityService{
+		tools:       tools,
+		escalations: escalations,
+	}
+}
+
+// Record stores a pending escalation for each request of tool its
+// declaration does not cover, and returns the new ones. A request that is
+// already pending is not recorded twice.
+func (s *CapabilityService) Record(tool Tool, requests []CapabilityRequest) ([]CapabilityEscalation, error) {
+	if len(requests) == 0 {
+		return nil, nil
+	}
+
+	pending, err := s.escalations.List(EscalationPending)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[CapabilityRequest]bool)
+	for _, escalation := range pending {
+		if escalation.ToolID == tool.ID {
+			seen[escalation.Request()] = true
+		}
+	}
+
+	var declared ToolCapabilities
+	if tool.Capabilities != nil {
+		declared = *tool.Capabilities
+	}
+
+	var recorded []CapabilityEscalation
+	for _, request := range requests {
+		if seen[request] || declared.Allows(request) {
+			continue
+		}
+		seen[request] = true
+
+		escalation := NewCapabilityEscalation(tool, request)
+		if err := s.escalations.Save(escalation); err != nil {
+			return recorded, err
+		}
+		recorded = append(recorded, escalation)
+	}
+	return recorded, nil
+}
+
+// Pending returns the escalations waiting for a decision, newest first
+func (s *CapabilityService) Pending() ([]CapabilityEscalation, error) {
+	return s.escalations.List(EscalationPending)
+}
+
+// Grant adds what escalation id asked for to the capabilities of its tool
+// and returns the saved tool
+func (s *CapabilityService) Grant(id string) (Tool, error) {
+	escalation, err := s.pending(id)
+	if err != nil {
+		return Tool{}, err
+	}
+
+	tool, err := s.tools.GetByID(escalation.ToolID)
+	if err != nil {
+		return Tool{}, err
+	}
+
+	var declared ToolCapabilities
+	if tool.Capabilities != nil {
+		declared = *tool.Capabilities
+	}
+	granted, err := tool.WithCapabilities(declared.Grant(escalation.Request()))
+	if err != nil {
+		return Tool{}, err
+	}
+	granted, err = s.tools.SaveWithMessage(granted, fmt.Sprintf("Grant %s", escalation.Request()))
+	if err != nil {
+		return Tool{}, fmt.Errorf("failed to save tool: %w", err)
+	}
+
+	if err := s.escalations.Save(escalation.Decided(EscalationGranted)); err != nil {
+		return Tool{}, err
+	}
+	return granted, nil
+}
+
+// Deny settles escalation id without changing the tool, which keeps being
+// refused the access
+func (s *CapabilityService) Deny(id string) (CapabilityEscalation, error) {
+	escalation, err := s.pending(id)
+	if err != nil {
+		return CapabilityEscalation{}, err
+	}
+
+	denied := escalation.Decided(EscalationDenied)
+	if err := s.escalations.Save(denied); err != nil {
+		return CapabilityEscalation{}, err
+	}
+	return denied, nil
+}
+
+func (s *CapabilityService) pending(id string) (CapabilityEscalation, error) {
+	escalation, err := s.escalations.GetByID(id)
+	if err != nil {
+		return CapabilityEscalation{}, err
+	}
+	if escalation.Status != EscalationPending {
+		return CapabilityEscalation{}, fmt.Errorf("%w: %s", ErrEscalationAlreadyDecided, escalation.Status)
+	}
+	return escalation, nil
+}
+
+// CapabilityGuardedRunner is a ToolRunner recording the escalations of the
+// runs of another runner and reporting new ones to notify, e.g. to prompt
+// the user
+type CapabilityGuardedRunner struct {
+	ToolRunner
+	capabilities *CapabilityService
+	notify       func(tool Tool, escalations []CapabilityEscalation)
+}
+
+// NewCapabilityGuardedRunner wraps runner; notify may be nil
+func NewCapabilityGuardedRunner(runner ToolRunner, capabilities *CapabilityService, notify func(tool Tool, escalations []CapabilityEscalation)) *CapabilityGuardedRunner {
+	return &CapabilityGuardedRunner{
+		ToolRunner:   runner,
+		capabilities: capabilities,
+		notify:       notify,
+	}
+}
+
+// Run runs the tool; a failure to record an escalation never fails the run
+func (r *CapabilityGuardedRunner) Run(tool Tool, args map[string]interface{}) (ToolRunOutput, error) {
+	output, err := r.ToolRunner.Run(tool, args)
+	if err != nil || len(output.CapabilityRequests) == 0 {
+		return output, err
+	}
+
+	escalations, err := r.capabilities.Record(tool, output.CapabilityRequests)
+	if err != nil {
+		log.Printf("Warning: Failed to record capability escalation of %s: %v", tool.Name, err)
+	}
+	if len(escalations) > 0 && r.notify != nil {
+		r.notify(tool, escalations)
+	}
+	return output, nil
+}
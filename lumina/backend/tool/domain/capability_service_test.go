@@ -0,0 +1,152 @@
+package domain_test
+
+import (
+	"sort"
+	"testing"
+
+	"lumina/backend/tool/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// MockCapabilityEscalationRepository for testing
+type MockCapabilityEscalationRepository struct {
+	escalations map[string]domain.CapabilityEscalation
+}
+
+func NewMockCapabilityEscalationRepository() *MockCapabilityEscalationRepository {
+	return &MockCapabilityEscalationRepository{escalations: make(map[string]domain.CapabilityEscalation)}
+}
+
+func (m *MockCapabilityEscalationRepository) Save(escalation domain.CapabilityEscalation) error {
+	m.escalations[escalation.ID] = escalation
+	return nil
+}
+
+func (m *MockCapabilityEscalationRepository) GetByID(id string) (domain.CapabilityEscalation, error) {
+	escalation, exists := m.escalations[id]
+	if !exists {
+		return domain.CapabilityEscalation{}, domain.ErrEscalationNotFound
+	}
+	return escalation, nil
+}
+
+func (m *MockCapabilityEscalationRepository) List(status domain.EscalationStatus) ([]domain.CapabilityEscalation, error) {
+	escalations := []domain.CapabilityEscalation{}
+	for _, escalation := range m.escalations {
+		if status == "" || escalation.Status == status {
+			escalations = append(escalations, escalation)
+		}
+	}
+	sort.Slice(escalations, func(i, j int) bool {
+		return escalations[i].CreatedAt.After(escalations[j].CreatedAt)
+	})
+	return escalations, nil
+}
+
+func (m *MockCapabilityEscalationRepository) Close() error {
+	return nil
+}
+
+// RefusedRunner is a ToolRunner whose runs are refused the given requests
+type RefusedRunner struct {
+	MockToolRunner
+	requests []domain.CapabilityRequest
+}
+
+func (r *RefusedRunner) Run(tool domain.Tool, args map[string]interface{}) (domain.ToolRunOutput, error) {
+	return domain.ToolRunOutput{Success: false, ExitCode: 1, CapabilityRequests: r.requests}, nil
+}
+
+var networkRequest = domain.CapabilityRequest{Kind: domain.CapabilityNetwork, Resource: "example.com:443"}
+
+func newCapabilityFixture(t *testing.T) (*MockToolRepository, *MockCapabilityEscalationRepository, *domain.CapabilityService, domain.Tool) {
+	tools := NewMockToolRepository()
+	escalations := NewMockCapabilityEscalationRepository()
+	tool := domain.NewTool("Fetch", "await fetch('https://example.com')")
+	tool, err := tools.Save(tool)
+	require.NoError(t, err)
+	return tools, escalations, domain.NewCapabilityService(tools, escalations), tool
+}
+
+func TestCapabilityService_Record(t *testing.T) {
+	// Given a tool that may read /etc/hosts
+	_, escalations, service, tool := newCapabilityFixture(t)
+	tool.Capabilities = &domain.ToolCapabilities{ReadPaths: []string{"/etc/hosts"}}
+	allowed := domain.CapabilityRequest{Kind: domain.CapabilityFileRead, Resource: "/etc/hosts"}
+
+	// When a run is refused the network twice and a declared read, and a second run the network again
+	recorded, err := service.Record(tool, []domain.CapabilityRequest{networkRequest, networkRequest, allowed})
+	require.NoError(t, err)
+	again, err := service.Record(tool, []domain.CapabilityRequest{networkRequest})
+	require.NoError(t, err)
+
+	// Then the network is pending once
+	require.Len(t, recorded, 1)
+	assert.Equal(t, domain.CapabilityNetwork, recorded[0].Kind)
+	assert.Equal(t, "Fetch", recorded[0].ToolName)
+	assert.Empty(t, again)
+	assert.Len(t, escalations.escalations, 1)
+}
+
+func TestCapabilityService_Grant(t *testing.T) {
+	// Given a pending network escalation
+	tools, _, service, tool := newCapabilityFixture(t)
+	recorded, err := service.Record(tool, []domain.CapabilityRequest{networkRequest})
+	require.NoError(t, err)
+
+	// When
+	granted, err := service.Grant(recorded[0].ID)
+
+	// Then the tool may use the network and the escalation is settled
+	require.NoError(t, err)
+	assert.True(t, granted.Capabilities.Network)
+	stored, err := tools.GetByID(tool.ID)
+	require.NoError(t, err)
+	assert.True(t, stored.Capabilities.Network)
+
+	pending, err := service.Pending()
+	require.NoError(t, err)
+	assert.Empty(t, pending)
+
+	_, err = service.Grant(recorded[0].ID)
+	assert.ErrorIs(t, err, domain.ErrEscalationAlreadyDecided)
+}
+
+func TestCapabilityService_Deny(t *testing.T) {
+	// Given a pending network escalation
+	tools, _, service, tool := newCapabilityFixture(t)
+	recorded, err := service.Record(tool, []domain.CapabilityRequest{networkRequest})
+	require.NoError(t, err)
+
+	// When
+	denied, err := service.Deny(recorded[0].ID)
+
+	// Then the tool is unchanged
+	require.NoError(t, err)
+	assert.Equal(t, domain.EscalationDenied, denied.Status)
+	stored, err := tools.GetByID(tool.ID)
+	require.NoError(t, err)
+	assert.False(t, stored.Capabilities.Network)
+}
+
+func TestCapabilityGuardedRunner_NotifiesNewEscalations(t *testing.T) {
+	// Given a runner refusing the network
+	_, _, service, tool := newCapabilityFixture(t)
+	var notified []domain.CapabilityEscalation
+	runner := domain.NewCapabilityGuardedRunner(&RefusedRunner{requests: []domain.CapabilityRequest{networkRequest}}, service, func(_ domain.Tool, escalations []domain.CapabilityEscalation) {
+		notified = append(notified, escalations...)
+	})
+
+	// When the tool runs twice
+	_, err := runner.Run(tool, nil)
+	require.NoError(t, err)
+	output, err := runner.Run(tool, nil)
+	require.NoError(t, err)
+
+	// Then the user is asked once and the run output is kept
+	require.Len(t, notified, 1)
+	assert.Equal(t, networkRequest, notified[0].Request())
+	assert.Equal(t, []domain.CapabilityRequest{networkRequest}, output.CapabilityRequests)
+}
@@ -0,0 +1,84 @@
+package domain_test
+
+import (
+	"testing"
+
+	"lumina/backend/tool/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToolCapabilities_Allows(t *testing.T) {
+	capabilities := domain.ToolCapabilities{
+		ReadPaths:  []string{"/etc/hosts"},
+		WritePaths: []string{"/tmp/reports"},
+	}
+
+	tests := []struct {
+		name    string
+		request domain.CapabilityRequest
+		allowed bool
+	}{
+		{"declared file", domain.CapabilityRequest{Kind: domain.CapabilityFileRead, Resource: "/etc/hosts"}, true},
+		{"file next to a declared one", domain.CapabilityRequest{Kind: domain.CapabilityFileRead, Resource: "/etc/hostname"}, false},
+		{"read inside a writable directory", domain.CapabilityRequest{Kind: domain.CapabilityFileRead, Resource: "/tmp/reports/today.txt"}, true},
+		{"write to a readable file", domain.CapabilityRequest{Kind: domain.CapabilityFileWrite, Resource: "/etc/hosts"}, false},
+		{"write outside a writable directory", domain.CapabilityRequest{Kind: domain.CapabilityFileWrite, Resource: "/tmp/other"}, false},
+		{"undeclared network", domain.CapabilityRequest{Kind: domain.CapabilityNetwork, Resource: "example.com:443"}, false},
+		{"undeclared subprocess", domain.CapabilityRequest{Kind: domain.CapabilitySubprocess}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// When
+			allowed := capabilities.Allows(tt.request)
+
+			// Then
+			assert.Equal(t, tt.allowed, allowed)
+		})
+	}
+}
+
+func TestToolCapabilities_Grant(t *testing.T) {
+	// Given
+	capabilities := domain.ToolCapabilities{ReadPaths: []string{"/etc/hosts"}}
+	read := domain.CapabilityRequest{Kind: domain.CapabilityFileRead, Resource: "/etc/hostname"}
+	network := domain.CapabilityRequest{Kind: domain.CapabilityNetwork, Resource: "example.com:443"}
+
+	// When
+	granted := capabilities.Grant(read).Grant(network)
+
+	// Then the requests are allowed and the original declaration is unchanged
+	assert.True(t, granted.Allows(read))
+	assert.True(t, granted.Network)
+	assert.Equal(t, []string{"/etc/hosts"}, capabilities.ReadPaths)
+}
+
+func TestToolCapabilities_GrantUnrestricted(t *testing.T) {
+	// Given
+	unrestricted := domain.CapabilityRequest{Kind: domain.CapabilityUnrestricted}
+	network := domain.CapabilityRequest{Kind: domain.CapabilityNetwork, Resource: "example.com:443"}
+
+	// When
+	granted := domain.ToolCapabilities{}.Grant(unrestricted)
+
+	// Then every access is allowed
+	assert.True(t, granted.Unrestricted)
+	assert.True(t, granted.Allows(network))
+	assert.False(t, domain.ToolCapabilities{}.Allows(unrestricted))
+}
+
+func TestTool_WithCapabilities(t *testing.T) {
+	// Given
+	tool := domain.NewTool("Report", "console.log(1);")
+
+	// When
+	declared, err := tool.WithCapabilities(domain.ToolCapabilities{WritePaths: []string{"/tmp/reports"}})
+	_, relativeErr := tool.WithCapabilities(domain.ToolCapabilities{ReadPaths: []string{"reports"}})
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/tmp/reports"}, declared.Capabilities.WritePaths)
+	assert.ErrorIs(t, relativeErr, domain.ErrInvalidCapabilities)
+}
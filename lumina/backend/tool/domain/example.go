@@ -0,0 +1,163 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrExampleNameEmpty = errors.New("example name cannot be empty")
+	ErrExampleNameTaken = errors.New("example name already taken for this tool")
+	ErrExampleNotFound  = errors.New("example not found")
+	ErrInvalidAssertion = errors.New("invalid example assertion")
+)
+
+// AssertionKind is how an assertion checks the output of a run
+type AssertionKind string
+
+const (
+	// AssertContains passes when stdout contains Value
+	AssertContains AssertionKind = "contains"
+	// AssertNotContains passes when stdout does not contain Value
+	AssertNotContains AssertionKind = "not_contains"
+	// AssertMatches passes when stdout matches the regular expression Value
+	AssertMatches AssertionKind = "matches"
+	// AssertExitCode passes when the exit code equals Value; without it an
+	// example must exit with 0
+	AssertExitCode AssertionKind = "exit_code"
+)
+
+// ExampleAssertion is one check on the output of an example run
+type ExampleAssertion struct {
+	Kind  AssertionKind `json:"kind"`
+	Value string        `json:"value"`
+}
+
+// ToolExample is a named invocation of a tool with the output it should
+// produce. Examples document a tool and double as its regression tests.
+type ToolExample struct {
+	ID        string                 `json:"id"`
+	ToolID    string                 `json:"tool_id"`
+	Name      string                 `json:"name"`
+	Arguments map[string]interface{} `json:"arguments"`
+	// ExpectedOutput, when set, must equal stdout apart from surrounding whitespace
+	ExpectedOutput string             `json:"expected_output"`
+	Assertions     []ExampleAssertion `json:"assertions"`
+	CreatedAt      time.Time          `json:"created_at"`
+	UpdatedAt      time.Time          `json:"updated_at"`
+}
+
+// NewToolExample creates an example for tool after checking its arguments
+// against the tool's parameters and its assertions
+func NewToolExample(tool Tool, name string, arguments map[string]interface{}, expectedOutput string, assertions []ExampleAssertion) (ToolExample, error) {
+	now := time.Now()
+	example := ToolExample{
+		ID:             uuid.New().String(),
+		ToolID:         tool.ID,
+		Name:           strings.TrimSpace(name),
+		Arguments:      arguments,
+		ExpectedOutput: expectedOutput,
+		Assertions:     assertions,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+
+	if err := example.Validate(tool); err != nil {
+		return ToolExample{}, err
+	}
+	return example, nil
+}
+
+// Validate checks the example against the current parameters of its tool
+func (e ToolExample) Validate(tool Tool) error {
+	if strings.TrimSpace(e.Name) == "" {
+		return ErrExampleNameEmpty
+	}
+
+	if _, err := tool.ResolveArguments(e.Arguments); err != nil {
+		return err
+	}
+
+	for _, assertion := range e.Assertions {
+		switch assertion.Kind {
+		case AssertContains, AssertNotContains:
+		case AssertMatches:
+			if _, err := regexp.Compile(assertion.Value); err != nil {
+				return fmt.Errorf("%w: %v", ErrInvalidAssertion, err)
+			}
+		case AssertExitCode:
+			if _, err := strconv.Atoi(strings.TrimSpace(assertion.Value)); err != nil {
+				return fmt.Errorf("%w: exit code %q is not a number", ErrInvalidAssertion, assertion.Value)
+			}
+		default:
+			return fmt.Errorf("%w: unknown kind %q", ErrInvalidAssertion, assertion.Kind)
+		}
+	}
+
+	return nil
+}
+
+// Check compares the output of a run with the example and returns one
+// message per failed expectation; no messages means the example passed
+func (e ToolExample) Check(output ToolRunOutput) []string {
+	var failures []string
+
+	expectedExitCode := 0
+	for _, assertion := range e.Assertions {
+		if assertion.Kind == AssertExitCode {
+			expectedExitCode, _ = strconv.Atoi(strings.TrimSpace(assertion.Value))
+		}
+	}
+	if output.ExitCode != expectedExitCode {
+		failures = append(failures, fmt.Sprintf("exit code %d, expected %d", output.ExitCode, expectedExitCode))
+	}
+
+	if e.ExpectedOutput != "" && strings.TrimSpace(output.Output) != strings.TrimSpace(e.ExpectedOutput) {
+		failures = append(failures, fmt.Sprintf("output %q, expected %q", strings.TrimSpace(output.Output), strings.TrimSpace(e.ExpectedOutput)))
+	}
+
+	for _, assertion := range e.Assertions {
+		switch assertion.Kind {
+		case AssertContains:
+			if !strings.Contains(output.Output, assertion.Value) {
+				failures = append(failures, fmt.Sprintf("output does not contain %q", assertion.Value))
+			}
+		case AssertNotContains:
+			if strings.Contains(output.Output, assertion.Value) {
+				failures = append(failures, fmt.Sprintf("output contains %q", assertion.Value))
+			}
+		case AssertMatches:
+			pattern, err := regexp.Compile(assertion.Value)
+			if err != nil || !pattern.MatchString(output.Output) {
+				failures = append(failures, fmt.Sprintf("output does not match %q", assertion.Value))
+			}
+		}
+	}
+
+	return failures
+}
+
+// ExampleResult is the outcome of running one example
+type ExampleResult struct {
+	ExampleID   string        `json:"example_id"`
+	ExampleName string        `json:"example_name"`
+	ToolID      string        `json:"tool_id"`
+	ToolName    string        `json:"tool_name"`
+	Passed      bool          `json:"passed"`
+	Failures    []string      `json:"failures"`
+	Output      ToolRunOutput `json:"output"`
+}
+
+// ExampleSuiteReport is the outcome of running many examples
+type ExampleSuiteReport struct {
+	Results    []ExampleResult `json:"results"`
+	Passed     int             `json:"passed"`
+	Failed     int             `json:"failed"`
+	DurationMs int64           `json:"duration_ms"`
+}
@@ -0,0 +1,112 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ExampleService runs tool examples and checks their output
+type ExampleService struct {
+	tools    ToolRepository
+	examples ToolExampleRepository
+	runner   ToolRunner
+}
+
+func NewExampleService(tools ToolRepository, examples ToolExampleRepository, runner ToolRunner) *ExampleService {
+	return &ExampleService{
+		tools:    tools,
+		examples: examples,
+		runner:   runner,
+	}
+}
+
+// RunTool runs the examples of one tool
+func (s *ExampleService) RunTool(toolID string) (ExampleSuiteReport, error) {
+	tool, err := s.tools.GetByID(toolID)
+	if err != nil {
+		return ExampleSuiteReport{}, err
+	}
+
+	examples, err := s.examples.ListByTool(toolID)
+	if err != nil {
+		return ExampleSuiteReport{}, fmt.Errorf("failed to list examples: %w", err)
+	}
+
+	return s.run(examples, map[string]Tool{tool.ID: tool})
+}
+
+// RunAll runs every example of every tool outside the trash, as a
+// regression suite for the whole library
+func (s *ExampleService) RunAll() (ExampleSuiteReport, error) {
+	examples, err := s.examples.List()
+	if err != nil {
+		return ExampleSuiteReport{}, fmt.Errorf("failed to list examples: %w", err)
+	}
+
+	tools := make(map[string]Tool)
+	var runnable []ToolExample
+	for _, example := range examples {
+		if _, loaded := tools[example.ToolID]; !loaded {
+			tool, err := s.tools.GetByID(example.ToolID)
+			if errors.Is(err, ErrToolNotFound) {
+				continue
+			}
+			if err != nil {
+				return ExampleSuiteReport{}, err
+			}
+			tools[tool.ID] = tool
+		}
+		runnable = append(runnable, example)
+	}
+
+	return s.run(runnable, tools)
+}
+
+func (s *ExampleService) run(examples []ToolExample, tools map[string]Tool) (ExampleSuiteReport, error) {
+	started := time.Now()
+	report := ExampleSuiteReport{Results: []ExampleResult{}}
+
+	for _, example := range examples {
+		result := s.runExample(tools[example.ToolID], example)
+		if result.Passed {
+			report.Passed++
+		} else {
+			report.Failed++
+		}
+		report.Results = append(report.Results, result)
+	}
+
+	report.DurationMs = time.Since(started).Milliseconds()
+	return report, nil
+}
+
+// runExample never fails the suite: problems running an example are
+// reported as failures of that example
+func (s *ExampleService) runExample(tool Tool, example ToolExample) ExampleResult {
+	result := ExampleResult{
+		ExampleID:   example.ID,
+		ExampleName: example.Name,
+		ToolID:      tool.ID,
+		ToolName:    tool.Name,
+		Failures:    []string{},
+	}
+
+	// Parameters may have changed since the example was written
+	args, err := tool.ResolveArguments(example.Arguments)
+	if err != nil {
+		result.Failures = append(result.Failures, err.Error())
+		return result
+	}
+
+	output, err := s.runner.Run(tool, args)
+	if err != nil {
+		result.Failures = append(result.Failures, fmt.Sprintf("failed to run: %v", err))
+		return result
+	}
+
+	result.Output = output
+	result.Failures = append(result.Failures, example.Check(output)...)
+	result.Passed = len(result.Failures) == 0
+	return result
+}
@@ -0,0 +1,225 @@
+package domain_test
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"lumina/backend/tool/domain"
+)
+
+// MockToolRunner prints "Hello, <name>" for tools taking a name
+type MockToolRunner struct {
+	runs     []map[string]interface{}
+	runError error
+}
+
+func (m *MockToolRunner) Run(tool domain.Tool, args map[string]interface{}) (domain.ToolRunOutput, error) {
+	if m.runError != nil {
+		return domain.ToolRunOutput{}, m.runError
+	}
+	m.runs = append(m.runs, args)
+
+	name, _ := args["name"].(string)
+	return domain.ToolRunOutput{Output: "Hello, " + name + "\n", Success: true}, nil
+}
+
+func (m *MockToolRunner) RunCode(code string) (domain.ToolRunOutput, error) {
+	if m.runError != nil {
+		return domain.ToolRunOutput{}, m.runError
+	}
+	return domain.ToolRunOutput{Success: true}, nil
+}
+
+// MockToolExampleRepository for testing
+type MockToolExampleRepository struct {
+	examples map[string]domain.ToolExample
+}
+
+func NewMockToolExampleRepository() *MockToolExampleRepository {
+	return &MockToolExampleRepository{examples: make(map[string]domain.ToolExample)}
+}
+
+func (m *MockToolExampleRepository) Save(example domain.ToolExample) error {
+	for _, saved := range m.examples {
+		if saved.ID != example.ID && saved.ToolID == example.ToolID && saved.Name == example.Name {
+			return domain.ErrExampleNameTaken
+		}
+	}
+	m.examples[example.ID] = example
+	return nil
+}
+
+func (m *MockToolExampleRepository) GetByID(id string) (domain.ToolExample, error) {
+	example, exists := m.examples[id]
+	if !exists {
+		return domain.ToolExample{}, domain.ErrExampleNotFound
+	}
+	return example, nil
+}
+
+func (m *MockToolExampleRepository) ListByTool(toolID string) ([]domain.ToolExample, error) {
+	var examples []domain.ToolExample
+	for _, example := range m.sorted() {
+		if example.ToolID == toolID {
+			examples = append(examples, example)
+		}
+	}
+	return examples, nil
+}
+
+func (m *MockToolExampleRepository) List() ([]domain.ToolExample, error) {
+	return m.sorted(), nil
+}
+
+func (m *MockToolExampleRepository) sorted() []domain.ToolExample {
+	var examples []domain.ToolExample
+	for _, example := range m.examples {
+		examples = append(examples, example)
+	}
+	sort.Slice(examples, func(i, j int) bool {
+		if examples[i].ToolID != examples[j].ToolID {
+			return examples[i].ToolID < examples[j].ToolID
+		}
+		return examples[i].Name < examples[j].Name
+	})
+	return examples
+}
+
+func (m *MockToolExampleRepository) Delete(id string) error {
+	if _, exists := m.examples[id]; !exists {
+		return domain.ErrExampleNotFound
+	}
+	delete(m.examples, id)
+	return nil
+}
+
+func (m *MockToolExampleRepository) DeleteByTool(toolID string) error {
+	for id, example := range m.examples {
+		if example.ToolID == toolID {
+			delete(m.examples, id)
+		}
+	}
+	return nil
+}
+
+func (m *MockToolExampleRepository) Close() error {
+	return nil
+}
+
+func TestExampleService_Interfaces(t *testing.T) {
+	var _ domain.ToolRunner = &MockToolRunner{}
+	var _ domain.ToolExampleRepository = &MockToolExampleRepository{}
+}
+
+func newExampleFixture(t *testing.T) (*MockToolRepository, *MockToolExampleRepository, *MockToolRunner, *domain.ExampleService, domain.Tool) {
+	tools := NewMockToolRepository()
+	examples := NewMockToolExampleRepository()
+	runner := &MockToolRunner{}
+
+	tool := newGreetTool(t)
+	_, err := tools.Save(tool)
+	require.NoError(t, err)
+
+	return tools, examples, runner, domain.NewExampleService(tools, examples, runner), tool
+}
+
+func TestExampleService_RunTool(t *testing.T) {
+	// Given a passing and a failing example
+	_, examples, runner, service, tool := newExampleFixture(t)
+
+	passing, err := domain.NewToolExample(tool, "ada", map[string]interface{}{"name": "Ada"}, "Hello, Ada", nil)
+	require.NoError(t, err)
+	failing, err := domain.NewToolExample(tool, "bob", map[string]interface{}{"name": "Bob"}, "Hi, Bob", nil)
+	require.NoError(t, err)
+	require.NoError(t, examples.Save(passing))
+	require.NoError(t, examples.Save(failing))
+
+	// When
+	report, err := service.RunTool(tool.ID)
+
+	// Then each example is reported with its outcome
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Passed)
+	assert.Equal(t, 1, report.Failed)
+	require.Len(t, report.Results, 2)
+	assert.True(t, report.Results[0].Passed)
+	assert.Equal(t, "Greet", report.Results[0].ToolName)
+	assert.False(t, report.Results[1].Passed)
+	assert.Len(t, report.Results[1].Failures, 1)
+	assert.Len(t, runner.runs, 2)
+}
+
+func TestExampleService_RunTool_UnknownTool(t *testing.T) {
+	// Given
+	_, _, _, service, _ := newExampleFixture(t)
+
+	// When
+	_, err := service.RunTool("missing")
+
+	// Then
+	assert.ErrorIs(t, err, domain.ErrToolNotFound)
+}
+
+func TestExampleService_RunAll_SkipsTrashedTools(t *testing.T) {
+	// Given examples of a live tool and of a trashed tool
+	tools, examples, _, service, tool := newExampleFixture(t)
+
+	trashed, err := domain.NewToolWithValidation("Old", "console.log('old')")
+	require.NoError(t, err)
+	_, err = tools.Save(*trashed)
+	require.NoError(t, err)
+
+	live, _ := domain.NewToolExample(tool, "ada", map[string]interface{}{"name": "Ada"}, "Hello, Ada", nil)
+	old, _ := domain.NewToolExample(*trashed, "old", nil, "old", nil)
+	require.NoError(t, examples.Save(live))
+	require.NoError(t, examples.Save(old))
+	require.NoError(t, tools.Delete(trashed.ID))
+
+	// When
+	report, err := service.RunAll()
+
+	// Then only the live tool's example runs
+	require.NoError(t, err)
+	require.Len(t, report.Results, 1)
+	assert.Equal(t, live.ID, report.Results[0].ExampleID)
+	assert.Equal(t, 1, report.Passed)
+}
+
+func TestExampleService_FailuresDoNotStopTheSuite(t *testing.T) {
+	// Given an example whose arguments no longer match the tool, and a runner that fails
+	tools, examples, runner, service, tool := newExampleFixture(t)
+
+	stale, _ := domain.NewToolExample(tool, "stale", map[string]interface{}{"name": "Ada"}, "Hello, Ada", nil)
+	require.NoError(t, examples.Save(stale))
+
+	tool, err := tools.GetByID(tool.ID)
+	require.NoError(t, err)
+	renamed, err := tool.WithParameters([]domain.ToolParameter{{Name: "who", Type: domain.ParameterString}})
+	require.NoError(t, err)
+	_, err = tools.Save(renamed)
+	require.NoError(t, err)
+
+	other, err := domain.NewToolWithValidation("Other", "console.log('x')")
+	require.NoError(t, err)
+	_, err = tools.Save(*other)
+	require.NoError(t, err)
+	broken, _ := domain.NewToolExample(*other, "broken", nil, "x", nil)
+	require.NoError(t, examples.Save(broken))
+
+	runner.runError = errors.New("node not found")
+
+	// When
+	report, err := service.RunAll()
+
+	// Then both examples fail with a reason
+	require.NoError(t, err)
+	assert.Equal(t, 2, report.Failed)
+	for _, result := range report.Results {
+		require.NotEmpty(t, result.Failures)
+		assert.True(t, strings.Contains(result.Failures[0], "unknown argument") || strings.Contains(result.Failures[0], "node not found"))
+	}
+}
@@ -0,0 +1,115 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"lumina/backend/tool/domain"
+)
+
+func newGreetTool(t *testing.T) domain.Tool {
+	created, err := domain.NewToolWithValidation("Greet", "console.log('hi')")
+	require.NoError(t, err)
+	tool, err := created.WithParameters([]domain.ToolParameter{
+		{Name: "name", Type: domain.ParameterString, Required: true},
+	})
+	require.NoError(t, err)
+	return tool
+}
+
+func TestNewToolExample_Validates(t *testing.T) {
+	tool := newGreetTool(t)
+
+	tests := []struct {
+		name       string
+		example    string
+		arguments  map[string]interface{}
+		assertions []domain.ExampleAssertion
+		wantErr    error
+	}{
+		{"valid", "ada", map[string]interface{}{"name": "Ada"}, []domain.ExampleAssertion{{Kind: domain.AssertMatches, Value: "^Hello"}}, nil},
+		{"empty name", "  ", map[string]interface{}{"name": "Ada"}, nil, domain.ErrExampleNameEmpty},
+		{"missing argument", "ada", nil, nil, domain.ErrInvalidArguments},
+		{"unknown kind", "ada", map[string]interface{}{"name": "Ada"}, []domain.ExampleAssertion{{Kind: "equals", Value: "x"}}, domain.ErrInvalidAssertion},
+		{"bad pattern", "ada", map[string]interface{}{"name": "Ada"}, []domain.ExampleAssertion{{Kind: domain.AssertMatches, Value: "("}}, domain.ErrInvalidAssertion},
+		{"bad exit code", "ada", map[string]interface{}{"name": "Ada"}, []domain.ExampleAssertion{{Kind: domain.AssertExitCode, Value: "one"}}, domain.ErrInvalidAssertion},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// When
+			example, err := domain.NewToolExample(tool, tt.example, tt.arguments, "", tt.assertions)
+
+			// Then
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.NotEmpty(t, example.ID)
+			assert.Equal(t, tool.ID, example.ToolID)
+		})
+	}
+}
+
+func TestToolExample_Check(t *testing.T) {
+	tests := []struct {
+		name     string
+		example  domain.ToolExample
+		output   domain.ToolRunOutput
+		failures int
+	}{
+		{
+			name:    "expected output ignores surrounding whitespace",
+			example: domain.ToolExample{ExpectedOutput: "Hello, Ada"},
+			output:  domain.ToolRunOutput{Output: "Hello, Ada\n"},
+		},
+		{
+			name:     "different output",
+			example:  domain.ToolExample{ExpectedOutput: "Hello, Ada"},
+			output:   domain.ToolRunOutput{Output: "Hello, Bob\n"},
+			failures: 1,
+		},
+		{
+			name:     "non-zero exit code fails by default",
+			example:  domain.ToolExample{},
+			output:   domain.ToolRunOutput{ExitCode: 1},
+			failures: 1,
+		},
+		{
+			name:    "exit code assertion",
+			example: domain.ToolExample{Assertions: []domain.ExampleAssertion{{Kind: domain.AssertExitCode, Value: "2"}}},
+			output:  domain.ToolRunOutput{ExitCode: 2},
+		},
+		{
+			name: "every failed assertion is reported",
+			example: domain.ToolExample{Assertions: []domain.ExampleAssertion{
+				{Kind: domain.AssertContains, Value: "Ada"},
+				{Kind: domain.AssertNotContains, Value: "error"},
+				{Kind: domain.AssertMatches, Value: `^\d+$`},
+			}},
+			output:   domain.ToolRunOutput{Output: "error"},
+			failures: 3,
+		},
+		{
+			name: "passing assertions",
+			example: domain.ToolExample{Assertions: []domain.ExampleAssertion{
+				{Kind: domain.AssertContains, Value: "Ada"},
+				{Kind: domain.AssertNotContains, Value: "error"},
+				{Kind: domain.AssertMatches, Value: `Ada$`},
+			}},
+			output: domain.ToolRunOutput{Output: "Hello, Ada"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// When
+			failures := tt.example.Check(tt.output)
+
+			// Then
+			assert.Len(t, failures, tt.failures)
+		})
+	}
+}
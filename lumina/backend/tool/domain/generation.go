@@ -0,0 +1,127 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	ErrGenerationFailed           = errors.New("could not generate a working tool")
+	ErrInvalidGeneratedTool       = errors.New("reply does not contain a valid tool")
+	ErrGenerationDescriptionEmpty = errors.New("tool description cannot be empty")
+)
+
+// MaxGenerationAttempts bounds how often a failing generated tool is sent
+// back to the model for a fix
+const MaxGenerationAttempts = 3
+
+// GeneratedExampleName is the name of the example saved with a generated tool
+const GeneratedExampleName = "generated"
+
+// GeneratedTool is the tool a language model proposed for a description
+type GeneratedTool struct {
+	Name        string           `json:"name"`
+	Description string           `json:"description"`
+	Code        string           `json:"code"`
+	Parameters  []ToolParameter  `json:"parameters"`
+	Example     GeneratedExample `json:"example"`
+}
+
+// GeneratedExample is the invocation the model expects the tool to pass
+type GeneratedExample struct {
+	Arguments      map[string]interface{} `json:"arguments"`
+	ExpectedOutput string                 `json:"expected_output"`
+}
+
+// ToolGenerationAttempt records why one generated version was rejected, or
+// that it passed
+type ToolGenerationAttempt struct {
+	Attempt  int           `json:"attempt"`
+	Passed   bool          `json:"passed"`
+	Failures []string      `json:"failures"`
+	Output   ToolRunOutput `json:"output"`
+}
+
+// ToolGenerationResult is the draft tool saved for a description, its
+// example and the attempts it took
+type ToolGenerationResult struct {
+	Tool     Tool                    `json:"tool"`
+	Example  ToolExample             `json:"example"`
+	Attempts []ToolGenerationAttempt `json:"attempts"`
+}
+
+var jsonFencePattern = regexp.MustCompile("(?s)```(?:json)?[ \t]*\n(.*?)```")
+
+// ParseGeneratedTool reads the JSON object of a model reply, either bare or
+// in a fenced code block
+func ParseGeneratedTool(reply string) (GeneratedTool, error) {
+	content := strings.TrimSpace(reply)
+	if match := jsonFencePattern.FindStringSubmatch(content); match != nil {
+		content = strings.TrimSpace(match[1])
+	}
+
+	var generated GeneratedTool
+	if err := json.Unmarshal([]byte(content), &generated); err != nil {
+		return GeneratedTool{}, fmt.Errorf("%w: %v", ErrInvalidGeneratedTool, err)
+	}
+	return generated, nil
+}
+
+// Build turns the proposal into a draft tool and its example, checking the
+// schema and the example's arguments
+func (g GeneratedTool) Build() (Tool, ToolExample, error) {
+	created, err := NewToolWithValidation(g.Name, g.Code)
+	if err != nil {
+		return Tool{}, ToolExample{}, err
+	}
+
+	tool, err := created.WithParameters(g.Parameters)
+	if err != nil {
+		return Tool{}, ToolExample{}, err
+	}
+	tool = tool.WithMetadata(g.Description, []string{"generated"})
+	tool.Draft = true
+
+	example, err := NewToolExample(tool, GeneratedExampleName, g.Example.Arguments, g.Example.ExpectedOutput, nil)
+	if err != nil {
+		return Tool{}, ToolExample{}, err
+	}
+	return tool, example, nil
+}
+
+// generationPrompt asks for a tool implementing description
+func generationPrompt(description string) string {
+	return `Write a Lumina tool: a TypeScript program run with ts-node that implements the description below.
+
+The tool receives its arguments as a JSON object in the LUMINA_ARGS environment variable
+(read it with JSON.parse(process.env.LUMINA_ARGS ?? "{}")), prints its result to stdout
+and exits with a non-zero code on failure.
+
+Reply with a single JSON object and nothing else:
+{
+  "name": "short human-readable name",
+  "description": "one sentence",
+  "code": "the TypeScript source",
+  "parameters": [{"name": "x", "type": "string|number|boolean|object|array", "description": "...", "required": true}],
+  "example": {"arguments": {"x": "..."}, "expected_output": "exact stdout for these arguments"}
+}
+
+Description:
+` + strings.TrimSpace(description)
+}
+
+// repairPrompt asks the model to fix the previous reply
+func repairPrompt(description, previousReply string, failures []string) string {
+	return generationPrompt(description) + `
+
+Your previous reply was:
+` + previousReply + `
+
+It was rejected:
+- ` + strings.Join(failures, "\n- ") + `
+
+Fix the problems and reply with the corrected JSON object only.`
+}
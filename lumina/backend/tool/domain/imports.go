@@ -0,0 +1,229 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var ErrImportNotFound = errors.New("imported tool not found")
+
+// ToolImportPrefix starts the specifier of an import of another saved tool,
+// as in import { x } from "lumina:tools/json-helpers"
+const ToolImportPrefix = "lumina:tools/"
+
+// toolImportPattern matches static imports, re-exports and dynamic imports
+var toolImportPattern = regexp.MustCompile(`(?:\bfrom|\bimport)\s*\(?\s*["']` + regexp.QuoteMeta(ToolImportPrefix) + `([^"'\r\n]+)["']`)
+
+// ImportCycleError reports tools that import each other, directly or not
+type ImportCycleError struct {
+	// Cycle names the tools along the cycle; the first is repeated at the end
+	Cycle []string
+}
+
+func (e *ImportCycleError) Error() string {
+	return "import cycle: " + strings.Join(e.Cycle, " -> ")
+}
+
+// ParseToolImports returns the names of the tools imported by code, in
+// order of first appearance
+func ParseToolImports(code string) []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, match := range toolImportPattern.FindAllStringSubmatch(code, -1) {
+		if !seen[match[1]] {
+			seen[match[1]] = true
+			names = append(names, match[1])
+		}
+	}
+	return names
+}
+
+// ToolDependency is one tool of a dependency graph
+type ToolDependency struct {
+	ToolID string `json:"tool_id"`
+	Name   string `json:"name"`
+	// Specifiers are the import specifiers that resolved to this tool
+	Specifiers []string `json:"specifiers"`
+	// Imports are the IDs of the tools this tool imports
+	Imports []string `json:"imports"`
+}
+
+// ToolDependencyGraph is a tool and everything it imports, transitively
+type ToolDependencyGraph struct {
+	RootID string `json:"root_id"`
+	// Nodes are in dependency order: every tool comes after the tools it
+	// imports, so the root is last
+	Nodes []ToolDependency `json:"nodes"`
+
+	tools map[string]Tool
+}
+
+// Dependencies returns the imported tools in dependency order, without the root
+func (g ToolDependencyGraph) Dependencies() []Tool {
+	var tools []Tool
+	for _, node := range g.Nodes {
+		if node.ToolID != g.RootID {
+			tools = append(tools, g.tools[node.ToolID])
+		}
+	}
+	return tools
+}
+
+// Node returns the graph node of a tool
+func (g ToolDependencyGraph) Node(toolID string) (ToolDependency, bool) {
+	for _, node := range g.Nodes {
+		if node.ToolID == toolID {
+			return node, true
+		}
+	}
+	return ToolDependency{}, false
+}
+
+// ResolveDependencies follows the imports of root through the repository. An
+// import names a tool either exactly or by its slug (see ToolSlug). It fails
+// with ErrImportNotFound for a missing tool and *ImportCycleError for a cycle.
+func ResolveDependencies(root Tool, repository ToolRepository) (ToolDependencyGraph, error) {
+	resolver := &dependencyResolver{
+		repository: repository,
+		graph:      ToolDependencyGraph{RootID: root.ID, tools: make(map[string]Tool)},
+		nodes:      make(map[string]*ToolDependency),
+		visiting:   make(map[string]bool),
+	}
+
+	if err := resolver.visit(root, ""); err != nil {
+		return ToolDependencyGraph{}, err
+	}
+
+	for _, id := range resolver.order {
+		resolver.graph.Nodes = append(resolver.graph.Nodes, *resolver.nodes[id])
+	}
+	return resolver.graph, nil
+}
+
+type dependencyResolver struct {
+	repository ToolRepository
+	graph      ToolDependencyGraph
+	nodes      map[string]*ToolDependency
+	order      []string
+	visiting   map[string]bool
+	path       []string
+	// bySlug is loaded on the first import that is not an exact name
+	bySlug map[string]Tool
+}
+
+func (r *dependencyResolver) visit(tool Tool, specifier string) error {
+	if r.visiting[tool.ID] {
+		return r.cycle(tool)
+	}
+
+	if node, done := r.nodes[tool.ID]; done {
+		node.Specifiers = appendSpecifier(node.Specifiers, specifier)
+		return nil
+	}
+
+	r.visiting[tool.ID] = true
+	r.path = append(r.path, tool.ID)
+	r.graph.tools[tool.ID] = tool
+
+	node := &ToolDependency{
+		ToolID:     tool.ID,
+		Name:       tool.Name,
+		Specifiers: appendSpecifier(nil, specifier),
+		Imports:    []string{},
+	}
+
+	for _, name := range ParseToolImports(tool.Code) {
+		imported, err := r.lookup(name)
+		if err != nil {
+			return fmt.Errorf("%s imports %s%s: %w", tool.Name, ToolImportPrefix, name, err)
+		}
+
+		if err := r.visit(imported, ToolImportPrefix+name); err != nil {
+			return err
+		}
+		if !containsString(node.Imports, imported.ID) {
+			node.Imports = append(node.Imports, imported.ID)
+		}
+	}
+
+	r.path = r.path[:len(r.path)-1]
+	delete(r.visiting, tool.ID)
+	r.nodes[tool.ID] = node
+	r.order = append(r.order, tool.ID)
+	return nil
+}
+
+// cycle builds the error for a tool reached again while its imports are resolved
+func (r *dependencyResolver) cycle(tool Tool) error {
+	start := 0
+	for i, id := range r.path {
+		if id == tool.ID {
+			start = i
+			break
+		}
+	}
+
+	var names []string
+	for _, id := range r.path[start:] {
+		names = append(names, r.graph.tools[id].Name)
+	}
+	names = append(names, tool.Name)
+	return &ImportCycleError{Cycle: names}
+}
+
+func (r *dependencyResolver) lookup(name string) (Tool, error) {
+	tool, err := r.repository.GetByName(name)
+	if err == nil {
+		return tool, nil
+	}
+	if !errors.Is(err, ErrToolNotFound) {
+		return Tool{}, err
+	}
+
+	if r.bySlug == nil {
+		tools, err := r.repository.List()
+		if err != nil {
+			return Tool{}, err
+		}
+
+		// Sort so that a slug shared by several tools resolves the same way every time
+		sort.Slice(tools, func(i, j int) bool { return tools[i].Name < tools[j].Name })
+		r.bySlug = make(map[string]Tool)
+		for _, candidate := range tools {
+			slug := ToolSlug(candidate.Name)
+			if _, taken := r.bySlug[slug]; !taken {
+				r.bySlug[slug] = candidate
+			}
+		}
+	}
+
+	if tool, ok := r.bySlug[name]; ok {
+		return tool, nil
+	}
+	return Tool{}, ErrImportNotFound
+}
+
+func appendSpecifier(specifiers []string, specifier string) []string {
+	if specifier == "" {
+		if specifiers == nil {
+			return []string{}
+		}
+		return specifiers
+	}
+	if containsString(specifiers, specifier) {
+		return specifiers
+	}
+	return append(specifiers, specifier)
+}
+
+func containsString(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,90 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"lumina/backend/tool/domain"
+)
+
+func TestParseToolImports(t *testing.T) {
+	// Given code importing tools in several ways
+	code := `import { parse } from "lumina:tools/json-helpers";
+import './local';
+import "lumina:tools/setup";
+export * from 'lumina:tools/json-helpers';
+const lazy = await import("lumina:tools/Formatter");`
+
+	// When parsing its imports
+	imports := domain.ParseToolImports(code)
+
+	// Then each tool is listed once, in order
+	assert.Equal(t, []string{"json-helpers", "setup", "Formatter"}, imports)
+}
+
+func TestResolveDependencies(t *testing.T) {
+	// Given tools sharing a helper (a diamond) imported by name and by slug
+	repo := NewMockToolRepository()
+	helpers := domain.NewTool("JSON helpers", "export const parse = JSON.parse;")
+	left := domain.NewTool("left", `import { parse } from "lumina:tools/json-helpers"; export const l = 1;`)
+	right := domain.NewTool("right", `import { parse } from "lumina:tools/JSON helpers"; export const r = 2;`)
+	root := domain.NewTool("root", `import { l } from "lumina:tools/left"; import { r } from "lumina:tools/right";`)
+	for _, tool := range []domain.Tool{helpers, left, right, root} {
+		_, err := repo.Save(tool)
+		require.NoError(t, err)
+	}
+
+	// When resolving the dependencies of the root
+	graph, err := domain.ResolveDependencies(root, repo)
+	require.NoError(t, err)
+
+	// Then every tool appears once, after the tools it imports
+	require.Len(t, graph.Nodes, 4)
+	assert.Equal(t, helpers.ID, graph.Nodes[0].ToolID)
+	assert.Equal(t, root.ID, graph.Nodes[3].ToolID)
+	assert.Equal(t, []string{left.ID, right.ID}, graph.Nodes[3].Imports)
+
+	node, ok := graph.Node(helpers.ID)
+	require.True(t, ok)
+	assert.ElementsMatch(t, []string{"lumina:tools/json-helpers", "lumina:tools/JSON helpers"}, node.Specifiers)
+
+	dependencies := graph.Dependencies()
+	require.Len(t, dependencies, 3)
+	assert.Equal(t, helpers.Code, dependencies[0].Code)
+}
+
+func TestResolveDependencies_Missing(t *testing.T) {
+	// Given a tool importing a tool that does not exist
+	repo := NewMockToolRepository()
+	root := domain.NewTool("root", `import { x } from "lumina:tools/missing";`)
+	_, err := repo.Save(root)
+	require.NoError(t, err)
+
+	// When resolving its dependencies
+	_, err = domain.ResolveDependencies(root, repo)
+
+	// Then the missing import is reported
+	assert.ErrorIs(t, err, domain.ErrImportNotFound)
+	assert.Contains(t, err.Error(), "lumina:tools/missing")
+}
+
+func TestResolveDependencies_Cycle(t *testing.T) {
+	// Given tools importing each other through a third one
+	repo := NewMockToolRepository()
+	a := domain.NewTool("a", `import "lumina:tools/b";`)
+	b := domain.NewTool("b", `import "lumina:tools/c";`)
+	c := domain.NewTool("c", `import "lumina:tools/b";`)
+	for _, tool := range []domain.Tool{a, b, c} {
+		_, err := repo.Save(tool)
+		require.NoError(t, err)
+	}
+
+	// When resolving the dependencies of a
+	_, err := domain.ResolveDependencies(a, repo)
+
+	// Then the cycle is named
+	var cycleErr *domain.ImportCycleError
+	require.ErrorAs(t, err, &cycleErr)
+	assert.Equal(t, []string{"b", "c", "b"}, cycleErr.Cycle)
+}
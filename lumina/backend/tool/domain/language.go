@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var ErrInvalidLanguage = errors.New("invalid tool language")
+
+// Languages a tool can be written in. Only TypeScript tools can import other
+// tools and npm packages, are type-checked and are synced to tool files.
+const (
+	LanguageTypeScript = "typescript"
+	// LanguageShell is a POSIX shell script
+	LanguageShell  = "shell"
+	LanguagePython = "python"
+	// LanguageGo is a main package using only the standard library
+	LanguageGo = "go"
+)
+
+// NormalizeLanguage treats an empty language as TypeScript, the language of
+// tools saved before there was a choice
+func NormalizeLanguage(language string) string {
+	language = strings.ToLower(strings.TrimSpace(language))
+	if language == "" {
+		return LanguageTypeScript
+	}
+	return language
+}
+
+// ParseLanguage checks that language is one a tool can be written in
+func ParseLanguage(language string) (string, error) {
+	switch normalized := NormalizeLanguage(language); normalized {
+	case LanguageTypeScript, LanguageShell, LanguagePython, LanguageGo:
+		return normalized, nil
+	}
+	return "", fmt.Errorf("%w: %q", ErrInvalidLanguage, language)
+}
+
+// IsTypeScript reports whether the tool is written in TypeScript
+func (t Tool) IsTypeScript() bool {
+	return NormalizeLanguage(t.Language) == LanguageTypeScript
+}
+
+// WithLanguage returns a copy of the tool whose code is run as language
+func (t Tool) WithLanguage(language string) (Tool, error) {
+	parsed, err := ParseLanguage(language)
+	if err != nil {
+		return Tool{}, err
+	}
+
+	changed := t
+	changed.Language = parsed
+	changed.UpdatedAt = time.Now()
+	return changed, nil
+}
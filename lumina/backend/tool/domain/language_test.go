@@ -0,0 +1,57 @@
+package domain_test
+
+import (
+	"testing"
+
+	"lumina/backend/tool/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLanguage(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected string
+		valid    bool
+	}{
+		{"", domain.LanguageTypeScript, true},
+		{"typescript", domain.LanguageTypeScript, true},
+		{" Python ", domain.LanguagePython, true},
+		{"shell", domain.LanguageShell, true},
+		{"go", domain.LanguageGo, true},
+		{"cobol", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			// When
+			language, err := domain.ParseLanguage(tt.value)
+
+			// Then
+			if tt.valid {
+				require.NoError(t, err)
+				assert.Equal(t, tt.expected, language)
+			} else {
+				assert.ErrorIs(t, err, domain.ErrInvalidLanguage)
+			}
+		})
+	}
+}
+
+func TestTool_WithLanguage(t *testing.T) {
+	// Given a tool saved before tools had a language
+	tool := domain.NewTool("Legacy", "console.log(1);")
+	tool.Language = ""
+	assert.True(t, tool.IsTypeScript())
+
+	// When
+	shell, err := tool.WithLanguage("shell")
+	_, invalidErr := tool.WithLanguage("cobol")
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, domain.LanguageShell, shell.Language)
+	assert.False(t, shell.IsTypeScript())
+	assert.ErrorIs(t, invalidErr, domain.ErrInvalidLanguage)
+}
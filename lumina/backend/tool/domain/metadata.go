@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// ToolQuery filters tools; empty fields match every tool
+type ToolQuery struct {
+	// Text is matched against name, description and code; each word may be a prefix
+	Text string `json:"text"`
+	Tag  string `json:"tag"`
+}
+
+// WithMetadata returns a copy of the tool with the given description and tags
+func (t Tool) WithMetadata(description string, tags []string) Tool {
+	updated := t
+	updated.Description = strings.TrimSpace(description)
+	updated.Tags = NormalizeTags(tags)
+	updated.UpdatedAt = time.Now()
+	return updated
+}
+
+// NormalizeTags lowercases tags, joins inner whitespace with dashes, and
+// returns them sorted without blanks or duplicates
+func NormalizeTags(tags []string) []string {
+	seen := make(map[string]bool)
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = NormalizeTag(tag)
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+
+	sort.Strings(normalized)
+	return normalized
+}
+
+// NormalizeTag is the form a single tag is stored and looked up in
+func NormalizeTag(tag string) string {
+	return strings.ToLower(strings.Join(strings.Fields(tag), "-"))
+}
@@ -0,0 +1,29 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"lumina/backend/tool/domain"
+)
+
+func TestNormalizeTags(t *testing.T) {
+	// When normalizing tags with mixed case, spacing and duplicates
+	tags := domain.NormalizeTags([]string{" Git ", "code  review", "git", "", "API"})
+
+	// Then they are lowercased, dashed, deduplicated and sorted
+	assert.Equal(t, []string{"api", "code-review", "git"}, tags)
+}
+
+func TestTool_WithMetadata(t *testing.T) {
+	// Given a tool
+	tool := domain.NewTool("Metadata Tool", "code")
+
+	// When setting its description and tags
+	updated := tool.WithMetadata("  Prints the weather  ", []string{"Weather", "http"})
+
+	// Then they are trimmed and normalized
+	assert.Equal(t, "Prints the weather", updated.Description)
+	assert.Equal(t, []string{"http", "weather"}, updated.Tags)
+	assert.Equal(t, tool.Code, updated.Code)
+}
@@ -0,0 +1,155 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+var ErrInvalidNamespace = errors.New("invalid tool namespace")
+
+// GlobalNamespace holds the tools visible in every project
+const GlobalNamespace = "global"
+
+const (
+	projectNamespacePrefix = "project:"
+	teamNamespacePrefix    = "team:"
+)
+
+// NamespaceKind is the scope a namespace stands for
+type NamespaceKind string
+
+const (
+	NamespaceGlobal  NamespaceKind = "global"
+	NamespaceProject NamespaceKind = "project"
+	NamespaceTeam    NamespaceKind = "team"
+)
+
+// ProjectNamespace is the namespace of the tools of the project rooted at root
+func ProjectNamespace(root string) string {
+	return projectNamespacePrefix + filepath.Clean(root)
+}
+
+// TeamNamespace is the namespace of the tools imported from a team
+func TeamNamespace(team string) string {
+	return teamNamespacePrefix + strings.TrimSpace(team)
+}
+
+// NormalizeNamespace treats an empty namespace as the global one
+func NormalizeNamespace(namespace string) string {
+	if strings.TrimSpace(namespace) == "" {
+		return GlobalNamespace
+	}
+	return strings.TrimSpace(namespace)
+}
+
+// ParseNamespace checks that namespace is "global", "project:<root>" or
+// "team:<name>" and returns its kind
+func ParseNamespace(namespace string) (NamespaceKind, error) {
+	namespace = NormalizeNamespace(namespace)
+	switch {
+	case namespace == GlobalNamespace:
+		return NamespaceGlobal, nil
+	case strings.HasPrefix(namespace, projectNamespacePrefix) && len(namespace) > len(projectNamespacePrefix):
+		return NamespaceProject, nil
+	case strings.HasPrefix(namespace, teamNamespacePrefix) && len(namespace) > len(teamNamespacePrefix):
+		return NamespaceTeam, nil
+	}
+	return "", fmt.Errorf("%w: %q", ErrInvalidNamespace, namespace)
+}
+
+// WithNamespace returns a copy of the tool moved to namespace
+func (t Tool) WithNamespace(namespace string) (Tool, error) {
+	if _, err := ParseNamespace(namespace); err != nil {
+		return Tool{}, err
+	}
+
+	moved := t
+	moved.Namespace = NormalizeNamespace(namespace)
+	return moved, nil
+}
+
+// ToolScope is the library as seen from one project: its own tools, the
+// tools imported from teams and the global tools. Other projects' tools are
+// not visible.
+//
+// When several visible tools share a name, the project's tool wins over a
+// team's, which wins over the global one; between teams, the first team in
+// alphabetical order wins.
+type ToolScope struct {
+	ProjectRoot string
+}
+
+// Visible reports whether tools in namespace can be seen from the scope
+func (s ToolScope) Visible(namespace string) bool {
+	kind, err := ParseNamespace(namespace)
+	if err != nil {
+		return false
+	}
+	if kind == NamespaceProject {
+		return s.ProjectRoot != "" && NormalizeNamespace(namespace) == ProjectNamespace(s.ProjectRoot)
+	}
+	return true
+}
+
+// precedence ranks the visible namespaces; lower wins
+func (s ToolScope) precedence(namespace string) int {
+	kind, _ := ParseNamespace(namespace)
+	switch kind {
+	case NamespaceProject:
+		return 0
+	case NamespaceTeam:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// wins reports whether a tool in namespace a shadows one of the same name in b
+func (s ToolScope) wins(a, b string) bool {
+	if s.precedence(a) != s.precedence(b) {
+		return s.precedence(a) < s.precedence(b)
+	}
+	return NormalizeNamespace(a) < NormalizeNamespace(b)
+}
+
+// VisibleTools keeps the tools the scope sees, dropping those shadowed by a
+// tool of the same name; the order of tools is kept
+func (s ToolScope) VisibleTools(tools []Tool) []Tool {
+	winners := make(map[string]Tool)
+	for _, tool := range tools {
+		if !s.Visible(tool.Namespace) {
+			continue
+		}
+		if current, ok := winners[tool.Name]; !ok || s.wins(tool.Namespace, current.Namespace) {
+			winners[tool.Name] = tool
+		}
+	}
+
+	visible := make([]Tool, 0, len(winners))
+	for _, tool := range tools {
+		if winner, ok := winners[tool.Name]; ok && winner.ID == tool.ID {
+			visible = append(visible, tool)
+		}
+	}
+	return visible
+}
+
+// Namespaces returns the visible namespaces among tools, in precedence order
+func (s ToolScope) Namespaces(tools []Tool) []string {
+	seen := make(map[string]bool)
+	var namespaces []string
+	for _, tool := range tools {
+		namespace := NormalizeNamespace(tool.Namespace)
+		if !seen[namespace] && s.Visible(namespace) {
+			seen[namespace] = true
+			namespaces = append(namespaces, namespace)
+		}
+	}
+	sort.Slice(namespaces, func(i, j int) bool {
+		return s.wins(namespaces[i], namespaces[j])
+	})
+	return namespaces
+}
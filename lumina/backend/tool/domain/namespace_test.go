@@ -0,0 +1,115 @@
+package domain_test
+
+import (
+	"testing"
+
+	"lumina/backend/tool/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseNamespace(t *testing.T) {
+	cases := map[string]domain.NamespaceKind{
+		"":                 domain.NamespaceGlobal,
+		"global":           domain.NamespaceGlobal,
+		"project:/src/app": domain.NamespaceProject,
+		"team:platform":    domain.NamespaceTeam,
+	}
+	for namespace, kind := range cases {
+		parsed, err := domain.ParseNamespace(namespace)
+		require.NoError(t, err, namespace)
+		assert.Equal(t, kind, parsed, namespace)
+	}
+
+	for _, invalid := range []string{"project:", "team:", "personal"} {
+		_, err := domain.ParseNamespace(invalid)
+		assert.ErrorIs(t, err, domain.ErrInvalidNamespace, invalid)
+	}
+}
+
+// namespacedTool creates a tool named name in namespace
+func namespacedTool(t *testing.T, name, namespace string) domain.Tool {
+	tool, err := domain.NewTool(name, "console.log('"+namespace+"')").WithNamespace(namespace)
+	require.NoError(t, err)
+	return tool
+}
+
+func TestToolScope_VisibleTools(t *testing.T) {
+	// Given tools sharing names across namespaces, and another project's tool
+	scope := domain.ToolScope{ProjectRoot: "/src/app"}
+	globalFormat := namespacedTool(t, "Format", domain.GlobalNamespace)
+	projectFormat := namespacedTool(t, "Format", domain.ProjectNamespace("/src/app"))
+	globalLint := namespacedTool(t, "Lint", domain.GlobalNamespace)
+	zetaLint := namespacedTool(t, "Lint", domain.TeamNamespace("zeta"))
+	alphaLint := namespacedTool(t, "Lint", domain.TeamNamespace("alpha"))
+	otherProject := namespacedTool(t, "Deploy", domain.ProjectNamespace("/src/other"))
+
+	// When
+	visible := scope.VisibleTools([]domain.Tool{globalFormat, projectFormat, globalLint, zetaLint, alphaLint, otherProject})
+
+	// Then the project wins over the global tool, the first team over the
+	// other team and the global tool, and the other project is hidden
+	require.Len(t, visible, 2)
+	assert.Equal(t, projectFormat.ID, visible[0].ID)
+	assert.Equal(t, alphaLint.ID, visible[1].ID)
+}
+
+func TestScopedToolRepository(t *testing.T) {
+	// Given a global and a project tool with the same name
+	repo := NewMockToolRepository()
+	global := namespacedTool(t, "Format", domain.GlobalNamespace)
+	project := namespacedTool(t, "Format", domain.ProjectNamespace("/src/app"))
+	_, err := repo.Save(global)
+	require.NoError(t, err)
+	_, err = repo.Save(project)
+	require.NoError(t, err)
+
+	// When seen from the project and from another one
+	inProject := domain.NewScopedToolRepository(repo, domain.ToolScope{ProjectRoot: "/src/app"})
+	elsewhere := domain.NewScopedToolRepository(repo, domain.ToolScope{ProjectRoot: "/src/other"})
+
+	// Then the name resolves to the project's tool only inside the project
+	resolved, err := inProject.GetByName("Format")
+	require.NoError(t, err)
+	assert.Equal(t, project.ID, resolved.ID)
+	resolved, err = elsewhere.GetByName("Format")
+	require.NoError(t, err)
+	assert.Equal(t, global.ID, resolved.ID)
+
+	// And search never returns the shadowed tool
+	found, err := inProject.Search(domain.ToolQuery{Text: "format"})
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+	assert.Equal(t, project.ID, found[0].ID)
+
+	// And the shadowed tool is still listed in its namespace
+	inGlobal, err := inProject.ListNamespace(domain.GlobalNamespace)
+	require.NoError(t, err)
+	require.Len(t, inGlobal, 1)
+	assert.Equal(t, global.ID, inGlobal[0].ID)
+
+	namespaces, err := inProject.Namespaces()
+	require.NoError(t, err)
+	assert.Equal(t, []string{domain.ProjectNamespace("/src/app"), domain.GlobalNamespace}, namespaces)
+}
+
+func TestScopedToolRepository_ListTags(t *testing.T) {
+	// Given tagged tools in the project and in another project
+	repo := NewMockToolRepository()
+	project := namespacedTool(t, "Format", domain.ProjectNamespace("/src/app"))
+	project.Tags = []string{"text"}
+	other := namespacedTool(t, "Deploy", domain.ProjectNamespace("/src/other"))
+	other.Tags = []string{"secret"}
+	_, err := repo.Save(project)
+	require.NoError(t, err)
+	_, err = repo.Save(other)
+	require.NoError(t, err)
+
+	// When listing tags from the project
+	tags, err := domain.NewScopedToolRepository(repo, domain.ToolScope{ProjectRoot: "/src/app"}).ListTags()
+
+	// Then only the tags of visible tools are listed
+	require.NoError(t, err)
+	assert.Equal(t, []string{"text"}, tags)
+}
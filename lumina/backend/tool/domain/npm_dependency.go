@@ -0,0 +1,107 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+var ErrInvalidDependencies = errors.New("invalid npm dependencies")
+
+// npmNamePattern accepts plain and scoped package names, e.g. zod or @types/node
+var npmNamePattern = regexp.MustCompile(`^(@[a-z0-9][a-z0-9._~-]*/)?[a-z0-9][a-z0-9._~-]*$`)
+
+// NpmDependency is an npm package a tool imports
+type NpmDependency struct {
+	Name string `json:"name"`
+	// Version is anything npm install accepts after the name: an exact
+	// version, a range, a tag, or a file: path to a tarball
+	Version string `json:"version"`
+}
+
+// ValidateDependencies checks that every dependency has a valid npm name and
+// a version, and that no package is declared twice
+func ValidateDependencies(dependencies []NpmDependency) error {
+	seen := make(map[string]bool)
+	for _, dependency := range dependencies {
+		if !npmNamePattern.MatchString(dependency.Name) {
+			return fmt.Errorf("%w: %q is not a valid package name", ErrInvalidDependencies, dependency.Name)
+		}
+		if strings.TrimSpace(dependency.Version) == "" {
+			return fmt.Errorf("%w: %s has no version", ErrInvalidDependencies, dependency.Name)
+		}
+		if seen[dependency.Name] {
+			return fmt.Errorf("%w: %s is declared twice", ErrInvalidDependencies, dependency.Name)
+		}
+		seen[dependency.Name] = true
+	}
+
+	return nil
+}
+
+// WithDependencies returns a copy of the tool declaring the given npm
+// dependencies, sorted by name
+func (t Tool) WithDependencies(dependencies []NpmDependency) (Tool, error) {
+	normalized := make([]NpmDependency, 0, len(dependencies))
+	for _, dependency := range dependencies {
+		normalized = append(normalized, NpmDependency{
+			Name:    strings.TrimSpace(dependency.Name),
+			Version: strings.TrimSpace(dependency.Version),
+		})
+	}
+	if err := ValidateDependencies(normalized); err != nil {
+		return Tool{}, err
+	}
+	sort.Slice(normalized, func(i, j int) bool { return normalized[i].Name < normalized[j].Name })
+
+	updated := t
+	updated.Dependencies = normalized
+	updated.UpdatedAt = time.Now()
+	return updated, nil
+}
+
+// PinnedDependencies returns the dependencies of revision with the versions
+// resolved when it first ran, so later runs install the same packages.
+// Dependencies without a resolved version keep their declared version.
+func (r ToolRevision) PinnedDependencies() []NpmDependency {
+	pinned := make([]NpmDependency, 0, len(r.Dependencies))
+	for _, dependency := range r.Dependencies {
+		if version, ok := r.ResolvedVersions[dependency.Name]; ok && !strings.HasPrefix(dependency.Version, "file:") {
+			dependency.Version = version
+		}
+		pinned = append(pinned, dependency)
+	}
+	return pinned
+}
+
+// NeedsResolvedVersions reports whether some dependency of the revision has
+// not been resolved yet
+func (r ToolRevision) NeedsResolvedVersions() bool {
+	for _, dependency := range r.Dependencies {
+		if _, ok := r.ResolvedVersions[dependency.Name]; !ok {
+			return true
+		}
+	}
+	return false
+}
+
+// MergeDependencies combines the dependencies of a tool and of the tools it
+// imports; the first declaration of a package wins, so list the root first
+func MergeDependencies(lists ...[]NpmDependency) []NpmDependency {
+	seen := make(map[string]bool)
+	var merged []NpmDependency
+	for _, list := range lists {
+		for _, dependency := range list {
+			if !seen[dependency.Name] {
+				seen[dependency.Name] = true
+				merged = append(merged, dependency)
+			}
+		}
+	}
+
+	sort.Slice(merged, func(i, j int) bool { return merged[i].Name < merged[j].Name })
+	return merged
+}
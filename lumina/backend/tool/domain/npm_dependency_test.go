@@ -0,0 +1,95 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"lumina/backend/tool/domain"
+)
+
+func TestValidateDependencies(t *testing.T) {
+	tests := []struct {
+		name         string
+		dependencies []domain.NpmDependency
+		wantErr      bool
+	}{
+		{"none", nil, false},
+		{"plain and scoped", []domain.NpmDependency{{Name: "zod", Version: "^3.23.0"}, {Name: "@types/node", Version: "20"}}, false},
+		{"tarball", []domain.NpmDependency{{Name: "yaml", Version: "file:/mirror/yaml-2.4.1.tgz"}}, false},
+		{"uppercase name", []domain.NpmDependency{{Name: "Lodash", Version: "4"}}, true},
+		{"missing version", []domain.NpmDependency{{Name: "lodash", Version: " "}}, true},
+		{"declared twice", []domain.NpmDependency{{Name: "zod", Version: "3"}, {Name: "zod", Version: "4"}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := domain.ValidateDependencies(tt.dependencies)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, domain.ErrInvalidDependencies)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestTool_WithDependencies(t *testing.T) {
+	// Given
+	tool := domain.NewTool("Parse YAML", "import YAML from 'yaml'")
+
+	// When
+	updated, err := tool.WithDependencies([]domain.NpmDependency{
+		{Name: " zod ", Version: "^3.23.0"},
+		{Name: "yaml", Version: "2.4.1 "},
+	})
+
+	// Then they are trimmed and sorted by name
+	require.NoError(t, err)
+	assert.Equal(t, []domain.NpmDependency{
+		{Name: "yaml", Version: "2.4.1"},
+		{Name: "zod", Version: "^3.23.0"},
+	}, updated.Dependencies)
+	assert.Empty(t, tool.Dependencies)
+}
+
+func TestToolRevision_PinnedDependencies(t *testing.T) {
+	// Given a revision whose first run resolved one of its ranges
+	revision := domain.ToolRevision{
+		Dependencies: []domain.NpmDependency{
+			{Name: "lodash", Version: "^4.17.0"},
+			{Name: "yaml", Version: "file:/mirror/yaml-2.4.1.tgz"},
+			{Name: "zod", Version: "^3.23.0"},
+		},
+		ResolvedVersions: map[string]string{"lodash": "4.17.21", "yaml": "2.4.1"},
+	}
+
+	// When
+	pinned := revision.PinnedDependencies()
+
+	// Then resolved ranges are pinned, tarballs and unresolved ranges are kept
+	assert.Equal(t, []domain.NpmDependency{
+		{Name: "lodash", Version: "4.17.21"},
+		{Name: "yaml", Version: "file:/mirror/yaml-2.4.1.tgz"},
+		{Name: "zod", Version: "^3.23.0"},
+	}, pinned)
+	assert.True(t, revision.NeedsResolvedVersions())
+
+	revision.ResolvedVersions["zod"] = "3.23.8"
+	assert.False(t, revision.NeedsResolvedVersions())
+}
+
+func TestMergeDependencies(t *testing.T) {
+	// Given the root's dependencies and those of an imported tool
+	root := []domain.NpmDependency{{Name: "zod", Version: "3.23.8"}}
+	imported := []domain.NpmDependency{{Name: "zod", Version: "^3.0.0"}, {Name: "lodash", Version: "4"}}
+
+	// When
+	merged := domain.MergeDependencies(root, imported)
+
+	// Then the root's declaration wins
+	assert.Equal(t, []domain.NpmDependency{
+		{Name: "lodash", Version: "4"},
+		{Name: "zod", Version: "3.23.8"},
+	}, merged)
+}
@@ -0,0 +1,188 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidParameters = errors.New("invalid tool parameters")
+	ErrInvalidArguments  = errors.New("invalid tool arguments")
+)
+
+// ParameterType is the JSON type of a tool argument
+type ParameterType string
+
+const (
+	ParameterString  ParameterType = "string"
+	ParameterNumber  ParameterType = "number"
+	ParameterBoolean ParameterType = "boolean"
+	ParameterObject  ParameterType = "object"
+	ParameterArray   ParameterType = "array"
+)
+
+var parameterNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// ToolParameter declares one argument a tool accepts
+type ToolParameter struct {
+	Name        string        `json:"name"`
+	Type        ParameterType `json:"type"`
+	Description string        `json:"description"`
+	// Default is used when the argument is omitted; nil means no default
+	Default  interface{} `json:"default,omitempty"`
+	Required bool        `json:"required"`
+}
+
+// ValidateParameters checks that a schema has unique, well-formed names,
+// known types and defaults of the declared type
+func ValidateParameters(parameters []ToolParameter) error {
+	seen := make(map[string]bool)
+	for _, parameter := range parameters {
+		if !parameterNamePattern.MatchString(parameter.Name) {
+			return fmt.Errorf("%w: %q is not a valid parameter name", ErrInvalidParameters, parameter.Name)
+		}
+		if seen[parameter.Name] {
+			return fmt.Errorf("%w: %s is declared twice", ErrInvalidParameters, parameter.Name)
+		}
+		seen[parameter.Name] = true
+
+		switch parameter.Type {
+		case ParameterString, ParameterNumber, ParameterBoolean, ParameterObject, ParameterArray:
+		default:
+			return fmt.Errorf("%w: %s has unknown type %q", ErrInvalidParameters, parameter.Name, parameter.Type)
+		}
+
+		if parameter.Default != nil {
+			if _, err := parameter.coerce(parameter.Default); err != nil {
+				return fmt.Errorf("%w: default of %s: %v", ErrInvalidParameters, parameter.Name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// WithParameters returns a copy of the tool declaring the given parameters
+func (t Tool) WithParameters(parameters []ToolParameter) (Tool, error) {
+	if err := ValidateParameters(parameters); err != nil {
+		return Tool{}, err
+	}
+
+	updated := t
+	updated.Parameters = parameters
+	updated.UpdatedAt = time.Now()
+	return updated, nil
+}
+
+// ResolveArguments validates args against the tool's parameters and returns
+// them with defaults filled in. String values are converted to the declared
+// type, so arguments typed on a command line are accepted too.
+func (t Tool) ResolveArguments(args map[string]interface{}) (map[string]interface{}, error) {
+	declared := make(map[string]bool)
+	for _, parameter := range t.Parameters {
+		declared[parameter.Name] = true
+	}
+	for name := range args {
+		if !declared[name] {
+			return nil, fmt.Errorf("%w: unknown argument %s", ErrInvalidArguments, name)
+		}
+	}
+
+	resolved := make(map[string]interface{})
+	for _, parameter := range t.Parameters {
+		value, ok := args[parameter.Name]
+		if !ok || value == nil {
+			if parameter.Default != nil {
+				value = parameter.Default
+			} else if parameter.Required {
+				return nil, fmt.Errorf("%w: missing required argument %s", ErrInvalidArguments, parameter.Name)
+			} else {
+				continue
+			}
+		}
+
+		coerced, err := parameter.coerce(value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", ErrInvalidArguments, parameter.Name, err)
+		}
+		resolved[parameter.Name] = coerced
+	}
+
+	return resolved, nil
+}
+
+// coerce converts value to the parameter's type or explains why it cannot
+func (p ToolParameter) coerce(value interface{}) (interface{}, error) {
+	text, isText := value.(string)
+
+	switch p.Type {
+	case ParameterString:
+		if isText {
+			return text, nil
+		}
+
+	case ParameterNumber:
+		if isText {
+			number, err := strconv.ParseFloat(strings.TrimSpace(text), 64)
+			if err != nil {
+				return nil, fmt.Errorf("expected a number, got %q", text)
+			}
+			return number, nil
+		}
+		switch number := value.(type) {
+		case float64:
+			return number, nil
+		case float32:
+			return float64(number), nil
+		case int:
+			return float64(number), nil
+		case int64:
+			return float64(number), nil
+		case json.Number:
+			return number.Float64()
+		}
+
+	case ParameterBoolean:
+		if isText {
+			boolean, err := strconv.ParseBool(strings.TrimSpace(text))
+			if err != nil {
+				return nil, fmt.Errorf("expected a boolean, got %q", text)
+			}
+			return boolean, nil
+		}
+		if boolean, ok := value.(bool); ok {
+			return boolean, nil
+		}
+
+	case ParameterObject:
+		if isText {
+			var object map[string]interface{}
+			if err := json.Unmarshal([]byte(text), &object); err != nil || object == nil {
+				return nil, fmt.Errorf("expected a JSON object, got %q", text)
+			}
+			return object, nil
+		}
+		if object, ok := value.(map[string]interface{}); ok {
+			return object, nil
+		}
+
+	case ParameterArray:
+		if isText {
+			var array []interface{}
+			if err := json.Unmarshal([]byte(text), &array); err != nil || array == nil {
+				return nil, fmt.Errorf("expected a JSON array, got %q", text)
+			}
+			return array, nil
+		}
+		if array, ok := value.([]interface{}); ok {
+			return array, nil
+		}
+	}
+
+	return nil, fmt.Errorf("expected %s, got %T", p.Type, value)
+}
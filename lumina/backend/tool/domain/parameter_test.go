@@ -0,0 +1,119 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"lumina/backend/tool/domain"
+)
+
+func TestValidateParameters(t *testing.T) {
+	testCases := []struct {
+		name        string
+		parameters  []domain.ToolParameter
+		expectError bool
+	}{
+		{
+			name: "valid schema",
+			parameters: []domain.ToolParameter{
+				{Name: "city", Type: domain.ParameterString, Required: true},
+				{Name: "days", Type: domain.ParameterNumber, Default: 3.0},
+				{Name: "verbose", Type: domain.ParameterBoolean, Default: false},
+			},
+			expectError: false,
+		},
+		{
+			name:        "invalid name should fail",
+			parameters:  []domain.ToolParameter{{Name: "my-arg", Type: domain.ParameterString}},
+			expectError: true,
+		},
+		{
+			name: "duplicate name should fail",
+			parameters: []domain.ToolParameter{
+				{Name: "city", Type: domain.ParameterString},
+				{Name: "city", Type: domain.ParameterNumber},
+			},
+			expectError: true,
+		},
+		{
+			name:        "unknown type should fail",
+			parameters:  []domain.ToolParameter{{Name: "when", Type: "date"}},
+			expectError: true,
+		},
+		{
+			name:        "default of the wrong type should fail",
+			parameters:  []domain.ToolParameter{{Name: "days", Type: domain.ParameterNumber, Default: "many"}},
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// When validating the schema
+			err := domain.ValidateParameters(tc.parameters)
+
+			// Then it should fail only for malformed schemas
+			if tc.expectError {
+				assert.ErrorIs(t, err, domain.ErrInvalidParameters)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestTool_ResolveArguments(t *testing.T) {
+	// Given a tool with typed parameters
+	tool, err := domain.NewTool("Forecast", "code").WithParameters([]domain.ToolParameter{
+		{Name: "city", Type: domain.ParameterString, Required: true},
+		{Name: "days", Type: domain.ParameterNumber, Default: 3.0},
+		{Name: "verbose", Type: domain.ParameterBoolean},
+		{Name: "units", Type: domain.ParameterArray},
+	})
+	require.NoError(t, err)
+
+	// When resolving arguments given as strings
+	resolved, err := tool.ResolveArguments(map[string]interface{}{
+		"city":    "Sofia",
+		"verbose": "true",
+		"units":   `["metric"]`,
+	})
+
+	// Then they are converted and defaults are filled in
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"city":    "Sofia",
+		"days":    3.0,
+		"verbose": true,
+		"units":   []interface{}{"metric"},
+	}, resolved)
+}
+
+func TestTool_ResolveArguments_Invalid(t *testing.T) {
+	// Given a tool with typed parameters
+	tool, err := domain.NewTool("Forecast", "code").WithParameters([]domain.ToolParameter{
+		{Name: "city", Type: domain.ParameterString, Required: true},
+		{Name: "days", Type: domain.ParameterNumber},
+	})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name string
+		args map[string]interface{}
+	}{
+		{name: "missing required argument", args: map[string]interface{}{"days": 2.0}},
+		{name: "wrong type", args: map[string]interface{}{"city": "Sofia", "days": "soon"}},
+		{name: "unknown argument", args: map[string]interface{}{"city": "Sofia", "country": "BG"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// When resolving the arguments
+			_, err := tool.ResolveArguments(tc.args)
+
+			// Then they are rejected
+			assert.ErrorIs(t, err, domain.ErrInvalidArguments)
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrPipelineNameEmpty = errors.New("pipeline name cannot be empty")
+	ErrPipelineNameTaken = errors.New("pipeline name already taken")
+	ErrPipelineNoSteps   = errors.New("pipeline needs at least one step")
+	ErrPipelineNotFound  = errors.New("pipeline not found")
+)
+
+// PipelineStep runs one tool of a pipeline
+type PipelineStep struct {
+	ToolID string `json:"tool_id"`
+	// Arguments are fixed arguments of the step; they win over piped values
+	Arguments map[string]interface{} `json:"arguments"`
+	// InputParameter receives the JSON output of the previous step. When it is
+	// empty the previous output must be an object whose fields are passed as
+	// the arguments of the same name.
+	InputParameter string `json:"input_parameter"`
+}
+
+// Pipeline chains tools so that the JSON printed by one step is the input of
+// the next
+type Pipeline struct {
+	ID        string         `json:"id"`
+	Name      string         `json:"name"`
+	Steps     []PipelineStep `json:"steps"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+}
+
+// NewPipeline creates a pipeline running steps in order
+func NewPipeline(name string, steps []PipelineStep) (Pipeline, error) {
+	now := time.Now()
+	pipeline := Pipeline{
+		ID:        uuid.New().String(),
+		Name:      strings.TrimSpace(name),
+		Steps:     steps,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if err := pipeline.Validate(); err != nil {
+		return Pipeline{}, err
+	}
+	return pipeline, nil
+}
+
+// Validate checks the shape of the pipeline; the tools are checked when it is saved
+func (p Pipeline) Validate() error {
+	if p.Name == "" {
+		return ErrPipelineNameEmpty
+	}
+	if len(p.Steps) == 0 {
+		return ErrPipelineNoSteps
+	}
+	for i, step := range p.Steps {
+		if strings.TrimSpace(step.ToolID) == "" {
+			return fmt.Errorf("step %d: %w", i+1, ErrToolNotFound)
+		}
+	}
+	return nil
+}
+
+// stepArguments combines the fixed arguments of a step with the value piped
+// from the previous step; previous is nil for the first step
+func (s PipelineStep) stepArguments(tool Tool, previous interface{}) (map[string]interface{}, error) {
+	args := make(map[string]interface{})
+
+	if previous != nil {
+		if s.InputParameter != "" {
+			args[s.InputParameter] = previous
+		} else {
+			fields, ok := previous.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("%w: previous step printed %s, expected a JSON object", ErrInvalidArguments, jsonKind(previous))
+			}
+			// Fields the tool does not declare are left out, so a step may
+			// ignore part of what the previous one printed
+			for _, parameter := range tool.Parameters {
+				if value, ok := fields[parameter.Name]; ok {
+					args[parameter.Name] = value
+				}
+			}
+		}
+	}
+
+	for name, value := range s.Arguments {
+		args[name] = value
+	}
+
+	return tool.ResolveArguments(args)
+}
+
+// parseStepOutput reads the JSON a step printed
+func parseStepOutput(output string) (interface{}, error) {
+	var value interface{}
+	if err := json.Unmarshal([]byte(strings.TrimSpace(output)), &value); err != nil {
+		return nil, fmt.Errorf("output is not JSON: %v", err)
+	}
+	return value, nil
+}
+
+func jsonKind(value interface{}) string {
+	switch value.(type) {
+	case []interface{}:
+		return "an array"
+	case string:
+		return "a string"
+	case float64:
+		return "a number"
+	case bool:
+		return "a boolean"
+	}
+	return "null"
+}
+
+// PipelineStepResult is the outcome of one step of a pipeline run
+type PipelineStepResult struct {
+	Index     int                    `json:"index"`
+	ToolID    string                 `json:"tool_id"`
+	ToolName  string                 `json:"tool_name"`
+	Arguments map[string]interface{} `json:"arguments"`
+	Output    ToolRunOutput          `json:"output"`
+	// Value is the JSON the step printed, passed on to the next step
+	Value   interface{} `json:"value"`
+	Success bool        `json:"success"`
+	Error   string      `json:"error"`
+}
+
+// PipelineRunResult is the outcome of a pipeline run; it stops at the first failed step
+type PipelineRunResult struct {
+	PipelineID string               `json:"pipeline_id"`
+	Steps      []PipelineStepResult `json:"steps"`
+	Success    bool                 `json:"success"`
+	// FailedStep is the index of the step that failed, -1 when none did
+	FailedStep int `json:"failed_step"`
+	// Output is what the last step printed
+	Output     string `json:"output"`
+	DurationMs int64  `json:"duration_ms"`
+}
@@ -0,0 +1,13 @@
+package domain
+
+// PipelineRepository defines the interface for pipeline persistence
+type PipelineRepository interface {
+	// Save creates or updates a pipeline; it fails with ErrPipelineNameTaken
+	// when another pipeline has the name
+	Save(pipeline Pipeline) error
+	GetByID(id string) (Pipeline, error)
+	// List returns every pipeline ordered by name
+	List() ([]Pipeline, error)
+	Delete(id string) error
+	Close() error
+}
@@ -0,0 +1,117 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// PipelineService saves and runs pipelines of tools
+type PipelineService struct {
+	tools     ToolRepository
+	pipelines PipelineRepository
+	runner    ToolRunner
+}
+
+func NewPipelineService(tools ToolRepository, pipelines PipelineRepository, runner ToolRunner) *PipelineService {
+	return &PipelineService{
+		tools:     tools,
+		pipelines: pipelines,
+		runner:    runner,
+	}
+}
+
+// Save stores a pipeline after checking that every step names a saved tool
+func (s *PipelineService) Save(pipeline Pipeline) error {
+	if err := pipeline.Validate(); err != nil {
+		return err
+	}
+
+	for i, step := range pipeline.Steps {
+		if _, err := s.tools.GetByID(step.ToolID); err != nil {
+			return fmt.Errorf("step %d: %w", i+1, err)
+		}
+	}
+
+	return s.pipelines.Save(pipeline)
+}
+
+// Run executes the steps of a pipeline in order. input is piped into the
+// first step like the output of a previous one; it may be nil.
+func (s *PipelineService) Run(pipelineID string, input map[string]interface{}) (PipelineRunResult, error) {
+	pipeline, err := s.pipelines.GetByID(pipelineID)
+	if err != nil {
+		return PipelineRunResult{}, err
+	}
+
+	started := time.Now()
+	result := PipelineRunResult{
+		PipelineID: pipeline.ID,
+		Steps:      []PipelineStepResult{},
+		Success:    true,
+		FailedStep: -1,
+	}
+
+	var previous interface{}
+	if input != nil {
+		previous = input
+	}
+
+	for i, step := range pipeline.Steps {
+		stepResult := s.runStep(i, step, previous, i == len(pipeline.Steps)-1)
+		result.Steps = append(result.Steps, stepResult)
+
+		if !stepResult.Success {
+			result.Success = false
+			result.FailedStep = i
+			break
+		}
+
+		previous = stepResult.Value
+		result.Output = stepResult.Output.Output
+	}
+
+	result.DurationMs = time.Since(started).Milliseconds()
+	return result, nil
+}
+
+// runStep never fails the run: problems are reported on the step
+func (s *PipelineService) runStep(index int, step PipelineStep, previous interface{}, last bool) PipelineStepResult {
+	result := PipelineStepResult{Index: index, ToolID: step.ToolID}
+
+	tool, err := s.tools.GetByID(step.ToolID)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.ToolName = tool.Name
+
+	args, err := step.stepArguments(tool, previous)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.Arguments = args
+
+	output, err := s.runner.Run(tool, args)
+	if err != nil {
+		result.Error = fmt.Sprintf("failed to run: %v", err)
+		return result
+	}
+	result.Output = output
+
+	if output.ExitCode != 0 {
+		result.Error = fmt.Sprintf("exited with code %d", output.ExitCode)
+		return result
+	}
+
+	// The last step may print anything; the others feed JSON to the next step
+	value, err := parseStepOutput(output.Output)
+	if err != nil && !last {
+		result.Error = err.Error()
+		return result
+	}
+
+	result.Value = value
+	result.Success = true
+	return result
+}
@@ -0,0 +1,228 @@
+package domain_test
+
+import (
+	"encoding/json"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"lumina/backend/tool/domain"
+)
+
+// ScriptedToolRunner answers each tool with a function of its arguments
+type ScriptedToolRunner struct {
+	scripts map[string]func(args map[string]interface{}) domain.ToolRunOutput
+}
+
+func (s *ScriptedToolRunner) Run(tool domain.Tool, args map[string]interface{}) (domain.ToolRunOutput, error) {
+	return s.scripts[tool.ID](args), nil
+}
+
+func (s *ScriptedToolRunner) RunCode(code string) (domain.ToolRunOutput, error) {
+	return domain.ToolRunOutput{Success: true}, nil
+}
+
+// MockPipelineRepository for testing
+type MockPipelineRepository struct {
+	pipelines map[string]domain.Pipeline
+}
+
+func NewMockPipelineRepository() *MockPipelineRepository {
+	return &MockPipelineRepository{pipelines: make(map[string]domain.Pipeline)}
+}
+
+func (m *MockPipelineRepository) Save(pipeline domain.Pipeline) error {
+	for _, saved := range m.pipelines {
+		if saved.ID != pipeline.ID && saved.Name == pipeline.Name {
+			return domain.ErrPipelineNameTaken
+		}
+	}
+	m.pipelines[pipeline.ID] = pipeline
+	return nil
+}
+
+func (m *MockPipelineRepository) GetByID(id string) (domain.Pipeline, error) {
+	pipeline, exists := m.pipelines[id]
+	if !exists {
+		return domain.Pipeline{}, domain.ErrPipelineNotFound
+	}
+	return pipeline, nil
+}
+
+func (m *MockPipelineRepository) List() ([]domain.Pipeline, error) {
+	var pipelines []domain.Pipeline
+	for _, pipeline := range m.pipelines {
+		pipelines = append(pipelines, pipeline)
+	}
+	sort.Slice(pipelines, func(i, j int) bool { return pipelines[i].Name < pipelines[j].Name })
+	return pipelines, nil
+}
+
+func (m *MockPipelineRepository) Delete(id string) error {
+	if _, exists := m.pipelines[id]; !exists {
+		return domain.ErrPipelineNotFound
+	}
+	delete(m.pipelines, id)
+	return nil
+}
+
+func (m *MockPipelineRepository) Close() error {
+	return nil
+}
+
+func TestPipelineService_Interfaces(t *testing.T) {
+	var _ domain.ToolRunner = &ScriptedToolRunner{}
+	var _ domain.PipelineRepository = &MockPipelineRepository{}
+}
+
+func jsonOutput(t *testing.T, value interface{}) domain.ToolRunOutput {
+	encoded, err := json.Marshal(value)
+	require.NoError(t, err)
+	return domain.ToolRunOutput{Output: string(encoded) + "\n", Success: true}
+}
+
+// newPipelineFixture saves a tool listing files, one counting them and one
+// formatting a report
+func newPipelineFixture(t *testing.T) (*MockToolRepository, *ScriptedToolRunner, *domain.PipelineService, []domain.Tool) {
+	tools := NewMockToolRepository()
+
+	var saved []domain.Tool
+	for _, spec := range []struct {
+		name       string
+		parameters []domain.ToolParameter
+	}{
+		{"List files", []domain.ToolParameter{{Name: "dir", Type: domain.ParameterString, Default: "."}}},
+		{"Count", []domain.ToolParameter{{Name: "items", Type: domain.ParameterArray, Required: true}}},
+		{"Report", []domain.ToolParameter{
+			{Name: "count", Type: domain.ParameterNumber, Required: true},
+			{Name: "title", Type: domain.ParameterString, Default: "Files"},
+		}},
+	} {
+		created, err := domain.NewToolWithValidation(spec.name, "console.log('x')")
+		require.NoError(t, err)
+		tool, err := created.WithParameters(spec.parameters)
+		require.NoError(t, err)
+		_, err = tools.Save(tool)
+		require.NoError(t, err)
+		saved = append(saved, tool)
+	}
+
+	runner := &ScriptedToolRunner{scripts: map[string]func(map[string]interface{}) domain.ToolRunOutput{
+		saved[0].ID: func(args map[string]interface{}) domain.ToolRunOutput {
+			return jsonOutput(t, []string{"a.go", "b.go"})
+		},
+		saved[1].ID: func(args map[string]interface{}) domain.ToolRunOutput {
+			return jsonOutput(t, map[string]interface{}{"count": len(args["items"].([]interface{})), "ignored": true})
+		},
+		saved[2].ID: func(args map[string]interface{}) domain.ToolRunOutput {
+			return domain.ToolRunOutput{Output: args["title"].(string) + ": 2 files\n", Success: true}
+		},
+	}}
+
+	return tools, runner, domain.NewPipelineService(tools, NewMockPipelineRepository(), runner), saved
+}
+
+func TestPipelineService_Run(t *testing.T) {
+	// Given a pipeline piping a list into a count, and the count object into a report
+	_, _, service, tools := newPipelineFixture(t)
+
+	pipeline, err := domain.NewPipeline("File report", []domain.PipelineStep{
+		{ToolID: tools[0].ID},
+		{ToolID: tools[1].ID, InputParameter: "items"},
+		{ToolID: tools[2].ID, Arguments: map[string]interface{}{"title": "Go files"}},
+	})
+	require.NoError(t, err)
+	require.NoError(t, service.Save(pipeline))
+
+	// When
+	result, err := service.Run(pipeline.ID, nil)
+
+	// Then every step ran and the last one printed plain text
+	require.NoError(t, err)
+	assert.True(t, result.Success)
+	assert.Equal(t, -1, result.FailedStep)
+	require.Len(t, result.Steps, 3)
+	assert.Equal(t, []interface{}{"a.go", "b.go"}, result.Steps[1].Arguments["items"])
+	assert.Equal(t, map[string]interface{}{"count": float64(2), "title": "Go files"}, result.Steps[2].Arguments)
+	assert.Equal(t, "Go files: 2 files\n", result.Output)
+}
+
+func TestPipelineService_Run_StopsAtFailedStep(t *testing.T) {
+	// Given a pipeline whose second step expects an object but gets an array
+	_, _, service, tools := newPipelineFixture(t)
+
+	pipeline, err := domain.NewPipeline("Broken", []domain.PipelineStep{
+		{ToolID: tools[0].ID},
+		{ToolID: tools[2].ID},
+		{ToolID: tools[1].ID},
+	})
+	require.NoError(t, err)
+	require.NoError(t, service.Save(pipeline))
+
+	// When
+	result, err := service.Run(pipeline.ID, nil)
+
+	// Then the failure is reported on the step and the rest is skipped
+	require.NoError(t, err)
+	assert.False(t, result.Success)
+	assert.Equal(t, 1, result.FailedStep)
+	require.Len(t, result.Steps, 2)
+	assert.True(t, result.Steps[0].Success)
+	assert.Contains(t, result.Steps[1].Error, "expected a JSON object")
+}
+
+func TestPipelineService_Run_NonJSONOutput(t *testing.T) {
+	// Given a step printing plain text before another step
+	_, runner, service, tools := newPipelineFixture(t)
+	runner.scripts[tools[0].ID] = func(map[string]interface{}) domain.ToolRunOutput {
+		return domain.ToolRunOutput{Output: "a.go b.go\n", Success: true}
+	}
+
+	pipeline, err := domain.NewPipeline("Text", []domain.PipelineStep{
+		{ToolID: tools[0].ID},
+		{ToolID: tools[1].ID, InputParameter: "items"},
+	})
+	require.NoError(t, err)
+	require.NoError(t, service.Save(pipeline))
+
+	// When
+	result, err := service.Run(pipeline.ID, nil)
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, 0, result.FailedStep)
+	assert.Contains(t, result.Steps[0].Error, "not JSON")
+}
+
+func TestPipelineService_Run_Input(t *testing.T) {
+	// Given a pipeline starting with the report
+	_, _, service, tools := newPipelineFixture(t)
+
+	pipeline, err := domain.NewPipeline("Report only", []domain.PipelineStep{{ToolID: tools[2].ID}})
+	require.NoError(t, err)
+	require.NoError(t, service.Save(pipeline))
+
+	// When the input is piped into the first step
+	result, err := service.Run(pipeline.ID, map[string]interface{}{"count": 2})
+
+	// Then
+	require.NoError(t, err)
+	assert.True(t, result.Success)
+	assert.Equal(t, "Files: 2 files\n", result.Output)
+}
+
+func TestPipelineService_Save_UnknownTool(t *testing.T) {
+	// Given
+	_, _, service, tools := newPipelineFixture(t)
+
+	pipeline, err := domain.NewPipeline("Missing", []domain.PipelineStep{{ToolID: tools[0].ID}, {ToolID: "missing"}})
+	require.NoError(t, err)
+
+	// When
+	err = service.Save(pipeline)
+
+	// Then
+	assert.ErrorIs(t, err, domain.ErrToolNotFound)
+	assert.Contains(t, err.Error(), "step 2")
+}
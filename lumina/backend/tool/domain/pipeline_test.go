@@ -0,0 +1,39 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"lumina/backend/tool/domain"
+)
+
+func TestNewPipeline(t *testing.T) {
+	tests := []struct {
+		name    string
+		title   string
+		steps   []domain.PipelineStep
+		wantErr error
+	}{
+		{"valid", " Report ", []domain.PipelineStep{{ToolID: "a"}, {ToolID: "b"}}, nil},
+		{"empty name", " ", []domain.PipelineStep{{ToolID: "a"}}, domain.ErrPipelineNameEmpty},
+		{"no steps", "Report", nil, domain.ErrPipelineNoSteps},
+		{"step without tool", "Report", []domain.PipelineStep{{ToolID: ""}}, domain.ErrToolNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// When
+			pipeline, err := domain.NewPipeline(tt.title, tt.steps)
+
+			// Then
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.NotEmpty(t, pipeline.ID)
+			assert.Equal(t, "Report", pipeline.Name)
+		})
+	}
+}
@@ -0,0 +1,8 @@
+package domain
+
+// ProjectWatcher takes snapshots of the project files
+type ProjectWatcher interface {
+	// Snapshot maps the slash-separated path of every project file to a
+	// fingerprint that changes when the file does
+	Snapshot() (map[string]string, error)
+}
@@ -0,0 +1,215 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var ErrRevisionNotFound = errors.New("tool revision not found")
+
+// maxDiffLines bounds the diff search, whose trace grows with the square of
+// the lines that differ (about 32 MB at this size); larger inputs are shown
+// as a full replacement
+const maxDiffLines = 2000
+
+// ToolRevision is an immutable snapshot of a tool written on every save
+type ToolRevision struct {
+	ToolID     string          `json:"tool_id"`
+	Revision   int             `json:"revision"` // 1 for the first save, then increasing
+	Name       string          `json:"name"`
+	Code       string          `json:"code"`
+	Parameters []ToolParameter `json:"parameters"`
+	// Dependencies are the npm packages declared by the revision
+	Dependencies []NpmDependency `json:"dependencies"`
+	// ResolvedVersions maps each dependency to the exact version installed
+	// the first time the revision ran; empty until then
+	ResolvedVersions map[string]string `json:"resolved_versions"`
+	Message          string            `json:"message"`
+	CreatedAt        time.Time         `json:"created_at"`
+}
+
+// RevisionDiffLineKind is the role of a line in a revision diff
+type RevisionDiffLineKind string
+
+const (
+	RevisionDiffContext RevisionDiffLineKind = "context"
+	RevisionDiffAdd     RevisionDiffLineKind = "add"
+	RevisionDiffRemove  RevisionDiffLineKind = "remove"
+)
+
+type RevisionDiffLine struct {
+	Kind RevisionDiffLineKind `json:"kind"`
+	Text string               `json:"text"`
+}
+
+// RevisionDiff is the line diff between the code of two revisions
+type RevisionDiff struct {
+	ToolID       string             `json:"tool_id"`
+	FromRevision int                `json:"from_revision"`
+	ToRevision   int                `json:"to_revision"`
+	Lines        []RevisionDiffLine `json:"lines"`
+	Added        int                `json:"added"`
+	Removed      int                `json:"removed"`
+}
+
+// DiffRevisions computes the line diff that turns from's code into to's code
+func DiffRevisions(from, to ToolRevision) RevisionDiff {
+	lines := diffLines(splitCodeLines(from.Code), splitCodeLines(to.Code))
+
+	diff := RevisionDiff{
+		ToolID:       to.ToolID,
+		FromRevision: from.Revision,
+		ToRevision:   to.Revision,
+		Lines:        lines,
+	}
+	for _, line := range lines {
+		switch line.Kind {
+		case RevisionDiffAdd:
+			diff.Added++
+		case RevisionDiffRemove:
+			diff.Removed++
+		}
+	}
+
+	return diff
+}
+
+func splitCodeLines(code string) []string {
+	if code == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(code, "\n"), "\n")
+}
+
+// diffLines returns a shortest edit script between a and b (Myers' algorithm)
+func diffLines(a, b []string) []RevisionDiffLine {
+	// Common prefix and suffix never need searching
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	var result []RevisionDiffLine
+	for _, line := range a[:prefix] {
+		result = append(result, RevisionDiffLine{Kind: RevisionDiffContext, Text: line})
+	}
+	result = append(result, myersDiff(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	for _, line := range a[len(a)-suffix:] {
+		result = append(result, RevisionDiffLine{Kind: RevisionDiffContext, Text: line})
+	}
+
+	return result
+}
+
+func myersDiff(a, b []string) []RevisionDiffLine {
+	n, m := len(a), len(b)
+	max := n + m
+	if max == 0 {
+		return nil
+	}
+	if max > maxDiffLines {
+		return replaceAll(a, b)
+	}
+
+	// v has a spare diagonal on each side, so that the window kept for every
+	// step stays in bounds
+	offset := max + 1
+	v := make([]int, 2*max+3)
+	// trace[d] holds diagonals -d-1 to d+1 of v before step d, the only
+	// ones step d reads
+	var trace [][]int
+
+search:
+	for d := 0; d <= max; d++ {
+		trace = append(trace, append([]int(nil), v[offset-d-1:offset+d+2]...))
+
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+
+			if x >= n && y >= m {
+				break search
+			}
+		}
+	}
+
+	// Walk the trace backwards, collecting the edit script in reverse
+	var reversed []RevisionDiffLine
+	x, y := n, m
+	for d := len(trace) - 1; d >= 0; d-- {
+		v, offset := trace[d], d+1
+		k := x - y
+
+		var prevK int
+		if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := v[offset+prevK]
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			reversed = append(reversed, RevisionDiffLine{Kind: RevisionDiffContext, Text: a[x-1]})
+			x--
+			y--
+		}
+
+		if d > 0 {
+			if x == prevX {
+				reversed = append(reversed, RevisionDiffLine{Kind: RevisionDiffAdd, Text: b[y-1]})
+			} else {
+				reversed = append(reversed, RevisionDiffLine{Kind: RevisionDiffRemove, Text: a[x-1]})
+			}
+		}
+
+		x, y = prevX, prevY
+	}
+
+	result := make([]RevisionDiffLine, len(reversed))
+	for i, line := range reversed {
+		result[len(reversed)-1-i] = line
+	}
+	return result
+}
+
+func replaceAll(a, b []string) []RevisionDiffLine {
+	result := make([]RevisionDiffLine, 0, len(a)+len(b))
+	for _, line := range a {
+		result = append(result, RevisionDiffLine{Kind: RevisionDiffRemove, Text: line})
+	}
+	for _, line := range b {
+		result = append(result, RevisionDiffLine{Kind: RevisionDiffAdd, Text: line})
+	}
+	return result
+}
+
+// WithRevision returns a copy of the tool with the code, parameters and
+// dependencies of revision
+func (t Tool) WithRevision(revision ToolRevision) Tool {
+	reverted := t.WithUpdatedCode(revision.Code)
+	reverted.Parameters = revision.Parameters
+	reverted.Dependencies = revision.Dependencies
+	return reverted
+}
+
+// RevertMessage is the revision message recorded when reverting to revision
+func RevertMessage(revision int) string {
+	return fmt.Sprintf("Revert to revision %d", revision)
+}
@@ -0,0 +1,112 @@
+package domain_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"lumina/backend/tool/domain"
+)
+
+func TestDiffRevisions_ChangedLine(t *testing.T) {
+	// Given two revisions that differ in one line
+	from := domain.ToolRevision{ToolID: "tool-1", Revision: 1, Code: "const a = 1;\nconst b = 2;\nconsole.log(a + b);"}
+	to := domain.ToolRevision{ToolID: "tool-1", Revision: 2, Code: "const a = 1;\nconst b = 3;\nconsole.log(a + b);"}
+
+	// When diffing them
+	diff := domain.DiffRevisions(from, to)
+
+	// Then only the changed line is removed and added
+	assert.Equal(t, 1, diff.FromRevision)
+	assert.Equal(t, 2, diff.ToRevision)
+	assert.Equal(t, 1, diff.Added)
+	assert.Equal(t, 1, diff.Removed)
+	assert.Equal(t, []domain.RevisionDiffLine{
+		{Kind: domain.RevisionDiffContext, Text: "const a = 1;"},
+		{Kind: domain.RevisionDiffRemove, Text: "const b = 2;"},
+		{Kind: domain.RevisionDiffAdd, Text: "const b = 3;"},
+		{Kind: domain.RevisionDiffContext, Text: "console.log(a + b);"},
+	}, diff.Lines)
+}
+
+func TestDiffRevisions_InsertionsAndDeletions(t *testing.T) {
+	// Given revisions where lines were inserted and removed in the middle
+	from := domain.ToolRevision{Revision: 1, Code: "a\nb\nc\nd\ne"}
+	to := domain.ToolRevision{Revision: 3, Code: "a\nc\nx\nd\ne\nf"}
+
+	// When diffing them
+	diff := domain.DiffRevisions(from, to)
+
+	// Then applying the diff to the old code yields the new code
+	var oldSide, newSide []string
+	for _, line := range diff.Lines {
+		if line.Kind != domain.RevisionDiffAdd {
+			oldSide = append(oldSide, line.Text)
+		}
+		if line.Kind != domain.RevisionDiffRemove {
+			newSide = append(newSide, line.Text)
+		}
+	}
+	assert.Equal(t, []string{"a", "b", "c", "d", "e"}, oldSide)
+	assert.Equal(t, []string{"a", "c", "x", "d", "e", "f"}, newSide)
+	assert.Equal(t, 2, diff.Added)
+	assert.Equal(t, 1, diff.Removed)
+}
+
+func TestDiffRevisions_Identical(t *testing.T) {
+	// Given two revisions with the same code
+	from := domain.ToolRevision{Revision: 1, Code: "console.log('same');\n"}
+	to := domain.ToolRevision{Revision: 2, Code: "console.log('same');\n"}
+
+	// When diffing them
+	diff := domain.DiffRevisions(from, to)
+
+	// Then nothing is added or removed
+	assert.Zero(t, diff.Added)
+	assert.Zero(t, diff.Removed)
+	assert.Len(t, diff.Lines, 1)
+}
+
+func TestDiffRevisions_ManyChanges(t *testing.T) {
+	// Given revisions where every other line of a long tool changed
+	var oldLines, newLines []string
+	for i := 0; i < 900; i++ {
+		oldLines = append(oldLines, fmt.Sprintf("line %d", i))
+		if i%2 == 0 {
+			newLines = append(newLines, fmt.Sprintf("line %d", i))
+		} else {
+			newLines = append(newLines, fmt.Sprintf("changed %d", i))
+		}
+	}
+	from := domain.ToolRevision{Revision: 1, Code: strings.Join(oldLines, "\n")}
+	to := domain.ToolRevision{Revision: 2, Code: strings.Join(newLines, "\n")}
+
+	// When diffing them
+	diff := domain.DiffRevisions(from, to)
+
+	// Then only the changed lines are removed and added
+	assert.Equal(t, 450, diff.Added)
+	assert.Equal(t, 450, diff.Removed)
+	assert.Len(t, diff.Lines, 1350)
+}
+
+func TestDiffRevisions_TooManyChanges(t *testing.T) {
+	// Given revisions sharing no line and too long to search
+	var oldLines, newLines []string
+	for i := 0; i < 1500; i++ {
+		oldLines = append(oldLines, fmt.Sprintf("old %d", i))
+		newLines = append(newLines, fmt.Sprintf("new %d", i))
+	}
+	from := domain.ToolRevision{Revision: 1, Code: strings.Join(oldLines, "\n")}
+	to := domain.ToolRevision{Revision: 2, Code: strings.Join(newLines, "\n")}
+
+	// When diffing them
+	diff := domain.DiffRevisions(from, to)
+
+	// Then the old code is shown replaced by the new code
+	assert.Equal(t, 1500, diff.Added)
+	assert.Equal(t, 1500, diff.Removed)
+	assert.Equal(t, domain.RevisionDiffLine{Kind: domain.RevisionDiffRemove, Text: "old 0"}, diff.Lines[0])
+	assert.Equal(t, domain.RevisionDiffLine{Kind: domain.RevisionDiffAdd, Text: "new 0"}, diff.Lines[1500])
+}
@@ -0,0 +1,104 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrToolRunNotFound = errors.New("tool run not found")
+
+// ToolRun is a recorded execution, either of a saved tool or of ad-hoc code
+type ToolRun struct {
+	ID string `json:"id"`
+	// ToolID, ToolName and Revision are empty for ad-hoc code
+	ToolID   string `json:"tool_id"`
+	ToolName string `json:"tool_name"`
+	Revision int    `json:"revision"`
+	// Code is only kept for ad-hoc runs; a tool run is described by its revision
+	Code       string                 `json:"code"`
+	Arguments  map[string]interface{} `json:"arguments"`
+	Output     string                 `json:"output"`
+	Error      string                 `json:"error"`
+	ExitCode   int                    `json:"exit_code"`
+	Success    bool                   `json:"success"`
+	DurationMs int64                  `json:"duration_ms"`
+	StartedAt  time.Time              `json:"started_at"`
+}
+
+// NewToolRun records a run of revision of tool
+func NewToolRun(tool Tool, revision int, args map[string]interface{}, output ToolRunOutput, startedAt time.Time) ToolRun {
+	run := newRun(output, startedAt)
+	run.ToolID = tool.ID
+	run.ToolName = tool.Name
+	run.Revision = revision
+	run.Arguments = args
+	return run
+}
+
+// NewAdHocRun records a run of code that is not saved as a tool
+func NewAdHocRun(code string, output ToolRunOutput, startedAt time.Time) ToolRun {
+	run := newRun(output, startedAt)
+	run.Code = code
+	return run
+}
+
+func newRun(output ToolRunOutput, startedAt time.Time) ToolRun {
+	return ToolRun{
+		ID:         uuid.New().String(),
+		Arguments:  map[string]interface{}{},
+		Output:     output.Output,
+		Error:      output.Error,
+		ExitCode:   output.ExitCode,
+		Success:    output.Success,
+		DurationMs: output.DurationMs,
+		StartedAt:  startedAt,
+	}
+}
+
+// IsAdHoc reports whether the run executed code that is not saved as a tool
+func (r ToolRun) IsAdHoc() bool {
+	return r.ToolID == ""
+}
+
+// ToolRunQuery filters the run history; zero fields match every run
+type ToolRunQuery struct {
+	ToolID string `json:"tool_id"`
+	// AdHocOnly keeps only runs of ad-hoc code
+	AdHocOnly bool      `json:"adhoc_only"`
+	Since     time.Time `json:"since"`
+	Until     time.Time `json:"until"`
+	// Limit caps the number of runs returned (all when <= 0)
+	Limit int `json:"limit"`
+}
+
+// ToolRunDiff is the line diff between the output of two runs
+type ToolRunDiff struct {
+	FromRunID string             `json:"from_run_id"`
+	ToRunID   string             `json:"to_run_id"`
+	Lines     []RevisionDiffLine `json:"lines"`
+	Added     int                `json:"added"`
+	Removed   int                `json:"removed"`
+}
+
+// DiffToolRuns computes the line diff that turns from's output into to's output
+func DiffToolRuns(from, to ToolRun) ToolRunDiff {
+	lines := diffLines(splitCodeLines(from.Output), splitCodeLines(to.Output))
+
+	diff := ToolRunDiff{
+		FromRunID: from.ID,
+		ToRunID:   to.ID,
+		Lines:     lines,
+	}
+	for _, line := range lines {
+		switch line.Kind {
+		case RevisionDiffAdd:
+			diff.Added++
+		case RevisionDiffRemove:
+			diff.Removed++
+		}
+	}
+
+	return diff
+}
@@ -0,0 +1,49 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"lumina/backend/tool/domain"
+)
+
+func TestNewToolRun(t *testing.T) {
+	// Given
+	tool, err := domain.NewToolWithValidation("Greet", "console.log('hi')")
+	require.NoError(t, err)
+	started := time.Now()
+
+	// When
+	run := domain.NewToolRun(*tool, 2, map[string]interface{}{"name": "Ada"}, domain.ToolRunOutput{
+		Output: "Hello", ExitCode: 0, Success: true, DurationMs: 5,
+	}, started)
+	adHoc := domain.NewAdHocRun("console.log(1)", domain.ToolRunOutput{Output: "1"}, started)
+
+	// Then
+	assert.NotEmpty(t, run.ID)
+	assert.Equal(t, tool.ID, run.ToolID)
+	assert.Equal(t, 2, run.Revision)
+	assert.Empty(t, run.Code)
+	assert.False(t, run.IsAdHoc())
+	assert.True(t, adHoc.IsAdHoc())
+	assert.Equal(t, "console.log(1)", adHoc.Code)
+	assert.NotNil(t, adHoc.Arguments)
+}
+
+func TestDiffToolRuns(t *testing.T) {
+	// Given the output of last week's and today's runs
+	lastWeek := domain.ToolRun{ID: "a", Output: "lint: 12\ntodo: 4\n"}
+	today := domain.ToolRun{ID: "b", Output: "lint: 9\ntodo: 4\n"}
+
+	// When
+	diff := domain.DiffToolRuns(lastWeek, today)
+
+	// Then
+	assert.Equal(t, "a", diff.FromRunID)
+	assert.Equal(t, "b", diff.ToRunID)
+	assert.Equal(t, 1, diff.Added)
+	assert.Equal(t, 1, diff.Removed)
+	assert.Len(t, diff.Lines, 3)
+}
@@ -0,0 +1,122 @@
+package domain
+
+import "sort"
+
+// ScopedToolRepository is a ToolRepository seen from one project. Names
+// resolve by the precedence rules of ToolScope, and listing or searching
+// returns only the visible tools. Tools can still be read, saved and
+// deleted by ID in any namespace.
+type ScopedToolRepository struct {
+	ToolRepository
+	scope ToolScope
+}
+
+func NewScopedToolRepository(repository ToolRepository, scope ToolScope) *ScopedToolRepository {
+	return &ScopedToolRepository{
+		ToolRepository: repository,
+		scope:          scope,
+	}
+}
+
+// Scope returns the project the repository resolves names for
+func (r *ScopedToolRepository) Scope() ToolScope {
+	return r.scope
+}
+
+// GetByName returns the visible tool with the name
+func (r *ScopedToolRepository) GetByName(name string) (Tool, error) {
+	tools, err := r.List()
+	if err != nil {
+		return Tool{}, err
+	}
+
+	for _, tool := range tools {
+		if tool.Name == name {
+			return tool, nil
+		}
+	}
+	return Tool{}, ErrToolNotFound
+}
+
+// List returns the visible tools
+func (r *ScopedToolRepository) List() ([]Tool, error) {
+	tools, err := r.ToolRepository.List()
+	if err != nil {
+		return nil, err
+	}
+	return r.scope.VisibleTools(tools), nil
+}
+
+// Search returns the visible tools matching query; a tool shadowed by
+// another of the same name never matches
+func (r *ScopedToolRepository) Search(query ToolQuery) ([]Tool, error) {
+	visible, err := r.List()
+	if err != nil {
+		return nil, err
+	}
+	visibleIDs := make(map[string]bool, len(visible))
+	for _, tool := range visible {
+		visibleIDs[tool.ID] = true
+	}
+
+	found, err := r.ToolRepository.Search(query)
+	if err != nil {
+		return nil, err
+	}
+
+	matches := make([]Tool, 0, len(found))
+	for _, tool := range found {
+		if visibleIDs[tool.ID] {
+			matches = append(matches, tool)
+		}
+	}
+	return matches, nil
+}
+
+// ListTags returns the tags of the visible tools, sorted
+func (r *ScopedToolRepository) ListTags() ([]string, error) {
+	tools, err := r.List()
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	tags := []string{}
+	for _, tool := range tools {
+		for _, tag := range tool.Tags {
+			if !seen[tag] {
+				seen[tag] = true
+				tags = append(tags, tag)
+			}
+		}
+	}
+	sort.Strings(tags)
+	return tags, nil
+}
+
+// ListNamespace returns every tool in namespace, visible or not
+func (r *ScopedToolRepository) ListNamespace(namespace string) ([]Tool, error) {
+	tools, err := r.ToolRepository.List()
+	if err != nil {
+		return nil, err
+	}
+
+	namespace = NormalizeNamespace(namespace)
+	inNamespace := []Tool{}
+	for _, tool := range tools {
+		if NormalizeNamespace(tool.Namespace) == namespace {
+			inNamespace = append(inNamespace, tool)
+		}
+	}
+	return inNamespace, nil
+}
+
+// Namespaces returns the namespaces visible from the project that hold
+// tools, in precedence order
+func (r *ScopedToolRepository) Namespaces() ([]string, error) {
+	tools, err := r.ToolRepository.List()
+	if err != nil {
+		return nil, err
+	}
+	return r.scope.Namespaces(tools), nil
+}
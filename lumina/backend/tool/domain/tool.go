@@ -12,15 +12,41 @@ var (
 	ErrToolNameEmpty = errors.New("tool name cannot be empty")
 	ErrToolCodeEmpty = errors.New("tool code cannot be empty")
 	ErrToolNotFound  = errors.New("tool not found")
+	ErrToolNameTaken = errors.New("tool name already taken")
+	// ErrToolVersionConflict means the tool was saved by someone else since it was read
+	ErrToolVersionConflict = errors.New("tool was modified since it was loaded")
 )
 
 // Tool represents a saved piece of code with a name
 type Tool struct {
-	ID        string    `json:"id"`
-	Name      string    `json:"name"`
-	Code      string    `json:"code"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Code        string    `json:"code"`
+	Description string    `json:"description"`
+	Tags        []string  `json:"tags"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	// Parameters is the input schema; arguments are validated against it
+	Parameters []ToolParameter `json:"parameters"`
+	// Dependencies are the npm packages the code imports
+	Dependencies []NpmDependency `json:"dependencies"`
+	// DeletedAt is set while the tool is in the trash
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	// Namespace is where the name of the tool is unique: GlobalNamespace, a
+	// project's or a team's (see ToolScope)
+	Namespace string `json:"namespace"`
+	// Language is the language the code is written in, LanguageTypeScript
+	// unless set otherwise
+	Language string `json:"language"`
+	// Capabilities is what the tool may do besides computing and printing.
+	// Tools saved before capabilities existed have none declared and run
+	// unrestricted.
+	Capabilities *ToolCapabilities `json:"capabilities"`
+	// Draft marks a tool that has not been reviewed yet, such as a generated one
+	Draft bool `json:"draft"`
+	// Version counts the saves of the tool; 0 until it is first saved. A save
+	// must carry the version it was read at.
+	Version int `json:"version"`
 }
 
 // NewTool creates a new tool with the given name and code
@@ -30,8 +56,12 @@ func NewTool(name, code string) Tool {
 		ID:        uuid.New().String(),
 		Name:      strings.TrimSpace(name),
 		Code:      strings.TrimSpace(code),
-		CreatedAt: now,
-		UpdatedAt: now,
+		Namespace: GlobalNamespace,
+		Language:  LanguageTypeScript,
+		// New tools start with no capabilities and are granted them as needed
+		Capabilities: &ToolCapabilities{},
+		CreatedAt:    now,
+		UpdatedAt:    now,
 	}
 }
 
@@ -54,20 +84,91 @@ func NewToolWithValidation(name, code string) (*Tool, error) {
 
 // WithUpdatedCode returns a copy of the tool with updated code and timestamp
 func (t Tool) WithUpdatedCode(newCode string) Tool {
-	return Tool{
-		ID:        t.ID,
-		Name:      t.Name,
-		Code:      strings.TrimSpace(newCode),
-		CreatedAt: t.CreatedAt,
-		UpdatedAt: time.Now(),
+	updated := t
+	updated.Code = strings.TrimSpace(newCode)
+	updated.UpdatedAt = time.Now()
+	return updated
+}
+
+// WithName returns a copy of the tool renamed to newName
+func (t Tool) WithName(newName string) (Tool, error) {
+	trimmedName := strings.TrimSpace(newName)
+	if trimmedName == "" {
+		return Tool{}, ErrToolNameEmpty
+	}
+
+	renamed := t
+	renamed.Name = trimmedName
+	renamed.UpdatedAt = time.Now()
+	return renamed, nil
+}
+
+// Published returns a copy of the tool that is no longer a draft
+func (t Tool) Published() Tool {
+	published := t
+	published.Draft = false
+	published.UpdatedAt = time.Now()
+	return published
+}
+
+// Duplicate returns a new tool with the same code under another name; an
+// empty name falls back to "<name> copy"
+func (t Tool) Duplicate(newName string) (Tool, error) {
+	if strings.TrimSpace(newName) == "" {
+		newName = t.Name + " copy"
 	}
+
+	duplicate, err := t.WithName(newName)
+	if err != nil {
+		return Tool{}, err
+	}
+
+	now := time.Now()
+	duplicate.ID = uuid.New().String()
+	duplicate.CreatedAt = now
+	duplicate.UpdatedAt = now
+	duplicate.DeletedAt = nil
+	duplicate.Version = 0
+	return duplicate, nil
 }
 
 // ToolRepository defines the interface for tool persistence operations
 type ToolRepository interface {
-	Save(tool Tool) error
+	// Save stores the tool, records its code as a new revision and returns
+	// the tool as stored, at its new version. It fails with
+	// ErrToolNameTaken when another tool of the namespace already has the
+	// name, with ErrToolVersionConflict when tool.Version is not the stored
+	// version, and with ErrToolNotFound when the tool is in the trash.
+	Save(tool Tool) (Tool, error)
+	// SaveWithMessage is Save with a message describing the revision
+	SaveWithMessage(tool Tool, message string) (Tool, error)
 	GetByID(id string) (Tool, error)
+	// GetByName returns a tool with the name, preferring the global one;
+	// ScopedToolRepository resolves names for a project
 	GetByName(name string) (Tool, error)
+	// GetByNamespaceAndName returns the tool with the name in namespace
+	GetByNamespaceAndName(namespace, name string) (Tool, error)
 	List() ([]Tool, error)
+	// Search returns the tools matching query outside the trash
+	Search(query ToolQuery) ([]Tool, error)
+	// ListTags returns every tag in use, sorted
+	ListTags() ([]string, error)
+	// ListRevisions returns the revisions of a tool, newest first
+	ListRevisions(toolID string) ([]ToolRevision, error)
+	GetRevision(toolID string, revision int) (ToolRevision, error)
+	DiffRevisions(toolID string, from, to int) (RevisionDiff, error)
+	// Revert saves the code of an earlier revision as a new revision
+	Revert(toolID string, revision int) (Tool, error)
+	// RecordResolvedVersions stores the exact npm versions installed for a revision
+	RecordResolvedVersions(toolID string, revision int, versions map[string]string) error
+	// Delete moves a tool to the trash, where it keeps its revisions
+	Delete(id string) error
+	// ListTrash returns the tools in the trash, most recently deleted first
+	ListTrash() ([]Tool, error)
+	// Restore takes a tool out of the trash; it fails with ErrToolNameTaken
+	// when another tool has taken its name meanwhile
+	Restore(id string) (Tool, error)
+	// Purge permanently removes a tool in the trash and its revisions
+	Purge(id string) error
 	Close() error
 }
\ No newline at end of file
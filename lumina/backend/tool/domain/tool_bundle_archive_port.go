@@ -0,0 +1,8 @@
+package domain
+
+// ToolBundleArchive stores bundles as single files
+type ToolBundleArchive interface {
+	Write(path string, bundle ToolBundle) error
+	// Read fails with ErrInvalidToolBundle when the file is not a bundle
+	Read(path string) (ToolBundle, error)
+}
@@ -0,0 +1,399 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// importMessage is the revision message of a tool overwritten by an import
+const importMessage = "Imported from bundle"
+
+// ToolBundleService exports tools with their revisions and examples to a
+// bundle file and imports such files into a namespace
+type ToolBundleService struct {
+	tools        ToolRepository
+	examples     ToolExampleRepository
+	archive      ToolBundleArchive
+	capabilities *CapabilityService
+}
+
+// NewToolBundleService creates the service; capabilities may be nil, in
+// which case undeclared capabilities of imported tools are dropped
+// unrecorded
+func NewToolBundleService(tools ToolRepository, examples ToolExampleRepository, archive ToolBundleArchive, capabilities *CapabilityService) *ToolBundleService {
+	return &ToolBundleService{
+		tools:        tools,
+		examples:     examples,
+		archive:      archive,
+		capabilities: capabilities,
+	}
+}
+
+// Export writes the tools with the given IDs to a bundle at path
+func (s *ToolBundleService) Export(toolIDs []string, path string) (ToolBundleExport, error) {
+	if len(toolIDs) == 0 {
+		return ToolBundleExport{}, ErrEmptyToolBundle
+	}
+
+	export := ToolBundleExport{Path: path, Tools: []string{}}
+	var bundled []BundledTool
+	for _, id := range toolIDs {
+		tool, err := s.tools.GetByID(id)
+		if err != nil {
+			return ToolBundleExport{}, fmt.Errorf("tool %s: %w", id, err)
+		}
+
+		revisions, err := s.tools.ListRevisions(id)
+		if err != nil {
+			return ToolBundleExport{}, fmt.Errorf("failed to read revisions of %s: %w", tool.Name, err)
+		}
+		// Oldest first, the order they are replayed in on import
+		for i, j := 0, len(revisions)-1; i < j; i, j = i+1, j-1 {
+			revisions[i], revisions[j] = revisions[j], revisions[i]
+		}
+
+		examples, err := s.examples.ListByTool(id)
+		if err != nil {
+			return ToolBundleExport{}, fmt.Errorf("failed to read examples of %s: %w", tool.Name, err)
+		}
+
+		bundled = append(bundled, BundledTool{Tool: tool, Revisions: revisions, Examples: examples})
+		export.Tools = append(export.Tools, tool.Name)
+	}
+
+	if err := s.archive.Write(path, NewToolBundle(bundled)); err != nil {
+		return ToolBundleExport{}, err
+	}
+	return export, nil
+}
+
+// Import adds the tools of the bundle at path to namespace. A tool whose
+// name is taken there is skipped, renamed or saved over the existing tool
+// according to strategy. New tools keep the revisions and examples of the
+// bundle; an overwritten tool keeps its own history and gets the bundled
+// version as a new revision.
+//
+// Every tool is checked before anything is written, and the import is all
+// or nothing: when a tool fails to save, the tools already created are
+// purged and the overwritten ones get their previous content back.
+//
+// The capabilities of bundled tools are not taken on trust: a new tool is
+// granted none and an overwritten one keeps those it had. What the bundle
+// declares beyond that is recorded as escalations for the user to decide on.
+func (s *ToolBundleService) Import(path string, strategy ImportConflictStrategy, namespace string) (ToolBundleImport, error) {
+	if _, err := ParseImportConflictStrategy(string(strategy)); err != nil {
+		return ToolBundleImport{}, err
+	}
+	if _, err := ParseNamespace(namespace); err != nil {
+		return ToolBundleImport{}, err
+	}
+	namespace = NormalizeNamespace(namespace)
+
+	bundle, err := s.archive.Read(path)
+	if err != nil {
+		return ToolBundleImport{}, err
+	}
+	if err := bundle.Validate(); err != nil {
+		return ToolBundleImport{}, err
+	}
+
+	steps, err := s.plan(bundle, strategy, namespace)
+	if err != nil {
+		return ToolBundleImport{}, err
+	}
+
+	var undo []func() error
+	for _, step := range steps {
+		if err := s.apply(step, namespace, &undo); err != nil {
+			err = fmt.Errorf("failed to import %s: %w", step.bundled.Tool.Name, err)
+			for i := len(undo) - 1; i >= 0; i-- {
+				if undoErr := undo[i](); undoErr != nil {
+					return ToolBundleImport{}, fmt.Errorf("%w (undoing the import also failed: %v)", err, undoErr)
+				}
+			}
+			return ToolBundleImport{}, err
+		}
+	}
+
+	for _, step := range steps {
+		s.askFor(step)
+	}
+	return importReport(steps, namespace), nil
+}
+
+// importAction is what Import does with one bundled tool
+type importAction int
+
+const (
+	importCreate importAction = iota
+	importSkip
+	importOverwrite
+)
+
+// importStep is the planned import of one bundled tool
+type importStep struct {
+	bundled BundledTool
+	action  importAction
+	// id and name are the ID and name a created tool gets
+	id   string
+	name string
+	// existing is the overwritten tool and examples its examples
+	existing Tool
+	examples []ToolExample
+	// capabilities are what the tool is granted and requests the rest of
+	// what it declares
+	capabilities *ToolCapabilities
+	requests     []CapabilityRequest
+}
+
+// plan decides what happens to each bundled tool without writing anything.
+// Names are claimed in bundle order, so a bundle holding two tools of the
+// same name treats the second as taken.
+func (s *ToolBundleService) plan(bundle ToolBundle, strategy ImportConflictStrategy, namespace string) ([]importStep, error) {
+	claimed := make(map[string]bool)
+	steps := make([]importStep, 0, len(bundle.Tools))
+	for _, bundled := range bundle.Tools {
+		name := bundled.Tool.Name
+		step := importStep{bundled: bundled, action: importCreate, id: uuid.New().String(), name: name}
+
+		existing, err := s.tools.GetByNamespaceAndName(namespace, name)
+		if err != nil && !errors.Is(err, ErrToolNotFound) {
+			return nil, fmt.Errorf("failed to import %s: %w", name, err)
+		}
+		stored := err == nil
+
+		switch {
+		case !stored && !claimed[name]:
+			// Free, so created under its own name
+
+		case strategy == ImportRename:
+			step.name, err = s.freeName(namespace, name, claimed)
+			if err != nil {
+				return nil, fmt.Errorf("failed to import %s: %w", name, err)
+			}
+
+		case strategy == ImportOverwrite && stored && !claimed[name]:
+			step.action = importOverwrite
+			step.existing = existing
+			step.examples, err = s.examples.ListByTool(existing.ID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read examples of %s: %w", name, err)
+			}
+
+		default:
+			// Skipped, or a second tool of the bundle for a name that
+			// another one already takes
+			step.action = importSkip
+		}
+
+		switch step.action {
+		case importCreate:
+			step.capabilities, step.requests = importedCapabilities(bundled.Tool.Capabilities, nil)
+		case importOverwrite:
+			step.capabilities, step.requests = importedCapabilities(bundled.Tool.Capabilities, &step.existing)
+		}
+
+		claimed[step.name] = true
+		steps = append(steps, step)
+	}
+	return steps, nil
+}
+
+// apply carries out one step, adding to undo what takes it back
+func (s *ToolBundleService) apply(step importStep, namespace string, undo *[]func() error) error {
+	switch step.action {
+	case importCreate:
+		*undo = append(*undo, func() error { return s.discard(step.id) })
+		return s.create(step, namespace)
+
+	case importOverwrite:
+		*undo = append(*undo, func() error { return s.putBack(step.existing, step.examples) })
+		return s.overwrite(step)
+	}
+	return nil
+}
+
+// askFor records what the tool of a step declares beyond what it was
+// granted; a failure to record never fails the import
+func (s *ToolBundleService) askFor(step importStep) {
+	if s.capabilities == nil || len(step.requests) == 0 {
+		return
+	}
+
+	tool := step.existing
+	if step.action == importCreate {
+		tool = Tool{ID: step.id, Name: step.name}
+	}
+	tool.Capabilities = step.capabilities
+	if _, err := s.capabilities.Record(tool, step.requests); err != nil {
+		log.Printf("Warning: Failed to record capability escalation of %s: %v", tool.Name, err)
+	}
+}
+
+// importReport lists the steps of a finished import
+func importReport(steps []importStep, namespace string) ToolBundleImport {
+	report := ToolBundleImport{
+		Namespace:   namespace,
+		Imported:    []string{},
+		Renamed:     map[string]string{},
+		Overwritten: []string{},
+		Skipped:     []string{},
+	}
+	for _, step := range steps {
+		name := step.bundled.Tool.Name
+		switch step.action {
+		case importCreate:
+			report.Imported = append(report.Imported, step.name)
+			if step.name != name {
+				report.Renamed[name] = step.name
+			}
+		case importOverwrite:
+			report.Overwritten = append(report.Overwritten, name)
+		case importSkip:
+			report.Skipped = append(report.Skipped, name)
+		}
+	}
+	return report
+}
+
+// create saves the bundled tool of step as a new tool, replaying its
+// revisions
+func (s *ToolBundleService) create(step importStep, namespace string) error {
+	bundled := step.bundled
+	tool := bundled.Tool
+	tool.ID = step.id
+	tool.Name = step.name
+	tool.Namespace = namespace
+	tool.Capabilities = step.capabilities
+	tool.Version = 0
+	tool.DeletedAt = nil
+
+	for i, revision := range bundled.Revisions {
+		snapshot := tool
+		snapshot.Code = revision.Code
+		snapshot.Parameters = revision.Parameters
+		snapshot.Dependencies = revision.Dependencies
+		snapshot.UpdatedAt = revision.CreatedAt
+		saved, err := s.tools.SaveWithMessage(snapshot, revision.Message)
+		if err != nil {
+			return err
+		}
+		tool.Version = saved.Version
+
+		// Keep the versions the revision was pinned to
+		if len(revision.ResolvedVersions) > 0 {
+			if err := s.tools.RecordResolvedVersions(tool.ID, i+1, revision.ResolvedVersions); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Bundles written by hand may have no history
+	last := len(bundled.Revisions) - 1
+	if last < 0 || bundled.Revisions[last].Code != tool.Code {
+		if _, err := s.tools.SaveWithMessage(tool, importMessage); err != nil {
+			return err
+		}
+	}
+
+	return s.replaceExamples(tool.ID, bundled.Examples)
+}
+
+// discard removes a tool created by a failed import, if it got that far
+func (s *ToolBundleService) discard(id string) error {
+	if _, err := s.tools.GetByID(id); errors.Is(err, ErrToolNotFound) {
+		return nil
+	}
+	if err := s.examples.DeleteByTool(id); err != nil {
+		return err
+	}
+	if err := s.tools.Delete(id); err != nil {
+		return err
+	}
+	return s.tools.Purge(id)
+}
+
+// overwrite saves the bundled tool of step over the existing one as a new
+// revision
+func (s *ToolBundleService) overwrite(step importStep) error {
+	existing, bundled := step.existing, step.bundled
+	updated := existing
+	updated.Code = bundled.Tool.Code
+	updated.Description = bundled.Tool.Description
+	updated.Tags = bundled.Tool.Tags
+	updated.Parameters = bundled.Tool.Parameters
+	updated.Dependencies = bundled.Tool.Dependencies
+	updated.Language = bundled.Tool.Language
+	updated.Capabilities = step.capabilities
+	updated.Draft = bundled.Tool.Draft
+	updated.UpdatedAt = time.Now()
+	if _, err := s.tools.SaveWithMessage(updated, importMessage); err != nil {
+		return err
+	}
+
+	if err := s.examples.DeleteByTool(existing.ID); err != nil {
+		return err
+	}
+	return s.replaceExamples(existing.ID, bundled.Examples)
+}
+
+// putBack saves the content and examples an overwrite replaced back over
+// the tool
+func (s *ToolBundleService) putBack(existing Tool, examples []ToolExample) error {
+	current, err := s.tools.GetByID(existing.ID)
+	if err != nil {
+		return err
+	}
+	if current.Version != existing.Version {
+		previous := existing
+		previous.Version = current.Version
+		previous.UpdatedAt = time.Now()
+		if _, err := s.tools.SaveWithMessage(previous, "Undid failed bundle import"); err != nil {
+			return err
+		}
+	}
+
+	if err := s.examples.DeleteByTool(existing.ID); err != nil {
+		return err
+	}
+	for _, example := range examples {
+		if err := s.examples.Save(example); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// replaceExamples saves copies of the bundled examples for toolID
+func (s *ToolBundleService) replaceExamples(toolID string, examples []ToolExample) error {
+	for _, example := range examples {
+		example.ID = uuid.New().String()
+		example.ToolID = toolID
+		if err := s.examples.Save(example); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// freeName returns "name 2", "name 3"... whichever is free in namespace and
+// not claimed by another tool of the import
+func (s *ToolBundleService) freeName(namespace, name string, claimed map[string]bool) (string, error) {
+	for n := 2; ; n++ {
+		candidate := fmt.Sprintf("%s %d", name, n)
+		if claimed[candidate] {
+			continue
+		}
+		_, err := s.tools.GetByNamespaceAndName(namespace, candidate)
+		if errors.Is(err, ErrToolNotFound) {
+			return candidate, nil
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+}
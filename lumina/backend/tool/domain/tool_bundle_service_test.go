@@ -0,0 +1,292 @@
+package domain_test
+
+import (
+	"testing"
+
+	"lumina/backend/tool/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// MemoryToolBundleArchive keeps bundles in memory, keyed by path
+type MemoryToolBundleArchive struct {
+	bundles map[string]domain.ToolBundle
+}
+
+func NewMemoryToolBundleArchive() *MemoryToolBundleArchive {
+	return &MemoryToolBundleArchive{bundles: make(map[string]domain.ToolBundle)}
+}
+
+func (a *MemoryToolBundleArchive) Write(path string, bundle domain.ToolBundle) error {
+	a.bundles[path] = bundle
+	return nil
+}
+
+func (a *MemoryToolBundleArchive) Read(path string) (domain.ToolBundle, error) {
+	bundle, exists := a.bundles[path]
+	if !exists {
+		return domain.ToolBundle{}, domain.ErrInvalidToolBundle
+	}
+	return bundle, nil
+}
+
+// exportGreet saves a tool with two revisions and an example in a fresh
+// repository and exports it to "greet.bundle"
+func exportGreet(t *testing.T, archive *MemoryToolBundleArchive) domain.Tool {
+	tools := NewMockToolRepository()
+	examples := NewMockToolExampleRepository()
+	service := domain.NewToolBundleService(tools, examples, archive, nil)
+
+	tool := domain.NewTool("Greet", "console.log('hi')")
+	tool, err := tools.SaveWithMessage(tool, "First")
+	require.NoError(t, err)
+	tool.Code = "console.log('hello')"
+	_, err = tools.SaveWithMessage(tool, "Say hello")
+	require.NoError(t, err)
+
+	example, err := domain.NewToolExample(tool, "says hello", nil, "hello", nil)
+	require.NoError(t, err)
+	require.NoError(t, examples.Save(example))
+
+	_, err = service.Export([]string{tool.ID}, "greet.bundle")
+	require.NoError(t, err)
+	return tool
+}
+
+func TestToolBundleService_Export(t *testing.T) {
+	// Given
+	archive := NewMemoryToolBundleArchive()
+
+	// When
+	tool := exportGreet(t, archive)
+
+	// Then the bundle holds the tool, its revisions oldest first and its example
+	bundle := archive.bundles["greet.bundle"]
+	require.NoError(t, bundle.Validate())
+	require.Len(t, bundle.Tools, 1)
+	assert.Equal(t, tool.ID, bundle.Tools[0].Tool.ID)
+	require.Len(t, bundle.Tools[0].Revisions, 2)
+	assert.Equal(t, "First", bundle.Tools[0].Revisions[0].Message)
+	assert.Equal(t, "Say hello", bundle.Tools[0].Revisions[1].Message)
+	require.Len(t, bundle.Tools[0].Examples, 1)
+}
+
+func TestToolBundleService_Export_NoTools(t *testing.T) {
+	// Given
+	service := domain.NewToolBundleService(NewMockToolRepository(), NewMockToolExampleRepository(), NewMemoryToolBundleArchive(), nil)
+
+	// When
+	_, err := service.Export(nil, "empty.bundle")
+
+	// Then
+	assert.ErrorIs(t, err, domain.ErrEmptyToolBundle)
+}
+
+func TestToolBundleService_Import_NewTool(t *testing.T) {
+	// Given a bundle and an empty repository
+	archive := NewMemoryToolBundleArchive()
+	exported := exportGreet(t, archive)
+	tools := NewMockToolRepository()
+	examples := NewMockToolExampleRepository()
+	service := domain.NewToolBundleService(tools, examples, archive, nil)
+
+	// When
+	report, err := service.Import("greet.bundle", domain.ImportSkip, "team:platform")
+
+	// Then the tool is created with its history and example under a new ID
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Greet"}, report.Imported)
+
+	imported, err := tools.GetByNamespaceAndName("team:platform", "Greet")
+	require.NoError(t, err)
+	assert.NotEqual(t, exported.ID, imported.ID)
+	assert.Equal(t, "console.log('hello')", imported.Code)
+
+	revisions, err := tools.ListRevisions(imported.ID)
+	require.NoError(t, err)
+	require.Len(t, revisions, 2)
+	assert.Equal(t, "Say hello", revisions[0].Message)
+
+	importedExamples, err := examples.ListByTool(imported.ID)
+	require.NoError(t, err)
+	require.Len(t, importedExamples, 1)
+	assert.Equal(t, "says hello", importedExamples[0].Name)
+}
+
+func TestToolBundleService_Import_Conflicts(t *testing.T) {
+	archive := NewMemoryToolBundleArchive()
+	exportGreet(t, archive)
+
+	newFixture := func() (*MockToolRepository, *MockToolExampleRepository, *domain.ToolBundleService, domain.Tool) {
+		tools := NewMockToolRepository()
+		examples := NewMockToolExampleRepository()
+		existing := domain.NewTool("Greet", "console.log('mine')")
+		_, err := tools.Save(existing)
+		require.NoError(t, err)
+		return tools, examples, domain.NewToolBundleService(tools, examples, archive, nil), existing
+	}
+
+	t.Run("skip", func(t *testing.T) {
+		// Given
+		tools, _, service, existing := newFixture()
+
+		// When
+		report, err := service.Import("greet.bundle", domain.ImportSkip, domain.GlobalNamespace)
+
+		// Then the existing tool is untouched
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Greet"}, report.Skipped)
+		assert.Empty(t, report.Imported)
+		kept, err := tools.GetByID(existing.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "console.log('mine')", kept.Code)
+	})
+
+	t.Run("rename", func(t *testing.T) {
+		// Given
+		tools, _, service, _ := newFixture()
+
+		// When
+		report, err := service.Import("greet.bundle", domain.ImportRename, domain.GlobalNamespace)
+
+		// Then the bundled tool is imported under a free name
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"Greet": "Greet 2"}, report.Renamed)
+		renamed, err := tools.GetByNamespaceAndName(domain.GlobalNamespace, "Greet 2")
+		require.NoError(t, err)
+		assert.Equal(t, "console.log('hello')", renamed.Code)
+	})
+
+	t.Run("overwrite", func(t *testing.T) {
+		// Given
+		tools, examples, service, existing := newFixture()
+
+		// When
+		report, err := service.Import("greet.bundle", domain.ImportOverwrite, domain.GlobalNamespace)
+
+		// Then the bundled code is a new revision of the existing tool
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Greet"}, report.Overwritten)
+		overwritten, err := tools.GetByID(existing.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "console.log('hello')", overwritten.Code)
+
+		revisions, err := tools.ListRevisions(existing.ID)
+		require.NoError(t, err)
+		require.Len(t, revisions, 2)
+		assert.Equal(t, "Imported from bundle", revisions[0].Message)
+
+		replaced, err := examples.ListByTool(existing.ID)
+		require.NoError(t, err)
+		assert.Len(t, replaced, 1)
+	})
+}
+
+func TestToolBundleService_Import_ChecksEveryToolFirst(t *testing.T) {
+	// Given a bundle whose second tool has an unknown language
+	archive := NewMemoryToolBundleArchive()
+	unknown := domain.NewTool("Report", "DISPLAY 'hi'")
+	unknown.Language = "cobol"
+	archive.bundles["mixed.bundle"] = domain.NewToolBundle([]domain.BundledTool{
+		{Tool: domain.NewTool("Greet", "console.log('hi')")},
+		{Tool: unknown},
+	})
+	tools := NewMockToolRepository()
+	service := domain.NewToolBundleService(tools, NewMockToolExampleRepository(), archive, nil)
+
+	// When
+	_, err := service.Import("mixed.bundle", domain.ImportSkip, domain.GlobalNamespace)
+
+	// Then nothing is imported
+	assert.ErrorIs(t, err, domain.ErrInvalidToolBundle)
+	listed, err := tools.List()
+	require.NoError(t, err)
+	assert.Empty(t, listed)
+}
+
+func TestToolBundleService_Import_AllOrNothing(t *testing.T) {
+	// Given an existing tool, and a bundle overwriting it and adding a tool
+	// whose examples can't be saved
+	tools := NewMockToolRepository()
+	examples := NewMockToolExampleRepository()
+	existing := domain.NewTool("Greet", "console.log('mine')")
+	existing, err := tools.Save(existing)
+	require.NoError(t, err)
+	kept, err := domain.NewToolExample(existing, "says mine", nil, "mine", nil)
+	require.NoError(t, err)
+	require.NoError(t, examples.Save(kept))
+
+	broken := domain.NewTool("Broken", "console.log('broken')")
+	twice, err := domain.NewToolExample(broken, "same name", nil, "broken", nil)
+	require.NoError(t, err)
+	archive := NewMemoryToolBundleArchive()
+	archive.bundles["partial.bundle"] = domain.NewToolBundle([]domain.BundledTool{
+		{Tool: domain.NewTool("Greet", "console.log('hello')")},
+		{Tool: broken, Examples: []domain.ToolExample{twice, twice}},
+	})
+	service := domain.NewToolBundleService(tools, examples, archive, nil)
+
+	// When
+	_, err = service.Import("partial.bundle", domain.ImportOverwrite, domain.GlobalNamespace)
+
+	// Then the created tool is gone and the overwritten one is put back
+	assert.ErrorIs(t, err, domain.ErrExampleNameTaken)
+	_, err = tools.GetByNamespaceAndName(domain.GlobalNamespace, "Broken")
+	assert.ErrorIs(t, err, domain.ErrToolNotFound)
+	trash, err := tools.ListTrash()
+	require.NoError(t, err)
+	assert.Empty(t, trash)
+
+	restored, err := tools.GetByID(existing.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "console.log('mine')", restored.Code)
+	restoredExamples, err := examples.ListByTool(existing.ID)
+	require.NoError(t, err)
+	require.Len(t, restoredExamples, 1)
+	assert.Equal(t, kept.ID, restoredExamples[0].ID)
+}
+
+func TestToolBundleService_Import_AsksForDeclaredCapabilities(t *testing.T) {
+	// Given a bundled tool declaring network access
+	declared, err := domain.NewTool("Fetch", "await fetch('https://example.com')").WithCapabilities(domain.ToolCapabilities{Network: true})
+	require.NoError(t, err)
+	archive := NewMemoryToolBundleArchive()
+	archive.bundles["fetch.bundle"] = domain.NewToolBundle([]domain.BundledTool{{Tool: declared}})
+	tools := NewMockToolRepository()
+	escalations := NewMockCapabilityEscalationRepository()
+	service := domain.NewToolBundleService(tools, NewMockToolExampleRepository(), archive, domain.NewCapabilityService(tools, escalations))
+
+	// When
+	_, err = service.Import("fetch.bundle", domain.ImportSkip, domain.GlobalNamespace)
+	require.NoError(t, err)
+
+	// Then the tool is imported without it and the user is asked for it
+	imported, err := tools.GetByNamespaceAndName(domain.GlobalNamespace, "Fetch")
+	require.NoError(t, err)
+	require.NotNil(t, imported.Capabilities)
+	assert.False(t, imported.Capabilities.Network)
+
+	pending, err := escalations.List(domain.EscalationPending)
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	assert.Equal(t, imported.ID, pending[0].ToolID)
+	assert.Equal(t, domain.CapabilityNetwork, pending[0].Kind)
+}
+
+func TestToolBundleService_Import_InvalidInput(t *testing.T) {
+	// Given
+	archive := NewMemoryToolBundleArchive()
+	archive.bundles["other.bundle"] = domain.ToolBundle{Format: "something else", Version: 1}
+	service := domain.NewToolBundleService(NewMockToolRepository(), NewMockToolExampleRepository(), archive, nil)
+
+	// When
+	_, formatErr := service.Import("other.bundle", domain.ImportSkip, domain.GlobalNamespace)
+	_, strategyErr := service.Import("other.bundle", "merge", domain.GlobalNamespace)
+	_, namespaceErr := service.Import("other.bundle", domain.ImportSkip, "planet:mars")
+
+	// Then
+	assert.ErrorIs(t, formatErr, domain.ErrInvalidToolBundle)
+	assert.ErrorIs(t, strategyErr, domain.ErrInvalidImportStrategy)
+	assert.ErrorIs(t, namespaceErr, domain.ErrInvalidNamespace)
+}
@@ -0,0 +1,37 @@
+package domain
+
+import "time"
+
+// ToolFileEntry is a file found in a tools directory
+type ToolFileEntry struct {
+	// Path is relative to the tools directory
+	Path    string
+	Content string
+}
+
+// ToolDirectory is a directory of tool files, such as .lumina/tools in a project
+type ToolDirectory interface {
+	// List returns every tool file in the directory
+	List() ([]ToolFileEntry, error)
+	Write(path, content string) error
+	// Delete removes a file; deleting a missing file is not an error
+	Delete(path string) error
+}
+
+// ToolSyncState records a tool as it was when last synced with its file
+type ToolSyncState struct {
+	ToolID string
+	Path   string
+	// Hash is HashToolFile of the tool at the last sync; it is the common
+	// base that tells which side changed since
+	Hash     string
+	SyncedAt time.Time
+}
+
+// ToolSyncStateRepository stores the sync state of one tools directory
+type ToolSyncStateRepository interface {
+	List() ([]ToolSyncState, error)
+	Save(state ToolSyncState) error
+	Delete(toolID string) error
+	Close() error
+}
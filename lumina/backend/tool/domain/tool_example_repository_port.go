@@ -0,0 +1,17 @@
+package domain
+
+// ToolExampleRepository defines the interface for tool example persistence
+type ToolExampleRepository interface {
+	// Save creates or updates an example; it fails with ErrExampleNameTaken
+	// when another example of the tool has the name
+	Save(example ToolExample) error
+	GetByID(id string) (ToolExample, error)
+	// ListByTool returns the examples of a tool ordered by name
+	ListByTool(toolID string) ([]ToolExample, error)
+	// List returns every example ordered by tool and name
+	List() ([]ToolExample, error)
+	Delete(id string) error
+	// DeleteByTool removes every example of a tool
+	DeleteByTool(toolID string) error
+	Close() error
+}
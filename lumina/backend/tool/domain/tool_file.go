@@ -0,0 +1,209 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var ErrInvalidToolFile = errors.New("invalid tool file")
+
+// ToolFileExtension is the extension of tool files in a tools directory
+const ToolFileExtension = ".ts"
+
+// frontMatterDelimiter opens and closes the metadata block of a tool file
+const frontMatterDelimiter = "// ---"
+
+// FormatToolFile renders a tool as a TypeScript file whose leading comment
+// block holds the metadata:
+//
+//	// ---
+//	// id: 6f1c...
+//	// name: Weather
+//	// description: Shows the forecast for a city
+//	// tags: ["http"]
+//	// parameters: [{"name":"city","type":"string",...}]
+//	// dependencies: [{"name":"zod","version":"^3.23.0"}]
+//	// capabilities: {"read_paths":null,"write_paths":null,"network":true,"subprocess":false}
+//	// ---
+//
+//	<code>
+//
+// Empty fields are left out, so the output is also the canonical form used
+// to compare a file with the stored tool. The namespace is left out too: a
+// file belongs to the namespace its tools directory is synced with. A file
+// without capabilities declares none; the capabilities a file declares are
+// only granted by the user, see ToolSyncService.
+func FormatToolFile(tool Tool) string {
+	var builder strings.Builder
+	builder.WriteString(frontMatterDelimiter + "\n")
+	writeFrontMatter(&builder, "id", formatFrontMatterText(tool.ID))
+	writeFrontMatter(&builder, "name", formatFrontMatterText(tool.Name))
+	if tool.Description != "" {
+		writeFrontMatter(&builder, "description", formatFrontMatterText(tool.Description))
+	}
+	if len(tool.Tags) > 0 {
+		writeFrontMatter(&builder, "tags", formatFrontMatterJSON(tool.Tags))
+	}
+	if len(tool.Parameters) > 0 {
+		writeFrontMatter(&builder, "parameters", formatFrontMatterJSON(tool.Parameters))
+	}
+	if len(tool.Dependencies) > 0 {
+		writeFrontMatter(&builder, "dependencies", formatFrontMatterJSON(tool.Dependencies))
+	}
+	if tool.Capabilities != nil {
+		writeFrontMatter(&builder, "capabilities", formatFrontMatterJSON(tool.Capabilities))
+	}
+	builder.WriteString(frontMatterDelimiter + "\n\n")
+	builder.WriteString(strings.TrimSpace(tool.Code))
+	builder.WriteString("\n")
+	return builder.String()
+}
+
+func writeFrontMatter(builder *strings.Builder, key, value string) {
+	builder.WriteString("// " + key + ": " + value + "\n")
+}
+
+// formatFrontMatterText keeps plain values readable and quotes the rest
+func formatFrontMatterText(value string) string {
+	if value == strings.TrimSpace(value) && !strings.ContainsAny(value, "\r\n") && !strings.HasPrefix(value, `"`) {
+		return value
+	}
+	return formatFrontMatterJSON(value)
+}
+
+func formatFrontMatterJSON(value interface{}) string {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return "null"
+	}
+	return string(encoded)
+}
+
+// ParseToolFile reads a tool written by FormatToolFile. The ID may be missing
+// for tools written by hand; timestamps are left zero. Unknown keys are ignored.
+func ParseToolFile(content string) (Tool, error) {
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+
+	start := 0
+	for start < len(lines) && strings.TrimSpace(lines[start]) == "" {
+		start++
+	}
+	if start == len(lines) || strings.TrimSpace(lines[start]) != frontMatterDelimiter {
+		return Tool{}, fmt.Errorf("%w: missing %q front-matter", ErrInvalidToolFile, frontMatterDelimiter)
+	}
+
+	var tool Tool
+	end := -1
+	for i := start + 1; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if line == frontMatterDelimiter {
+			end = i
+			break
+		}
+
+		entry, ok := strings.CutPrefix(line, "//")
+		if !ok {
+			return Tool{}, fmt.Errorf("%w: line %d is not a comment", ErrInvalidToolFile, i+1)
+		}
+		key, value, ok := strings.Cut(strings.TrimSpace(entry), ":")
+		if !ok {
+			return Tool{}, fmt.Errorf("%w: line %d is not a key: value pair", ErrInvalidToolFile, i+1)
+		}
+		if err := setFrontMatter(&tool, strings.TrimSpace(key), strings.TrimSpace(value)); err != nil {
+			return Tool{}, fmt.Errorf("%w: %s: %v", ErrInvalidToolFile, strings.TrimSpace(key), err)
+		}
+	}
+	if end < 0 {
+		return Tool{}, fmt.Errorf("%w: front-matter is not closed", ErrInvalidToolFile)
+	}
+
+	tool.Name = strings.TrimSpace(tool.Name)
+	tool.Code = strings.TrimSpace(strings.Join(lines[end+1:], "\n"))
+	tool.Tags = NormalizeTags(tool.Tags)
+
+	if tool.Name == "" {
+		return Tool{}, fmt.Errorf("%w: %v", ErrInvalidToolFile, ErrToolNameEmpty)
+	}
+	if tool.Code == "" {
+		return Tool{}, fmt.Errorf("%w: %v", ErrInvalidToolFile, ErrToolCodeEmpty)
+	}
+	if err := ValidateParameters(tool.Parameters); err != nil {
+		return Tool{}, fmt.Errorf("%w: %v", ErrInvalidToolFile, err)
+	}
+	if err := ValidateDependencies(tool.Dependencies); err != nil {
+		return Tool{}, fmt.Errorf("%w: %v", ErrInvalidToolFile, err)
+	}
+	if tool.Capabilities != nil {
+		if err := tool.Capabilities.Validate(); err != nil {
+			return Tool{}, fmt.Errorf("%w: %v", ErrInvalidToolFile, err)
+		}
+	}
+
+	return tool, nil
+}
+
+func setFrontMatter(tool *Tool, key, value string) error {
+	switch key {
+	case "id":
+		return parseFrontMatterText(value, &tool.ID)
+	case "name":
+		return parseFrontMatterText(value, &tool.Name)
+	case "description":
+		return parseFrontMatterText(value, &tool.Description)
+	case "tags":
+		return json.Unmarshal([]byte(value), &tool.Tags)
+	case "parameters":
+		return json.Unmarshal([]byte(value), &tool.Parameters)
+	case "dependencies":
+		return json.Unmarshal([]byte(value), &tool.Dependencies)
+	case "capabilities":
+		return json.Unmarshal([]byte(value), &tool.Capabilities)
+	}
+	return nil
+}
+
+func parseFrontMatterText(value string, target *string) error {
+	if strings.HasPrefix(value, `"`) {
+		return json.Unmarshal([]byte(value), target)
+	}
+	*target = value
+	return nil
+}
+
+// HashToolFile fingerprints the synced content of a tool
+func HashToolFile(tool Tool) string {
+	sum := sha256.Sum256([]byte(FormatToolFile(tool)))
+	return hex.EncodeToString(sum[:])
+}
+
+// ToolFileName suggests a file name for a tool from its name, e.g.
+// "JSON helpers" becomes "json-helpers.ts"
+func ToolFileName(tool Tool) string {
+	return ToolSlug(tool.Name) + ToolFileExtension
+}
+
+// ToolSlug is the lowercase, dash-separated form of a tool name used in
+// file names and imports
+func ToolSlug(toolName string) string {
+	var slug strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(toolName) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			slug.WriteRune(r)
+			dash = false
+		} else if !dash && slug.Len() > 0 {
+			slug.WriteRune('-')
+			dash = true
+		}
+	}
+
+	name := strings.TrimSuffix(slug.String(), "-")
+	if name == "" {
+		name = "tool"
+	}
+	return name
+}
@@ -0,0 +1,77 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"lumina/backend/tool/domain"
+)
+
+func TestToolFile_RoundTrip(t *testing.T) {
+	// Given a tool with every kind of metadata
+	tool, err := domain.NewTool("Weather", "const args = JSON.parse(process.env.LUMINA_ARGS ?? '{}');\nconsole.log(args.city);").
+		WithMetadata("Shows the forecast\nfor a city", []string{"http"}).
+		WithParameters([]domain.ToolParameter{{Name: "city", Type: domain.ParameterString, Required: true}})
+	require.NoError(t, err)
+	tool, err = tool.WithDependencies([]domain.NpmDependency{{Name: "zod", Version: "^3.23.0"}})
+	require.NoError(t, err)
+
+	// When formatting and parsing it back
+	content := domain.FormatToolFile(tool)
+	parsed, err := domain.ParseToolFile(content)
+
+	// Then the synced fields survive
+	require.NoError(t, err)
+	assert.Equal(t, tool.ID, parsed.ID)
+	assert.Equal(t, tool.Name, parsed.Name)
+	assert.Equal(t, tool.Code, parsed.Code)
+	assert.Equal(t, tool.Description, parsed.Description)
+	assert.Equal(t, tool.Tags, parsed.Tags)
+	assert.Equal(t, tool.Parameters, parsed.Parameters)
+	assert.Equal(t, tool.Dependencies, parsed.Dependencies)
+	assert.Equal(t, domain.HashToolFile(tool), domain.HashToolFile(parsed))
+	assert.Contains(t, content, "// name: Weather\n")
+}
+
+func TestParseToolFile_HandWritten(t *testing.T) {
+	// Given a file written by hand without an ID
+	content := "\n// ---\n// name: Hello\n// owner: someone\n// ---\nconsole.log('hello');\n"
+
+	// When parsing it
+	tool, err := domain.ParseToolFile(content)
+
+	// Then unknown keys are ignored and the ID is left empty
+	require.NoError(t, err)
+	assert.Empty(t, tool.ID)
+	assert.Equal(t, "Hello", tool.Name)
+	assert.Equal(t, "console.log('hello');", tool.Code)
+}
+
+func TestParseToolFile_Invalid(t *testing.T) {
+	testCases := []struct {
+		name    string
+		content string
+	}{
+		{name: "no front-matter", content: "console.log('hi');"},
+		{name: "unclosed front-matter", content: "// ---\n// name: Hi\nconsole.log('hi');"},
+		{name: "missing name", content: "// ---\n// id: 1\n// ---\nconsole.log('hi');"},
+		{name: "missing code", content: "// ---\n// name: Hi\n// ---\n"},
+		{name: "malformed tags", content: "// ---\n// name: Hi\n// tags: http\n// ---\nconsole.log('hi');"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// When parsing the file
+			_, err := domain.ParseToolFile(tc.content)
+
+			// Then it is rejected
+			assert.ErrorIs(t, err, domain.ErrInvalidToolFile)
+		})
+	}
+}
+
+func TestToolFileName(t *testing.T) {
+	assert.Equal(t, "json-helpers.ts", domain.ToolFileName(domain.Tool{Name: "JSON helpers!"}))
+	assert.Equal(t, "tool.ts", domain.ToolFileName(domain.Tool{Name: "???"}))
+}
@@ -0,0 +1,7 @@
+package domain
+
+// ToolGenerationModel answers a prompt with text; the configured chat
+// service satisfies it
+type ToolGenerationModel interface {
+	SendMessage(message string) (string, error)
+}
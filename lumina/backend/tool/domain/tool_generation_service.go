@@ -0,0 +1,130 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ToolGenerationService writes tools from a description with a language
+// model, checks them by running their example and saves them as drafts
+type ToolGenerationService struct {
+	tools    ToolRepository
+	examples ToolExampleRepository
+	runner   ToolRunner
+	model    ToolGenerationModel
+}
+
+// NewToolGenerationService checks candidates with runner, which should
+// neither record runs nor escalate capabilities: candidates are not saved
+func NewToolGenerationService(tools ToolRepository, examples ToolExampleRepository, runner ToolRunner, model ToolGenerationModel) *ToolGenerationService {
+	return &ToolGenerationService{
+		tools:    tools,
+		examples: examples,
+		runner:   runner,
+		model:    model,
+	}
+}
+
+// Generate asks the model for a tool implementing description and runs its
+// example, sending the failures back for up to MaxGenerationAttempts
+// attempts. The first passing tool is saved as a draft with its example;
+// when none passes it fails with ErrGenerationFailed.
+func (s *ToolGenerationService) Generate(description string) (ToolGenerationResult, error) {
+	if strings.TrimSpace(description) == "" {
+		return ToolGenerationResult{}, ErrGenerationDescriptionEmpty
+	}
+
+	var result ToolGenerationResult
+	prompt := generationPrompt(description)
+	for attempt := 1; attempt <= MaxGenerationAttempts; attempt++ {
+		reply, err := s.model.SendMessage(prompt)
+		if err != nil {
+			return result, fmt.Errorf("failed to generate tool: %w", err)
+		}
+
+		tool, example, checked := s.check(reply)
+		checked.Attempt = attempt
+		result.Attempts = append(result.Attempts, checked)
+
+		if checked.Passed {
+			return s.save(result, tool, example)
+		}
+		prompt = repairPrompt(description, reply, checked.Failures)
+	}
+
+	last := result.Attempts[len(result.Attempts)-1]
+	return result, fmt.Errorf("%w after %d attempts: %s", ErrGenerationFailed, MaxGenerationAttempts, strings.Join(last.Failures, "; "))
+}
+
+// check builds the tool of a reply and runs its example; every problem is
+// a failure to report back to the model
+func (s *ToolGenerationService) check(reply string) (Tool, ToolExample, ToolGenerationAttempt) {
+	attempt := ToolGenerationAttempt{Failures: []string{}}
+
+	generated, err := ParseGeneratedTool(reply)
+	if err != nil {
+		attempt.Failures = append(attempt.Failures, err.Error())
+		return Tool{}, ToolExample{}, attempt
+	}
+
+	tool, example, err := generated.Build()
+	if err != nil {
+		attempt.Failures = append(attempt.Failures, err.Error())
+		return Tool{}, ToolExample{}, attempt
+	}
+
+	args, err := tool.ResolveArguments(example.Arguments)
+	if err != nil {
+		attempt.Failures = append(attempt.Failures, err.Error())
+		return Tool{}, ToolExample{}, attempt
+	}
+
+	output, err := s.runner.Run(tool, args)
+	if err != nil {
+		attempt.Failures = append(attempt.Failures, fmt.Sprintf("failed to run: %v", err))
+		return Tool{}, ToolExample{}, attempt
+	}
+
+	attempt.Output = output
+	attempt.Failures = append(attempt.Failures, example.Check(output)...)
+	attempt.Passed = len(attempt.Failures) == 0
+	return tool, example, attempt
+}
+
+// save stores the passing tool under a free name, then its example
+func (s *ToolGenerationService) save(result ToolGenerationResult, tool Tool, example ToolExample) (ToolGenerationResult, error) {
+	name, err := s.freeName(tool.Name)
+	if err != nil {
+		return result, err
+	}
+	tool.Name = name
+
+	tool, err = s.tools.SaveWithMessage(tool, "Generated from a description")
+	if err != nil {
+		return result, fmt.Errorf("failed to save generated tool: %w", err)
+	}
+
+	if err := s.examples.Save(example); err != nil {
+		return result, fmt.Errorf("failed to save generated example: %w", err)
+	}
+
+	result.Tool = tool
+	result.Example = example
+	return result, nil
+}
+
+// freeName returns name, or "name 2", "name 3"... when it is taken
+func (s *ToolGenerationService) freeName(name string) (string, error) {
+	candidate := name
+	for n := 2; ; n++ {
+		_, err := s.tools.GetByName(candidate)
+		if errors.Is(err, ErrToolNotFound) {
+			return candidate, nil
+		}
+		if err != nil {
+			return "", err
+		}
+		candidate = fmt.Sprintf("%s %d", name, n)
+	}
+}
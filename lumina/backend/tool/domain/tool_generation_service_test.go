@@ -0,0 +1,157 @@
+package domain_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"lumina/backend/tool/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ScriptedModel answers prompts with canned replies, in order
+type ScriptedModel struct {
+	replies []string
+	prompts []string
+	err     error
+}
+
+func (m *ScriptedModel) SendMessage(message string) (string, error) {
+	if m.err != nil {
+		return "", m.err
+	}
+	m.prompts = append(m.prompts, message)
+	reply := m.replies[0]
+	if len(m.replies) > 1 {
+		m.replies = m.replies[1:]
+	}
+	return reply, nil
+}
+
+// greetReply is a generated greeting tool whose example expects expected
+func greetReply(expected string) string {
+	return "```json\n" + `{
+  "name": "Greet",
+  "description": "Greets someone",
+  "code": "const { name } = JSON.parse(process.env.LUMINA_ARGS ?? '{}'); console.log('Hello, ' + name);",
+  "parameters": [{"name": "name", "type": "string", "required": true}],
+  "example": {"arguments": {"name": "Ada"}, "expected_output": "` + expected + `"}
+}` + "\n```"
+}
+
+func newGenerationFixture(replies ...string) (*MockToolRepository, *MockToolExampleRepository, *ScriptedModel, *domain.ToolGenerationService) {
+	tools := NewMockToolRepository()
+	examples := NewMockToolExampleRepository()
+	model := &ScriptedModel{replies: replies}
+	return tools, examples, model, domain.NewToolGenerationService(tools, examples, &MockToolRunner{}, model)
+}
+
+func TestToolGenerationService_RetriesUntilTheExamplePasses(t *testing.T) {
+	// Given a model that first replies with prose, then with a tool failing its example, then with a passing one
+	tools, examples, model, service := newGenerationFixture("not json", greetReply("Hi, Ada"), greetReply("Hello, Ada"))
+
+	// When
+	result, err := service.Generate("greet someone by name")
+
+	// Then the passing tool is saved as a draft with its example
+	require.NoError(t, err)
+	require.Len(t, result.Attempts, 3)
+	assert.False(t, result.Attempts[0].Passed)
+	assert.False(t, result.Attempts[1].Passed)
+	assert.True(t, result.Attempts[2].Passed)
+
+	saved, err := tools.GetByName("Greet")
+	require.NoError(t, err)
+	assert.True(t, saved.Draft)
+	assert.Equal(t, saved.Version, result.Tool.Version)
+	assert.Equal(t, "Greets someone", saved.Description)
+	require.Len(t, saved.Parameters, 1)
+
+	savedExamples, err := examples.ListByTool(saved.ID)
+	require.NoError(t, err)
+	require.Len(t, savedExamples, 1)
+	assert.Equal(t, domain.GeneratedExampleName, savedExamples[0].Name)
+
+	// And each retry told the model what was wrong
+	require.Len(t, model.prompts, 3)
+	assert.Contains(t, model.prompts[1], "reply does not contain a valid tool")
+	assert.Contains(t, model.prompts[2], "Hi, Ada")
+}
+
+func TestToolGenerationService_GivesUpAfterTheLastAttempt(t *testing.T) {
+	// Given a model that never gets the example right
+	tools, _, _, service := newGenerationFixture(greetReply("Hi, Ada"))
+
+	// When
+	result, err := service.Generate("greet someone by name")
+
+	// Then nothing is saved
+	assert.ErrorIs(t, err, domain.ErrGenerationFailed)
+	assert.Len(t, result.Attempts, domain.MaxGenerationAttempts)
+	listed, err := tools.List()
+	require.NoError(t, err)
+	assert.Empty(t, listed)
+}
+
+func TestToolGenerationService_PicksAFreeName(t *testing.T) {
+	// Given a tool already named like the generated one
+	tools, _, _, service := newGenerationFixture(greetReply("Hello, Ada"))
+	_, err := tools.Save(domain.NewTool("Greet", "console.log('hi')"))
+	require.NoError(t, err)
+
+	// When
+	result, err := service.Generate("greet someone by name")
+
+	// Then the generated tool gets a numbered name
+	require.NoError(t, err)
+	assert.Equal(t, "Greet 2", result.Tool.Name)
+}
+
+func TestToolGenerationService_ModelErrors(t *testing.T) {
+	// Given a model that can't be reached
+	_, _, model, service := newGenerationFixture()
+	model.err = errors.New("OpenAI API key is not set")
+
+	// When
+	_, err := service.Generate("anything")
+
+	// Then the error is returned without retrying
+	assert.ErrorContains(t, err, "OpenAI API key is not set")
+
+	// And an empty description is rejected up front
+	_, err = service.Generate("  ")
+	assert.Equal(t, domain.ErrGenerationDescriptionEmpty, err)
+}
+
+func TestParseGeneratedTool(t *testing.T) {
+	// Given a bare JSON reply and a fenced one
+	bare := strings.TrimSuffix(strings.TrimPrefix(greetReply("Hello, Ada"), "```json\n"), "\n```")
+
+	// When
+	fromBare, bareErr := domain.ParseGeneratedTool(bare)
+	fromFence, fenceErr := domain.ParseGeneratedTool("Here you go:\n" + greetReply("Hello, Ada"))
+	_, invalidErr := domain.ParseGeneratedTool("I can't do that")
+
+	// Then both parse the same way and prose is rejected
+	require.NoError(t, bareErr)
+	require.NoError(t, fenceErr)
+	assert.Equal(t, fromBare, fromFence)
+	assert.Equal(t, "Greet", fromBare.Name)
+	assert.Equal(t, "Ada", fromBare.Example.Arguments["name"])
+	assert.ErrorIs(t, invalidErr, domain.ErrInvalidGeneratedTool)
+}
+
+func TestGeneratedTool_Build(t *testing.T) {
+	// Given a proposal whose example misses a required argument
+	generated, err := domain.ParseGeneratedTool(greetReply("Hello, Ada"))
+	require.NoError(t, err)
+	generated.Example.Arguments = map[string]interface{}{}
+
+	// When
+	_, _, buildErr := generated.Build()
+
+	// Then the example is rejected against the schema
+	assert.Error(t, buildErr)
+}
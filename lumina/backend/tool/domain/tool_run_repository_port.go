@@ -0,0 +1,13 @@
+package domain
+
+// ToolRunRepository stores the history of executions
+type ToolRunRepository interface {
+	Save(run ToolRun) error
+	GetByID(id string) (ToolRun, error)
+	// List returns the runs matching query, most recent first
+	List(query ToolRunQuery) ([]ToolRun, error)
+	// UsageStats summarizes the runs of every tool that ran, keyed by tool
+	// ID; ad-hoc runs are left out
+	UsageStats() (map[string]ToolUsage, error)
+	Close() error
+}
@@ -0,0 +1,21 @@
+package domain
+
+// ToolRunOutput is what a tool printed and how it exited
+type ToolRunOutput struct {
+	Output     string `json:"output"`
+	Error      string `json:"error"`
+	ExitCode   int    `json:"exit_code"`
+	Success    bool   `json:"success"`
+	DurationMs int64  `json:"duration_ms"`
+	// CapabilityRequests are the accesses the run was refused because the
+	// tool did not declare them
+	CapabilityRequests []CapabilityRequest `json:"capability_requests,omitempty"`
+}
+
+// ToolRunner executes saved tools together with the tools they import
+type ToolRunner interface {
+	// Run executes the tool with arguments already resolved against its parameters
+	Run(tool Tool, args map[string]interface{}) (ToolRunOutput, error)
+	// RunCode executes ad-hoc code that is not saved as a tool
+	RunCode(code string) (ToolRunOutput, error)
+}
@@ -0,0 +1,486 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrSyncSideInvalid = errors.New("sync side must be \"file\" or \"database\"")
+
+// SyncSide names the copy of a tool kept when resolving a conflict
+type SyncSide string
+
+const (
+	SyncKeepFile     SyncSide = "file"
+	SyncKeepDatabase SyncSide = "database"
+)
+
+// ToolSyncConflict is a tool the sync left alone because both sides changed,
+// or a file it could not read
+type ToolSyncConflict struct {
+	ToolID string `json:"tool_id"`
+	Name   string `json:"name"`
+	Path   string `json:"path"`
+	Reason string `json:"reason"`
+}
+
+// ToolSyncReport lists what a sync did, by tool name
+type ToolSyncReport struct {
+	Imported     []string           `json:"imported"`
+	Exported     []string           `json:"exported"`
+	UpdatedTools []string           `json:"updated_tools"`
+	UpdatedFiles []string           `json:"updated_files"`
+	TrashedTools []string           `json:"trashed_tools"`
+	DeletedFiles []string           `json:"deleted_files"`
+	Conflicts    []ToolSyncConflict `json:"conflicts"`
+}
+
+// ToolSyncService keeps the tools of one namespace and a tools directory in
+// step. Each side's changes since the last sync are carried over to the
+// other; a tool changed on both sides is reported as a conflict and left
+// untouched until it is resolved.
+//
+// The capabilities a file declares are not taken on trust: the tool keeps
+// those it was already granted, the others are recorded as escalations for
+// the user to decide on, and the file is rewritten with what was kept.
+type ToolSyncService struct {
+	repository   ToolRepository
+	directory    ToolDirectory
+	states       ToolSyncStateRepository
+	namespace    string
+	capabilities *CapabilityService
+}
+
+// NewToolSyncService syncs directory with the tools in namespace, usually
+// the project namespace of the project holding the directory. Files don't
+// name their namespace: every file is a tool of namespace. capabilities may
+// be nil, in which case undeclared capabilities are dropped unrecorded.
+func NewToolSyncService(repository ToolRepository, directory ToolDirectory, states ToolSyncStateRepository, namespace string, capabilities *CapabilityService) *ToolSyncService {
+	return &ToolSyncService{
+		repository:   repository,
+		directory:    directory,
+		states:       states,
+		namespace:    NormalizeNamespace(namespace),
+		capabilities: capabilities,
+	}
+}
+
+// toolFile is a parsed tool file
+type toolFile struct {
+	path string
+	tool Tool
+	hash string
+}
+
+// syncSnapshot is both sides of the sync and the state between them
+type syncSnapshot struct {
+	tools    map[string]Tool
+	others   map[string]Tool
+	files    map[string]toolFile
+	states   map[string]ToolSyncState
+	newFiles []toolFile
+	paths    map[string]bool
+}
+
+// Sync runs a two-way sync and reports what changed
+func (s *ToolSyncService) Sync() (ToolSyncReport, error) {
+	var report ToolSyncReport
+
+	snapshot, err := s.snapshot(&report)
+	if err != nil {
+		return ToolSyncReport{}, err
+	}
+
+	for id, tool := range snapshot.tools {
+		file, hasFile := snapshot.files[id]
+		state, hasState := snapshot.states[id]
+		toolHash := HashToolFile(tool)
+
+		switch {
+		case hasFile:
+			err = s.reconcile(tool, file, state, hasState, &report)
+
+		case hasState && state.Hash == toolHash:
+			// The file was deleted and the tool is unchanged
+			err = s.trashTool(tool, &report)
+
+		case hasState:
+			report.Conflicts = append(report.Conflicts, ToolSyncConflict{
+				ToolID: id, Name: tool.Name, Path: state.Path,
+				Reason: "the file was deleted but the tool changed",
+			})
+
+		default:
+			err = s.exportTool(tool, snapshot.freePath(tool), &report)
+		}
+
+		if err != nil {
+			return ToolSyncReport{}, err
+		}
+	}
+
+	for id, file := range snapshot.files {
+		if _, hasTool := snapshot.tools[id]; hasTool {
+			continue
+		}
+		if other, elsewhere := snapshot.others[id]; elsewhere {
+			report.Conflicts = append(report.Conflicts, ToolSyncConflict{
+				ToolID: id, Name: file.tool.Name, Path: file.path,
+				Reason: fmt.Sprintf("the tool is in namespace %s", NormalizeNamespace(other.Namespace)),
+			})
+			continue
+		}
+		state, hasState := snapshot.states[id]
+
+		switch {
+		case hasState && state.Hash == file.hash:
+			// The tool was deleted and the file is unchanged
+			err = s.deleteFile(file, &report)
+
+		case hasState:
+			report.Conflicts = append(report.Conflicts, ToolSyncConflict{
+				ToolID: id, Name: file.tool.Name, Path: file.path,
+				Reason: "the tool was deleted but the file changed",
+			})
+
+		default:
+			err = s.importFile(file, &report)
+		}
+
+		if err != nil {
+			return ToolSyncReport{}, err
+		}
+	}
+
+	for _, file := range snapshot.newFiles {
+		if err := s.importFile(file, &report); err != nil {
+			return ToolSyncReport{}, err
+		}
+	}
+
+	report.sort()
+	return report, nil
+}
+
+// Resolve settles a conflict by keeping one side of the tool. Keeping a side
+// where the tool no longer exists deletes it on the other side too.
+func (s *ToolSyncService) Resolve(toolID string, keep SyncSide) (ToolSyncReport, error) {
+	if keep != SyncKeepFile && keep != SyncKeepDatabase {
+		return ToolSyncReport{}, ErrSyncSideInvalid
+	}
+
+	var report ToolSyncReport
+	snapshot, err := s.snapshot(&report)
+	if err != nil {
+		return ToolSyncReport{}, err
+	}
+	report.Conflicts = nil
+
+	tool, hasTool := snapshot.tools[toolID]
+	file, hasFile := snapshot.files[toolID]
+	if !hasTool && !hasFile {
+		return ToolSyncReport{}, ErrToolNotFound
+	}
+
+	switch {
+	case keep == SyncKeepFile && hasFile && hasTool:
+		err = s.updateTool(tool, file, &report)
+	case keep == SyncKeepFile && hasFile:
+		err = s.importFile(file, &report)
+	case keep == SyncKeepFile:
+		err = s.trashTool(tool, &report)
+	case hasTool && hasFile:
+		err = s.writeFile(tool, file.path, &report.UpdatedFiles)
+	case hasTool:
+		path := snapshot.freePath(tool)
+		if state, ok := snapshot.states[toolID]; ok {
+			path = state.Path
+		}
+		err = s.exportTool(tool, path, &report)
+	default:
+		err = s.deleteFile(file, &report)
+	}
+
+	if err != nil {
+		return ToolSyncReport{}, err
+	}
+
+	report.sort()
+	return report, nil
+}
+
+// sort orders the report for stable display
+func (r *ToolSyncReport) sort() {
+	for _, names := range [][]string{r.Imported, r.Exported, r.UpdatedTools, r.UpdatedFiles, r.TrashedTools, r.DeletedFiles} {
+		sort.Strings(names)
+	}
+	sort.Slice(r.Conflicts, func(i, j int) bool {
+		return r.Conflicts[i].Path < r.Conflicts[j].Path
+	})
+}
+
+func (s *ToolSyncService) snapshot(report *ToolSyncReport) (syncSnapshot, error) {
+	snapshot := syncSnapshot{
+		tools:  make(map[string]Tool),
+		others: make(map[string]Tool),
+		files:  make(map[string]toolFile),
+		states: make(map[string]ToolSyncState),
+		paths:  make(map[string]bool),
+	}
+
+	tools, err := s.repository.List()
+	if err != nil {
+		return syncSnapshot{}, fmt.Errorf("failed to list tools: %w", err)
+	}
+	// Tool files are TypeScript, so tools in other languages stay in the
+	// store, as do tools of other namespaces
+	for _, tool := range tools {
+		switch {
+		case NormalizeNamespace(tool.Namespace) != s.namespace:
+			snapshot.others[tool.ID] = tool
+		case tool.IsTypeScript():
+			snapshot.tools[tool.ID] = tool
+		}
+	}
+
+	states, err := s.states.List()
+	if err != nil {
+		return syncSnapshot{}, fmt.Errorf("failed to load sync state: %w", err)
+	}
+	for _, state := range states {
+		snapshot.states[state.ToolID] = state
+	}
+
+	entries, err := s.directory.List()
+	if err != nil {
+		return syncSnapshot{}, fmt.Errorf("failed to list tool files: %w", err)
+	}
+	for _, entry := range entries {
+		snapshot.paths[entry.Path] = true
+
+		tool, err := ParseToolFile(entry.Content)
+		if err != nil {
+			report.Conflicts = append(report.Conflicts, ToolSyncConflict{Path: entry.Path, Reason: err.Error()})
+			continue
+		}
+
+		file := toolFile{path: entry.Path, tool: tool, hash: HashToolFile(tool)}
+		if tool.ID == "" {
+			snapshot.newFiles = append(snapshot.newFiles, file)
+			continue
+		}
+		if other, duplicate := snapshot.files[tool.ID]; duplicate {
+			report.Conflicts = append(report.Conflicts, ToolSyncConflict{
+				ToolID: tool.ID, Name: tool.Name, Path: entry.Path,
+				Reason: fmt.Sprintf("%s has the same tool id", other.path),
+			})
+			continue
+		}
+		snapshot.files[tool.ID] = file
+	}
+
+	return snapshot, nil
+}
+
+// freePath picks an unused file name for a tool and reserves it
+func (s syncSnapshot) freePath(tool Tool) string {
+	path := ToolFileName(tool)
+	if s.paths[path] {
+		path = strings.TrimSuffix(path, ToolFileExtension) + "-" + shortID(tool.ID) + ToolFileExtension
+	}
+	s.paths[path] = true
+	return path
+}
+
+func shortID(id string) string {
+	if len(id) > 8 {
+		return id[:8]
+	}
+	return id
+}
+
+// reconcile syncs a tool that exists on both sides
+func (s *ToolSyncService) reconcile(tool Tool, file toolFile, state ToolSyncState, hasState bool, report *ToolSyncReport) error {
+	toolHash := HashToolFile(tool)
+
+	switch {
+	case toolHash == file.hash:
+		if hasState && state.Hash == toolHash && state.Path == file.path {
+			return nil
+		}
+		return s.saveState(tool.ID, file.path, toolHash)
+
+	case hasState && state.Hash == file.hash:
+		return s.writeFile(tool, file.path, &report.UpdatedFiles)
+
+	case hasState && state.Hash == toolHash:
+		return s.updateTool(tool, file, report)
+	}
+
+	report.Conflicts = append(report.Conflicts, ToolSyncConflict{
+		ToolID: tool.ID, Name: tool.Name, Path: file.path,
+		Reason: "both the file and the tool changed",
+	})
+	return nil
+}
+
+// updateTool saves the content of a file over the stored tool
+func (s *ToolSyncService) updateTool(tool Tool, file toolFile, report *ToolSyncReport) error {
+	updated := tool
+	updated.Name = file.tool.Name
+	updated.Code = file.tool.Code
+	updated.Description = file.tool.Description
+	updated.Tags = file.tool.Tags
+	updated.Parameters = file.tool.Parameters
+	updated.Dependencies = file.tool.Dependencies
+	capabilities, requests := importedCapabilities(file.tool.Capabilities, &tool)
+	updated.Capabilities = capabilities
+	updated.UpdatedAt = time.Now()
+
+	updated, err := s.repository.SaveWithMessage(updated, "Synced from "+file.path)
+	if err != nil {
+		// A save that raced this sync is a conflict like any other
+		if errors.Is(err, ErrToolNameTaken) || errors.Is(err, ErrToolVersionConflict) {
+			report.Conflicts = append(report.Conflicts, ToolSyncConflict{
+				ToolID: tool.ID, Name: file.tool.Name, Path: file.path, Reason: err.Error(),
+			})
+			return nil
+		}
+		return fmt.Errorf("failed to save tool %s: %w", tool.Name, err)
+	}
+
+	report.UpdatedTools = append(report.UpdatedTools, updated.Name)
+	s.askFor(updated, requests)
+	return s.syncedFrom(updated, file)
+}
+
+// importFile stores a file's tool, giving it an ID first when it has none
+func (s *ToolSyncService) importFile(file toolFile, report *ToolSyncReport) error {
+	tool := file.tool
+	tool.Namespace = s.namespace
+	now := time.Now()
+	tool.CreatedAt = now
+	tool.UpdatedAt = now
+
+	restored := false
+	var stored *Tool
+	if tool.ID == "" {
+		tool.ID = uuid.New().String()
+	} else {
+		// A trashed tool with this ID comes back instead of being shadowed,
+		// and the file is saved over it as a new revision
+		trashed, err := s.repository.Restore(tool.ID)
+		switch {
+		case err == nil:
+			restored = true
+			stored = &trashed
+			tool.CreatedAt = trashed.CreatedAt
+			tool.Version = trashed.Version
+		case errors.Is(err, ErrToolNameTaken):
+			report.Conflicts = append(report.Conflicts, ToolSyncConflict{
+				ToolID: tool.ID, Name: tool.Name, Path: file.path, Reason: err.Error(),
+			})
+			return nil
+		case !errors.Is(err, ErrToolNotFound):
+			return fmt.Errorf("failed to restore tool %s: %w", tool.Name, err)
+		}
+	}
+
+	capabilities, requests := importedCapabilities(file.tool.Capabilities, stored)
+	tool.Capabilities = capabilities
+
+	tool, err := s.repository.SaveWithMessage(tool, "Imported from "+file.path)
+	if err != nil {
+		// A tool that can't take the file goes back to the trash
+		if restored {
+			if trashErr := s.repository.Delete(file.tool.ID); trashErr != nil {
+				return fmt.Errorf("failed to import tool %s: %w", file.tool.Name, trashErr)
+			}
+		}
+		// A save that raced this sync is a conflict like any other
+		if errors.Is(err, ErrToolNameTaken) || errors.Is(err, ErrToolVersionConflict) {
+			report.Conflicts = append(report.Conflicts, ToolSyncConflict{
+				ToolID: file.tool.ID, Name: file.tool.Name, Path: file.path, Reason: err.Error(),
+			})
+			return nil
+		}
+		return fmt.Errorf("failed to import tool %s: %w", file.tool.Name, err)
+	}
+
+	report.Imported = append(report.Imported, tool.Name)
+	s.askFor(tool, requests)
+	return s.syncedFrom(tool, file)
+}
+
+// syncedFrom records that the stored tool was synced from file. The file is
+// rewritten when the tool differs from it, to give it an ID or the
+// capabilities the tool was granted, so later syncs match the two.
+func (s *ToolSyncService) syncedFrom(tool Tool, file toolFile) error {
+	if HashToolFile(tool) != file.hash {
+		return s.writeFile(tool, file.path, nil)
+	}
+	return s.saveState(tool.ID, file.path, file.hash)
+}
+
+// askFor records the capabilities a file declared beyond those its tool
+// was granted; a failure to record never fails the sync
+func (s *ToolSyncService) askFor(tool Tool, requests []CapabilityRequest) {
+	if s.capabilities == nil {
+		return
+	}
+	if _, err := s.capabilities.Record(tool, requests); err != nil {
+		log.Printf("Warning: Failed to record capability escalation of %s: %v", tool.Name, err)
+	}
+}
+
+func (s *ToolSyncService) exportTool(tool Tool, path string, report *ToolSyncReport) error {
+	return s.writeFile(tool, path, &report.Exported)
+}
+
+// writeFile writes the tool to its file and records the sync
+func (s *ToolSyncService) writeFile(tool Tool, path string, names *[]string) error {
+	if err := s.directory.Write(path, FormatToolFile(tool)); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if names != nil {
+		*names = append(*names, tool.Name)
+	}
+	return s.saveState(tool.ID, path, HashToolFile(tool))
+}
+
+func (s *ToolSyncService) trashTool(tool Tool, report *ToolSyncReport) error {
+	if err := s.repository.Delete(tool.ID); err != nil {
+		return fmt.Errorf("failed to delete tool %s: %w", tool.Name, err)
+	}
+	report.TrashedTools = append(report.TrashedTools, tool.Name)
+	return s.deleteState(tool.ID)
+}
+
+func (s *ToolSyncService) deleteFile(file toolFile, report *ToolSyncReport) error {
+	if err := s.directory.Delete(file.path); err != nil {
+		return fmt.Errorf("failed to delete %s: %w", file.path, err)
+	}
+	report.DeletedFiles = append(report.DeletedFiles, file.path)
+	return s.deleteState(file.tool.ID)
+}
+
+func (s *ToolSyncService) saveState(toolID, path, hash string) error {
+	state := ToolSyncState{ToolID: toolID, Path: path, Hash: hash, SyncedAt: time.Now()}
+	if err := s.states.Save(state); err != nil {
+		return fmt.Errorf("failed to save sync state: %w", err)
+	}
+	return nil
+}
+
+func (s *ToolSyncService) deleteState(toolID string) error {
+	if err := s.states.Delete(toolID); err != nil {
+		return fmt.Errorf("failed to delete sync state: %w", err)
+	}
+	return nil
+}
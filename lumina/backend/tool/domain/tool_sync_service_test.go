@@ -0,0 +1,347 @@
+package domain_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"lumina/backend/tool/domain"
+)
+
+// MockToolDirectory keeps tool files in memory
+type MockToolDirectory struct {
+	files map[string]string
+}
+
+func NewMockToolDirectory() *MockToolDirectory {
+	return &MockToolDirectory{files: make(map[string]string)}
+}
+
+func (m *MockToolDirectory) List() ([]domain.ToolFileEntry, error) {
+	var entries []domain.ToolFileEntry
+	for path, content := range m.files {
+		entries = append(entries, domain.ToolFileEntry{Path: path, Content: content})
+	}
+	return entries, nil
+}
+
+func (m *MockToolDirectory) Write(path, content string) error {
+	m.files[path] = content
+	return nil
+}
+
+func (m *MockToolDirectory) Delete(path string) error {
+	delete(m.files, path)
+	return nil
+}
+
+// MockToolSyncStateRepository keeps sync state in memory
+type MockToolSyncStateRepository struct {
+	states map[string]domain.ToolSyncState
+}
+
+func NewMockToolSyncStateRepository() *MockToolSyncStateRepository {
+	return &MockToolSyncStateRepository{states: make(map[string]domain.ToolSyncState)}
+}
+
+func (m *MockToolSyncStateRepository) List() ([]domain.ToolSyncState, error) {
+	var states []domain.ToolSyncState
+	for _, state := range m.states {
+		states = append(states, state)
+	}
+	return states, nil
+}
+
+func (m *MockToolSyncStateRepository) Save(state domain.ToolSyncState) error {
+	m.states[state.ToolID] = state
+	return nil
+}
+
+func (m *MockToolSyncStateRepository) Delete(toolID string) error {
+	delete(m.states, toolID)
+	return nil
+}
+
+func (m *MockToolSyncStateRepository) Close() error {
+	return nil
+}
+
+// syncNamespace is the namespace the fixture's tools directory syncs with
+var syncNamespace = domain.ProjectNamespace("/src/app")
+
+func newSyncFixture() (*MockToolRepository, *MockToolDirectory, *domain.ToolSyncService) {
+	repo := NewMockToolRepository()
+	directory := NewMockToolDirectory()
+	return repo, directory, domain.NewToolSyncService(repo, directory, NewMockToolSyncStateRepository(), syncNamespace, nil)
+}
+
+// projectTool is a new tool in the synced namespace
+func projectTool(name, code string) domain.Tool {
+	tool := domain.NewTool(name, code)
+	tool.Namespace = syncNamespace
+	return tool
+}
+
+func TestToolSyncService_ExportsAndImports(t *testing.T) {
+	// Given a stored tool and a hand-written tool file
+	repo, directory, service := newSyncFixture()
+	stored := projectTool("Stored Tool", "console.log('stored');")
+	_, err := repo.Save(stored)
+	require.NoError(t, err)
+	directory.files["hello.ts"] = "// ---\n// name: Hello\n// ---\nconsole.log('hello');\n"
+
+	// When syncing
+	report, err := service.Sync()
+	require.NoError(t, err)
+
+	// Then the stored tool gets a file and the file becomes a tool
+	assert.Equal(t, []string{"Stored Tool"}, report.Exported)
+	assert.Equal(t, []string{"Hello"}, report.Imported)
+	assert.Empty(t, report.Conflicts)
+	assert.Contains(t, directory.files["stored-tool.ts"], "// id: "+stored.ID)
+
+	imported, err := repo.GetByName("Hello")
+	require.NoError(t, err)
+	assert.Contains(t, directory.files["hello.ts"], "// id: "+imported.ID)
+	assert.Equal(t, syncNamespace, imported.Namespace)
+
+	// And a second sync has nothing to do
+	report, err = service.Sync()
+	require.NoError(t, err)
+	assert.Equal(t, domain.ToolSyncReport{}, report)
+}
+
+func TestToolSyncService_SkipsOtherLanguages(t *testing.T) {
+	// Given a stored Python tool
+	repo, directory, service := newSyncFixture()
+	python, err := projectTool("Python Tool", "print('hi')").WithLanguage(domain.LanguagePython)
+	require.NoError(t, err)
+	_, err = repo.Save(python)
+	require.NoError(t, err)
+
+	// When syncing
+	report, err := service.Sync()
+	require.NoError(t, err)
+
+	// Then it gets no TypeScript file
+	assert.Empty(t, report.Exported)
+	assert.Empty(t, directory.files)
+}
+
+func TestToolSyncService_CarriesOneSidedChanges(t *testing.T) {
+	// Given two tools in sync with their files
+	repo, directory, service := newSyncFixture()
+	fromFile := projectTool("From File", "console.log(1);")
+	fromStore := projectTool("From Store", "console.log(1);")
+	_, err := repo.Save(fromFile)
+	require.NoError(t, err)
+	_, err = repo.Save(fromStore)
+	require.NoError(t, err)
+	_, err = service.Sync()
+	require.NoError(t, err)
+
+	// When one is edited in its file and the other in the store
+	directory.files["from-file.ts"] = strings.Replace(directory.files["from-file.ts"], "console.log(1);", "console.log(2);", 1)
+	storeSide, err := repo.GetByID(fromStore.ID)
+	require.NoError(t, err)
+	_, err = repo.Save(storeSide.WithUpdatedCode("console.log(3);"))
+	require.NoError(t, err)
+
+	report, err := service.Sync()
+	require.NoError(t, err)
+
+	// Then each change reaches the other side
+	assert.Equal(t, []string{"From File"}, report.UpdatedTools)
+	assert.Equal(t, []string{"From Store"}, report.UpdatedFiles)
+	updated, err := repo.GetByID(fromFile.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "console.log(2);", updated.Code)
+	assert.Contains(t, directory.files["from-store.ts"], "console.log(3);")
+}
+
+func TestToolSyncService_ReportsAndResolvesConflicts(t *testing.T) {
+	// Given a tool in sync with its file
+	repo, directory, service := newSyncFixture()
+	tool := projectTool("Contested", "console.log('base');")
+	_, err := repo.Save(tool)
+	require.NoError(t, err)
+	_, err = service.Sync()
+	require.NoError(t, err)
+
+	// When both the file and the tool change
+	directory.files["contested.ts"] = strings.Replace(directory.files["contested.ts"], "'base'", "'file'", 1)
+	storeSide, err := repo.GetByID(tool.ID)
+	require.NoError(t, err)
+	_, err = repo.Save(storeSide.WithUpdatedCode("console.log('store');"))
+	require.NoError(t, err)
+
+	report, err := service.Sync()
+	require.NoError(t, err)
+
+	// Then the conflict is reported and neither side is touched
+	require.Len(t, report.Conflicts, 1)
+	assert.Equal(t, tool.ID, report.Conflicts[0].ToolID)
+	assert.Equal(t, "contested.ts", report.Conflicts[0].Path)
+	assert.Contains(t, directory.files["contested.ts"], "'file'")
+	stored, err := repo.GetByID(tool.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "console.log('store');", stored.Code)
+
+	// And keeping the file settles it
+	_, err = service.Resolve(tool.ID, domain.SyncKeepFile)
+	require.NoError(t, err)
+	stored, err = repo.GetByID(tool.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "console.log('file');", stored.Code)
+
+	report, err = service.Sync()
+	require.NoError(t, err)
+	assert.Empty(t, report.Conflicts)
+}
+
+func TestToolSyncService_PropagatesDeletes(t *testing.T) {
+	// Given two tools in sync with their files
+	repo, directory, service := newSyncFixture()
+	fileDeleted := projectTool("File Deleted", "console.log(1);")
+	toolDeleted := projectTool("Tool Deleted", "console.log(2);")
+	_, err := repo.Save(fileDeleted)
+	require.NoError(t, err)
+	_, err = repo.Save(toolDeleted)
+	require.NoError(t, err)
+	_, err = service.Sync()
+	require.NoError(t, err)
+
+	// When one file is removed and the other tool is trashed
+	delete(directory.files, "file-deleted.ts")
+	require.NoError(t, repo.Delete(toolDeleted.ID))
+
+	report, err := service.Sync()
+	require.NoError(t, err)
+
+	// Then the delete reaches the other side
+	assert.Equal(t, []string{"File Deleted"}, report.TrashedTools)
+	assert.Equal(t, []string{"tool-deleted.ts"}, report.DeletedFiles)
+	_, err = repo.GetByID(fileDeleted.ID)
+	assert.Equal(t, domain.ErrToolNotFound, err)
+	assert.NotContains(t, directory.files, "tool-deleted.ts")
+}
+
+func TestToolSyncService_RestoresTrashedToolFromFile(t *testing.T) {
+	// Given a synced tool that was trashed and whose sync state was lost
+	repo, directory, service := newSyncFixture()
+	tool := projectTool("Trashed", "console.log(1);")
+	_, err := repo.Save(tool)
+	require.NoError(t, err)
+	_, err = service.Sync()
+	require.NoError(t, err)
+	require.NoError(t, repo.Delete(tool.ID))
+	directory.files["trashed.ts"] = strings.Replace(directory.files["trashed.ts"], "console.log(1);", "console.log(2);", 1)
+
+	// When syncing without the state
+	report, err := domain.NewToolSyncService(repo, directory, NewMockToolSyncStateRepository(), syncNamespace, nil).Sync()
+
+	// Then the tool comes back with the file as a new revision
+	require.NoError(t, err)
+	assert.Empty(t, report.Conflicts)
+	assert.Equal(t, []string{"Trashed"}, report.Imported)
+	restored, err := repo.GetByID(tool.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "console.log(2);", restored.Code)
+	assert.Equal(t, 2, restored.Version)
+}
+
+func TestToolSyncService_KeepsToOwnNamespace(t *testing.T) {
+	// Given a global tool, and a file naming it that was synced before
+	repo, directory, service := newSyncFixture()
+	global := domain.NewTool("Global", "console.log('global');")
+	_, err := repo.Save(global)
+	require.NoError(t, err)
+	directory.files["global.ts"] = domain.FormatToolFile(global)
+
+	// When syncing
+	report, err := service.Sync()
+	require.NoError(t, err)
+
+	// Then the global tool is not exported and its file is left alone
+	assert.Empty(t, report.Exported)
+	assert.Empty(t, report.DeletedFiles)
+	require.Len(t, report.Conflicts, 1)
+	assert.Equal(t, global.ID, report.Conflicts[0].ToolID)
+	assert.Contains(t, directory.files, "global.ts")
+	stored, err := repo.GetByID(global.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.GlobalNamespace, stored.Namespace)
+}
+
+func TestToolSyncService_AsksForDeclaredCapabilities(t *testing.T) {
+	// Given a new file declaring network access
+	repo := NewMockToolRepository()
+	directory := NewMockToolDirectory()
+	escalations := NewMockCapabilityEscalationRepository()
+	service := domain.NewToolSyncService(repo, directory, NewMockToolSyncStateRepository(), syncNamespace, domain.NewCapabilityService(repo, escalations))
+	directory.files["fetch.ts"] = "// ---\n// name: Fetch\n// capabilities: {\"network\":true}\n// ---\nawait fetch('https://example.com');\n"
+
+	// When syncing
+	report, err := service.Sync()
+	require.NoError(t, err)
+
+	// Then the tool is imported without it and the user is asked for it
+	assert.Equal(t, []string{"Fetch"}, report.Imported)
+	imported, err := repo.GetByName("Fetch")
+	require.NoError(t, err)
+	require.NotNil(t, imported.Capabilities)
+	assert.False(t, imported.Capabilities.Network)
+
+	pending, err := escalations.List(domain.EscalationPending)
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	assert.Equal(t, imported.ID, pending[0].ToolID)
+	assert.Equal(t, domain.CapabilityNetwork, pending[0].Kind)
+
+	// And the file shows what was granted, so a second sync has nothing to do
+	assert.NotContains(t, directory.files["fetch.ts"], `"network":true`)
+	report, err = service.Sync()
+	require.NoError(t, err)
+	assert.Equal(t, domain.ToolSyncReport{}, report)
+}
+
+func TestToolSyncService_KeepsGrantedCapabilities(t *testing.T) {
+	// Given a synced tool granted network access
+	repo, directory, service := newSyncFixture()
+	tool, err := projectTool("Fetch", "await fetch('https://example.com');").WithCapabilities(domain.ToolCapabilities{Network: true})
+	require.NoError(t, err)
+	_, err = repo.Save(tool)
+	require.NoError(t, err)
+	_, err = service.Sync()
+	require.NoError(t, err)
+
+	// When its file changes and also declares a path to read
+	directory.files["fetch.ts"] = strings.Replace(directory.files["fetch.ts"], `"read_paths":null`, `"read_paths":["/etc/hosts"]`, 1)
+	directory.files["fetch.ts"] = strings.Replace(directory.files["fetch.ts"], "example.com", "example.org", 1)
+	report, err := service.Sync()
+	require.NoError(t, err)
+
+	// Then the change is saved with only the granted capabilities
+	assert.Equal(t, []string{"Fetch"}, report.UpdatedTools)
+	updated, err := repo.GetByID(tool.ID)
+	require.NoError(t, err)
+	assert.Contains(t, updated.Code, "example.org")
+	assert.True(t, updated.Capabilities.Network)
+	assert.Empty(t, updated.Capabilities.ReadPaths)
+}
+
+func TestToolSyncService_InvalidFile(t *testing.T) {
+	// Given a file that is not a tool file
+	_, directory, service := newSyncFixture()
+	directory.files["notes.ts"] = "console.log('no front-matter');"
+
+	// When syncing
+	report, err := service.Sync()
+
+	// Then it is reported and left alone
+	require.NoError(t, err)
+	require.Len(t, report.Conflicts, 1)
+	assert.Equal(t, "notes.ts", report.Conflicts[0].Path)
+	assert.Equal(t, "console.log('no front-matter');", directory.files["notes.ts"])
+}
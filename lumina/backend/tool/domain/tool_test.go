@@ -1,6 +1,7 @@
 package domain_test
 
 import (
+	"strings"
 	"testing"
 	"time"
 
@@ -104,6 +105,44 @@ func TestTool_UpdateTimestamp(t *testing.T) {
 	assert.Equal(t, originalTool.CreatedAt, updatedTool.CreatedAt)
 }
 
+func TestTool_WithName(t *testing.T) {
+	// Given a tool
+	tool := domain.NewTool("Old Name", "code")
+
+	// When renaming it
+	renamed, err := tool.WithName("  New Name  ")
+
+	// Then only the name changes
+	assert.NoError(t, err)
+	assert.Equal(t, "New Name", renamed.Name)
+	assert.Equal(t, tool.ID, renamed.ID)
+	assert.Equal(t, tool.Code, renamed.Code)
+
+	// And an empty name is rejected
+	_, err = tool.WithName("   ")
+	assert.Equal(t, domain.ErrToolNameEmpty, err)
+}
+
+func TestTool_Duplicate(t *testing.T) {
+	// Given a tool that has been saved a few times
+	tool := domain.NewTool("Original", "console.log('dup');")
+	tool.Version = 3
+
+	// When duplicating it with and without a name
+	named, err := tool.Duplicate("Second")
+	assert.NoError(t, err)
+	unnamed, err := tool.Duplicate("")
+	assert.NoError(t, err)
+
+	// Then the copies get new IDs and the same code
+	assert.NotEqual(t, tool.ID, named.ID)
+	assert.NotEqual(t, named.ID, unnamed.ID)
+	assert.Equal(t, "Second", named.Name)
+	assert.Equal(t, "Original copy", unnamed.Name)
+	assert.Equal(t, tool.Code, named.Code)
+	assert.Zero(t, named.Version)
+}
+
 func TestTool_Repository_Interface(t *testing.T) {
 	// The repository interface should be properly defined
 	var _ domain.ToolRepository = &MockToolRepository{}
@@ -112,6 +151,7 @@ func TestTool_Repository_Interface(t *testing.T) {
 // MockToolRepository for testing
 type MockToolRepository struct {
 	savedTools map[string]domain.Tool
+	revisions  map[string][]domain.ToolRevision
 	saveError  error
 	getError   error
 }
@@ -119,15 +159,45 @@ type MockToolRepository struct {
 func NewMockToolRepository() *MockToolRepository {
 	return &MockToolRepository{
 		savedTools: make(map[string]domain.Tool),
+		revisions:  make(map[string][]domain.ToolRevision),
 	}
 }
 
-func (m *MockToolRepository) Save(tool domain.Tool) error {
+func (m *MockToolRepository) Save(tool domain.Tool) (domain.Tool, error) {
+	return m.SaveWithMessage(tool, "")
+}
+
+func (m *MockToolRepository) SaveWithMessage(tool domain.Tool, message string) (domain.Tool, error) {
 	if m.saveError != nil {
-		return m.saveError
+		return domain.Tool{}, m.saveError
 	}
+	for _, saved := range m.savedTools {
+		sameNamespace := domain.NormalizeNamespace(saved.Namespace) == domain.NormalizeNamespace(tool.Namespace)
+		if saved.ID != tool.ID && saved.Name == tool.Name && sameNamespace && saved.DeletedAt == nil {
+			return domain.Tool{}, domain.ErrToolNameTaken
+		}
+	}
+	stored, exists := m.savedTools[tool.ID]
+	if exists && stored.DeletedAt != nil {
+		return domain.Tool{}, domain.ErrToolNotFound
+	}
+	if (exists && stored.Version != tool.Version) || (!exists && tool.Version != 0) {
+		return domain.Tool{}, domain.ErrToolVersionConflict
+	}
+	tool.Version++
+	tool.Namespace = domain.NormalizeNamespace(tool.Namespace)
 	m.savedTools[tool.ID] = tool
-	return nil
+	m.revisions[tool.ID] = append(m.revisions[tool.ID], domain.ToolRevision{
+		ToolID:       tool.ID,
+		Revision:     len(m.revisions[tool.ID]) + 1,
+		Name:         tool.Name,
+		Code:         tool.Code,
+		Parameters:   tool.Parameters,
+		Dependencies: tool.Dependencies,
+		Message:      message,
+		CreatedAt:    tool.UpdatedAt,
+	})
+	return tool, nil
 }
 
 func (m *MockToolRepository) GetByID(id string) (domain.Tool, error) {
@@ -136,7 +206,7 @@ func (m *MockToolRepository) GetByID(id string) (domain.Tool, error) {
 	}
 
 	tool, exists := m.savedTools[id]
-	if !exists {
+	if !exists || tool.DeletedAt != nil {
 		return domain.Tool{}, domain.ErrToolNotFound
 	}
 	return tool, nil
@@ -148,7 +218,20 @@ func (m *MockToolRepository) GetByName(name string) (domain.Tool, error) {
 	}
 
 	for _, tool := range m.savedTools {
-		if tool.Name == name {
+		if tool.Name == name && tool.DeletedAt == nil {
+			return tool, nil
+		}
+	}
+	return domain.Tool{}, domain.ErrToolNotFound
+}
+
+func (m *MockToolRepository) GetByNamespaceAndName(namespace, name string) (domain.Tool, error) {
+	if m.getError != nil {
+		return domain.Tool{}, m.getError
+	}
+
+	for _, tool := range m.savedTools {
+		if tool.Name == name && domain.NormalizeNamespace(tool.Namespace) == domain.NormalizeNamespace(namespace) && tool.DeletedAt == nil {
 			return tool, nil
 		}
 	}
@@ -162,11 +245,160 @@ func (m *MockToolRepository) List() ([]domain.Tool, error) {
 
 	tools := make([]domain.Tool, 0, len(m.savedTools))
 	for _, tool := range m.savedTools {
-		tools = append(tools, tool)
+		if tool.DeletedAt == nil {
+			tools = append(tools, tool)
+		}
+	}
+	return tools, nil
+}
+
+func (m *MockToolRepository) Search(query domain.ToolQuery) ([]domain.Tool, error) {
+	tools, err := m.List()
+	if err != nil {
+		return nil, err
+	}
+
+	text := strings.ToLower(query.Text)
+	tag := domain.NormalizeTag(query.Tag)
+	var matches []domain.Tool
+	for _, tool := range tools {
+		content := strings.ToLower(tool.Name + " " + tool.Description + " " + tool.Code)
+		if text != "" && !strings.Contains(content, text) {
+			continue
+		}
+		if tag != "" && !containsTag(tool.Tags, tag) {
+			continue
+		}
+		matches = append(matches, tool)
+	}
+	return matches, nil
+}
+
+func (m *MockToolRepository) ListTags() ([]string, error) {
+	tools, err := m.List()
+	if err != nil {
+		return nil, err
+	}
+
+	var tags []string
+	for _, tool := range tools {
+		tags = append(tags, tool.Tags...)
+	}
+	return domain.NormalizeTags(tags), nil
+}
+
+func containsTag(tags []string, tag string) bool {
+	for _, candidate := range tags {
+		if candidate == tag {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *MockToolRepository) ListRevisions(toolID string) ([]domain.ToolRevision, error) {
+	if m.getError != nil {
+		return nil, m.getError
+	}
+
+	saved := m.revisions[toolID]
+	revisions := make([]domain.ToolRevision, 0, len(saved))
+	for i := len(saved) - 1; i >= 0; i-- {
+		revisions = append(revisions, saved[i])
+	}
+	return revisions, nil
+}
+
+func (m *MockToolRepository) GetRevision(toolID string, revision int) (domain.ToolRevision, error) {
+	if m.getError != nil {
+		return domain.ToolRevision{}, m.getError
+	}
+
+	saved := m.revisions[toolID]
+	if revision < 1 || revision > len(saved) {
+		return domain.ToolRevision{}, domain.ErrRevisionNotFound
+	}
+	return saved[revision-1], nil
+}
+
+func (m *MockToolRepository) DiffRevisions(toolID string, from, to int) (domain.RevisionDiff, error) {
+	fromRevision, err := m.GetRevision(toolID, from)
+	if err != nil {
+		return domain.RevisionDiff{}, err
+	}
+	toRevision, err := m.GetRevision(toolID, to)
+	if err != nil {
+		return domain.RevisionDiff{}, err
+	}
+	return domain.DiffRevisions(fromRevision, toRevision), nil
+}
+
+func (m *MockToolRepository) Revert(toolID string, revision int) (domain.Tool, error) {
+	tool, err := m.GetByID(toolID)
+	if err != nil {
+		return domain.Tool{}, err
+	}
+	target, err := m.GetRevision(toolID, revision)
+	if err != nil {
+		return domain.Tool{}, err
+	}
+	return m.SaveWithMessage(tool.WithRevision(target), domain.RevertMessage(revision))
+}
+
+func (m *MockToolRepository) RecordResolvedVersions(toolID string, revision int, versions map[string]string) error {
+	for i, saved := range m.revisions[toolID] {
+		if saved.Revision == revision {
+			m.revisions[toolID][i].ResolvedVersions = versions
+			return nil
+		}
+	}
+	return domain.ErrRevisionNotFound
+}
+
+func (m *MockToolRepository) Delete(id string) error {
+	tool, err := m.GetByID(id)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	tool.DeletedAt = &now
+	m.savedTools[id] = tool
+	return nil
+}
+
+func (m *MockToolRepository) ListTrash() ([]domain.Tool, error) {
+	var tools []domain.Tool
+	for _, tool := range m.savedTools {
+		if tool.DeletedAt != nil {
+			tools = append(tools, tool)
+		}
 	}
 	return tools, nil
 }
 
+func (m *MockToolRepository) Restore(id string) (domain.Tool, error) {
+	tool, exists := m.savedTools[id]
+	if !exists || tool.DeletedAt == nil {
+		return domain.Tool{}, domain.ErrToolNotFound
+	}
+	if _, err := m.GetByName(tool.Name); err == nil {
+		return domain.Tool{}, domain.ErrToolNameTaken
+	}
+	tool.DeletedAt = nil
+	m.savedTools[id] = tool
+	return tool, nil
+}
+
+func (m *MockToolRepository) Purge(id string) error {
+	tool, exists := m.savedTools[id]
+	if !exists || tool.DeletedAt == nil {
+		return domain.ErrToolNotFound
+	}
+	delete(m.savedTools, id)
+	delete(m.revisions, id)
+	return nil
+}
+
 func (m *MockToolRepository) Close() error {
 	// Mock implementation - nothing to close
 	return nil
@@ -0,0 +1,13 @@
+package domain
+
+// ToolTriggerRepository defines the interface for tool trigger persistence
+type ToolTriggerRepository interface {
+	Save(trigger ToolTrigger) error
+	GetByID(id string) (ToolTrigger, error)
+	// ListByTool returns the triggers of a tool, oldest first
+	ListByTool(toolID string) ([]ToolTrigger, error)
+	// List returns every trigger, oldest first
+	List() ([]ToolTrigger, error)
+	Delete(id string) error
+	Close() error
+}
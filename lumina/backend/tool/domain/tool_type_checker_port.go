@@ -0,0 +1,7 @@
+package domain
+
+// ToolTypeChecker type-checks the code of a tool, together with the tools
+// and npm packages it imports, without running it
+type ToolTypeChecker interface {
+	Check(tool Tool) ([]ToolDiagnostic, error)
+}
@@ -0,0 +1,184 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrTriggerNotFound = errors.New("tool trigger not found")
+	ErrInvalidTrigger  = errors.New("invalid tool trigger")
+	ErrTriggerRunning  = errors.New("tool trigger is already running")
+)
+
+// TriggerKind is what makes a trigger run its tool
+type TriggerKind string
+
+const (
+	// TriggerFileChange runs the tool when a project file matching Glob changes
+	TriggerFileChange TriggerKind = "file_change"
+	// TriggerInterval runs the tool every IntervalSeconds
+	TriggerInterval TriggerKind = "interval"
+	// TriggerManual is the reason reported for runs requested by hand
+	TriggerManual TriggerKind = "manual"
+)
+
+// MinTriggerInterval keeps interval triggers from flooding the run history
+const MinTriggerInterval = 10 * time.Second
+
+// ToolTrigger re-runs a tool automatically, for tools that monitor the project
+type ToolTrigger struct {
+	ID     string      `json:"id"`
+	ToolID string      `json:"tool_id"`
+	Kind   TriggerKind `json:"kind"`
+	// Glob selects project files by slash-separated path; ** matches any
+	// number of directories, e.g. "src/**/*.ts"
+	Glob            string                 `json:"glob"`
+	IntervalSeconds int                    `json:"interval_seconds"`
+	Arguments       map[string]interface{} `json:"arguments"`
+	Enabled         bool                   `json:"enabled"`
+	// The last run, used to tell whether the output changed
+	LastRunAt    *time.Time `json:"last_run_at,omitempty"`
+	LastOutput   string     `json:"last_output"`
+	LastExitCode int        `json:"last_exit_code"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// NewToolTrigger creates an enabled trigger for tool
+func NewToolTrigger(tool Tool, kind TriggerKind, glob string, intervalSeconds int, arguments map[string]interface{}) (ToolTrigger, error) {
+	now := time.Now()
+	trigger := ToolTrigger{
+		ID:              uuid.New().String(),
+		ToolID:          tool.ID,
+		Kind:            kind,
+		Glob:            strings.TrimSpace(glob),
+		IntervalSeconds: intervalSeconds,
+		Arguments:       arguments,
+		Enabled:         true,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+
+	if err := trigger.Validate(tool); err != nil {
+		return ToolTrigger{}, err
+	}
+	return trigger, nil
+}
+
+// Validate checks the trigger and its arguments against its tool
+func (t ToolTrigger) Validate(tool Tool) error {
+	switch t.Kind {
+	case TriggerFileChange:
+		if t.Glob == "" {
+			return fmt.Errorf("%w: a file trigger needs a glob", ErrInvalidTrigger)
+		}
+		if _, err := path.Match(strings.ReplaceAll(t.Glob, "**", "*"), ""); err != nil {
+			return fmt.Errorf("%w: glob %q: %v", ErrInvalidTrigger, t.Glob, err)
+		}
+	case TriggerInterval:
+		if t.Interval() < MinTriggerInterval {
+			return fmt.Errorf("%w: interval must be at least %s", ErrInvalidTrigger, MinTriggerInterval)
+		}
+	default:
+		return fmt.Errorf("%w: unknown kind %q", ErrInvalidTrigger, t.Kind)
+	}
+
+	if _, err := tool.ResolveArguments(t.Arguments); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Interval is the period of an interval trigger
+func (t ToolTrigger) Interval() time.Duration {
+	return time.Duration(t.IntervalSeconds) * time.Second
+}
+
+// IntervalDue reports whether an interval trigger should run at now
+func (t ToolTrigger) IntervalDue(now time.Time) bool {
+	return t.Kind == TriggerInterval && (t.LastRunAt == nil || !now.Before(t.LastRunAt.Add(t.Interval())))
+}
+
+// WithResult returns a copy of the trigger remembering output as its last run
+func (t ToolTrigger) WithResult(output ToolRunOutput, ranAt time.Time) ToolTrigger {
+	updated := t
+	updated.LastRunAt = &ranAt
+	updated.LastOutput = output.Output
+	updated.LastExitCode = output.ExitCode
+	return updated
+}
+
+// OutputChanged reports whether output differs from the last run; the first
+// run always counts as a change
+func (t ToolTrigger) OutputChanged(output ToolRunOutput) bool {
+	return t.LastRunAt == nil || t.LastOutput != output.Output || t.LastExitCode != output.ExitCode
+}
+
+// TriggerResult is the outcome of a run started by a trigger
+type TriggerResult struct {
+	TriggerID string      `json:"trigger_id"`
+	ToolID    string      `json:"tool_id"`
+	ToolName  string      `json:"tool_name"`
+	Reason    TriggerKind `json:"reason"`
+	// ChangedFiles are the files that started a file trigger
+	ChangedFiles []string      `json:"changed_files"`
+	Output       ToolRunOutput `json:"output"`
+	// Changed is set when the output differs from the previous run
+	Changed bool      `json:"changed"`
+	Error   string    `json:"error"`
+	RanAt   time.Time `json:"ran_at"`
+}
+
+// MatchGlob reports whether the slash-separated path matches pattern, where
+// ** matches zero or more directories and other segments follow path.Match
+func MatchGlob(pattern, name string) bool {
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+func matchSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for skip := 0; skip <= len(name); skip++ {
+				if matchSegments(pattern[1:], name[skip:]) {
+					return true
+				}
+			}
+			return false
+		}
+
+		if len(name) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], name[0]); !ok {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}
+
+// ChangedFiles compares two snapshots of path to fingerprint and returns the
+// paths added, removed or modified, sorted
+func ChangedFiles(before, after map[string]string) []string {
+	var changed []string
+	for name, fingerprint := range after {
+		if previous, ok := before[name]; !ok || previous != fingerprint {
+			changed = append(changed, name)
+		}
+	}
+	for name := range before {
+		if _, ok := after[name]; !ok {
+			changed = append(changed, name)
+		}
+	}
+
+	sort.Strings(changed)
+	return changed
+}
@@ -0,0 +1,6 @@
+package domain
+
+// TriggerNotifier is told about trigger runs whose output changed
+type TriggerNotifier interface {
+	TriggerOutputChanged(result TriggerResult)
+}
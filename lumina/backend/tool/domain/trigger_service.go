@@ -0,0 +1,275 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// TriggerService runs tools when their triggers fire. File triggers are
+// polled: each tick walks the project once and compares the files matching
+// each trigger with the previous tick, and the first snapshot only sets the
+// baseline. Tools run outside the lock, so triggers can be edited meanwhile,
+// and a trigger never runs its tool twice at the same time.
+type TriggerService struct {
+	tools    ToolRepository
+	triggers ToolTriggerRepository
+	runner   ToolRunner
+	watcher  ProjectWatcher
+
+	mu        sync.Mutex
+	snapshots map[string]map[string]string
+	running   map[string]bool
+	notifier  TriggerNotifier
+}
+
+func NewTriggerService(tools ToolRepository, triggers ToolTriggerRepository, runner ToolRunner, watcher ProjectWatcher) *TriggerService {
+	return &TriggerService{
+		tools:     tools,
+		triggers:  triggers,
+		runner:    runner,
+		watcher:   watcher,
+		snapshots: make(map[string]map[string]string),
+		running:   make(map[string]bool),
+	}
+}
+
+// Save stores a trigger after checking it against its tool
+func (s *TriggerService) Save(trigger ToolTrigger) error {
+	tool, err := s.tools.GetByID(trigger.ToolID)
+	if err != nil {
+		return err
+	}
+	if err := trigger.Validate(tool); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// A changed glob starts from a new baseline
+	delete(s.snapshots, trigger.ID)
+	return s.triggers.Save(trigger)
+}
+
+// Delete removes a trigger
+func (s *TriggerService) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.snapshots, id)
+	return s.triggers.Delete(id)
+}
+
+// SetNotifier sets who is told about runs whose output changed
+func (s *TriggerService) SetNotifier(notifier TriggerNotifier) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.notifier = notifier
+}
+
+// Start runs Tick every interval in the background until ctx is done
+func (s *TriggerService) Start(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				if _, err := s.Tick(now); err != nil {
+					log.Printf("Warning: Failed to check tool triggers: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+// Tick runs the tools of the enabled triggers that are due at now; a
+// trigger that is still running, e.g. fired by hand, is skipped
+func (s *TriggerService) Tick(now time.Time) ([]TriggerResult, error) {
+	due, err := s.dueTriggers(now)
+	if err != nil {
+		return nil, err
+	}
+
+	results := []TriggerResult{}
+	for _, firing := range due {
+		if result, fired := s.fireAlone(firing.trigger, firing.trigger.Kind, firing.changedFiles, now); fired {
+			results = append(results, result)
+		}
+	}
+	return results, nil
+}
+
+// dueTrigger is a trigger that fires on this tick
+type dueTrigger struct {
+	trigger      ToolTrigger
+	changedFiles []string
+}
+
+// dueTriggers picks the triggers that fire at now and moves the file
+// triggers to their new baseline
+func (s *TriggerService) dueTriggers(now time.Time) ([]dueTrigger, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	triggers, err := s.triggers.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tool triggers: %w", err)
+	}
+
+	// One walk of the project serves every file trigger
+	var project map[string]string
+	var watchErr error
+	for _, trigger := range triggers {
+		if trigger.Enabled && trigger.Kind == TriggerFileChange {
+			if project, watchErr = s.watcher.Snapshot(); watchErr != nil {
+				log.Printf("Warning: Failed to watch the project: %v", watchErr)
+			}
+			break
+		}
+	}
+
+	var due []dueTrigger
+	for _, trigger := range triggers {
+		if !trigger.Enabled {
+			continue
+		}
+
+		switch trigger.Kind {
+		case TriggerInterval:
+			if trigger.IntervalDue(now) {
+				due = append(due, dueTrigger{trigger: trigger})
+			}
+		case TriggerFileChange:
+			if watchErr != nil {
+				continue
+			}
+			if changed := s.changedFiles(trigger, project); len(changed) > 0 {
+				due = append(due, dueTrigger{trigger: trigger, changedFiles: changed})
+			}
+		}
+	}
+	return due, nil
+}
+
+// Fire runs the tool of a trigger now, whatever its kind, unless it is
+// already running
+func (s *TriggerService) Fire(id string) (TriggerResult, error) {
+	trigger, err := s.triggers.GetByID(id)
+	if err != nil {
+		return TriggerResult{}, err
+	}
+
+	result, fired := s.fireAlone(trigger, TriggerManual, nil, time.Now())
+	if !fired {
+		return TriggerResult{}, ErrTriggerRunning
+	}
+	return result, nil
+}
+
+// fireAlone fires a trigger unless it is already running, and reports
+// whether it did
+func (s *TriggerService) fireAlone(trigger ToolTrigger, reason TriggerKind, changedFiles []string, now time.Time) (TriggerResult, bool) {
+	s.mu.Lock()
+	if s.running[trigger.ID] {
+		s.mu.Unlock()
+		return TriggerResult{}, false
+	}
+	s.running[trigger.ID] = true
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.running, trigger.ID)
+		s.mu.Unlock()
+	}()
+	return s.fire(trigger, reason, changedFiles, now), true
+}
+
+// changedFiles compares the files of the project matching a file trigger
+// with the previous tick
+func (s *TriggerService) changedFiles(trigger ToolTrigger, project map[string]string) []string {
+	snapshot := make(map[string]string)
+	for path, fingerprint := range project {
+		if MatchGlob(trigger.Glob, path) {
+			snapshot[path] = fingerprint
+		}
+	}
+
+	previous, watched := s.snapshots[trigger.ID]
+	s.snapshots[trigger.ID] = snapshot
+	if !watched {
+		return nil
+	}
+	return ChangedFiles(previous, snapshot)
+}
+
+// fire runs the tool of a trigger and remembers the output; problems are
+// reported on the result
+func (s *TriggerService) fire(trigger ToolTrigger, reason TriggerKind, changedFiles []string, now time.Time) TriggerResult {
+	result := TriggerResult{
+		TriggerID:    trigger.ID,
+		ToolID:       trigger.ToolID,
+		Reason:       reason,
+		ChangedFiles: changedFiles,
+		RanAt:        now,
+	}
+
+	tool, err := s.tools.GetByID(trigger.ToolID)
+	if errors.Is(err, ErrToolNotFound) {
+		result.Error = "tool is in the trash or was deleted"
+		return result
+	}
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.ToolName = tool.Name
+
+	args, err := tool.ResolveArguments(trigger.Arguments)
+	if err == nil {
+		result.Output, err = s.runner.Run(tool, args)
+	}
+	if err != nil {
+		result.Error = err.Error()
+		// Still record the attempt, so an interval trigger waits for its next period
+		result.Output = ToolRunOutput{Error: err.Error(), ExitCode: -1}
+	}
+
+	// The notifier is called outside the lock, so it may use the service
+	if notifier := s.record(trigger.ID, &result, now); result.Changed && notifier != nil {
+		notifier.TriggerOutputChanged(result)
+	}
+	return result
+}
+
+// record saves the output of a run on its trigger, marks the result as
+// changed when the output differs from the last run, and returns the
+// notifier to tell about it
+func (s *TriggerService) record(id string, result *TriggerResult, now time.Time) TriggerNotifier {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// The trigger may have been edited or deleted while its tool ran
+	current, err := s.triggers.GetByID(id)
+	if err != nil {
+		if !errors.Is(err, ErrTriggerNotFound) {
+			log.Printf("Warning: Failed to save tool trigger result: %v", err)
+		}
+		return nil
+	}
+
+	result.Changed = current.OutputChanged(result.Output)
+	if err := s.triggers.Save(current.WithResult(result.Output, now)); err != nil {
+		log.Printf("Warning: Failed to save tool trigger result: %v", err)
+	}
+	return s.notifier
+}
@@ -0,0 +1,370 @@
+package domain_test
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"lumina/backend/tool/domain"
+)
+
+// CountingToolRunner prints a configurable output and counts its runs;
+// during calls back while the tool "runs"
+type CountingToolRunner struct {
+	output string
+	runs   int
+	during func()
+}
+
+func (c *CountingToolRunner) Run(tool domain.Tool, args map[string]interface{}) (domain.ToolRunOutput, error) {
+	c.runs++
+	if c.during != nil {
+		c.during()
+	}
+	return domain.ToolRunOutput{Output: c.output, Success: true}, nil
+}
+
+func (c *CountingToolRunner) RunCode(code string) (domain.ToolRunOutput, error) {
+	return domain.ToolRunOutput{Success: true}, nil
+}
+
+// MockProjectWatcher returns whatever files the test sets and counts walks
+type MockProjectWatcher struct {
+	files     map[string]string
+	snapshots int
+}
+
+func (m *MockProjectWatcher) Snapshot() (map[string]string, error) {
+	m.snapshots++
+	snapshot := make(map[string]string, len(m.files))
+	for name, fingerprint := range m.files {
+		snapshot[name] = fingerprint
+	}
+	return snapshot, nil
+}
+
+// RecordingTriggerNotifier keeps the results it is told about; during is
+// called back with each of them
+type RecordingTriggerNotifier struct {
+	results []domain.TriggerResult
+	during  func(result domain.TriggerResult)
+}
+
+func (r *RecordingTriggerNotifier) TriggerOutputChanged(result domain.TriggerResult) {
+	r.results = append(r.results, result)
+	if r.during != nil {
+		r.during(result)
+	}
+}
+
+// MockToolTriggerRepository for testing
+type MockToolTriggerRepository struct {
+	triggers map[string]domain.ToolTrigger
+}
+
+func NewMockToolTriggerRepository() *MockToolTriggerRepository {
+	return &MockToolTriggerRepository{triggers: make(map[string]domain.ToolTrigger)}
+}
+
+func (m *MockToolTriggerRepository) Save(trigger domain.ToolTrigger) error {
+	m.triggers[trigger.ID] = trigger
+	return nil
+}
+
+func (m *MockToolTriggerRepository) GetByID(id string) (domain.ToolTrigger, error) {
+	trigger, exists := m.triggers[id]
+	if !exists {
+		return domain.ToolTrigger{}, domain.ErrTriggerNotFound
+	}
+	return trigger, nil
+}
+
+func (m *MockToolTriggerRepository) ListByTool(toolID string) ([]domain.ToolTrigger, error) {
+	var triggers []domain.ToolTrigger
+	for _, trigger := range m.sorted() {
+		if trigger.ToolID == toolID {
+			triggers = append(triggers, trigger)
+		}
+	}
+	return triggers, nil
+}
+
+func (m *MockToolTriggerRepository) List() ([]domain.ToolTrigger, error) {
+	return m.sorted(), nil
+}
+
+func (m *MockToolTriggerRepository) sorted() []domain.ToolTrigger {
+	var triggers []domain.ToolTrigger
+	for _, trigger := range m.triggers {
+		triggers = append(triggers, trigger)
+	}
+	sort.Slice(triggers, func(i, j int) bool { return triggers[i].CreatedAt.Before(triggers[j].CreatedAt) })
+	return triggers
+}
+
+func (m *MockToolTriggerRepository) Delete(id string) error {
+	if _, exists := m.triggers[id]; !exists {
+		return domain.ErrTriggerNotFound
+	}
+	delete(m.triggers, id)
+	return nil
+}
+
+func (m *MockToolTriggerRepository) Close() error {
+	return nil
+}
+
+func TestTriggerService_Interfaces(t *testing.T) {
+	var _ domain.ToolRunner = &CountingToolRunner{}
+	var _ domain.ProjectWatcher = &MockProjectWatcher{}
+	var _ domain.TriggerNotifier = &RecordingTriggerNotifier{}
+	var _ domain.ToolTriggerRepository = &MockToolTriggerRepository{}
+}
+
+type triggerFixture struct {
+	tools    *MockToolRepository
+	triggers *MockToolTriggerRepository
+	runner   *CountingToolRunner
+	watcher  *MockProjectWatcher
+	notifier *RecordingTriggerNotifier
+	service  *domain.TriggerService
+	tool     domain.Tool
+}
+
+func newTriggerFixture(t *testing.T) *triggerFixture {
+	f := &triggerFixture{
+		tools:    NewMockToolRepository(),
+		triggers: NewMockToolTriggerRepository(),
+		runner:   &CountingToolRunner{output: "3\n"},
+		watcher:  &MockProjectWatcher{files: map[string]string{"src/a.ts": "1", "README.md": "1"}},
+		notifier: &RecordingTriggerNotifier{},
+	}
+	f.service = domain.NewTriggerService(f.tools, f.triggers, f.runner, f.watcher)
+	f.service.SetNotifier(f.notifier)
+
+	tool, err := domain.NewToolWithValidation("Count TODOs", "console.log(3)")
+	require.NoError(t, err)
+	_, err = f.tools.Save(*tool)
+	require.NoError(t, err)
+	f.tool = *tool
+	return f
+}
+
+func (f *triggerFixture) tick(t *testing.T, now time.Time) []domain.TriggerResult {
+	results, err := f.service.Tick(now)
+	require.NoError(t, err)
+	return results
+}
+
+func TestTriggerService_IntervalTrigger(t *testing.T) {
+	// Given a trigger running every minute
+	f := newTriggerFixture(t)
+	trigger, err := domain.NewToolTrigger(f.tool, domain.TriggerInterval, "", 60, nil)
+	require.NoError(t, err)
+	require.NoError(t, f.service.Save(trigger))
+	now := time.Now()
+
+	// When ticking now, half a minute later and a minute later
+	first := f.tick(t, now)
+	second := f.tick(t, now.Add(30*time.Second))
+	third := f.tick(t, now.Add(60*time.Second))
+
+	// Then it ran twice, and only the first output counted as a change
+	require.Len(t, first, 1)
+	assert.True(t, first[0].Changed)
+	assert.Equal(t, domain.TriggerInterval, first[0].Reason)
+	assert.Empty(t, second)
+	require.Len(t, third, 1)
+	assert.False(t, third[0].Changed)
+	assert.Equal(t, 2, f.runner.runs)
+	assert.Len(t, f.notifier.results, 1)
+
+	// And the last output is stored on the trigger
+	saved, err := f.triggers.GetByID(trigger.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "3\n", saved.LastOutput)
+}
+
+func TestTriggerService_FileTrigger(t *testing.T) {
+	// Given a trigger watching TypeScript sources
+	f := newTriggerFixture(t)
+	trigger, err := domain.NewToolTrigger(f.tool, domain.TriggerFileChange, "src/**/*.ts", 0, nil)
+	require.NoError(t, err)
+	require.NoError(t, f.service.Save(trigger))
+	now := time.Now()
+
+	// When the first tick sets the baseline and an unrelated file changes
+	baseline := f.tick(t, now)
+	f.watcher.files["README.md"] = "2"
+	unrelated := f.tick(t, now.Add(time.Second))
+
+	// Then nothing runs
+	assert.Empty(t, baseline)
+	assert.Empty(t, unrelated)
+
+	// When a matching file changes and one is added
+	f.watcher.files["src/a.ts"] = "2"
+	f.watcher.files["src/lib/b.ts"] = "1"
+	f.runner.output = "4\n"
+	results := f.tick(t, now.Add(2*time.Second))
+
+	// Then the tool runs once with the changed files
+	require.Len(t, results, 1)
+	assert.Equal(t, []string{"src/a.ts", "src/lib/b.ts"}, results[0].ChangedFiles)
+	assert.True(t, results[0].Changed)
+	assert.Equal(t, 1, f.runner.runs)
+	assert.Len(t, f.notifier.results, 1)
+}
+
+func TestTriggerService_WalksProjectOncePerTick(t *testing.T) {
+	// Given two file triggers and an interval trigger
+	f := newTriggerFixture(t)
+	for _, glob := range []string{"src/**/*.ts", "*.md"} {
+		trigger, err := domain.NewToolTrigger(f.tool, domain.TriggerFileChange, glob, 0, nil)
+		require.NoError(t, err)
+		require.NoError(t, f.service.Save(trigger))
+	}
+	interval, err := domain.NewToolTrigger(f.tool, domain.TriggerInterval, "", 60, nil)
+	require.NoError(t, err)
+	require.NoError(t, f.service.Save(interval))
+	now := time.Now()
+
+	// When ticking twice with both globs matching a change
+	f.tick(t, now)
+	f.watcher.files["src/a.ts"] = "2"
+	f.watcher.files["README.md"] = "2"
+	results := f.tick(t, now.Add(time.Second))
+
+	// Then the project was walked once per tick and both file triggers fired
+	assert.Equal(t, 2, f.watcher.snapshots)
+	assert.Len(t, results, 2)
+}
+
+func TestTriggerService_EditsWhileToolRuns(t *testing.T) {
+	// Given two interval triggers, where the first tool run deletes the
+	// second trigger and edits the first
+	f := newTriggerFixture(t)
+	first, err := domain.NewToolTrigger(f.tool, domain.TriggerInterval, "", 60, nil)
+	require.NoError(t, err)
+	require.NoError(t, f.service.Save(first))
+	second, err := domain.NewToolTrigger(f.tool, domain.TriggerInterval, "", 60, nil)
+	require.NoError(t, err)
+	second.CreatedAt = first.CreatedAt.Add(time.Second)
+	require.NoError(t, f.service.Save(second))
+
+	f.runner.during = func() {
+		f.runner.during = nil
+		require.NoError(t, f.service.Delete(second.ID))
+		edited := first
+		edited.IntervalSeconds = 120
+		require.NoError(t, f.service.Save(edited))
+	}
+
+	// When ticking
+	f.tick(t, time.Now())
+
+	// Then the edits made during the run are kept
+	_, err = f.triggers.GetByID(second.ID)
+	assert.ErrorIs(t, err, domain.ErrTriggerNotFound)
+	saved, err := f.triggers.GetByID(first.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 120, saved.IntervalSeconds)
+	assert.Equal(t, "3\n", saved.LastOutput)
+}
+
+func TestTriggerService_SkipsDisabledAndTrashedTools(t *testing.T) {
+	// Given a disabled trigger, and a trigger of a tool in the trash
+	f := newTriggerFixture(t)
+
+	disabled, err := domain.NewToolTrigger(f.tool, domain.TriggerInterval, "", 60, nil)
+	require.NoError(t, err)
+	disabled.Enabled = false
+	require.NoError(t, f.service.Save(disabled))
+
+	trashed, err := domain.NewToolWithValidation("Old", "console.log(1)")
+	require.NoError(t, err)
+	_, err = f.tools.Save(*trashed)
+	require.NoError(t, err)
+	orphan, err := domain.NewToolTrigger(*trashed, domain.TriggerInterval, "", 60, nil)
+	require.NoError(t, err)
+	require.NoError(t, f.service.Save(orphan))
+	require.NoError(t, f.tools.Delete(trashed.ID))
+
+	// When
+	results := f.tick(t, time.Now())
+
+	// Then the disabled trigger is skipped and the orphan reports an error
+	require.Len(t, results, 1)
+	assert.Equal(t, orphan.ID, results[0].TriggerID)
+	assert.NotEmpty(t, results[0].Error)
+	assert.Equal(t, 0, f.runner.runs)
+}
+
+func TestTriggerService_Fire(t *testing.T) {
+	// Given a file trigger
+	f := newTriggerFixture(t)
+	trigger, err := domain.NewToolTrigger(f.tool, domain.TriggerFileChange, "**/*.md", 0, nil)
+	require.NoError(t, err)
+	require.NoError(t, f.service.Save(trigger))
+
+	// When it is fired by hand
+	result, err := f.service.Fire(trigger.ID)
+
+	// Then the tool runs right away
+	require.NoError(t, err)
+	assert.Equal(t, domain.TriggerManual, result.Reason)
+	assert.Equal(t, "3\n", result.Output.Output)
+	assert.Equal(t, 1, f.runner.runs)
+
+	_, err = f.service.Fire("missing")
+	assert.ErrorIs(t, err, domain.ErrTriggerNotFound)
+}
+
+func TestTriggerService_FireWhileRunning(t *testing.T) {
+	// Given an interval trigger whose tool is fired by hand while a tick runs it
+	f := newTriggerFixture(t)
+	trigger, err := domain.NewToolTrigger(f.tool, domain.TriggerInterval, "", 60, nil)
+	require.NoError(t, err)
+	require.NoError(t, f.service.Save(trigger))
+
+	var fireErr error
+	f.runner.during = func() {
+		f.runner.during = nil
+		_, fireErr = f.service.Fire(trigger.ID)
+	}
+
+	// When ticking
+	results := f.tick(t, time.Now())
+
+	// Then the tool runs once, for the tick
+	require.Len(t, results, 1)
+	assert.ErrorIs(t, fireErr, domain.ErrTriggerRunning)
+	assert.Equal(t, 1, f.runner.runs)
+
+	// And the trigger can be fired again once the run is over
+	_, err = f.service.Fire(trigger.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, f.runner.runs)
+}
+
+func TestTriggerService_NotifierUsesService(t *testing.T) {
+	// Given a notifier that deletes the trigger it is told about
+	f := newTriggerFixture(t)
+	trigger, err := domain.NewToolTrigger(f.tool, domain.TriggerInterval, "", 60, nil)
+	require.NoError(t, err)
+	require.NoError(t, f.service.Save(trigger))
+
+	f.notifier.during = func(result domain.TriggerResult) {
+		require.NoError(t, f.service.Delete(result.TriggerID))
+	}
+
+	// When its output changes
+	f.tick(t, time.Now())
+
+	// Then the notifier is told without holding up the service
+	require.Len(t, f.notifier.results, 1)
+	_, err = f.triggers.GetByID(trigger.ID)
+	assert.ErrorIs(t, err, domain.ErrTriggerNotFound)
+}
@@ -0,0 +1,106 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"lumina/backend/tool/domain"
+)
+
+func TestNewToolTrigger_Validates(t *testing.T) {
+	tool := newGreetTool(t)
+	args := map[string]interface{}{"name": "Ada"}
+
+	tests := []struct {
+		name     string
+		kind     domain.TriggerKind
+		glob     string
+		interval int
+		args     map[string]interface{}
+		wantErr  error
+	}{
+		{"file change", domain.TriggerFileChange, "src/**/*.ts", 0, args, nil},
+		{"interval", domain.TriggerInterval, "", 60, args, nil},
+		{"missing glob", domain.TriggerFileChange, " ", 0, args, domain.ErrInvalidTrigger},
+		{"bad glob", domain.TriggerFileChange, "src/[", 0, args, domain.ErrInvalidTrigger},
+		{"interval too short", domain.TriggerInterval, "", 1, args, domain.ErrInvalidTrigger},
+		{"unknown kind", "cron", "", 60, args, domain.ErrInvalidTrigger},
+		{"invalid arguments", domain.TriggerInterval, "", 60, nil, domain.ErrInvalidArguments},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// When
+			trigger, err := domain.NewToolTrigger(tool, tt.kind, tt.glob, tt.interval, tt.args)
+
+			// Then
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, trigger.Enabled)
+			assert.Equal(t, tool.ID, trigger.ToolID)
+		})
+	}
+}
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{"*.go", "main.go", true},
+		{"*.go", "cmd/main.go", false},
+		{"**/*.go", "main.go", true},
+		{"**/*.go", "backend/tool/domain/tool.go", true},
+		{"src/**/*.ts", "src/a/b/c.ts", true},
+		{"src/**/*.ts", "src/c.ts", true},
+		{"src/**/*.ts", "lib/c.ts", false},
+		{"api/schema.json", "api/schema.json", true},
+		{"src/**", "src/a/b", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.path, func(t *testing.T) {
+			assert.Equal(t, tt.want, domain.MatchGlob(tt.pattern, tt.path))
+		})
+	}
+}
+
+func TestChangedFiles(t *testing.T) {
+	// Given
+	before := map[string]string{"a.go": "1", "b.go": "1", "c.go": "1"}
+	after := map[string]string{"a.go": "1", "b.go": "2", "d.go": "1"}
+
+	// When
+	changed := domain.ChangedFiles(before, after)
+
+	// Then modified, removed and added files are reported
+	assert.Equal(t, []string{"b.go", "c.go", "d.go"}, changed)
+}
+
+func TestToolTrigger_IntervalDueAndOutputChanged(t *testing.T) {
+	// Given an interval trigger that never ran
+	tool := newGreetTool(t)
+	trigger, err := domain.NewToolTrigger(tool, domain.TriggerInterval, "", 60, map[string]interface{}{"name": "Ada"})
+	require.NoError(t, err)
+	now := time.Now()
+
+	// Then it is due and any output is a change
+	assert.True(t, trigger.IntervalDue(now))
+	assert.True(t, trigger.OutputChanged(domain.ToolRunOutput{}))
+
+	// When it runs
+	ran := trigger.WithResult(domain.ToolRunOutput{Output: "3\n"}, now)
+
+	// Then it waits a full interval, and only a different output is a change
+	assert.False(t, ran.IntervalDue(now.Add(59*time.Second)))
+	assert.True(t, ran.IntervalDue(now.Add(60*time.Second)))
+	assert.False(t, ran.OutputChanged(domain.ToolRunOutput{Output: "3\n"}))
+	assert.True(t, ran.OutputChanged(domain.ToolRunOutput{Output: "4\n"}))
+	assert.True(t, ran.OutputChanged(domain.ToolRunOutput{Output: "3\n", ExitCode: 1}))
+}
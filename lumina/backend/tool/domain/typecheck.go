@@ -0,0 +1,74 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrToolTypeErrors         = errors.New("tool has type errors")
+	ErrInvalidTypeCheckPolicy = errors.New("invalid type-check policy")
+)
+
+// maxReportedDiagnostics bounds the diagnostics listed in a blocking error
+const maxReportedDiagnostics = 5
+
+// ToolDiagnostic is a type error in the code of a tool. Problems that are
+// not tied to a position, such as an unknown import, have line 0.
+type ToolDiagnostic struct {
+	Line    int    `json:"line"`
+	Column  int    `json:"column"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func (d ToolDiagnostic) String() string {
+	if d.Line == 0 {
+		return d.Message
+	}
+	return fmt.Sprintf("%d:%d %s %s", d.Line, d.Column, d.Code, d.Message)
+}
+
+// TypeCheckPolicy decides what happens when a tool is saved with type errors
+type TypeCheckPolicy string
+
+const (
+	// TypeCheckDraft saves the tool anyway and marks it as a draft
+	TypeCheckDraft TypeCheckPolicy = "draft"
+	// TypeCheckBlock refuses the save
+	TypeCheckBlock TypeCheckPolicy = "block"
+)
+
+// ParseTypeCheckPolicy accepts "draft" and "block"
+func ParseTypeCheckPolicy(value string) (TypeCheckPolicy, error) {
+	switch policy := TypeCheckPolicy(strings.TrimSpace(value)); policy {
+	case TypeCheckDraft, TypeCheckBlock:
+		return policy, nil
+	}
+	return "", fmt.Errorf("%w: %q", ErrInvalidTypeCheckPolicy, value)
+}
+
+// Apply returns the tool to save given the diagnostics of its code: it is
+// unchanged without diagnostics, and otherwise becomes a draft or is
+// rejected with ErrToolTypeErrors
+func (p TypeCheckPolicy) Apply(tool Tool, diagnostics []ToolDiagnostic) (Tool, error) {
+	if len(diagnostics) == 0 {
+		return tool, nil
+	}
+
+	if p == TypeCheckDraft {
+		tool.Draft = true
+		return tool, nil
+	}
+
+	lines := make([]string, 0, maxReportedDiagnostics+1)
+	for i, diagnostic := range diagnostics {
+		if i == maxReportedDiagnostics {
+			lines = append(lines, fmt.Sprintf("and %d more", len(diagnostics)-i))
+			break
+		}
+		lines = append(lines, diagnostic.String())
+	}
+	return Tool{}, fmt.Errorf("%w:\n%s", ErrToolTypeErrors, strings.Join(lines, "\n"))
+}
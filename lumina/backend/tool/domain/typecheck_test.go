@@ -0,0 +1,58 @@
+package domain_test
+
+import (
+	"fmt"
+	"testing"
+
+	"lumina/backend/tool/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTypeCheckPolicy_Apply(t *testing.T) {
+	// Given a tool and a type error in its code
+	tool := domain.NewTool("Typed", "const n: number = 'one';")
+	diagnostics := []domain.ToolDiagnostic{
+		{Line: 1, Column: 7, Code: "TS2322", Message: "Type 'string' is not assignable to type 'number'."},
+	}
+
+	// When applying each policy
+	drafted, draftErr := domain.TypeCheckDraft.Apply(tool, diagnostics)
+	_, blockErr := domain.TypeCheckBlock.Apply(tool, diagnostics)
+	clean, cleanErr := domain.TypeCheckBlock.Apply(tool, nil)
+
+	// Then the draft policy keeps the tool as a draft, the block policy
+	// refuses it with the diagnostic, and clean code passes unchanged
+	require.NoError(t, draftErr)
+	assert.True(t, drafted.Draft)
+	assert.ErrorIs(t, blockErr, domain.ErrToolTypeErrors)
+	assert.ErrorContains(t, blockErr, "1:7 TS2322 Type 'string' is not assignable")
+	require.NoError(t, cleanErr)
+	assert.Equal(t, tool, clean)
+}
+
+func TestTypeCheckPolicy_Apply_ListsTheFirstDiagnostics(t *testing.T) {
+	// Given many type errors
+	var diagnostics []domain.ToolDiagnostic
+	for line := 1; line <= 8; line++ {
+		diagnostics = append(diagnostics, domain.ToolDiagnostic{Line: line, Column: 1, Code: "TS2304", Message: fmt.Sprintf("error %d", line)})
+	}
+
+	// When blocking
+	_, err := domain.TypeCheckBlock.Apply(domain.NewTool("Broken", "x"), diagnostics)
+
+	// Then only the first few are listed
+	assert.ErrorContains(t, err, "error 5")
+	assert.NotContains(t, err.Error(), "error 6")
+	assert.ErrorContains(t, err, "and 3 more")
+}
+
+func TestParseTypeCheckPolicy(t *testing.T) {
+	policy, err := domain.ParseTypeCheckPolicy("block")
+	require.NoError(t, err)
+	assert.Equal(t, domain.TypeCheckBlock, policy)
+
+	_, err = domain.ParseTypeCheckPolicy("warn")
+	assert.ErrorIs(t, err, domain.ErrInvalidTypeCheckPolicy)
+}
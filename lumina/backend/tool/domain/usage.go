@@ -0,0 +1,93 @@
+package domain
+
+import (
+	"sort"
+	"time"
+)
+
+// FrecencySamples is how many of the latest runs of a tool weigh into its frecency
+const FrecencySamples = 10
+
+// ToolUsage summarizes the recorded runs of a tool
+type ToolUsage struct {
+	ToolID       string `json:"tool_id"`
+	RunCount     int    `json:"run_count"`
+	FailureCount int    `json:"failure_count"`
+	// FailureRate is FailureCount / RunCount, from 0 to 1
+	FailureRate       float64    `json:"failure_rate"`
+	AverageDurationMs float64    `json:"average_duration_ms"`
+	LastRunAt         *time.Time `json:"last_run_at,omitempty"`
+	// RecentRuns are the start times of the latest runs, newest first, at
+	// most FrecencySamples of them
+	RecentRuns []time.Time `json:"recent_runs"`
+}
+
+// NewToolUsage derives the failure rate and last run of a tool's runs
+func NewToolUsage(toolID string, runCount, failureCount int, averageDurationMs float64, recentRuns []time.Time) ToolUsage {
+	usage := ToolUsage{
+		ToolID:            toolID,
+		RunCount:          runCount,
+		FailureCount:      failureCount,
+		AverageDurationMs: averageDurationMs,
+		RecentRuns:        recentRuns,
+	}
+	if usage.RecentRuns == nil {
+		usage.RecentRuns = []time.Time{}
+	}
+	if runCount > 0 {
+		usage.FailureRate = float64(failureCount) / float64(runCount)
+	}
+	if len(recentRuns) > 0 {
+		last := recentRuns[0]
+		usage.LastRunAt = &last
+	}
+	return usage
+}
+
+// Frecency scores how often and how recently a tool ran: the run count
+// scaled by the average recency weight of the latest runs
+func (u ToolUsage) Frecency(now time.Time) float64 {
+	if u.RunCount == 0 || len(u.RecentRuns) == 0 {
+		return 0
+	}
+
+	var total float64
+	for _, startedAt := range u.RecentRuns {
+		total += recencyWeight(now.Sub(startedAt))
+	}
+	return float64(u.RunCount) * total / float64(len(u.RecentRuns))
+}
+
+// recencyWeight buckets the age of a run, so a run today counts ten times
+// as much as one from last year
+func recencyWeight(age time.Duration) float64 {
+	const day = 24 * time.Hour
+	switch {
+	case age < 4*day:
+		return 100
+	case age < 14*day:
+		return 70
+	case age < 31*day:
+		return 50
+	case age < 90*day:
+		return 30
+	default:
+		return 10
+	}
+}
+
+// RankByFrecency orders tools by descending frecency; tools that never ran
+// come last, and ties keep their order
+func RankByFrecency(tools []Tool, usage map[string]ToolUsage, now time.Time) []Tool {
+	ranked := make([]Tool, len(tools))
+	copy(ranked, tools)
+
+	scores := make(map[string]float64, len(ranked))
+	for _, tool := range ranked {
+		scores[tool.ID] = usage[tool.ID].Frecency(now)
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return scores[ranked[i].ID] > scores[ranked[j].ID]
+	})
+	return ranked
+}
@@ -0,0 +1,61 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"lumina/backend/tool/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewToolUsage(t *testing.T) {
+	// Given four runs, one of them failed
+	now := time.Now()
+	recent := []time.Time{now, now.Add(-time.Hour)}
+
+	// When
+	usage := domain.NewToolUsage("tool-1", 4, 1, 12.5, recent)
+
+	// Then the rate and last run are derived
+	assert.Equal(t, 0.25, usage.FailureRate)
+	require.NotNil(t, usage.LastRunAt)
+	assert.True(t, usage.LastRunAt.Equal(now))
+
+	// And a tool that never ran has neither
+	idle := domain.NewToolUsage("tool-2", 0, 0, 0, nil)
+	assert.Zero(t, idle.FailureRate)
+	assert.Nil(t, idle.LastRunAt)
+	assert.Zero(t, idle.Frecency(now))
+}
+
+func TestToolUsage_Frecency(t *testing.T) {
+	// Given a tool run often long ago and one run a few times today
+	now := time.Now()
+	old := domain.NewToolUsage("old", 20, 0, 0, []time.Time{now.Add(-200 * 24 * time.Hour)})
+	fresh := domain.NewToolUsage("fresh", 3, 0, 0, []time.Time{now, now, now})
+
+	// Then recent use outweighs a larger but stale count
+	assert.Equal(t, 200.0, old.Frecency(now))
+	assert.Equal(t, 300.0, fresh.Frecency(now))
+}
+
+func TestRankByFrecency(t *testing.T) {
+	// Given three tools, two of which ran
+	now := time.Now()
+	first := domain.NewTool("First", "1")
+	second := domain.NewTool("Second", "2")
+	third := domain.NewTool("Third", "3")
+	usage := map[string]domain.ToolUsage{
+		second.ID: domain.NewToolUsage(second.ID, 5, 0, 0, []time.Time{now}),
+		third.ID:  domain.NewToolUsage(third.ID, 1, 0, 0, []time.Time{now}),
+	}
+
+	// When
+	ranked := domain.RankByFrecency([]domain.Tool{first, second, third}, usage, now)
+
+	// Then the most used comes first and the unused one last
+	require.Len(t, ranked, 3)
+	assert.Equal(t, []string{"Second", "Third", "First"}, []string{ranked[0].Name, ranked[1].Name, ranked[2].Name})
+}
@@ -0,0 +1,62 @@
+package infrastructure
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+)
+
+// watcherSkippedDirs are too large or too busy to poll
+var watcherSkippedDirs = map[string]bool{
+	".git":         true,
+	"node_modules": true,
+}
+
+// FileProjectWatcher implements the ProjectWatcher port by walking the
+// project tree; a file's fingerprint is its size and modification time
+type FileProjectWatcher struct {
+	root string
+}
+
+// NewFileProjectWatcher creates a watcher for the project rooted at root
+func NewFileProjectWatcher(root string) *FileProjectWatcher {
+	return &FileProjectWatcher{root: root}
+}
+
+func (w *FileProjectWatcher) Snapshot() (map[string]string, error) {
+	snapshot := make(map[string]string)
+
+	err := filepath.WalkDir(w.root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			// Skip entries we can't read (permissions, etc.)
+			if entry != nil && entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if entry.IsDir() {
+			if path != w.root && watcherSkippedDirs[entry.Name()] {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		relative, err := filepath.Rel(w.root, path)
+		if err != nil {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return nil
+		}
+		snapshot[filepath.ToSlash(relative)] = fmt.Sprintf("%d:%d", info.Size(), info.ModTime().UnixNano())
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return snapshot, nil
+}
@@ -0,0 +1,74 @@
+package infrastructure
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"lumina/backend/tool/domain"
+)
+
+// ToolsDirName is where a project keeps its tool files, relative to its root
+var ToolsDirName = filepath.Join(".lumina", "tools")
+
+// FileToolDirectory implements the ToolDirectory port on a directory of the
+// OS filesystem. Only the top level is read; other files are ignored.
+type FileToolDirectory struct {
+	root string
+}
+
+// NewFileToolDirectory creates a tool directory rooted at root, which is
+// created on the first write
+func NewFileToolDirectory(root string) *FileToolDirectory {
+	return &FileToolDirectory{root: root}
+}
+
+// List returns every tool file in the directory; a missing directory is empty
+func (d *FileToolDirectory) List() ([]domain.ToolFileEntry, error) {
+	entries, err := os.ReadDir(d.root)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var files []domain.ToolFileEntry
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), domain.ToolFileExtension) {
+			continue
+		}
+
+		content, err := os.ReadFile(filepath.Join(d.root, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, domain.ToolFileEntry{Path: entry.Name(), Content: string(content)})
+	}
+
+	return files, nil
+}
+
+// Write writes a tool file, creating the directory as needed
+func (d *FileToolDirectory) Write(path, content string) error {
+	if err := os.MkdirAll(d.root, 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(d.resolve(path), []byte(content), 0644)
+}
+
+// Delete removes a tool file; deleting a missing file is not an error
+func (d *FileToolDirectory) Delete(path string) error {
+	err := os.Remove(d.resolve(path))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// resolve keeps paths inside the directory
+func (d *FileToolDirectory) resolve(path string) string {
+	return filepath.Join(d.root, filepath.Base(filepath.FromSlash(path)))
+}
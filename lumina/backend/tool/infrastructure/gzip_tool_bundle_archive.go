@@ -0,0 +1,67 @@
+package infrastructure
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"lumina/backend/tool/domain"
+)
+
+// GzipToolBundleArchive implements ToolBundleArchive as gzip-compressed JSON
+type GzipToolBundleArchive struct{}
+
+func NewGzipToolBundleArchive() *GzipToolBundleArchive {
+	return &GzipToolBundleArchive{}
+}
+
+// Write replaces path only once the whole bundle is written
+func (a *GzipToolBundleArchive) Write(path string, bundle domain.ToolBundle) error {
+	file, err := os.CreateTemp(filepath.Dir(path), ".bundle-*")
+	if err != nil {
+		return fmt.Errorf("failed to create bundle file: %w", err)
+	}
+	defer os.Remove(file.Name())
+
+	compressed := gzip.NewWriter(file)
+	encoder := json.NewEncoder(compressed)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(bundle); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write bundle: %w", err)
+	}
+	if err := compressed.Close(); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write bundle: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write bundle: %w", err)
+	}
+
+	if err := os.Rename(file.Name(), path); err != nil {
+		return fmt.Errorf("failed to write bundle: %w", err)
+	}
+	return nil
+}
+
+func (a *GzipToolBundleArchive) Read(path string) (domain.ToolBundle, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return domain.ToolBundle{}, fmt.Errorf("failed to open bundle: %w", err)
+	}
+	defer file.Close()
+
+	compressed, err := gzip.NewReader(file)
+	if err != nil {
+		return domain.ToolBundle{}, fmt.Errorf("%w: %v", domain.ErrInvalidToolBundle, err)
+	}
+	defer compressed.Close()
+
+	var bundle domain.ToolBundle
+	if err := json.NewDecoder(compressed).Decode(&bundle); err != nil {
+		return domain.ToolBundle{}, fmt.Errorf("%w: %v", domain.ErrInvalidToolBundle, err)
+	}
+	return bundle, nil
+}
@@ -0,0 +1,148 @@
+package infrastructure
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"lumina/backend/tool/domain"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// escalationColumns are the columns read by scanEscalation, in order
+const escalationColumns = "id, tool_id, tool_name, kind, resource, status, created_at, decided_at"
+
+type SQLiteCapabilityEscalationRepository struct {
+	db *sql.DB
+}
+
+func NewSQLiteCapabilityEscalationRepository(dbPath string) (*SQLiteCapabilityEscalationRepository, error) {
+	db, err := sql.Open("sqlite3", dbPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	createTableSQL := `
+	CREATE TABLE IF NOT EXISTS capability_escalations (
+		id TEXT PRIMARY KEY,
+		tool_id TEXT NOT NULL,
+		tool_name TEXT NOT NULL,
+		kind TEXT NOT NULL,
+		resource TEXT NOT NULL DEFAULT '',
+		status TEXT NOT NULL,
+		created_at DATETIME NOT NULL,
+		decided_at DATETIME
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_capability_escalations_status ON capability_escalations(status, created_at);
+	`
+
+	if _, err := db.Exec(createTableSQL); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create capability escalations table: %w", err)
+	}
+
+	return &SQLiteCapabilityEscalationRepository{db: db}, nil
+}
+
+func (r *SQLiteCapabilityEscalationRepository) Save(escalation domain.CapabilityEscalation) error {
+	upsertSQL := `
+	INSERT INTO capability_escalations (` + escalationColumns + `)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	ON CONFLICT(id) DO UPDATE SET
+		status = excluded.status,
+		decided_at = excluded.decided_at
+	`
+
+	_, err := r.db.Exec(upsertSQL,
+		escalation.ID,
+		escalation.ToolID,
+		escalation.ToolName,
+		string(escalation.Kind),
+		escalation.Resource,
+		string(escalation.Status),
+		escalation.CreatedAt,
+		escalation.DecidedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save capability escalation: %w", err)
+	}
+
+	return nil
+}
+
+func (r *SQLiteCapabilityEscalationRepository) GetByID(id string) (domain.CapabilityEscalation, error) {
+	escalation, err := scanEscalation(r.db.QueryRow("SELECT "+escalationColumns+" FROM capability_escalations WHERE id = ?", id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.CapabilityEscalation{}, domain.ErrEscalationNotFound
+		}
+		return domain.CapabilityEscalation{}, fmt.Errorf("failed to get capability escalation: %w", err)
+	}
+
+	return escalation, nil
+}
+
+func (r *SQLiteCapabilityEscalationRepository) List(status domain.EscalationStatus) ([]domain.CapabilityEscalation, error) {
+	query := "SELECT " + escalationColumns + " FROM capability_escalations"
+	var args []interface{}
+	if status != "" {
+		query += " WHERE status = ?"
+		args = append(args, string(status))
+	}
+	query += " ORDER BY created_at DESC"
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list capability escalations: %w", err)
+	}
+	defer rows.Close()
+
+	escalations := []domain.CapabilityEscalation{}
+	for rows.Next() {
+		escalation, err := scanEscalation(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan capability escalation: %w", err)
+		}
+		escalations = append(escalations, escalation)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating capability escalations: %w", err)
+	}
+
+	return escalations, nil
+}
+
+func (r *SQLiteCapabilityEscalationRepository) Close() error {
+	return r.db.Close()
+}
+
+func scanEscalation(row rowScanner) (domain.CapabilityEscalation, error) {
+	var escalation domain.CapabilityEscalation
+	var kind, status string
+	var decidedAt sql.NullTime
+
+	err := row.Scan(
+		&escalation.ID,
+		&escalation.ToolID,
+		&escalation.ToolName,
+		&kind,
+		&escalation.Resource,
+		&status,
+		&escalation.CreatedAt,
+		&decidedAt,
+	)
+	if err != nil {
+		return domain.CapabilityEscalation{}, err
+	}
+
+	escalation.Kind = domain.CapabilityKind(kind)
+	escalation.Status = domain.EscalationStatus(status)
+	if decidedAt.Valid {
+		escalation.DecidedAt = &decidedAt.Time
+	}
+
+	return escalation, nil
+}
@@ -0,0 +1,64 @@
+package infrastructure_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"lumina/backend/tool/domain"
+	"lumina/backend/tool/infrastructure"
+)
+
+func TestSQLiteCapabilityEscalationRepository_SaveAndList(t *testing.T) {
+	// Given two escalations of a tool
+	dbFile := "test_capability_escalations.db"
+	defer cleanupDatabase(dbFile)
+
+	repo, err := infrastructure.NewSQLiteCapabilityEscalationRepository(dbFile)
+	require.NoError(t, err)
+	defer repo.Close()
+
+	tool := domain.NewTool("Fetch", "await fetch('https://example.com')")
+	network := domain.NewCapabilityEscalation(tool, domain.CapabilityRequest{Kind: domain.CapabilityNetwork, Resource: "example.com:443"})
+	read := domain.NewCapabilityEscalation(tool, domain.CapabilityRequest{Kind: domain.CapabilityFileRead, Resource: "/etc/hosts"})
+	read.CreatedAt = network.CreatedAt.Add(time.Second)
+	require.NoError(t, repo.Save(network))
+	require.NoError(t, repo.Save(read))
+
+	// When the network escalation is denied
+	require.NoError(t, repo.Save(network.Decided(domain.EscalationDenied)))
+
+	// Then only the read is pending, and both are listed newest first
+	pending, err := repo.List(domain.EscalationPending)
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	assert.Equal(t, read.ID, pending[0].ID)
+	assert.Equal(t, "/etc/hosts", pending[0].Resource)
+
+	all, err := repo.List("")
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+	assert.Equal(t, read.ID, all[0].ID)
+
+	denied, err := repo.GetByID(network.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.EscalationDenied, denied.Status)
+	assert.NotNil(t, denied.DecidedAt)
+}
+
+func TestSQLiteCapabilityEscalationRepository_NotFound(t *testing.T) {
+	// Given
+	dbFile := "test_capability_escalations_missing.db"
+	defer cleanupDatabase(dbFile)
+
+	repo, err := infrastructure.NewSQLiteCapabilityEscalationRepository(dbFile)
+	require.NoError(t, err)
+	defer repo.Close()
+
+	// When
+	_, err = repo.GetByID("missing")
+
+	// Then
+	assert.ErrorIs(t, err, domain.ErrEscalationNotFound)
+}
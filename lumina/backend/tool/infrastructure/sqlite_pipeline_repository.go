@@ -0,0 +1,133 @@
+package infrastructure
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"lumina/backend/tool/domain"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// pipelineColumns are the columns read by scanPipeline, in order
+const pipelineColumns = "id, name, steps, created_at, updated_at"
+
+type SQLitePipelineRepository struct {
+	db *sql.DB
+}
+
+func NewSQLitePipelineRepository(dbPath string) (*SQLitePipelineRepository, error) {
+	db, err := sql.Open("sqlite3", dbPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	// Steps are stored as JSON; they reference tools by ID so renames keep working
+	createTableSQL := `
+	CREATE TABLE IF NOT EXISTS pipelines (
+		id TEXT PRIMARY KEY,
+		name TEXT NOT NULL UNIQUE,
+		steps TEXT NOT NULL,
+		created_at DATETIME NOT NULL,
+		updated_at DATETIME NOT NULL
+	);
+	`
+
+	if _, err := db.Exec(createTableSQL); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create pipelines table: %w", err)
+	}
+
+	return &SQLitePipelineRepository{db: db}, nil
+}
+
+func (r *SQLitePipelineRepository) Save(pipeline domain.Pipeline) error {
+	steps, err := json.Marshal(pipeline.Steps)
+	if err != nil {
+		return fmt.Errorf("failed to marshal pipeline steps: %w", err)
+	}
+
+	upsertSQL := `
+	INSERT INTO pipelines (id, name, steps, created_at, updated_at)
+	VALUES (?, ?, ?, ?, ?)
+	ON CONFLICT(id) DO UPDATE SET
+		name = excluded.name,
+		steps = excluded.steps,
+		updated_at = excluded.updated_at
+	`
+
+	_, err = r.db.Exec(upsertSQL, pipeline.ID, pipeline.Name, string(steps), pipeline.CreatedAt, pipeline.UpdatedAt)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return domain.ErrPipelineNameTaken
+		}
+		return fmt.Errorf("failed to save pipeline: %w", err)
+	}
+
+	return nil
+}
+
+func (r *SQLitePipelineRepository) GetByID(id string) (domain.Pipeline, error) {
+	pipeline, err := scanPipeline(r.db.QueryRow("SELECT "+pipelineColumns+" FROM pipelines WHERE id = ?", id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.Pipeline{}, domain.ErrPipelineNotFound
+		}
+		return domain.Pipeline{}, fmt.Errorf("failed to get pipeline: %w", err)
+	}
+
+	return pipeline, nil
+}
+
+func (r *SQLitePipelineRepository) List() ([]domain.Pipeline, error) {
+	rows, err := r.db.Query("SELECT " + pipelineColumns + " FROM pipelines ORDER BY name")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pipelines: %w", err)
+	}
+	defer rows.Close()
+
+	pipelines := []domain.Pipeline{}
+	for rows.Next() {
+		pipeline, err := scanPipeline(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan pipeline: %w", err)
+		}
+		pipelines = append(pipelines, pipeline)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating pipelines: %w", err)
+	}
+
+	return pipelines, nil
+}
+
+func (r *SQLitePipelineRepository) Delete(id string) error {
+	result, err := r.db.Exec("DELETE FROM pipelines WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete pipeline: %w", err)
+	}
+
+	return requireAffected(result, domain.ErrPipelineNotFound)
+}
+
+func (r *SQLitePipelineRepository) Close() error {
+	return r.db.Close()
+}
+
+func scanPipeline(row rowScanner) (domain.Pipeline, error) {
+	var pipeline domain.Pipeline
+	var steps string
+
+	if err := row.Scan(&pipeline.ID, &pipeline.Name, &steps, &pipeline.CreatedAt, &pipeline.UpdatedAt); err != nil {
+		return domain.Pipeline{}, err
+	}
+
+	if err := json.Unmarshal([]byte(steps), &pipeline.Steps); err != nil {
+		return domain.Pipeline{}, fmt.Errorf("failed to unmarshal pipeline steps: %w", err)
+	}
+
+	return pipeline, nil
+}
@@ -0,0 +1,86 @@
+package infrastructure_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"lumina/backend/tool/domain"
+	"lumina/backend/tool/infrastructure"
+)
+
+func TestSQLitePipelineRepository_SaveAndGet(t *testing.T) {
+	// Given a pipeline of two steps
+	dbFile := "test_pipelines.db"
+	defer cleanupDatabase(dbFile)
+
+	repo, err := infrastructure.NewSQLitePipelineRepository(dbFile)
+	require.NoError(t, err)
+	defer repo.Close()
+
+	pipeline, err := domain.NewPipeline("Report", []domain.PipelineStep{
+		{ToolID: "fetch", Arguments: map[string]interface{}{"url": "https://example.com"}},
+		{ToolID: "summarize", InputParameter: "data"},
+	})
+	require.NoError(t, err)
+
+	// When saving it, then updating it
+	require.NoError(t, repo.Save(pipeline))
+	pipeline.Steps = pipeline.Steps[:1]
+	require.NoError(t, repo.Save(pipeline))
+
+	// Then the latest steps are read back
+	loaded, err := repo.GetByID(pipeline.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Report", loaded.Name)
+	require.Len(t, loaded.Steps, 1)
+	assert.Equal(t, "fetch", loaded.Steps[0].ToolID)
+	assert.Equal(t, "https://example.com", loaded.Steps[0].Arguments["url"])
+
+	pipelines, err := repo.List()
+	require.NoError(t, err)
+	assert.Len(t, pipelines, 1)
+}
+
+func TestSQLitePipelineRepository_NameTaken(t *testing.T) {
+	// Given a pipeline
+	dbFile := "test_pipelines_taken.db"
+	defer cleanupDatabase(dbFile)
+
+	repo, err := infrastructure.NewSQLitePipelineRepository(dbFile)
+	require.NoError(t, err)
+	defer repo.Close()
+
+	first, err := domain.NewPipeline("Report", []domain.PipelineStep{{ToolID: "a"}})
+	require.NoError(t, err)
+	require.NoError(t, repo.Save(first))
+
+	// When saving another pipeline with the same name
+	second, err := domain.NewPipeline("Report", []domain.PipelineStep{{ToolID: "b"}})
+	require.NoError(t, err)
+
+	// Then
+	assert.ErrorIs(t, repo.Save(second), domain.ErrPipelineNameTaken)
+}
+
+func TestSQLitePipelineRepository_Delete(t *testing.T) {
+	// Given a pipeline
+	dbFile := "test_pipelines_delete.db"
+	defer cleanupDatabase(dbFile)
+
+	repo, err := infrastructure.NewSQLitePipelineRepository(dbFile)
+	require.NoError(t, err)
+	defer repo.Close()
+
+	pipeline, err := domain.NewPipeline("Report", []domain.PipelineStep{{ToolID: "a"}})
+	require.NoError(t, err)
+	require.NoError(t, repo.Save(pipeline))
+
+	// When
+	require.NoError(t, repo.Delete(pipeline.ID))
+
+	// Then
+	_, err = repo.GetByID(pipeline.ID)
+	assert.ErrorIs(t, err, domain.ErrPipelineNotFound)
+	assert.ErrorIs(t, repo.Delete(pipeline.ID), domain.ErrPipelineNotFound)
+}
@@ -0,0 +1,192 @@
+package infrastructure
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"lumina/backend/tool/domain"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// exampleColumns are the columns read by scanExample, in order
+const exampleColumns = "id, tool_id, name, arguments, expected_output, assertions, created_at, updated_at"
+
+type SQLiteToolExampleRepository struct {
+	db *sql.DB
+}
+
+func NewSQLiteToolExampleRepository(dbPath string) (*SQLiteToolExampleRepository, error) {
+	db, err := sql.Open("sqlite3", dbPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	createTableSQL := `
+	CREATE TABLE IF NOT EXISTS tool_examples (
+		id TEXT PRIMARY KEY,
+		tool_id TEXT NOT NULL,
+		name TEXT NOT NULL,
+		arguments TEXT NOT NULL DEFAULT '{}',
+		expected_output TEXT NOT NULL DEFAULT '',
+		assertions TEXT NOT NULL DEFAULT '[]',
+		created_at DATETIME NOT NULL,
+		updated_at DATETIME NOT NULL,
+		UNIQUE (tool_id, name)
+	);
+	`
+
+	if _, err := db.Exec(createTableSQL); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create tool examples table: %w", err)
+	}
+
+	return &SQLiteToolExampleRepository{db: db}, nil
+}
+
+func (r *SQLiteToolExampleRepository) Save(example domain.ToolExample) error {
+	arguments := example.Arguments
+	if arguments == nil {
+		arguments = map[string]interface{}{}
+	}
+	argumentsJSON, err := json.Marshal(arguments)
+	if err != nil {
+		return fmt.Errorf("failed to marshal example arguments: %w", err)
+	}
+
+	assertions := example.Assertions
+	if assertions == nil {
+		assertions = []domain.ExampleAssertion{}
+	}
+	assertionsJSON, err := json.Marshal(assertions)
+	if err != nil {
+		return fmt.Errorf("failed to marshal example assertions: %w", err)
+	}
+
+	upsertSQL := `
+	INSERT INTO tool_examples (id, tool_id, name, arguments, expected_output, assertions, created_at, updated_at)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	ON CONFLICT(id) DO UPDATE SET
+		name = excluded.name,
+		arguments = excluded.arguments,
+		expected_output = excluded.expected_output,
+		assertions = excluded.assertions,
+		updated_at = excluded.updated_at
+	`
+
+	_, err = r.db.Exec(upsertSQL,
+		example.ID,
+		example.ToolID,
+		example.Name,
+		string(argumentsJSON),
+		example.ExpectedOutput,
+		string(assertionsJSON),
+		example.CreatedAt,
+		example.UpdatedAt,
+	)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return domain.ErrExampleNameTaken
+		}
+		return fmt.Errorf("failed to save example: %w", err)
+	}
+
+	return nil
+}
+
+func (r *SQLiteToolExampleRepository) GetByID(id string) (domain.ToolExample, error) {
+	row := r.db.QueryRow("SELECT "+exampleColumns+" FROM tool_examples WHERE id = ?", id)
+
+	example, err := scanExample(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.ToolExample{}, domain.ErrExampleNotFound
+		}
+		return domain.ToolExample{}, fmt.Errorf("failed to get example: %w", err)
+	}
+
+	return example, nil
+}
+
+func (r *SQLiteToolExampleRepository) ListByTool(toolID string) ([]domain.ToolExample, error) {
+	return r.query("SELECT "+exampleColumns+" FROM tool_examples WHERE tool_id = ? ORDER BY name", toolID)
+}
+
+func (r *SQLiteToolExampleRepository) List() ([]domain.ToolExample, error) {
+	return r.query("SELECT " + exampleColumns + " FROM tool_examples ORDER BY tool_id, name")
+}
+
+func (r *SQLiteToolExampleRepository) query(query string, args ...interface{}) ([]domain.ToolExample, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list examples: %w", err)
+	}
+	defer rows.Close()
+
+	examples := []domain.ToolExample{}
+	for rows.Next() {
+		example, err := scanExample(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan example: %w", err)
+		}
+		examples = append(examples, example)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating examples: %w", err)
+	}
+
+	return examples, nil
+}
+
+func (r *SQLiteToolExampleRepository) Delete(id string) error {
+	result, err := r.db.Exec("DELETE FROM tool_examples WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete example: %w", err)
+	}
+
+	return requireAffected(result, domain.ErrExampleNotFound)
+}
+
+func (r *SQLiteToolExampleRepository) DeleteByTool(toolID string) error {
+	if _, err := r.db.Exec("DELETE FROM tool_examples WHERE tool_id = ?", toolID); err != nil {
+		return fmt.Errorf("failed to delete examples: %w", err)
+	}
+
+	return nil
+}
+
+func (r *SQLiteToolExampleRepository) Close() error {
+	return r.db.Close()
+}
+
+func scanExample(row rowScanner) (domain.ToolExample, error) {
+	var example domain.ToolExample
+	var arguments, assertions string
+
+	err := row.Scan(
+		&example.ID,
+		&example.ToolID,
+		&example.Name,
+		&arguments,
+		&example.ExpectedOutput,
+		&assertions,
+		&example.CreatedAt,
+		&example.UpdatedAt,
+	)
+	if err != nil {
+		return domain.ToolExample{}, err
+	}
+
+	if err := json.Unmarshal([]byte(arguments), &example.Arguments); err != nil {
+		return domain.ToolExample{}, fmt.Errorf("failed to unmarshal example arguments: %w", err)
+	}
+
+	if err := json.Unmarshal([]byte(assertions), &example.Assertions); err != nil {
+		return domain.ToolExample{}, fmt.Errorf("failed to unmarshal example assertions: %w", err)
+	}
+
+	return example, nil
+}
@@ -0,0 +1,108 @@
+package infrastructure_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"lumina/backend/tool/domain"
+	"lumina/backend/tool/infrastructure"
+)
+
+func TestSQLiteToolExampleRepository_SaveAndList(t *testing.T) {
+	// Given a tool with a parameter
+	dbFile := "test_tool_examples.db"
+	defer cleanupDatabase(dbFile)
+
+	repo, err := infrastructure.NewSQLiteToolExampleRepository(dbFile)
+	require.NoError(t, err)
+	defer repo.Close()
+
+	created, err := domain.NewToolWithValidation("Greet", "console.log('hi')")
+	require.NoError(t, err)
+	tool, err := created.WithParameters([]domain.ToolParameter{{Name: "name", Type: domain.ParameterString}})
+	require.NoError(t, err)
+
+	// When saving two examples
+	second, err := domain.NewToolExample(tool, "world", map[string]interface{}{"name": "World"}, "Hello, World", nil)
+	require.NoError(t, err)
+	first, err := domain.NewToolExample(tool, "ada", map[string]interface{}{"name": "Ada"}, "", []domain.ExampleAssertion{
+		{Kind: domain.AssertContains, Value: "Ada"},
+	})
+	require.NoError(t, err)
+	require.NoError(t, repo.Save(second))
+	require.NoError(t, repo.Save(first))
+
+	// Then they are listed by name with their arguments and assertions
+	examples, err := repo.ListByTool(tool.ID)
+	require.NoError(t, err)
+	require.Len(t, examples, 2)
+	assert.Equal(t, "ada", examples[0].Name)
+	assert.Equal(t, "Ada", examples[0].Arguments["name"])
+	assert.Equal(t, []domain.ExampleAssertion{{Kind: domain.AssertContains, Value: "Ada"}}, examples[0].Assertions)
+	assert.Equal(t, "Hello, World", examples[1].ExpectedOutput)
+
+	all, err := repo.List()
+	require.NoError(t, err)
+	assert.Len(t, all, 2)
+}
+
+func TestSQLiteToolExampleRepository_NameTaken(t *testing.T) {
+	// Given an example
+	dbFile := "test_tool_examples_taken.db"
+	defer cleanupDatabase(dbFile)
+
+	repo, err := infrastructure.NewSQLiteToolExampleRepository(dbFile)
+	require.NoError(t, err)
+	defer repo.Close()
+
+	tool, err := domain.NewToolWithValidation("Hello", "console.log('hi')")
+	require.NoError(t, err)
+	example, err := domain.NewToolExample(*tool, "basic", nil, "hi", nil)
+	require.NoError(t, err)
+	require.NoError(t, repo.Save(example))
+
+	// When saving another example of the tool with the same name
+	duplicate, err := domain.NewToolExample(*tool, "basic", nil, "hi", nil)
+	require.NoError(t, err)
+	err = repo.Save(duplicate)
+
+	// Then the name is reported as taken
+	assert.ErrorIs(t, err, domain.ErrExampleNameTaken)
+}
+
+func TestSQLiteToolExampleRepository_Delete(t *testing.T) {
+	// Given examples of two tools
+	dbFile := "test_tool_examples_delete.db"
+	defer cleanupDatabase(dbFile)
+
+	repo, err := infrastructure.NewSQLiteToolExampleRepository(dbFile)
+	require.NoError(t, err)
+	defer repo.Close()
+
+	tool, err := domain.NewToolWithValidation("Hello", "console.log('hi')")
+	require.NoError(t, err)
+	other, err := domain.NewToolWithValidation("Bye", "console.log('bye')")
+	require.NoError(t, err)
+
+	first, _ := domain.NewToolExample(*tool, "first", nil, "hi", nil)
+	second, _ := domain.NewToolExample(*tool, "second", nil, "hi", nil)
+	kept, _ := domain.NewToolExample(*other, "kept", nil, "bye", nil)
+	for _, example := range []domain.ToolExample{first, second, kept} {
+		require.NoError(t, repo.Save(example))
+	}
+
+	// When deleting one example, then every example of the tool
+	require.NoError(t, repo.Delete(first.ID))
+	_, err = repo.GetByID(first.ID)
+	assert.ErrorIs(t, err, domain.ErrExampleNotFound)
+	assert.ErrorIs(t, repo.Delete(first.ID), domain.ErrExampleNotFound)
+
+	require.NoError(t, repo.DeleteByTool(tool.ID))
+
+	// Then only the other tool's example is left
+	all, err := repo.List()
+	require.NoError(t, err)
+	require.Len(t, all, 1)
+	assert.Equal(t, kept.ID, all[0].ID)
+}
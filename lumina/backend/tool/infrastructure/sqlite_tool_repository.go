@@ -2,14 +2,41 @@ package infrastructure
 
 import (
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
+	"unicode"
 
 	"lumina/backend/tool/domain"
 
-	_ "github.com/mattn/go-sqlite3"
+	"github.com/mattn/go-sqlite3"
 )
 
+// toolColumns are the columns read by scanTool, in order
+const toolColumns = "id, name, code, description, tags, parameters, dependencies, created_at, updated_at, deleted_at, version, draft, namespace, language, capabilities"
+
+// revisionColumns are the columns read by scanRevision, in order
+const revisionColumns = "tool_id, revision, name, code, parameters, dependencies, resolved_versions, message, created_at"
+
+// columnMigrations add the columns introduced after a table was first created
+var columnMigrations = []string{
+	"ALTER TABLE tools ADD COLUMN parameters TEXT NOT NULL DEFAULT '[]'",
+	"ALTER TABLE tool_revisions ADD COLUMN parameters TEXT NOT NULL DEFAULT '[]'",
+	"ALTER TABLE tools ADD COLUMN description TEXT NOT NULL DEFAULT ''",
+	"ALTER TABLE tools ADD COLUMN tags TEXT NOT NULL DEFAULT '[]'",
+	"ALTER TABLE tools ADD COLUMN dependencies TEXT NOT NULL DEFAULT '[]'",
+	"ALTER TABLE tool_revisions ADD COLUMN dependencies TEXT NOT NULL DEFAULT '[]'",
+	"ALTER TABLE tool_revisions ADD COLUMN resolved_versions TEXT NOT NULL DEFAULT '{}'",
+	"ALTER TABLE tools ADD COLUMN version INTEGER NOT NULL DEFAULT 0",
+	"ALTER TABLE tools ADD COLUMN draft BOOLEAN NOT NULL DEFAULT 0",
+	"ALTER TABLE tools ADD COLUMN namespace TEXT NOT NULL DEFAULT 'global'",
+	"ALTER TABLE tools ADD COLUMN language TEXT NOT NULL DEFAULT 'typescript'",
+	// NULL for tools saved before capabilities were declared
+	"ALTER TABLE tools ADD COLUMN capabilities TEXT",
+}
+
 type SQLiteToolRepository struct {
 	db *sql.DB
 }
@@ -20,17 +47,31 @@ func NewSQLiteToolRepository(dbPath string) (*SQLiteToolRepository, error) {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
 
-	// Create tools table if it doesn't exist
+	// Create tools table if it doesn't exist. Names are only unique among
+	// the tools of a namespace outside the trash, which the partial index
+	// below enforces.
 	createTableSQL := `
 	CREATE TABLE IF NOT EXISTS tools (
 		id TEXT PRIMARY KEY,
-		name TEXT NOT NULL UNIQUE,
+		name TEXT NOT NULL,
+		code TEXT NOT NULL,
+		created_at DATETIME NOT NULL,
+		updated_at DATETIME NOT NULL,
+		deleted_at DATETIME
+	);
+
+	CREATE TABLE IF NOT EXISTS tool_revisions (
+		tool_id TEXT NOT NULL,
+		revision INTEGER NOT NULL,
+		name TEXT NOT NULL,
 		code TEXT NOT NULL,
+		message TEXT NOT NULL DEFAULT '',
 		created_at DATETIME NOT NULL,
-		updated_at DATETIME NOT NULL
+		PRIMARY KEY (tool_id, revision)
 	);
 
-	CREATE INDEX IF NOT EXISTS idx_tools_name ON tools(name);
+	-- Full-text index over tools; the docid is the rowid of the tool
+	CREATE VIRTUAL TABLE IF NOT EXISTS tools_fts USING fts4(name, description, code, tokenize=unicode61);
 	`
 
 	if _, err := db.Exec(createTableSQL); err != nil {
@@ -38,54 +79,241 @@ func NewSQLiteToolRepository(dbPath string) (*SQLiteToolRepository, error) {
 		return nil, fmt.Errorf("failed to create tools table: %w", err)
 	}
 
+	if err := migrateToolsForTrash(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	for _, migration := range columnMigrations {
+		if _, err := db.Exec(migration); err != nil && !strings.Contains(err.Error(), "duplicate column name") {
+			db.Close()
+			return nil, fmt.Errorf("failed to migrate tools tables: %w", err)
+		}
+	}
+
+	// Names used to be unique across all tools
+	createIndexSQL := `
+	DROP INDEX IF EXISTS idx_tools_active_name;
+	CREATE UNIQUE INDEX IF NOT EXISTS idx_tools_active_namespace_name ON tools(namespace, name) WHERE deleted_at IS NULL;
+	`
+
+	if _, err := db.Exec(createIndexSQL); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create tools index: %w", err)
+	}
+
+	// Tools saved before revisions existed start their history at revision 1
+	backfillSQL := `
+	INSERT INTO tool_revisions (tool_id, revision, name, code, parameters, message, created_at)
+	SELECT id, 1, name, code, parameters, '', updated_at
+	FROM tools
+	WHERE id NOT IN (SELECT tool_id FROM tool_revisions)
+	`
+
+	if _, err := db.Exec(backfillSQL); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to backfill tool revisions: %w", err)
+	}
+
+	// Index tools saved before search existed
+	backfillSearchSQL := `
+	INSERT INTO tools_fts (docid, name, description, code)
+	SELECT rowid, name, description, code
+	FROM tools
+	WHERE rowid NOT IN (SELECT docid FROM tools_fts)
+	`
+
+	if _, err := db.Exec(backfillSearchSQL); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to backfill tool search index: %w", err)
+	}
+
 	return &SQLiteToolRepository{db: db}, nil
 }
 
-func (r *SQLiteToolRepository) Save(tool domain.Tool) error {
+// migrateToolsForTrash rebuilds a tools table created before the trash
+// existed: it adds deleted_at and drops the column-level UNIQUE on name, which
+// SQLite cannot alter in place.
+func migrateToolsForTrash(db *sql.DB) error {
+	rows, err := db.Query("PRAGMA table_info(tools)")
+	if err != nil {
+		return fmt.Errorf("failed to inspect tools table: %w", err)
+	}
+
+	hasDeletedAt := false
+	for rows.Next() {
+		var cid, notNull, primaryKey int
+		var name, columnType string
+		var defaultValue sql.NullString
+		if err := rows.Scan(&cid, &name, &columnType, &notNull, &defaultValue, &primaryKey); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to inspect tools table: %w", err)
+		}
+		if name == "deleted_at" {
+			hasDeletedAt = true
+		}
+	}
+	rows.Close()
+
+	if hasDeletedAt {
+		return nil
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	migrateSQL := `
+	CREATE TABLE tools_migrated (
+		id TEXT PRIMARY KEY,
+		name TEXT NOT NULL,
+		code TEXT NOT NULL,
+		created_at DATETIME NOT NULL,
+		updated_at DATETIME NOT NULL,
+		deleted_at DATETIME
+	);
+
+	INSERT INTO tools_migrated (id, name, code, created_at, updated_at)
+	SELECT id, name, code, created_at, updated_at FROM tools;
+
+	DROP TABLE tools;
+
+	ALTER TABLE tools_migrated RENAME TO tools;
+	`
+
+	if _, err := tx.Exec(migrateSQL); err != nil {
+		return fmt.Errorf("failed to migrate tools table: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (r *SQLiteToolRepository) Save(tool domain.Tool) (domain.Tool, error) {
+	return r.SaveWithMessage(tool, "")
+}
+
+func (r *SQLiteToolRepository) SaveWithMessage(tool domain.Tool, message string) (domain.Tool, error) {
 	// Start a transaction
 	tx, err := r.db.Begin()
 	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
+		return domain.Tool{}, fmt.Errorf("failed to begin transaction: %w", err)
 	}
 	defer tx.Rollback()
 
-	// Use INSERT OR REPLACE to handle both create and update operations
+	parameters, err := json.Marshal(nonNilParameters(tool.Parameters))
+	if err != nil {
+		return domain.Tool{}, fmt.Errorf("failed to marshal tool parameters: %w", err)
+	}
+
+	tags, err := json.Marshal(domain.NormalizeTags(tool.Tags))
+	if err != nil {
+		return domain.Tool{}, fmt.Errorf("failed to marshal tool tags: %w", err)
+	}
+
+	dependencies, err := json.Marshal(nonNilDependencies(tool.Dependencies))
+	if err != nil {
+		return domain.Tool{}, fmt.Errorf("failed to marshal tool dependencies: %w", err)
+	}
+
+	capabilities, err := marshalCapabilities(tool.Capabilities)
+	if err != nil {
+		return domain.Tool{}, err
+	}
+
+	// A tool read at a version has been stored before; if its row is gone,
+	// it was purged since and saving it must not bring it back
+	if tool.Version != 0 {
+		exists, err := toolExists(tx, tool.ID)
+		if err != nil {
+			return domain.Tool{}, err
+		}
+		if !exists {
+			return domain.Tool{}, domain.ErrToolVersionConflict
+		}
+	}
+
+	// Upsert on the ID only, so a clash with another tool's name surfaces as
+	// a constraint error instead of replacing that tool. The update only
+	// applies when the stored version is the one the tool was read at and
+	// the tool is not in the trash.
 	insertSQL := `
-	INSERT OR REPLACE INTO tools (id, name, code, created_at, updated_at)
-	VALUES (?, ?, ?, ?, ?)
+	INSERT INTO tools (id, name, code, description, tags, parameters, dependencies, created_at, updated_at, version, draft, namespace, language, capabilities)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	ON CONFLICT(id) DO UPDATE SET
+		name = excluded.name,
+		code = excluded.code,
+		description = excluded.description,
+		tags = excluded.tags,
+		parameters = excluded.parameters,
+		dependencies = excluded.dependencies,
+		updated_at = excluded.updated_at,
+		version = excluded.version,
+		draft = excluded.draft,
+		namespace = excluded.namespace,
+		language = excluded.language,
+		capabilities = excluded.capabilities
+	WHERE tools.version = excluded.version - 1 AND tools.deleted_at IS NULL
 	`
 
-	_, err = tx.Exec(insertSQL, tool.ID, tool.Name, tool.Code, tool.CreatedAt, tool.UpdatedAt)
+	result, err := tx.Exec(insertSQL, tool.ID, tool.Name, tool.Code, tool.Description, string(tags), string(parameters), string(dependencies), tool.CreatedAt, tool.UpdatedAt, tool.Version+1, tool.Draft, domain.NormalizeNamespace(tool.Namespace), domain.NormalizeLanguage(tool.Language), capabilities)
 	if err != nil {
-		return fmt.Errorf("failed to save tool: %w", err)
+		if isUniqueViolation(err) {
+			return domain.Tool{}, domain.ErrToolNameTaken
+		}
+		return domain.Tool{}, fmt.Errorf("failed to save tool: %w", err)
+	}
+	if err := requireAffected(result, domain.ErrToolVersionConflict); err != nil {
+		// The row is also left alone when the tool is in the trash
+		if trashed, _ := isTrashed(tx, tool.ID); trashed {
+			return domain.Tool{}, domain.ErrToolNotFound
+		}
+		return domain.Tool{}, err
+	}
+
+	if err := indexTool(tx, tool.ID); err != nil {
+		return domain.Tool{}, err
+	}
+
+	// Every save appends an immutable revision
+	revisionSQL := `
+	INSERT INTO tool_revisions (tool_id, revision, name, code, parameters, dependencies, message, created_at)
+	SELECT ?, COALESCE(MAX(revision), 0) + 1, ?, ?, ?, ?, ?, ?
+	FROM tool_revisions
+	WHERE tool_id = ?
+	`
+
+	_, err = tx.Exec(revisionSQL, tool.ID, tool.Name, tool.Code, string(parameters), string(dependencies), message, tool.UpdatedAt, tool.ID)
+	if err != nil {
+		return domain.Tool{}, fmt.Errorf("failed to save tool revision: %w", err)
 	}
 
 	// Commit the transaction
 	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("failed to commit transaction: %w", err)
+		return domain.Tool{}, fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
-	return nil
+	stored := tool
+	stored.Version++
+	stored.Tags = domain.NormalizeTags(tool.Tags)
+	stored.Namespace = domain.NormalizeNamespace(tool.Namespace)
+	stored.Language = domain.NormalizeLanguage(tool.Language)
+	return stored, nil
 }
 
 func (r *SQLiteToolRepository) GetByID(id string) (domain.Tool, error) {
 	query := `
-	SELECT id, name, code, created_at, updated_at
+	SELECT ` + toolColumns + `
 	FROM tools
-	WHERE id = ?
+	WHERE id = ? AND deleted_at IS NULL
 	`
 
-	var tool domain.Tool
-	var createdAt, updatedAt time.Time
-
-	err := r.db.QueryRow(query, id).Scan(
-		&tool.ID,
-		&tool.Name,
-		&tool.Code,
-		&createdAt,
-		&updatedAt,
-	)
-
+	tool, err := scanTool(r.db.QueryRow(query, id))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return domain.Tool{}, domain.ErrToolNotFound
@@ -93,30 +321,37 @@ func (r *SQLiteToolRepository) GetByID(id string) (domain.Tool, error) {
 		return domain.Tool{}, fmt.Errorf("failed to get tool by ID: %w", err)
 	}
 
-	tool.CreatedAt = createdAt
-	tool.UpdatedAt = updatedAt
-
 	return tool, nil
 }
 
 func (r *SQLiteToolRepository) GetByName(name string) (domain.Tool, error) {
 	query := `
-	SELECT id, name, code, created_at, updated_at
+	SELECT ` + toolColumns + `
 	FROM tools
-	WHERE name = ?
+	WHERE name = ? AND deleted_at IS NULL
+	ORDER BY namespace != 'global', namespace
+	LIMIT 1
 	`
 
-	var tool domain.Tool
-	var createdAt, updatedAt time.Time
+	tool, err := scanTool(r.db.QueryRow(query, name))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.Tool{}, domain.ErrToolNotFound
+		}
+		return domain.Tool{}, fmt.Errorf("failed to get tool by name: %w", err)
+	}
 
-	err := r.db.QueryRow(query, name).Scan(
-		&tool.ID,
-		&tool.Name,
-		&tool.Code,
-		&createdAt,
-		&updatedAt,
-	)
+	return tool, nil
+}
+
+func (r *SQLiteToolRepository) GetByNamespaceAndName(namespace, name string) (domain.Tool, error) {
+	query := `
+	SELECT ` + toolColumns + `
+	FROM tools
+	WHERE namespace = ? AND name = ? AND deleted_at IS NULL
+	`
 
+	tool, err := scanTool(r.db.QueryRow(query, domain.NormalizeNamespace(namespace), name))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return domain.Tool{}, domain.ErrToolNotFound
@@ -124,20 +359,22 @@ func (r *SQLiteToolRepository) GetByName(name string) (domain.Tool, error) {
 		return domain.Tool{}, fmt.Errorf("failed to get tool by name: %w", err)
 	}
 
-	tool.CreatedAt = createdAt
-	tool.UpdatedAt = updatedAt
-
 	return tool, nil
 }
 
 func (r *SQLiteToolRepository) List() ([]domain.Tool, error) {
 	query := `
-	SELECT id, name, code, created_at, updated_at
+	SELECT ` + toolColumns + `
 	FROM tools
+	WHERE deleted_at IS NULL
 	ORDER BY created_at DESC
 	`
 
-	rows, err := r.db.Query(query)
+	return r.queryTools(query)
+}
+
+func (r *SQLiteToolRepository) queryTools(query string, args ...interface{}) ([]domain.Tool, error) {
+	rows, err := r.db.Query(query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list tools: %w", err)
 	}
@@ -145,24 +382,11 @@ func (r *SQLiteToolRepository) List() ([]domain.Tool, error) {
 
 	var tools []domain.Tool
 	for rows.Next() {
-		var tool domain.Tool
-		var createdAt, updatedAt time.Time
-
-		err := rows.Scan(
-			&tool.ID,
-			&tool.Name,
-			&tool.Code,
-			&createdAt,
-			&updatedAt,
-		)
-
+		tool, err := scanTool(rows)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan tool: %w", err)
 		}
 
-		tool.CreatedAt = createdAt
-		tool.UpdatedAt = updatedAt
-
 		tools = append(tools, tool)
 	}
 
@@ -173,6 +397,425 @@ func (r *SQLiteToolRepository) List() ([]domain.Tool, error) {
 	return tools, nil
 }
 
+func (r *SQLiteToolRepository) Search(query domain.ToolQuery) ([]domain.Tool, error) {
+	conditions := []string{"deleted_at IS NULL"}
+	var args []interface{}
+
+	if match := ftsQuery(query.Text); match != "" {
+		conditions = append(conditions, "rowid IN (SELECT docid FROM tools_fts WHERE tools_fts MATCH ?)")
+		args = append(args, match)
+	} else if strings.TrimSpace(query.Text) != "" {
+		// Only punctuation was typed; nothing can match
+		return nil, nil
+	}
+
+	if tag := domain.NormalizeTag(query.Tag); tag != "" {
+		conditions = append(conditions, "EXISTS (SELECT 1 FROM json_each(tools.tags) WHERE json_each.value = ?)")
+		args = append(args, tag)
+	}
+
+	// Tools whose name contains the text come first
+	sqlQuery := `
+	SELECT ` + toolColumns + `
+	FROM tools
+	WHERE ` + strings.Join(conditions, " AND ") + `
+	ORDER BY instr(lower(name), lower(?)) = 0, updated_at DESC
+	`
+	args = append(args, strings.TrimSpace(query.Text))
+
+	return r.queryTools(sqlQuery, args...)
+}
+
+func (r *SQLiteToolRepository) ListTags() ([]string, error) {
+	query := `
+	SELECT DISTINCT json_each.value
+	FROM tools, json_each(tools.tags)
+	WHERE tools.deleted_at IS NULL
+	ORDER BY json_each.value
+	`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tool tags: %w", err)
+	}
+	defer rows.Close()
+
+	tags := []string{}
+	for rows.Next() {
+		var tag string
+		if err := rows.Scan(&tag); err != nil {
+			return nil, fmt.Errorf("failed to scan tool tag: %w", err)
+		}
+		tags = append(tags, tag)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating tool tags: %w", err)
+	}
+
+	return tags, nil
+}
+
+// indexTool refreshes the search index entry of a saved tool
+func indexTool(tx *sql.Tx, id string) error {
+	if _, err := tx.Exec(`DELETE FROM tools_fts WHERE docid = (SELECT rowid FROM tools WHERE id = ?)`, id); err != nil {
+		return fmt.Errorf("failed to update tool search index: %w", err)
+	}
+
+	indexSQL := `
+	INSERT INTO tools_fts (docid, name, description, code)
+	SELECT rowid, name, description, code FROM tools WHERE id = ?
+	`
+
+	if _, err := tx.Exec(indexSQL, id); err != nil {
+		return fmt.Errorf("failed to update tool search index: %w", err)
+	}
+
+	return nil
+}
+
+// isTrashed reports whether the tool with the ID is in the trash
+func toolExists(tx *sql.Tx, id string) (bool, error) {
+	var exists bool
+	err := tx.QueryRow(`SELECT EXISTS(SELECT 1 FROM tools WHERE id = ?)`, id).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to look up tool: %w", err)
+	}
+	return exists, nil
+}
+
+func isTrashed(tx *sql.Tx, id string) (bool, error) {
+	var trashed bool
+	err := tx.QueryRow(`SELECT deleted_at IS NOT NULL FROM tools WHERE id = ?`, id).Scan(&trashed)
+	if err != nil {
+		return false, err
+	}
+	return trashed, nil
+}
+
+// ftsQuery turns free text into a full-text query matching every word as a
+// prefix, dropping punctuation that would be read as query syntax
+func ftsQuery(text string) string {
+	words := strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	terms := make([]string, len(words))
+	for i, word := range words {
+		terms[i] = word + "*"
+	}
+	return strings.Join(terms, " ")
+}
+
+func (r *SQLiteToolRepository) ListRevisions(toolID string) ([]domain.ToolRevision, error) {
+	query := `
+	SELECT ` + revisionColumns + `
+	FROM tool_revisions
+	WHERE tool_id = ?
+	ORDER BY revision DESC
+	`
+
+	rows, err := r.db.Query(query, toolID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tool revisions: %w", err)
+	}
+	defer rows.Close()
+
+	var revisions []domain.ToolRevision
+	for rows.Next() {
+		revision, err := scanRevision(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan tool revision: %w", err)
+		}
+
+		revisions = append(revisions, revision)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating tool revisions: %w", err)
+	}
+
+	if len(revisions) == 0 {
+		// Distinguish an unknown tool from one without history
+		if _, err := r.GetByID(toolID); err != nil {
+			return nil, err
+		}
+	}
+
+	return revisions, nil
+}
+
+func (r *SQLiteToolRepository) GetRevision(toolID string, revision int) (domain.ToolRevision, error) {
+	query := `
+	SELECT ` + revisionColumns + `
+	FROM tool_revisions
+	WHERE tool_id = ? AND revision = ?
+	`
+
+	toolRevision, err := scanRevision(r.db.QueryRow(query, toolID, revision))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.ToolRevision{}, domain.ErrRevisionNotFound
+		}
+		return domain.ToolRevision{}, fmt.Errorf("failed to get tool revision: %w", err)
+	}
+
+	return toolRevision, nil
+}
+
+func (r *SQLiteToolRepository) DiffRevisions(toolID string, from, to int) (domain.RevisionDiff, error) {
+	fromRevision, err := r.GetRevision(toolID, from)
+	if err != nil {
+		return domain.RevisionDiff{}, err
+	}
+
+	toRevision, err := r.GetRevision(toolID, to)
+	if err != nil {
+		return domain.RevisionDiff{}, err
+	}
+
+	return domain.DiffRevisions(fromRevision, toRevision), nil
+}
+
+func (r *SQLiteToolRepository) Revert(toolID string, revision int) (domain.Tool, error) {
+	tool, err := r.GetByID(toolID)
+	if err != nil {
+		return domain.Tool{}, err
+	}
+
+	target, err := r.GetRevision(toolID, revision)
+	if err != nil {
+		return domain.Tool{}, err
+	}
+
+	return r.SaveWithMessage(tool.WithRevision(target), domain.RevertMessage(revision))
+}
+
+func (r *SQLiteToolRepository) RecordResolvedVersions(toolID string, revision int, versions map[string]string) error {
+	encoded, err := json.Marshal(versions)
+	if err != nil {
+		return fmt.Errorf("failed to marshal resolved versions: %w", err)
+	}
+
+	result, err := r.db.Exec(`UPDATE tool_revisions SET resolved_versions = ? WHERE tool_id = ? AND revision = ?`, string(encoded), toolID, revision)
+	if err != nil {
+		return fmt.Errorf("failed to record resolved versions: %w", err)
+	}
+
+	return requireAffected(result, domain.ErrRevisionNotFound)
+}
+
+func (r *SQLiteToolRepository) Delete(id string) error {
+	result, err := r.db.Exec(`UPDATE tools SET deleted_at = ? WHERE id = ? AND deleted_at IS NULL`, time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("failed to delete tool: %w", err)
+	}
+
+	return requireAffected(result, domain.ErrToolNotFound)
+}
+
+func (r *SQLiteToolRepository) ListTrash() ([]domain.Tool, error) {
+	query := `
+	SELECT ` + toolColumns + `
+	FROM tools
+	WHERE deleted_at IS NOT NULL
+	ORDER BY deleted_at DESC
+	`
+
+	return r.queryTools(query)
+}
+
+func (r *SQLiteToolRepository) Restore(id string) (domain.Tool, error) {
+	result, err := r.db.Exec(`UPDATE tools SET deleted_at = NULL WHERE id = ? AND deleted_at IS NOT NULL`, id)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return domain.Tool{}, domain.ErrToolNameTaken
+		}
+		return domain.Tool{}, fmt.Errorf("failed to restore tool: %w", err)
+	}
+
+	if err := requireAffected(result, domain.ErrToolNotFound); err != nil {
+		return domain.Tool{}, err
+	}
+
+	return r.GetByID(id)
+}
+
+func (r *SQLiteToolRepository) Purge(id string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	unindexSQL := `
+	DELETE FROM tools_fts
+	WHERE docid IN (SELECT rowid FROM tools WHERE id = ? AND deleted_at IS NOT NULL)
+	`
+
+	if _, err := tx.Exec(unindexSQL, id); err != nil {
+		return fmt.Errorf("failed to remove tool from search index: %w", err)
+	}
+
+	// Only tools already in the trash can be removed for good
+	result, err := tx.Exec(`DELETE FROM tools WHERE id = ? AND deleted_at IS NOT NULL`, id)
+	if err != nil {
+		return fmt.Errorf("failed to purge tool: %w", err)
+	}
+
+	if err := requireAffected(result, domain.ErrToolNotFound); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(`DELETE FROM tool_revisions WHERE tool_id = ?`, id); err != nil {
+		return fmt.Errorf("failed to purge tool revisions: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanTool(row rowScanner) (domain.Tool, error) {
+	var tool domain.Tool
+	var tags, parameters, dependencies string
+	var deletedAt sql.NullTime
+	var capabilities sql.NullString
+
+	err := row.Scan(
+		&tool.ID,
+		&tool.Name,
+		&tool.Code,
+		&tool.Description,
+		&tags,
+		&parameters,
+		&dependencies,
+		&tool.CreatedAt,
+		&tool.UpdatedAt,
+		&deletedAt,
+		&tool.Version,
+		&tool.Draft,
+		&tool.Namespace,
+		&tool.Language,
+		&capabilities,
+	)
+	if err != nil {
+		return domain.Tool{}, err
+	}
+
+	if deletedAt.Valid {
+		tool.DeletedAt = &deletedAt.Time
+	}
+
+	if err := json.Unmarshal([]byte(tags), &tool.Tags); err != nil {
+		return domain.Tool{}, fmt.Errorf("failed to unmarshal tool tags: %w", err)
+	}
+
+	if err := json.Unmarshal([]byte(parameters), &tool.Parameters); err != nil {
+		return domain.Tool{}, fmt.Errorf("failed to unmarshal tool parameters: %w", err)
+	}
+
+	if err := json.Unmarshal([]byte(dependencies), &tool.Dependencies); err != nil {
+		return domain.Tool{}, fmt.Errorf("failed to unmarshal tool dependencies: %w", err)
+	}
+
+	if capabilities.Valid {
+		tool.Capabilities = &domain.ToolCapabilities{}
+		if err := json.Unmarshal([]byte(capabilities.String), tool.Capabilities); err != nil {
+			return domain.Tool{}, fmt.Errorf("failed to unmarshal tool capabilities: %w", err)
+		}
+	}
+
+	return tool, nil
+}
+
+// marshalCapabilities stores undeclared capabilities as NULL
+func marshalCapabilities(capabilities *domain.ToolCapabilities) (sql.NullString, error) {
+	if capabilities == nil {
+		return sql.NullString{}, nil
+	}
+	encoded, err := json.Marshal(capabilities)
+	if err != nil {
+		return sql.NullString{}, fmt.Errorf("failed to marshal tool capabilities: %w", err)
+	}
+	return sql.NullString{String: string(encoded), Valid: true}, nil
+}
+
+func scanRevision(row rowScanner) (domain.ToolRevision, error) {
+	var revision domain.ToolRevision
+	var parameters, dependencies, resolvedVersions string
+
+	err := row.Scan(
+		&revision.ToolID,
+		&revision.Revision,
+		&revision.Name,
+		&revision.Code,
+		&parameters,
+		&dependencies,
+		&resolvedVersions,
+		&revision.Message,
+		&revision.CreatedAt,
+	)
+	if err != nil {
+		return domain.ToolRevision{}, err
+	}
+
+	if err := json.Unmarshal([]byte(parameters), &revision.Parameters); err != nil {
+		return domain.ToolRevision{}, fmt.Errorf("failed to unmarshal revision parameters: %w", err)
+	}
+
+	if err := json.Unmarshal([]byte(dependencies), &revision.Dependencies); err != nil {
+		return domain.ToolRevision{}, fmt.Errorf("failed to unmarshal revision dependencies: %w", err)
+	}
+
+	if err := json.Unmarshal([]byte(resolvedVersions), &revision.ResolvedVersions); err != nil {
+		return domain.ToolRevision{}, fmt.Errorf("failed to unmarshal resolved versions: %w", err)
+	}
+
+	return revision, nil
+}
+
+// nonNilParameters keeps tools without parameters stored as [] rather than null
+func nonNilParameters(parameters []domain.ToolParameter) []domain.ToolParameter {
+	if parameters == nil {
+		return []domain.ToolParameter{}
+	}
+	return parameters
+}
+
+// nonNilDependencies keeps tools without dependencies stored as [] rather than null
+func nonNilDependencies(dependencies []domain.NpmDependency) []domain.NpmDependency {
+	if dependencies == nil {
+		return []domain.NpmDependency{}
+	}
+	return dependencies
+}
+
+// isUniqueViolation reports whether err is a UNIQUE constraint failure
+func isUniqueViolation(err error) bool {
+	var sqliteErr sqlite3.Error
+	return errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique
+}
+
+// requireAffected returns notFound when the statement changed no rows
+func requireAffected(result sql.Result, notFound error) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to read affected rows: %w", err)
+	}
+	if affected == 0 {
+		return notFound
+	}
+	return nil
+}
+
 func (r *SQLiteToolRepository) Close() error {
 	return r.db.Close()
 }
\ No newline at end of file
@@ -1,6 +1,7 @@
 package infrastructure_test
 
 import (
+	"database/sql"
 	"os"
 	"testing"
 
@@ -21,7 +22,7 @@ func TestSQLiteToolRepository_SaveAndRetrieve(t *testing.T) {
 
 	// When saving a tool
 	tool := domain.NewTool("Test Tool", "function hello() { return 'world'; }")
-	err = repo.Save(tool)
+	_, err = repo.Save(tool)
 	require.NoError(t, err)
 
 	// Then it should be retrievable by ID
@@ -62,7 +63,7 @@ func TestSQLiteToolRepository_SaveAndRetrieveByName(t *testing.T) {
 
 	// When saving a tool
 	tool := domain.NewTool("Unique Tool", "console.log('unique');")
-	err = repo.Save(tool)
+	_, err = repo.Save(tool)
 	require.NoError(t, err)
 
 	// Then it should be retrievable by name
@@ -104,11 +105,11 @@ func TestSQLiteToolRepository_List(t *testing.T) {
 	tool2 := domain.NewTool("Tool 2", "code2")
 	tool3 := domain.NewTool("Tool 3", "code3")
 
-	err = repo.Save(tool1)
+	_, err = repo.Save(tool1)
 	require.NoError(t, err)
-	err = repo.Save(tool2)
+	_, err = repo.Save(tool2)
 	require.NoError(t, err)
-	err = repo.Save(tool3)
+	_, err = repo.Save(tool3)
 	require.NoError(t, err)
 
 	// Then listing should return all tools
@@ -153,12 +154,14 @@ func TestSQLiteToolRepository_UpdateExistingTool(t *testing.T) {
 	defer repo.Close()
 
 	originalTool := domain.NewTool("Update Test", "original code")
-	err = repo.Save(originalTool)
+	_, err = repo.Save(originalTool)
+	require.NoError(t, err)
+	stored, err := repo.GetByID(originalTool.ID)
 	require.NoError(t, err)
 
 	// When saving an updated version (same ID)
-	updatedTool := originalTool.WithUpdatedCode("updated code")
-	err = repo.Save(updatedTool)
+	updatedTool := stored.WithUpdatedCode("updated code")
+	_, err = repo.Save(updatedTool)
 	require.NoError(t, err)
 
 	// Then the retrieved tool should have the updated code
@@ -170,6 +173,697 @@ func TestSQLiteToolRepository_UpdateExistingTool(t *testing.T) {
 	assert.True(t, retrieved.UpdatedAt.After(originalTool.UpdatedAt))
 }
 
+func TestSQLiteToolRepository_Save_VersionConflict(t *testing.T) {
+	// Given a tool loaded by two editors
+	dbFile := "test_tools_version_conflict.db"
+	defer cleanupDatabase(dbFile)
+
+	repo, err := infrastructure.NewSQLiteToolRepository(dbFile)
+	require.NoError(t, err)
+	defer repo.Close()
+
+	_, err = repo.Save(domain.NewTool("Shared", "original"))
+	require.NoError(t, err)
+	shared, err := repo.GetByName("Shared")
+	require.NoError(t, err)
+	assert.Equal(t, 1, shared.Version)
+
+	// When the first editor saves and the second saves what it loaded
+	_, err = repo.Save(shared.WithUpdatedCode("first edit"))
+	require.NoError(t, err)
+	_, err = repo.Save(shared.WithUpdatedCode("second edit"))
+
+	// Then the stale save is rejected without a revision and the first edit is kept
+	assert.Equal(t, domain.ErrToolVersionConflict, err)
+	retrieved, err := repo.GetByID(shared.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "first edit", retrieved.Code)
+	assert.Equal(t, 2, retrieved.Version)
+
+	revisions, err := repo.ListRevisions(shared.ID)
+	require.NoError(t, err)
+	assert.Len(t, revisions, 2)
+
+	// And saving a brand new tool under an existing ID is a conflict too
+	fresh := domain.NewTool("Fresh", "code")
+	fresh.ID = shared.ID
+	_, err = repo.Save(fresh)
+	assert.Equal(t, domain.ErrToolVersionConflict, err)
+}
+
+func TestSQLiteToolRepository_Draft(t *testing.T) {
+	// Given a draft tool
+	dbFile := "test_tools_draft.db"
+	defer cleanupDatabase(dbFile)
+
+	repo, err := infrastructure.NewSQLiteToolRepository(dbFile)
+	require.NoError(t, err)
+	defer repo.Close()
+
+	draft := domain.NewTool("Draft", "console.log(1);")
+	draft.Draft = true
+	_, err = repo.Save(draft)
+	require.NoError(t, err)
+
+	// When it is published
+	stored, err := repo.GetByID(draft.ID)
+	require.NoError(t, err)
+	assert.True(t, stored.Draft)
+	_, err = repo.Save(stored.Published())
+	require.NoError(t, err)
+
+	// Then it is no longer a draft
+	published, err := repo.GetByID(draft.ID)
+	require.NoError(t, err)
+	assert.False(t, published.Draft)
+}
+
+func TestSQLiteToolRepository_Language(t *testing.T) {
+	// Given a TypeScript tool and a Python tool
+	dbFile := "test_tools_language.db"
+	defer cleanupDatabase(dbFile)
+
+	repo, err := infrastructure.NewSQLiteToolRepository(dbFile)
+	require.NoError(t, err)
+	defer repo.Close()
+
+	script := domain.NewTool("Script", "console.log(1);")
+	_, err = repo.Save(script)
+	require.NoError(t, err)
+	python, err := domain.NewTool("Python", "print(1)").WithLanguage(domain.LanguagePython)
+	require.NoError(t, err)
+	_, err = repo.Save(python)
+	require.NoError(t, err)
+
+	// When
+	storedScript, err := repo.GetByID(script.ID)
+	require.NoError(t, err)
+	storedPython, err := repo.GetByID(python.ID)
+	require.NoError(t, err)
+
+	// Then
+	assert.Equal(t, domain.LanguageTypeScript, storedScript.Language)
+	assert.Equal(t, domain.LanguagePython, storedPython.Language)
+}
+
+func TestSQLiteToolRepository_Capabilities(t *testing.T) {
+	// Given a tool declaring capabilities and a tool declaring none
+	dbFile := "test_tools_capabilities.db"
+	defer cleanupDatabase(dbFile)
+
+	repo, err := infrastructure.NewSQLiteToolRepository(dbFile)
+	require.NoError(t, err)
+	defer repo.Close()
+
+	declared, err := domain.NewTool("Declared", "console.log(1);").WithCapabilities(domain.ToolCapabilities{ReadPaths: []string{"/etc/hosts"}, Network: true})
+	require.NoError(t, err)
+	_, err = repo.Save(declared)
+	require.NoError(t, err)
+	legacy := domain.NewTool("Legacy", "console.log(2);")
+	legacy.Capabilities = nil
+	_, err = repo.Save(legacy)
+	require.NoError(t, err)
+
+	// When
+	storedDeclared, err := repo.GetByID(declared.ID)
+	require.NoError(t, err)
+	storedLegacy, err := repo.GetByID(legacy.ID)
+	require.NoError(t, err)
+
+	// Then
+	require.NotNil(t, storedDeclared.Capabilities)
+	assert.Equal(t, []string{"/etc/hosts"}, storedDeclared.Capabilities.ReadPaths)
+	assert.True(t, storedDeclared.Capabilities.Network)
+	assert.Nil(t, storedLegacy.Capabilities)
+}
+
+func TestSQLiteToolRepository_SaveCreatesRevisions(t *testing.T) {
+	// Given a temporary database
+	dbFile := "test_tools_revisions.db"
+	defer cleanupDatabase(dbFile)
+
+	repo, err := infrastructure.NewSQLiteToolRepository(dbFile)
+	require.NoError(t, err)
+	defer repo.Close()
+
+	// When saving a tool and then an updated version with a message
+	tool := domain.NewTool("Revision Tool", "console.log(1);")
+	tool, err = repo.Save(tool)
+	require.NoError(t, err)
+	_, err = repo.SaveWithMessage(tool.WithUpdatedCode("console.log(2);"), "print two")
+	require.NoError(t, err)
+
+	// Then both saves are kept as revisions, newest first
+	revisions, err := repo.ListRevisions(tool.ID)
+	require.NoError(t, err)
+	require.Len(t, revisions, 2)
+	assert.Equal(t, 2, revisions[0].Revision)
+	assert.Equal(t, "console.log(2);", revisions[0].Code)
+	assert.Equal(t, "print two", revisions[0].Message)
+	assert.Equal(t, 1, revisions[1].Revision)
+	assert.Equal(t, "console.log(1);", revisions[1].Code)
+	assert.Empty(t, revisions[1].Message)
+}
+
+func TestSQLiteToolRepository_EditsKeepOneToolWithItsRevisions(t *testing.T) {
+	// Given a saved tool
+	dbFile := "test_tools_edits.db"
+	defer cleanupDatabase(dbFile)
+
+	repo, err := infrastructure.NewSQLiteToolRepository(dbFile)
+	require.NoError(t, err)
+	defer repo.Close()
+
+	tool := domain.NewTool("Edited", "console.log(1);")
+	_, err = repo.Save(tool)
+	require.NoError(t, err)
+
+	// When it is edited twice the way the editor saves it
+	for _, code := range []string{"console.log(2);", "console.log(3);"} {
+		stored, err := repo.GetByID(tool.ID)
+		require.NoError(t, err)
+		_, err = repo.Save(stored.WithUpdatedCode(code))
+		require.NoError(t, err)
+	}
+
+	// Then the tool keeps its ID and every revision stays attached to it
+	tools, err := repo.List()
+	require.NoError(t, err)
+	require.Len(t, tools, 1)
+	assert.Equal(t, tool.ID, tools[0].ID)
+	assert.Equal(t, "console.log(3);", tools[0].Code)
+
+	revisions, err := repo.ListRevisions(tool.ID)
+	require.NoError(t, err)
+	assert.Len(t, revisions, 3)
+}
+
+func TestSQLiteToolRepository_DiffRevisions(t *testing.T) {
+	// Given a tool with two revisions
+	dbFile := "test_tools_diff.db"
+	defer cleanupDatabase(dbFile)
+
+	repo, err := infrastructure.NewSQLiteToolRepository(dbFile)
+	require.NoError(t, err)
+	defer repo.Close()
+
+	tool := domain.NewTool("Diff Tool", "const a = 1;\nconsole.log(a);")
+	tool, err = repo.Save(tool)
+	require.NoError(t, err)
+	_, err = repo.Save(tool.WithUpdatedCode("const a = 2;\nconsole.log(a);"))
+	require.NoError(t, err)
+
+	// When diffing the revisions
+	diff, err := repo.DiffRevisions(tool.ID, 1, 2)
+
+	// Then the changed line is reported
+	require.NoError(t, err)
+	assert.Equal(t, 1, diff.Added)
+	assert.Equal(t, 1, diff.Removed)
+
+	// And an unknown revision is reported as not found
+	_, err = repo.DiffRevisions(tool.ID, 1, 5)
+	assert.Equal(t, domain.ErrRevisionNotFound, err)
+}
+
+func TestSQLiteToolRepository_Revert(t *testing.T) {
+	// Given a tool with two revisions
+	dbFile := "test_tools_revert.db"
+	defer cleanupDatabase(dbFile)
+
+	repo, err := infrastructure.NewSQLiteToolRepository(dbFile)
+	require.NoError(t, err)
+	defer repo.Close()
+
+	tool := domain.NewTool("Revert Tool", "first")
+	tool, err = repo.Save(tool)
+	require.NoError(t, err)
+	_, err = repo.Save(tool.WithUpdatedCode("second"))
+	require.NoError(t, err)
+
+	// When reverting to the first revision
+	reverted, err := repo.Revert(tool.ID, 1)
+	require.NoError(t, err)
+
+	// Then the tool has the old code and the revert is a new revision
+	assert.Equal(t, "first", reverted.Code)
+	retrieved, err := repo.GetByID(tool.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "first", retrieved.Code)
+
+	revisions, err := repo.ListRevisions(tool.ID)
+	require.NoError(t, err)
+	require.Len(t, revisions, 3)
+	assert.Equal(t, 3, revisions[0].Revision)
+	assert.Equal(t, "Revert to revision 1", revisions[0].Message)
+}
+
+func TestSQLiteToolRepository_ListRevisions_UnknownTool(t *testing.T) {
+	// Given a temporary database
+	dbFile := "test_tools_revisions_unknown.db"
+	defer cleanupDatabase(dbFile)
+
+	repo, err := infrastructure.NewSQLiteToolRepository(dbFile)
+	require.NoError(t, err)
+	defer repo.Close()
+
+	// When listing revisions of a non-existent tool
+	_, err = repo.ListRevisions("non-existent-id")
+
+	// Then it should return not found error
+	assert.Equal(t, domain.ErrToolNotFound, err)
+}
+
+func TestSQLiteToolRepository_Save_NameTaken(t *testing.T) {
+	// Given a database with a saved tool
+	dbFile := "test_tools_name_taken.db"
+	defer cleanupDatabase(dbFile)
+
+	repo, err := infrastructure.NewSQLiteToolRepository(dbFile)
+	require.NoError(t, err)
+	defer repo.Close()
+
+	first := domain.NewTool("Taken", "first")
+	_, err = repo.Save(first)
+	require.NoError(t, err)
+
+	// When saving another tool, or renaming one, to the same name
+	_, err = repo.Save(domain.NewTool("Taken", "second"))
+	assert.Equal(t, domain.ErrToolNameTaken, err)
+
+	other := domain.NewTool("Other", "other")
+	other, err = repo.Save(other)
+	require.NoError(t, err)
+	renamed, err := other.WithName("Taken")
+	require.NoError(t, err)
+	_, err = repo.Save(renamed)
+
+	// Then both are rejected and the first tool is untouched
+	assert.Equal(t, domain.ErrToolNameTaken, err)
+	retrieved, err := repo.GetByName("Taken")
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, retrieved.ID)
+	assert.Equal(t, "first", retrieved.Code)
+}
+
+func TestSQLiteToolRepository_DeleteAndRestore(t *testing.T) {
+	// Given a database with a saved tool
+	dbFile := "test_tools_trash.db"
+	defer cleanupDatabase(dbFile)
+
+	repo, err := infrastructure.NewSQLiteToolRepository(dbFile)
+	require.NoError(t, err)
+	defer repo.Close()
+
+	tool := domain.NewTool("Trash Me", "code")
+	_, err = repo.Save(tool)
+	require.NoError(t, err)
+
+	// When deleting it
+	require.NoError(t, repo.Delete(tool.ID))
+
+	// Then it is only visible in the trash
+	_, err = repo.GetByID(tool.ID)
+	assert.Equal(t, domain.ErrToolNotFound, err)
+	tools, err := repo.List()
+	require.NoError(t, err)
+	assert.Empty(t, tools)
+
+	trash, err := repo.ListTrash()
+	require.NoError(t, err)
+	require.Len(t, trash, 1)
+	assert.Equal(t, tool.ID, trash[0].ID)
+	assert.NotNil(t, trash[0].DeletedAt)
+
+	// And restoring it brings it back with its history
+	restored, err := repo.Restore(tool.ID)
+	require.NoError(t, err)
+	assert.Nil(t, restored.DeletedAt)
+	revisions, err := repo.ListRevisions(tool.ID)
+	require.NoError(t, err)
+	assert.Len(t, revisions, 1)
+}
+
+func TestSQLiteToolRepository_Save_Trashed(t *testing.T) {
+	// Given a tool an editor loaded before it was moved to the trash
+	dbFile := "test_tools_save_trashed.db"
+	defer cleanupDatabase(dbFile)
+
+	repo, err := infrastructure.NewSQLiteToolRepository(dbFile)
+	require.NoError(t, err)
+	defer repo.Close()
+
+	_, err = repo.Save(domain.NewTool("Trashed", "original"))
+	require.NoError(t, err)
+	loaded, err := repo.GetByName("Trashed")
+	require.NoError(t, err)
+	require.NoError(t, repo.Delete(loaded.ID))
+
+	// When the editor saves it
+	_, err = repo.Save(loaded.WithUpdatedCode("edited"))
+
+	// Then the save is refused and the trashed tool is untouched
+	assert.Equal(t, domain.ErrToolNotFound, err)
+	trash, err := repo.ListTrash()
+	require.NoError(t, err)
+	require.Len(t, trash, 1)
+	assert.Equal(t, "original", trash[0].Code)
+	revisions, err := repo.ListRevisions(loaded.ID)
+	require.NoError(t, err)
+	assert.Len(t, revisions, 1)
+}
+
+func TestSQLiteToolRepository_Restore_NameTaken(t *testing.T) {
+	// Given a deleted tool whose name was reused
+	dbFile := "test_tools_restore_taken.db"
+	defer cleanupDatabase(dbFile)
+
+	repo, err := infrastructure.NewSQLiteToolRepository(dbFile)
+	require.NoError(t, err)
+	defer repo.Close()
+
+	deleted := domain.NewTool("Reused", "old")
+	_, err = repo.Save(deleted)
+	require.NoError(t, err)
+	require.NoError(t, repo.Delete(deleted.ID))
+	_, err = repo.Save(domain.NewTool("Reused", "new"))
+	require.NoError(t, err)
+
+	// When restoring the deleted tool
+	_, err = repo.Restore(deleted.ID)
+
+	// Then it is refused and stays in the trash
+	assert.Equal(t, domain.ErrToolNameTaken, err)
+	trash, err := repo.ListTrash()
+	require.NoError(t, err)
+	assert.Len(t, trash, 1)
+}
+
+func TestSQLiteToolRepository_Purge(t *testing.T) {
+	// Given a database with a saved tool
+	dbFile := "test_tools_purge.db"
+	defer cleanupDatabase(dbFile)
+
+	repo, err := infrastructure.NewSQLiteToolRepository(dbFile)
+	require.NoError(t, err)
+	defer repo.Close()
+
+	tool := domain.NewTool("Purge Me", "code")
+	_, err = repo.Save(tool)
+	require.NoError(t, err)
+
+	// When purging it before it is in the trash
+	err = repo.Purge(tool.ID)
+
+	// Then it is refused
+	assert.Equal(t, domain.ErrToolNotFound, err)
+
+	// And once in the trash it is removed for good
+	require.NoError(t, repo.Delete(tool.ID))
+	require.NoError(t, repo.Purge(tool.ID))
+	trash, err := repo.ListTrash()
+	require.NoError(t, err)
+	assert.Empty(t, trash)
+	_, err = repo.Restore(tool.ID)
+	assert.Equal(t, domain.ErrToolNotFound, err)
+}
+
+func TestSQLiteToolRepository_SaveAfterPurge(t *testing.T) {
+	// Given a tool loaded by an editor and then purged
+	dbFile := "test_tools_save_after_purge.db"
+	defer cleanupDatabase(dbFile)
+
+	repo, err := infrastructure.NewSQLiteToolRepository(dbFile)
+	require.NoError(t, err)
+	defer repo.Close()
+
+	loaded, err := repo.Save(domain.NewTool("Purged", "code"))
+	require.NoError(t, err)
+	require.NoError(t, repo.Delete(loaded.ID))
+	require.NoError(t, repo.Purge(loaded.ID))
+
+	// When the editor saves what it loaded
+	_, err = repo.Save(loaded.WithUpdatedCode("edited"))
+
+	// Then the stale save is rejected instead of bringing the tool back
+	assert.Equal(t, domain.ErrToolVersionConflict, err)
+	_, err = repo.GetByID(loaded.ID)
+	assert.Equal(t, domain.ErrToolNotFound, err)
+}
+
+func TestSQLiteToolRepository_MigratesLegacyTable(t *testing.T) {
+	// Given a database created before the trash existed
+	dbFile := "test_tools_legacy.db"
+	defer cleanupDatabase(dbFile)
+
+	db, err := sql.Open("sqlite3", dbFile)
+	require.NoError(t, err)
+	_, err = db.Exec(`
+	CREATE TABLE tools (
+		id TEXT PRIMARY KEY,
+		name TEXT NOT NULL UNIQUE,
+		code TEXT NOT NULL,
+		created_at DATETIME NOT NULL,
+		updated_at DATETIME NOT NULL
+	);
+	INSERT INTO tools VALUES ('legacy-id', 'Legacy', 'old code', '2024-01-01 00:00:00', '2024-01-01 00:00:00');
+	`)
+	require.NoError(t, err)
+	db.Close()
+
+	// When opening the repository
+	repo, err := infrastructure.NewSQLiteToolRepository(dbFile)
+	require.NoError(t, err)
+	defer repo.Close()
+
+	// Then the existing tool is kept and can be trashed and its name reused
+	tool, err := repo.GetByID("legacy-id")
+	require.NoError(t, err)
+	assert.Equal(t, "old code", tool.Code)
+
+	require.NoError(t, repo.Delete("legacy-id"))
+	_, err = repo.Save(domain.NewTool("Legacy", "new code"))
+	require.NoError(t, err)
+}
+
+func TestSQLiteToolRepository_Parameters(t *testing.T) {
+	// Given a temporary database
+	dbFile := "test_tools_parameters.db"
+	defer cleanupDatabase(dbFile)
+
+	repo, err := infrastructure.NewSQLiteToolRepository(dbFile)
+	require.NoError(t, err)
+	defer repo.Close()
+
+	// When saving a tool, then declaring parameters on it
+	tool := domain.NewTool("Greeter", "console.log('hi');")
+	tool, err = repo.Save(tool)
+	require.NoError(t, err)
+
+	withParameters, err := tool.WithParameters([]domain.ToolParameter{
+		{Name: "name", Type: domain.ParameterString, Description: "Who to greet", Default: "world"},
+	})
+	require.NoError(t, err)
+	_, err = repo.Save(withParameters)
+	require.NoError(t, err)
+
+	// Then the parameters are persisted
+	retrieved, err := repo.GetByID(tool.ID)
+	require.NoError(t, err)
+	require.Len(t, retrieved.Parameters, 1)
+	assert.Equal(t, "name", retrieved.Parameters[0].Name)
+	assert.Equal(t, domain.ParameterString, retrieved.Parameters[0].Type)
+	assert.Equal(t, "Who to greet", retrieved.Parameters[0].Description)
+	assert.Equal(t, "world", retrieved.Parameters[0].Default)
+
+	// And reverting to the first revision removes them again
+	reverted, err := repo.Revert(tool.ID, 1)
+	require.NoError(t, err)
+	assert.Empty(t, reverted.Parameters)
+	retrieved, err = repo.GetByID(tool.ID)
+	require.NoError(t, err)
+	assert.Empty(t, retrieved.Parameters)
+}
+
+func TestSQLiteToolRepository_Search(t *testing.T) {
+	// Given tools with descriptions and tags
+	dbFile := "test_tools_search.db"
+	defer cleanupDatabase(dbFile)
+
+	repo, err := infrastructure.NewSQLiteToolRepository(dbFile)
+	require.NoError(t, err)
+	defer repo.Close()
+
+	weather := domain.NewTool("Weather", "fetch('https://api.weather.example')").
+		WithMetadata("Shows the forecast for a city", []string{"http", "Daily"})
+	commits := domain.NewTool("Commit Summary", "execSync('git log --oneline')").
+		WithMetadata("Summarizes recent commits", []string{"git", "daily"})
+	trashed := domain.NewTool("Old Forecast", "console.log('forecast')")
+	_, err = repo.Save(weather)
+	require.NoError(t, err)
+	_, err = repo.Save(commits)
+	require.NoError(t, err)
+	_, err = repo.Save(trashed)
+	require.NoError(t, err)
+	require.NoError(t, repo.Delete(trashed.ID))
+
+	// When searching by a word prefix from a description
+	found, err := repo.Search(domain.ToolQuery{Text: "forec"})
+	require.NoError(t, err)
+
+	// Then only the matching tool outside the trash is returned
+	require.Len(t, found, 1)
+	assert.Equal(t, weather.ID, found[0].ID)
+	assert.Equal(t, []string{"daily", "http"}, found[0].Tags)
+
+	// And code is searched too
+	found, err = repo.Search(domain.ToolQuery{Text: "git log"})
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+	assert.Equal(t, commits.ID, found[0].ID)
+
+	// And tags filter alone or together with text
+	found, err = repo.Search(domain.ToolQuery{Tag: "DAILY"})
+	require.NoError(t, err)
+	assert.Len(t, found, 2)
+	found, err = repo.Search(domain.ToolQuery{Text: "commits", Tag: "http"})
+	require.NoError(t, err)
+	assert.Empty(t, found)
+
+	// And query syntax in the text is harmless
+	_, err = repo.Search(domain.ToolQuery{Text: `"weather" OR -( NEAR`})
+	assert.NoError(t, err)
+}
+
+func TestSQLiteToolRepository_Search_FollowsUpdates(t *testing.T) {
+	// Given a saved tool
+	dbFile := "test_tools_search_updates.db"
+	defer cleanupDatabase(dbFile)
+
+	repo, err := infrastructure.NewSQLiteToolRepository(dbFile)
+	require.NoError(t, err)
+	defer repo.Close()
+
+	tool := domain.NewTool("Indexed", "console.log('alpha');")
+	tool, err = repo.Save(tool)
+	require.NoError(t, err)
+
+	// When its code changes
+	_, err = repo.Save(tool.WithUpdatedCode("console.log('beta');"))
+	require.NoError(t, err)
+
+	// Then search sees the new code only
+	found, err := repo.Search(domain.ToolQuery{Text: "alpha"})
+	require.NoError(t, err)
+	assert.Empty(t, found)
+	found, err = repo.Search(domain.ToolQuery{Text: "beta"})
+	require.NoError(t, err)
+	assert.Len(t, found, 1)
+}
+
+func TestSQLiteToolRepository_ListTags(t *testing.T) {
+	// Given tools with overlapping tags
+	dbFile := "test_tools_tags.db"
+	defer cleanupDatabase(dbFile)
+
+	repo, err := infrastructure.NewSQLiteToolRepository(dbFile)
+	require.NoError(t, err)
+	defer repo.Close()
+
+	_, err = repo.Save(domain.NewTool("One", "1").WithMetadata("", []string{"git", "http"}))
+	require.NoError(t, err)
+	_, err = repo.Save(domain.NewTool("Two", "2").WithMetadata("", []string{"git"}))
+	require.NoError(t, err)
+
+	// When listing tags
+	tags, err := repo.ListTags()
+
+	// Then each tag appears once, sorted
+	require.NoError(t, err)
+	assert.Equal(t, []string{"git", "http"}, tags)
+}
+
 func cleanupDatabase(dbFile string) {
 	os.Remove(dbFile)
-}
\ No newline at end of file
+}
+
+func TestSQLiteToolRepository_Dependencies(t *testing.T) {
+	// Given a tool declaring npm dependencies
+	dbFile := "test_tools_dependencies.db"
+	defer cleanupDatabase(dbFile)
+
+	repo, err := infrastructure.NewSQLiteToolRepository(dbFile)
+	require.NoError(t, err)
+	defer repo.Close()
+
+	tool, err := domain.NewTool("Validate", "import { z } from 'zod';").
+		WithDependencies([]domain.NpmDependency{{Name: "zod", Version: "^3.23.0"}})
+	require.NoError(t, err)
+	_, err = repo.Save(tool)
+	require.NoError(t, err)
+
+	// When its first run records the installed version
+	require.NoError(t, repo.RecordResolvedVersions(tool.ID, 1, map[string]string{"zod": "3.23.8"}))
+
+	// Then the tool and its revision keep the declaration and the revision the version
+	retrieved, err := repo.GetByID(tool.ID)
+	require.NoError(t, err)
+	assert.Equal(t, tool.Dependencies, retrieved.Dependencies)
+
+	revision, err := repo.GetRevision(tool.ID, 1)
+	require.NoError(t, err)
+	assert.Equal(t, tool.Dependencies, revision.Dependencies)
+	assert.Equal(t, map[string]string{"zod": "3.23.8"}, revision.ResolvedVersions)
+	assert.Equal(t, []domain.NpmDependency{{Name: "zod", Version: "3.23.8"}}, revision.PinnedDependencies())
+
+	// And a new revision starts unresolved
+	_, err = repo.Save(retrieved.WithUpdatedCode("import { z } from 'zod'; z;"))
+	require.NoError(t, err)
+	latest, err := repo.GetRevision(tool.ID, 2)
+	require.NoError(t, err)
+	assert.Empty(t, latest.ResolvedVersions)
+
+	assert.ErrorIs(t, repo.RecordResolvedVersions(tool.ID, 9, map[string]string{}), domain.ErrRevisionNotFound)
+}
+func TestSQLiteToolRepository_Namespaces(t *testing.T) {
+	// Given a global tool
+	dbFile := "test_tools_namespaces.db"
+	defer cleanupDatabase(dbFile)
+
+	repo, err := infrastructure.NewSQLiteToolRepository(dbFile)
+	require.NoError(t, err)
+	defer repo.Close()
+
+	global := domain.NewTool("Format", "console.log('global')")
+	_, err = repo.Save(global)
+	require.NoError(t, err)
+
+	// When saving a tool with the same name in a project and in a team
+	project, err := domain.NewTool("Format", "console.log('project')").WithNamespace(domain.ProjectNamespace("/src/app"))
+	require.NoError(t, err)
+	team, err := domain.NewTool("Format", "console.log('team')").WithNamespace(domain.TeamNamespace("platform"))
+	require.NoError(t, err)
+
+	// Then names only clash within a namespace
+	_, err = repo.Save(project)
+	require.NoError(t, err)
+	_, err = repo.Save(team)
+	require.NoError(t, err)
+	_, err = repo.Save(domain.NewTool("Format", "console.log('again')"))
+	assert.Equal(t, domain.ErrToolNameTaken, err)
+
+	retrieved, err := repo.GetByID(project.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.ProjectNamespace("/src/app"), retrieved.Namespace)
+
+	// And an unscoped lookup by name prefers the global tool
+	byName, err := repo.GetByName("Format")
+	require.NoError(t, err)
+	assert.Equal(t, global.ID, byName.ID)
+
+	inTeam, err := repo.GetByNamespaceAndName(domain.TeamNamespace("platform"), "Format")
+	require.NoError(t, err)
+	assert.Equal(t, team.ID, inTeam.ID)
+	_, err = repo.GetByNamespaceAndName(domain.TeamNamespace("other"), "Format")
+	assert.Equal(t, domain.ErrToolNotFound, err)
+}
@@ -0,0 +1,260 @@
+package infrastructure
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"lumina/backend/tool/domain"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// runColumns are the columns read by scanRun, in order
+const runColumns = "id, tool_id, tool_name, revision, code, arguments, output, error, exit_code, success, duration_ms, started_at"
+
+type SQLiteToolRunRepository struct {
+	db *sql.DB
+}
+
+func NewSQLiteToolRunRepository(dbPath string) (*SQLiteToolRunRepository, error) {
+	db, err := sql.Open("sqlite3", dbPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	// Runs outlive the tools they executed, so tool_id is not a foreign key.
+	// started_at is stored in UTC so that time ranges compare as text.
+	createTableSQL := `
+	CREATE TABLE IF NOT EXISTS tool_runs (
+		id TEXT PRIMARY KEY,
+		tool_id TEXT NOT NULL DEFAULT '',
+		tool_name TEXT NOT NULL DEFAULT '',
+		revision INTEGER NOT NULL DEFAULT 0,
+		code TEXT NOT NULL DEFAULT '',
+		arguments TEXT NOT NULL DEFAULT '{}',
+		output TEXT NOT NULL,
+		error TEXT NOT NULL,
+		exit_code INTEGER NOT NULL,
+		success BOOLEAN NOT NULL,
+		duration_ms INTEGER NOT NULL,
+		started_at DATETIME NOT NULL
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_tool_runs_tool_started ON tool_runs(tool_id, started_at);
+	`
+
+	if _, err := db.Exec(createTableSQL); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create tool_runs table: %w", err)
+	}
+
+	return &SQLiteToolRunRepository{db: db}, nil
+}
+
+func (r *SQLiteToolRunRepository) Save(run domain.ToolRun) error {
+	arguments := run.Arguments
+	if arguments == nil {
+		arguments = map[string]interface{}{}
+	}
+	argumentsJSON, err := json.Marshal(arguments)
+	if err != nil {
+		return fmt.Errorf("failed to marshal run arguments: %w", err)
+	}
+
+	insertSQL := `
+	INSERT INTO tool_runs (` + runColumns + `)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	_, err = r.db.Exec(insertSQL,
+		run.ID,
+		run.ToolID,
+		run.ToolName,
+		run.Revision,
+		run.Code,
+		string(argumentsJSON),
+		run.Output,
+		run.Error,
+		run.ExitCode,
+		run.Success,
+		run.DurationMs,
+		run.StartedAt.UTC(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save tool run: %w", err)
+	}
+
+	return nil
+}
+
+func (r *SQLiteToolRunRepository) GetByID(id string) (domain.ToolRun, error) {
+	run, err := scanRun(r.db.QueryRow("SELECT "+runColumns+" FROM tool_runs WHERE id = ?", id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.ToolRun{}, domain.ErrToolRunNotFound
+		}
+		return domain.ToolRun{}, fmt.Errorf("failed to get tool run: %w", err)
+	}
+
+	return run, nil
+}
+
+func (r *SQLiteToolRunRepository) List(query domain.ToolRunQuery) ([]domain.ToolRun, error) {
+	var conditions []string
+	var args []interface{}
+
+	if query.ToolID != "" {
+		conditions = append(conditions, "tool_id = ?")
+		args = append(args, query.ToolID)
+	}
+	if query.AdHocOnly {
+		conditions = append(conditions, "tool_id = ''")
+	}
+	if !query.Since.IsZero() {
+		conditions = append(conditions, "started_at >= ?")
+		args = append(args, query.Since.UTC())
+	}
+	if !query.Until.IsZero() {
+		conditions = append(conditions, "started_at < ?")
+		args = append(args, query.Until.UTC())
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	// SQLite treats a negative LIMIT as "no limit"
+	limit := query.Limit
+	if limit <= 0 {
+		limit = -1
+	}
+	args = append(args, limit)
+
+	rows, err := r.db.Query("SELECT "+runColumns+" FROM tool_runs "+where+" ORDER BY started_at DESC LIMIT ?", args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tool runs: %w", err)
+	}
+	defer rows.Close()
+
+	runs := []domain.ToolRun{}
+	for rows.Next() {
+		run, err := scanRun(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan tool run: %w", err)
+		}
+		runs = append(runs, run)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating tool runs: %w", err)
+	}
+
+	return runs, nil
+}
+
+func (r *SQLiteToolRunRepository) UsageStats() (map[string]domain.ToolUsage, error) {
+	recentRuns, err := r.recentRuns()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.Query(`
+	SELECT tool_id, COUNT(*), SUM(CASE WHEN success THEN 0 ELSE 1 END), AVG(duration_ms)
+	FROM tool_runs
+	WHERE tool_id != ''
+	GROUP BY tool_id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tool usage: %w", err)
+	}
+	defer rows.Close()
+
+	usage := make(map[string]domain.ToolUsage)
+	for rows.Next() {
+		var toolID string
+		var runCount, failureCount int
+		var averageDurationMs float64
+		if err := rows.Scan(&toolID, &runCount, &failureCount, &averageDurationMs); err != nil {
+			return nil, fmt.Errorf("failed to scan tool usage: %w", err)
+		}
+		usage[toolID] = domain.NewToolUsage(toolID, runCount, failureCount, averageDurationMs, recentRuns[toolID])
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating tool usage: %w", err)
+	}
+
+	return usage, nil
+}
+
+// recentRuns returns the start times of the latest runs of each tool,
+// newest first
+func (r *SQLiteToolRunRepository) recentRuns() (map[string][]time.Time, error) {
+	rows, err := r.db.Query(`
+	SELECT tool_id, started_at FROM (
+		SELECT tool_id, started_at, ROW_NUMBER() OVER (PARTITION BY tool_id ORDER BY started_at DESC) AS position
+		FROM tool_runs
+		WHERE tool_id != ''
+	)
+	WHERE position <= ?
+	ORDER BY tool_id, position
+	`, domain.FrecencySamples)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query recent tool runs: %w", err)
+	}
+	defer rows.Close()
+
+	recent := make(map[string][]time.Time)
+	for rows.Next() {
+		var toolID string
+		var startedAt time.Time
+		if err := rows.Scan(&toolID, &startedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan recent tool run: %w", err)
+		}
+		recent[toolID] = append(recent[toolID], startedAt)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating recent tool runs: %w", err)
+	}
+
+	return recent, nil
+}
+
+func (r *SQLiteToolRunRepository) Close() error {
+	return r.db.Close()
+}
+
+func scanRun(row rowScanner) (domain.ToolRun, error) {
+	var run domain.ToolRun
+	var arguments string
+
+	err := row.Scan(
+		&run.ID,
+		&run.ToolID,
+		&run.ToolName,
+		&run.Revision,
+		&run.Code,
+		&arguments,
+		&run.Output,
+		&run.Error,
+		&run.ExitCode,
+		&run.Success,
+		&run.DurationMs,
+		&run.StartedAt,
+	)
+	if err != nil {
+		return domain.ToolRun{}, err
+	}
+
+	if err := json.Unmarshal([]byte(arguments), &run.Arguments); err != nil {
+		return domain.ToolRun{}, fmt.Errorf("failed to unmarshal run arguments: %w", err)
+	}
+
+	return run, nil
+}
@@ -0,0 +1,137 @@
+package infrastructure_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"lumina/backend/tool/domain"
+	"lumina/backend/tool/infrastructure"
+)
+
+func TestSQLiteToolRunRepository_SaveAndGet(t *testing.T) {
+	// Given a recorded run of a tool
+	dbFile := "test_tool_runs.db"
+	defer cleanupDatabase(dbFile)
+
+	repo, err := infrastructure.NewSQLiteToolRunRepository(dbFile)
+	require.NoError(t, err)
+	defer repo.Close()
+
+	tool, err := domain.NewToolWithValidation("Greet", "console.log('hi')")
+	require.NoError(t, err)
+
+	run := domain.NewToolRun(*tool, 3, map[string]interface{}{"name": "Ada"}, domain.ToolRunOutput{
+		Output:     "Hello, Ada\n",
+		Error:      "warning\n",
+		ExitCode:   0,
+		Success:    true,
+		DurationMs: 42,
+	}, time.Now())
+
+	// When
+	require.NoError(t, repo.Save(run))
+	loaded, err := repo.GetByID(run.ID)
+
+	// Then every field is kept
+	require.NoError(t, err)
+	assert.Equal(t, tool.ID, loaded.ToolID)
+	assert.Equal(t, "Greet", loaded.ToolName)
+	assert.Equal(t, 3, loaded.Revision)
+	assert.Equal(t, "Ada", loaded.Arguments["name"])
+	assert.Equal(t, "Hello, Ada\n", loaded.Output)
+	assert.Equal(t, "warning\n", loaded.Error)
+	assert.True(t, loaded.Success)
+	assert.Equal(t, int64(42), loaded.DurationMs)
+	assert.True(t, run.StartedAt.Equal(loaded.StartedAt))
+
+	_, err = repo.GetByID("missing")
+	assert.ErrorIs(t, err, domain.ErrToolRunNotFound)
+}
+
+func TestSQLiteToolRunRepository_List(t *testing.T) {
+	// Given runs of a tool from last week and today, and an ad-hoc run
+	dbFile := "test_tool_runs_list.db"
+	defer cleanupDatabase(dbFile)
+
+	repo, err := infrastructure.NewSQLiteToolRunRepository(dbFile)
+	require.NoError(t, err)
+	defer repo.Close()
+
+	tool, err := domain.NewToolWithValidation("Count TODOs", "console.log(1)")
+	require.NoError(t, err)
+
+	now := time.Now()
+	lastWeek := domain.NewToolRun(*tool, 1, nil, domain.ToolRunOutput{Output: "12"}, now.Add(-7*24*time.Hour))
+	today := domain.NewToolRun(*tool, 2, nil, domain.ToolRunOutput{Output: "9"}, now.Add(-time.Hour))
+	adHoc := domain.NewAdHocRun("console.log(2)", domain.ToolRunOutput{Output: "2"}, now)
+	for _, run := range []domain.ToolRun{lastWeek, today, adHoc} {
+		require.NoError(t, repo.Save(run))
+	}
+
+	// When listing everything, by tool, ad-hoc only, by time and with a limit
+	all, err := repo.List(domain.ToolRunQuery{})
+	require.NoError(t, err)
+	byTool, err := repo.List(domain.ToolRunQuery{ToolID: tool.ID})
+	require.NoError(t, err)
+	adHocOnly, err := repo.List(domain.ToolRunQuery{AdHocOnly: true})
+	require.NoError(t, err)
+	recent, err := repo.List(domain.ToolRunQuery{ToolID: tool.ID, Since: now.Add(-24 * time.Hour)})
+	require.NoError(t, err)
+	older, err := repo.List(domain.ToolRunQuery{Until: now.Add(-24 * time.Hour)})
+	require.NoError(t, err)
+	latest, err := repo.List(domain.ToolRunQuery{Limit: 1})
+	require.NoError(t, err)
+
+	// Then runs come back newest first
+	require.Len(t, all, 3)
+	assert.Equal(t, []string{adHoc.ID, today.ID, lastWeek.ID}, []string{all[0].ID, all[1].ID, all[2].ID})
+	assert.Len(t, byTool, 2)
+	require.Len(t, adHocOnly, 1)
+	assert.Equal(t, "console.log(2)", adHocOnly[0].Code)
+	require.Len(t, recent, 1)
+	assert.Equal(t, today.ID, recent[0].ID)
+	require.Len(t, older, 1)
+	assert.Equal(t, lastWeek.ID, older[0].ID)
+	require.Len(t, latest, 1)
+	assert.Equal(t, adHoc.ID, latest[0].ID)
+}
+
+func TestSQLiteToolRunRepository_UsageStats(t *testing.T) {
+	// Given a tool that ran three times, failing once, and an ad-hoc run
+	dbFile := "test_tool_runs_usage.db"
+	defer cleanupDatabase(dbFile)
+
+	repo, err := infrastructure.NewSQLiteToolRunRepository(dbFile)
+	require.NoError(t, err)
+	defer repo.Close()
+
+	tool := domain.NewTool("Greet", "console.log('hi')")
+	now := time.Now()
+	outputs := []domain.ToolRunOutput{
+		{Success: true, DurationMs: 10},
+		{Success: false, ExitCode: 1, DurationMs: 20},
+		{Success: true, DurationMs: 60},
+	}
+	for i, output := range outputs {
+		require.NoError(t, repo.Save(domain.NewToolRun(tool, 1, nil, output, now.Add(time.Duration(i-3)*time.Hour))))
+	}
+	require.NoError(t, repo.Save(domain.NewAdHocRun("console.log(1)", domain.ToolRunOutput{Success: true}, now)))
+
+	// When
+	stats, err := repo.UsageStats()
+
+	// Then the tool's runs are summarized, newest first, and ad-hoc runs are left out
+	require.NoError(t, err)
+	require.Len(t, stats, 1)
+	usage := stats[tool.ID]
+	assert.Equal(t, 3, usage.RunCount)
+	assert.Equal(t, 1, usage.FailureCount)
+	assert.InDelta(t, 1.0/3, usage.FailureRate, 0.001)
+	assert.InDelta(t, 30, usage.AverageDurationMs, 0.001)
+	require.Len(t, usage.RecentRuns, 3)
+	require.NotNil(t, usage.LastRunAt)
+	assert.True(t, usage.LastRunAt.Equal(now.Add(-time.Hour)))
+	assert.True(t, usage.RecentRuns[2].Equal(now.Add(-3*time.Hour)))
+}
@@ -0,0 +1,98 @@
+package infrastructure
+
+import (
+	"database/sql"
+	"fmt"
+
+	"lumina/backend/tool/domain"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// SQLiteToolSyncStateRepository stores the sync state of one tools
+// directory. Several directories can share a database; rows are keyed by
+// the directory.
+type SQLiteToolSyncStateRepository struct {
+	db        *sql.DB
+	directory string
+}
+
+func NewSQLiteToolSyncStateRepository(dbPath string, directory string) (*SQLiteToolSyncStateRepository, error) {
+	db, err := sql.Open("sqlite3", dbPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	createTableSQL := `
+	CREATE TABLE IF NOT EXISTS tool_sync_state (
+		directory TEXT NOT NULL,
+		tool_id TEXT NOT NULL,
+		path TEXT NOT NULL,
+		hash TEXT NOT NULL,
+		synced_at DATETIME NOT NULL,
+		PRIMARY KEY (directory, tool_id)
+	);
+	`
+
+	if _, err := db.Exec(createTableSQL); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create tool sync state table: %w", err)
+	}
+
+	return &SQLiteToolSyncStateRepository{db: db, directory: directory}, nil
+}
+
+func (r *SQLiteToolSyncStateRepository) List() ([]domain.ToolSyncState, error) {
+	query := `
+	SELECT tool_id, path, hash, synced_at
+	FROM tool_sync_state
+	WHERE directory = ?
+	ORDER BY path
+	`
+
+	rows, err := r.db.Query(query, r.directory)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tool sync state: %w", err)
+	}
+	defer rows.Close()
+
+	var states []domain.ToolSyncState
+	for rows.Next() {
+		var state domain.ToolSyncState
+		if err := rows.Scan(&state.ToolID, &state.Path, &state.Hash, &state.SyncedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan tool sync state: %w", err)
+		}
+		states = append(states, state)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating tool sync state: %w", err)
+	}
+
+	return states, nil
+}
+
+func (r *SQLiteToolSyncStateRepository) Save(state domain.ToolSyncState) error {
+	insertSQL := `
+	INSERT OR REPLACE INTO tool_sync_state (directory, tool_id, path, hash, synced_at)
+	VALUES (?, ?, ?, ?, ?)
+	`
+
+	if _, err := r.db.Exec(insertSQL, r.directory, state.ToolID, state.Path, state.Hash, state.SyncedAt); err != nil {
+		return fmt.Errorf("failed to save tool sync state: %w", err)
+	}
+
+	return nil
+}
+
+func (r *SQLiteToolSyncStateRepository) Delete(toolID string) error {
+	if _, err := r.db.Exec("DELETE FROM tool_sync_state WHERE directory = ? AND tool_id = ?", r.directory, toolID); err != nil {
+		return fmt.Errorf("failed to delete tool sync state: %w", err)
+	}
+
+	return nil
+}
+
+func (r *SQLiteToolSyncStateRepository) Close() error {
+	return r.db.Close()
+}
@@ -0,0 +1,45 @@
+package infrastructure_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"lumina/backend/tool/domain"
+	"lumina/backend/tool/infrastructure"
+)
+
+func TestSQLiteToolSyncStateRepository_SaveListDelete(t *testing.T) {
+	// Given two directories sharing a database
+	dbFile := "test_tool_sync_state.db"
+	defer cleanupDatabase(dbFile)
+
+	repo, err := infrastructure.NewSQLiteToolSyncStateRepository(dbFile, "/project-a/.lumina/tools")
+	require.NoError(t, err)
+	defer repo.Close()
+
+	other, err := infrastructure.NewSQLiteToolSyncStateRepository(dbFile, "/project-b/.lumina/tools")
+	require.NoError(t, err)
+	defer other.Close()
+
+	// When saving state for a tool, then saving it again
+	require.NoError(t, repo.Save(domain.ToolSyncState{ToolID: "tool-1", Path: "a.ts", Hash: "h1", SyncedAt: time.Now()}))
+	require.NoError(t, repo.Save(domain.ToolSyncState{ToolID: "tool-1", Path: "a.ts", Hash: "h2", SyncedAt: time.Now()}))
+
+	// Then only the latest state is kept, for that directory only
+	states, err := repo.List()
+	require.NoError(t, err)
+	require.Len(t, states, 1)
+	assert.Equal(t, "h2", states[0].Hash)
+
+	otherStates, err := other.List()
+	require.NoError(t, err)
+	assert.Empty(t, otherStates)
+
+	// And deleting it clears it
+	require.NoError(t, repo.Delete("tool-1"))
+	states, err = repo.List()
+	require.NoError(t, err)
+	assert.Empty(t, states)
+}
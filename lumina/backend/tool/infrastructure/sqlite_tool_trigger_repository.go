@@ -0,0 +1,189 @@
+package infrastructure
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"lumina/backend/tool/domain"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// triggerColumns are the columns read by scanTrigger, in order
+const triggerColumns = "id, tool_id, kind, glob, interval_seconds, arguments, enabled, last_run_at, last_output, last_exit_code, created_at, updated_at"
+
+type SQLiteToolTriggerRepository struct {
+	db *sql.DB
+}
+
+func NewSQLiteToolTriggerRepository(dbPath string) (*SQLiteToolTriggerRepository, error) {
+	db, err := sql.Open("sqlite3", dbPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	createTableSQL := `
+	CREATE TABLE IF NOT EXISTS tool_triggers (
+		id TEXT PRIMARY KEY,
+		tool_id TEXT NOT NULL,
+		kind TEXT NOT NULL,
+		glob TEXT NOT NULL DEFAULT '',
+		interval_seconds INTEGER NOT NULL DEFAULT 0,
+		arguments TEXT NOT NULL DEFAULT '{}',
+		enabled BOOLEAN NOT NULL,
+		last_run_at DATETIME,
+		last_output TEXT NOT NULL DEFAULT '',
+		last_exit_code INTEGER NOT NULL DEFAULT 0,
+		created_at DATETIME NOT NULL,
+		updated_at DATETIME NOT NULL
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_tool_triggers_tool ON tool_triggers(tool_id);
+	`
+
+	if _, err := db.Exec(createTableSQL); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create tool triggers table: %w", err)
+	}
+
+	return &SQLiteToolTriggerRepository{db: db}, nil
+}
+
+func (r *SQLiteToolTriggerRepository) Save(trigger domain.ToolTrigger) error {
+	arguments := trigger.Arguments
+	if arguments == nil {
+		arguments = map[string]interface{}{}
+	}
+	argumentsJSON, err := json.Marshal(arguments)
+	if err != nil {
+		return fmt.Errorf("failed to marshal trigger arguments: %w", err)
+	}
+
+	upsertSQL := `
+	INSERT INTO tool_triggers (` + triggerColumns + `)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	ON CONFLICT(id) DO UPDATE SET
+		kind = excluded.kind,
+		glob = excluded.glob,
+		interval_seconds = excluded.interval_seconds,
+		arguments = excluded.arguments,
+		enabled = excluded.enabled,
+		last_run_at = excluded.last_run_at,
+		last_output = excluded.last_output,
+		last_exit_code = excluded.last_exit_code,
+		updated_at = excluded.updated_at
+	`
+
+	_, err = r.db.Exec(upsertSQL,
+		trigger.ID,
+		trigger.ToolID,
+		string(trigger.Kind),
+		trigger.Glob,
+		trigger.IntervalSeconds,
+		string(argumentsJSON),
+		trigger.Enabled,
+		trigger.LastRunAt,
+		trigger.LastOutput,
+		trigger.LastExitCode,
+		trigger.CreatedAt,
+		trigger.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save tool trigger: %w", err)
+	}
+
+	return nil
+}
+
+func (r *SQLiteToolTriggerRepository) GetByID(id string) (domain.ToolTrigger, error) {
+	trigger, err := scanTrigger(r.db.QueryRow("SELECT "+triggerColumns+" FROM tool_triggers WHERE id = ?", id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.ToolTrigger{}, domain.ErrTriggerNotFound
+		}
+		return domain.ToolTrigger{}, fmt.Errorf("failed to get tool trigger: %w", err)
+	}
+
+	return trigger, nil
+}
+
+func (r *SQLiteToolTriggerRepository) ListByTool(toolID string) ([]domain.ToolTrigger, error) {
+	return r.query("SELECT "+triggerColumns+" FROM tool_triggers WHERE tool_id = ? ORDER BY created_at", toolID)
+}
+
+func (r *SQLiteToolTriggerRepository) List() ([]domain.ToolTrigger, error) {
+	return r.query("SELECT " + triggerColumns + " FROM tool_triggers ORDER BY created_at")
+}
+
+func (r *SQLiteToolTriggerRepository) query(query string, args ...interface{}) ([]domain.ToolTrigger, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tool triggers: %w", err)
+	}
+	defer rows.Close()
+
+	triggers := []domain.ToolTrigger{}
+	for rows.Next() {
+		trigger, err := scanTrigger(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan tool trigger: %w", err)
+		}
+		triggers = append(triggers, trigger)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating tool triggers: %w", err)
+	}
+
+	return triggers, nil
+}
+
+func (r *SQLiteToolTriggerRepository) Delete(id string) error {
+	result, err := r.db.Exec("DELETE FROM tool_triggers WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete tool trigger: %w", err)
+	}
+
+	return requireAffected(result, domain.ErrTriggerNotFound)
+}
+
+func (r *SQLiteToolTriggerRepository) Close() error {
+	return r.db.Close()
+}
+
+func scanTrigger(row rowScanner) (domain.ToolTrigger, error) {
+	var trigger domain.ToolTrigger
+	var kind, arguments string
+	var lastRunAt sql.NullTime
+
+	err := row.Scan(
+		&trigger.ID,
+		&trigger.ToolID,
+		&kind,
+		&trigger.Glob,
+		&trigger.IntervalSeconds,
+		&arguments,
+		&trigger.Enabled,
+		&lastRunAt,
+		&trigger.LastOutput,
+		&trigger.LastExitCode,
+		&trigger.CreatedAt,
+		&trigger.UpdatedAt,
+	)
+	if err != nil {
+		return domain.ToolTrigger{}, err
+	}
+
+	trigger.Kind = domain.TriggerKind(kind)
+	if lastRunAt.Valid {
+		trigger.LastRunAt = &lastRunAt.Time
+	}
+
+	if err := json.Unmarshal([]byte(arguments), &trigger.Arguments); err != nil {
+		return domain.ToolTrigger{}, fmt.Errorf("failed to unmarshal trigger arguments: %w", err)
+	}
+
+	return trigger, nil
+}
@@ -0,0 +1,78 @@
+package infrastructure_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"lumina/backend/tool/domain"
+	"lumina/backend/tool/infrastructure"
+)
+
+func TestSQLiteToolTriggerRepository_SaveAndList(t *testing.T) {
+	// Given a file trigger and an interval trigger of a tool
+	dbFile := "test_tool_triggers.db"
+	defer cleanupDatabase(dbFile)
+
+	repo, err := infrastructure.NewSQLiteToolTriggerRepository(dbFile)
+	require.NoError(t, err)
+	defer repo.Close()
+
+	tool, err := domain.NewToolWithValidation("Count TODOs", "console.log(1)")
+	require.NoError(t, err)
+
+	onChange, err := domain.NewToolTrigger(*tool, domain.TriggerFileChange, "src/**/*.ts", 0, nil)
+	require.NoError(t, err)
+	everyMinute, err := domain.NewToolTrigger(*tool, domain.TriggerInterval, "", 60, nil)
+	require.NoError(t, err)
+	everyMinute.CreatedAt = onChange.CreatedAt.Add(time.Second)
+
+	require.NoError(t, repo.Save(onChange))
+	require.NoError(t, repo.Save(everyMinute))
+
+	// When the interval trigger records a run
+	ranAt := time.Now()
+	require.NoError(t, repo.Save(everyMinute.WithResult(domain.ToolRunOutput{Output: "12\n", ExitCode: 0}, ranAt)))
+
+	// Then the triggers are listed oldest first with their last run
+	triggers, err := repo.ListByTool(tool.ID)
+	require.NoError(t, err)
+	require.Len(t, triggers, 2)
+	assert.Equal(t, domain.TriggerFileChange, triggers[0].Kind)
+	assert.Equal(t, "src/**/*.ts", triggers[0].Glob)
+	assert.Nil(t, triggers[0].LastRunAt)
+	assert.True(t, triggers[0].Enabled)
+	assert.Equal(t, 60, triggers[1].IntervalSeconds)
+	require.NotNil(t, triggers[1].LastRunAt)
+	assert.True(t, ranAt.Equal(*triggers[1].LastRunAt))
+	assert.Equal(t, "12\n", triggers[1].LastOutput)
+
+	all, err := repo.List()
+	require.NoError(t, err)
+	assert.Len(t, all, 2)
+}
+
+func TestSQLiteToolTriggerRepository_Delete(t *testing.T) {
+	// Given a trigger
+	dbFile := "test_tool_triggers_delete.db"
+	defer cleanupDatabase(dbFile)
+
+	repo, err := infrastructure.NewSQLiteToolTriggerRepository(dbFile)
+	require.NoError(t, err)
+	defer repo.Close()
+
+	tool, err := domain.NewToolWithValidation("Lint", "console.log(1)")
+	require.NoError(t, err)
+	trigger, err := domain.NewToolTrigger(*tool, domain.TriggerInterval, "", 30, nil)
+	require.NoError(t, err)
+	require.NoError(t, repo.Save(trigger))
+
+	// When
+	require.NoError(t, repo.Delete(trigger.ID))
+
+	// Then
+	_, err = repo.GetByID(trigger.ID)
+	assert.ErrorIs(t, err, domain.ErrTriggerNotFound)
+	assert.ErrorIs(t, repo.Delete(trigger.ID), domain.ErrTriggerNotFound)
+}
@@ -0,0 +1,254 @@
+package infrastructure
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"lumina/backend/tool/domain"
+	typescriptdomain "lumina/backend/typescript_execution/domain"
+)
+
+// TypeScriptToolRunner runs tools with the TypeScript executor, linking the
+// saved tools they import as modules. Tools in other languages run with the
+// executor registered for their language.
+type TypeScriptToolRunner struct {
+	executor      typescriptdomain.TypeScriptExecutor
+	executors     *typescriptdomain.ExecutorRegistry
+	repository    domain.ToolRepository
+	runRepository domain.ToolRunRepository
+}
+
+// NewTypeScriptToolRunner creates a runner for the tools in repository. Every
+// run is recorded in runRepository when it is not nil.
+func NewTypeScriptToolRunner(executor typescriptdomain.TypeScriptExecutor, repository domain.ToolRepository, runRepository domain.ToolRunRepository) *TypeScriptToolRunner {
+	return NewTypeScriptToolRunnerWithExecutors(executor, nil, repository, runRepository)
+}
+
+// NewTypeScriptToolRunnerWithExecutors creates a runner that also runs tools
+// in the languages of executors; without a registry, only TypeScript tools run
+func NewTypeScriptToolRunnerWithExecutors(executor typescriptdomain.TypeScriptExecutor, executors *typescriptdomain.ExecutorRegistry, repository domain.ToolRepository, runRepository domain.ToolRunRepository) *TypeScriptToolRunner {
+	return &TypeScriptToolRunner{
+		executor:      executor,
+		executors:     executors,
+		repository:    repository,
+		runRepository: runRepository,
+	}
+}
+
+func (r *TypeScriptToolRunner) Run(tool domain.Tool, args map[string]interface{}) (domain.ToolRunOutput, error) {
+	revision := r.currentRevision(tool)
+	started := time.Now()
+
+	var output domain.ToolRunOutput
+	var err error
+	if tool.IsTypeScript() {
+		output, err = r.runTypeScript(tool, revision, args)
+	} else {
+		output, err = r.runScript(tool, args)
+	}
+	if err != nil {
+		return domain.ToolRunOutput{}, err
+	}
+
+	if r.runRepository != nil {
+		r.record(domain.NewToolRun(tool, revision.Revision, args, output, started))
+	}
+	return output, nil
+}
+
+// runTypeScript runs a TypeScript tool with the tools it imports and the npm
+// packages of its revision
+func (r *TypeScriptToolRunner) runTypeScript(tool domain.Tool, revision domain.ToolRevision, args map[string]interface{}) (domain.ToolRunOutput, error) {
+	graph, err := domain.ResolveDependencies(tool, r.repository)
+	if err != nil {
+		return domain.ToolRunOutput{}, err
+	}
+
+	dependencies := toolDependencies(graph, revision)
+
+	var resolved map[string]string
+	output, err := r.execute(func() (*typescriptdomain.ExecutionResult, error) {
+		result, err := r.executor.Execute(tool.Code, typescriptdomain.ExecuteOptions{
+			Modules:     toolModules(graph),
+			Packages:    toolPackages(dependencies),
+			Args:        args,
+			Permissions: toolPermissions(tool),
+		})
+		if result != nil {
+			resolved = result.ResolvedPackages
+		}
+		return result, err
+	})
+	if err != nil {
+		return domain.ToolRunOutput{}, err
+	}
+
+	// The first run of a revision fixes the versions later runs install
+	if revision.Revision > 0 && len(revision.Dependencies) > 0 && revision.NeedsResolvedVersions() {
+		r.recordResolvedVersions(revision, resolved)
+	}
+	return output, nil
+}
+
+// runScript runs a tool in another language on its own
+func (r *TypeScriptToolRunner) runScript(tool domain.Tool, args map[string]interface{}) (domain.ToolRunOutput, error) {
+	language := typescriptdomain.Language(domain.NormalizeLanguage(tool.Language))
+	if r.executors == nil {
+		return domain.ToolRunOutput{}, fmt.Errorf("%w: %s", typescriptdomain.ErrUnsupportedLanguage, language)
+	}
+	executor, err := r.executors.Get(language)
+	if err != nil {
+		return domain.ToolRunOutput{}, err
+	}
+
+	permissions := toolPermissions(tool)
+	output, err := r.execute(func() (*typescriptdomain.ExecutionResult, error) {
+		return executor.Execute(tool.Code, typescriptdomain.ExecuteOptions{Args: args, Permissions: permissions})
+	})
+	// The run is refused like any access the tool does not declare, so
+	// that the user can grant the tool an unrestricted run
+	if errors.Is(err, typescriptdomain.ErrRestrictionUnsupported) {
+		return domain.ToolRunOutput{
+			Error:              fmt.Sprintf("%s tools can't run restricted; grant the tool %s access to run it\n", language, domain.CapabilityUnrestricted),
+			ExitCode:           1,
+			CapabilityRequests: []domain.CapabilityRequest{{Kind: domain.CapabilityUnrestricted}},
+		}, nil
+	}
+	return output, err
+}
+
+func (r *TypeScriptToolRunner) RunCode(code string) (domain.ToolRunOutput, error) {
+	started := time.Now()
+	output, err := r.execute(func() (*typescriptdomain.ExecutionResult, error) {
+		return r.executor.Execute(code, typescriptdomain.ExecuteOptions{})
+	})
+	if err != nil {
+		return domain.ToolRunOutput{}, err
+	}
+
+	if r.runRepository != nil {
+		r.record(domain.NewAdHocRun(code, output, started))
+	}
+	return output, nil
+}
+
+// execute times a call to the executor
+func (r *TypeScriptToolRunner) execute(run func() (*typescriptdomain.ExecutionResult, error)) (domain.ToolRunOutput, error) {
+	started := time.Now()
+	result, err := run()
+	if err != nil {
+		return domain.ToolRunOutput{}, fmt.Errorf("failed to execute tool: %w", err)
+	}
+
+	return domain.ToolRunOutput{
+		Output:             result.Output,
+		Error:              result.Error,
+		ExitCode:           result.ExitCode,
+		Success:            result.Success,
+		DurationMs:         time.Since(started).Milliseconds(),
+		CapabilityRequests: capabilityRequests(result.Denials),
+	}, nil
+}
+
+// currentRevision is the revision a run executes. Tools that were never
+// saved get a revision 0 holding their declared dependencies.
+func (r *TypeScriptToolRunner) currentRevision(tool domain.Tool) domain.ToolRevision {
+	revisions, err := r.repository.ListRevisions(tool.ID)
+	if err != nil || len(revisions) == 0 {
+		return domain.ToolRevision{ToolID: tool.ID, Dependencies: tool.Dependencies}
+	}
+	return revisions[0]
+}
+
+// recordResolvedVersions keeps the versions installed for the dependencies
+// of revision; a failure to record never fails the run
+func (r *TypeScriptToolRunner) recordResolvedVersions(revision domain.ToolRevision, installed map[string]string) {
+	versions := make(map[string]string)
+	for _, dependency := range revision.Dependencies {
+		if version, ok := installed[dependency.Name]; ok {
+			versions[dependency.Name] = version
+		}
+	}
+	if len(versions) == 0 {
+		return
+	}
+
+	if err := r.repository.RecordResolvedVersions(revision.ToolID, revision.Revision, versions); err != nil {
+		log.Printf("Warning: Failed to record resolved npm versions: %v", err)
+	}
+}
+
+// record stores the run; a failure to record never fails the run
+func (r *TypeScriptToolRunner) record(run domain.ToolRun) {
+	if err := r.runRepository.Save(run); err != nil {
+		log.Printf("Warning: Failed to record tool run: %v", err)
+	}
+}
+
+// toolDependencies are the npm packages of a tool and the tools it imports,
+// with the tool's own packages pinned to the versions of its revision
+func toolDependencies(graph domain.ToolDependencyGraph, revision domain.ToolRevision) []domain.NpmDependency {
+	lists := [][]domain.NpmDependency{revision.PinnedDependencies()}
+	for _, dependency := range graph.Dependencies() {
+		lists = append(lists, dependency.Dependencies)
+	}
+	return domain.MergeDependencies(lists...)
+}
+
+// toolPermissions restricts a run to the capabilities the tool declares;
+// tools declaring none or granted an unrestricted run are not restricted
+func toolPermissions(tool domain.Tool) typescriptdomain.Permissions {
+	if tool.Capabilities == nil || tool.Capabilities.Unrestricted {
+		return typescriptdomain.Permissions{}
+	}
+	return typescriptdomain.Permissions{
+		Restricted:   true,
+		ReadPaths:    tool.Capabilities.ReadPaths,
+		WritePaths:   tool.Capabilities.WritePaths,
+		Network:      tool.Capabilities.Network,
+		ChildProcess: tool.Capabilities.Subprocess,
+	}
+}
+
+// deniedCapabilities maps the permissions a run can be denied to the
+// capabilities that would have allowed them
+var deniedCapabilities = map[string]domain.CapabilityKind{
+	typescriptdomain.PermissionFileSystemRead:  domain.CapabilityFileRead,
+	typescriptdomain.PermissionFileSystemWrite: domain.CapabilityFileWrite,
+	typescriptdomain.PermissionChildProcess:    domain.CapabilitySubprocess,
+	typescriptdomain.PermissionWorkerThreads:   domain.CapabilitySubprocess,
+	typescriptdomain.PermissionNetwork:         domain.CapabilityNetwork,
+}
+
+func capabilityRequests(denials []typescriptdomain.PermissionDenial) []domain.CapabilityRequest {
+	var requests []domain.CapabilityRequest
+	for _, denial := range denials {
+		if kind, ok := deniedCapabilities[denial.Permission]; ok {
+			requests = append(requests, domain.CapabilityRequest{Kind: kind, Resource: denial.Resource})
+		}
+	}
+	return requests
+}
+
+func toolPackages(dependencies []domain.NpmDependency) []typescriptdomain.Package {
+	var packages []typescriptdomain.Package
+	for _, dependency := range dependencies {
+		packages = append(packages, typescriptdomain.Package{Name: dependency.Name, Version: dependency.Version})
+	}
+	return packages
+}
+
+// toolModules turns the tools imported by a tool into modules for the executor
+func toolModules(graph domain.ToolDependencyGraph) []typescriptdomain.Module {
+	var modules []typescriptdomain.Module
+	for _, dependency := range graph.Dependencies() {
+		node, _ := graph.Node(dependency.ID)
+		modules = append(modules, typescriptdomain.Module{
+			Specifiers: node.Specifiers,
+			Code:       dependency.Code,
+		})
+	}
+	return modules
+}
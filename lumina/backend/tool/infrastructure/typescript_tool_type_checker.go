@@ -0,0 +1,52 @@
+package infrastructure
+
+import (
+	"lumina/backend/tool/domain"
+	typescriptdomain "lumina/backend/typescript_execution/domain"
+)
+
+// TypeScriptToolTypeChecker type-checks tools with the TypeScript compiler,
+// linking the saved tools they import the way TypeScriptToolRunner does
+type TypeScriptToolTypeChecker struct {
+	checker    typescriptdomain.TypeChecker
+	repository domain.ToolRepository
+}
+
+func NewTypeScriptToolTypeChecker(checker typescriptdomain.TypeChecker, repository domain.ToolRepository) *TypeScriptToolTypeChecker {
+	return &TypeScriptToolTypeChecker{
+		checker:    checker,
+		repository: repository,
+	}
+}
+
+// Check reports an import that can't be resolved as a diagnostic without a
+// position, since the compiler would only see a missing module. Tools in
+// other languages have nothing to check.
+func (c *TypeScriptToolTypeChecker) Check(tool domain.Tool) ([]domain.ToolDiagnostic, error) {
+	if !tool.IsTypeScript() {
+		return []domain.ToolDiagnostic{}, nil
+	}
+
+	graph, err := domain.ResolveDependencies(tool, c.repository)
+	if err != nil {
+		return []domain.ToolDiagnostic{{Message: err.Error()}}, nil
+	}
+
+	// The code being checked may not be saved yet, so use its own declaration
+	dependencies := toolDependencies(graph, domain.ToolRevision{ToolID: tool.ID, Dependencies: tool.Dependencies})
+	found, err := c.checker.Check(tool.Code, toolModules(graph), toolPackages(dependencies))
+	if err != nil {
+		return nil, err
+	}
+
+	diagnostics := make([]domain.ToolDiagnostic, 0, len(found))
+	for _, diagnostic := range found {
+		diagnostics = append(diagnostics, domain.ToolDiagnostic{
+			Line:    diagnostic.Line,
+			Column:  diagnostic.Column,
+			Code:    diagnostic.Code,
+			Message: diagnostic.Message,
+		})
+	}
+	return diagnostics, nil
+}
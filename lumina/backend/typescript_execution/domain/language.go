@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+var ErrUnsupportedLanguage = errors.New("unsupported language")
+
+// Language is the language a snippet of code is written in
+type Language string
+
+const (
+	LanguageTypeScript Language = "typescript"
+	// LanguageShell is a POSIX shell script run by sh
+	LanguageShell  Language = "shell"
+	LanguagePython Language = "python"
+	// LanguageGo is a main package run with go run
+	LanguageGo Language = "go"
+)
+
+// ArgumentEnvPrefix prefixes the environment variables that hold each
+// argument of a run on its own, e.g. LUMINA_ARG_CITY, for languages where
+// decoding ArgumentsEnvVar is awkward
+const ArgumentEnvPrefix = "LUMINA_ARG_"
+
+// CodeExecutor runs code in one language. Modules and packages are
+// TypeScript's; executors of other languages refuse runs that have them.
+type CodeExecutor interface {
+	Execute(code string, opts ExecuteOptions) (*ExecutionResult, error)
+}
+
+// ExecutorRegistry finds the executor for a language
+type ExecutorRegistry struct {
+	executors map[Language]CodeExecutor
+}
+
+func NewExecutorRegistry() *ExecutorRegistry {
+	return &ExecutorRegistry{executors: make(map[Language]CodeExecutor)}
+}
+
+// Register makes executor run the code of language, replacing any executor
+// registered before
+func (r *ExecutorRegistry) Register(language Language, executor CodeExecutor) {
+	r.executors[language] = executor
+}
+
+// Get returns the executor of language or ErrUnsupportedLanguage
+func (r *ExecutorRegistry) Get(language Language) (CodeExecutor, error) {
+	executor, ok := r.executors[language]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedLanguage, language)
+	}
+	return executor, nil
+}
+
+// Languages returns the registered languages in alphabetical order
+func (r *ExecutorRegistry) Languages() []Language {
+	languages := make([]Language, 0, len(r.executors))
+	for language := range r.executors {
+		languages = append(languages, language)
+	}
+	sort.Slice(languages, func(i, j int) bool {
+		return languages[i] < languages[j]
+	})
+	return languages
+}
@@ -0,0 +1,38 @@
+package domain
+
+import "errors"
+
+// ErrRestrictionUnsupported is returned for restricted runs of code whose
+// executor can't enforce permissions
+var ErrRestrictionUnsupported = errors.New("restricted runs are not supported")
+
+// Names of the permissions a run can be denied, as reported by Node.js.
+// PermissionNetwork is Lumina's own, since Node.js has no network permission.
+const (
+	PermissionFileSystemRead  = "FileSystemRead"
+	PermissionFileSystemWrite = "FileSystemWrite"
+	PermissionChildProcess    = "ChildProcess"
+	PermissionWorkerThreads   = "WorkerThreads"
+	PermissionNetwork         = "Network"
+)
+
+// Permissions restrict what executed code may do. The zero value restricts
+// nothing. Restricted code runs in a private working directory, which it
+// may read and write, with a minimal environment.
+type Permissions struct {
+	Restricted bool
+	// ReadPaths and WritePaths are the files and directories, besides the
+	// working directory, the code may read or write
+	ReadPaths  []string
+	WritePaths []string
+	Network    bool
+	// ChildProcess allows starting processes and worker threads
+	ChildProcess bool
+}
+
+// PermissionDenial is an access a restricted run attempted and was refused
+type PermissionDenial struct {
+	Permission string `json:"permission"`
+	// Resource is the path or host:port that was refused; empty for processes
+	Resource string `json:"resource"`
+}
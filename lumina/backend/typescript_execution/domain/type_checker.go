@@ -0,0 +1,16 @@
+package domain
+
+// Diagnostic is one problem the TypeScript compiler found in the checked code
+type Diagnostic struct {
+	Line    int    `json:"line"`
+	Column  int    `json:"column"`
+	Code    string `json:"code"` // e.g. "TS2304"
+	Message string `json:"message"`
+}
+
+// TypeChecker type-checks TypeScript code without running it
+type TypeChecker interface {
+	// Check compiles code next to modules and packages, as Execute would run
+	// it, and returns the diagnostics of code itself
+	Check(code string, modules []Module, packages []Package) ([]Diagnostic, error)
+}
@@ -6,9 +6,47 @@ type ExecutionResult struct {
 	Error      string `json:"error"`
 	Success    bool   `json:"success"`
 	ExitCode   int    `json:"exitCode"`
+	// ResolvedPackages maps each npm package of the run to the version installed
+	ResolvedPackages map[string]string `json:"resolvedPackages,omitempty"`
+	// Denials are the accesses refused to a run with restricted permissions
+	Denials []PermissionDenial `json:"denials,omitempty"`
+}
+
+// ArgumentsEnvVar is the environment variable holding the JSON-encoded
+// arguments of a run, e.g. JSON.parse(process.env.LUMINA_ARGS ?? "{}")
+const ArgumentsEnvVar = "LUMINA_ARGS"
+
+// Module is an extra source file the executed code can import
+type Module struct {
+	// Specifiers are the import paths that refer to the module, such as
+	// "lumina:tools/json-helpers"
+	Specifiers []string `json:"specifiers"`
+	Code       string   `json:"code"`
+}
+
+// Package is an npm package installed for a run
+type Package struct {
+	Name string `json:"name"`
+	// Version is anything npm install accepts after the name, including a
+	// file: path to a tarball
+	Version string `json:"version"`
+}
+
+// ExecuteOptions are what a run gets besides its code; the zero value runs
+// the code on its own, unrestricted
+type ExecuteOptions struct {
+	// Modules are source files code and modules may import by their specifiers
+	Modules []Module
+	// Packages are the npm packages code and modules may import
+	Packages []Package
+	// Args are available in ArgumentsEnvVar
+	Args map[string]interface{}
+	// Permissions restrict what the run may access
+	Permissions Permissions
 }
 
 // TypeScriptExecutor defines the interface for executing TypeScript code
 type TypeScriptExecutor interface {
-	Execute(code string) (*ExecutionResult, error)
+	// Execute compiles and runs code with opts
+	Execute(code string, opts ExecuteOptions) (*ExecutionResult, error)
 }
\ No newline at end of file
@@ -0,0 +1,38 @@
+package infrastructure
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	typescriptdomain "lumina/backend/typescript_execution/domain"
+)
+
+// TypeScriptCompiler is the compiler package restricted runs and checks use
+var TypeScriptCompiler = typescriptCompiler
+
+// SeedPackageSet stores files as the installed set of packages in the cache
+// at root, so tests run without npm or network access. Paths in files are
+// relative to the set's node_modules.
+func SeedPackageSet(root string, packages []typescriptdomain.Package, files map[string]string) error {
+	setDir := filepath.Join(root, "sets", packageSetKey(packages))
+	for name, content := range files {
+		path := filepath.Join(setDir, "node_modules", filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			return err
+		}
+	}
+
+	resolved := make(map[string]string, len(packages))
+	for _, pkg := range packages {
+		resolved[pkg.Name] = pkg.Version
+	}
+	encoded, err := json.Marshal(resolved)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(setDir, installedManifest), encoded, 0644)
+}
@@ -0,0 +1,51 @@
+// Globals available to Lumina tools, which run on Node.js through ts-node.
+// Only what tools commonly use is declared; a tool that needs the full
+// Node.js API declares @types/node as a dependency instead.
+
+declare var process: {
+  env: Record<string, string | undefined>;
+  argv: string[];
+  exitCode: number | undefined;
+  cwd(): string;
+  exit(code?: number): never;
+  stdout: { write(chunk: string): boolean };
+  stderr: { write(chunk: string): boolean };
+  stdin: any;
+};
+
+declare var console: {
+  log(...data: any[]): void;
+  info(...data: any[]): void;
+  warn(...data: any[]): void;
+  error(...data: any[]): void;
+  debug(...data: any[]): void;
+  table(data: any): void;
+};
+
+declare var Buffer: any;
+declare var module: any;
+declare var __dirname: string;
+declare var __filename: string;
+declare function require(id: string): any;
+
+declare function setTimeout(callback: (...args: any[]) => void, ms?: number, ...args: any[]): any;
+declare function clearTimeout(handle: any): void;
+declare function setInterval(callback: (...args: any[]) => void, ms?: number, ...args: any[]): any;
+declare function clearInterval(handle: any): void;
+declare function fetch(input: string, init?: any): Promise<any>;
+
+// Node.js built-in modules import as untyped without @types/node
+declare module "node:*";
+declare module "fs";
+declare module "fs/promises";
+declare module "path";
+declare module "os";
+declare module "child_process";
+declare module "crypto";
+declare module "http";
+declare module "https";
+declare module "url";
+declare module "util";
+declare module "readline";
+declare module "stream";
+declare module "events";
@@ -0,0 +1,30 @@
+// Preloaded into runs without network access, since the Node.js permission
+// model has no network permission. Every refused connection is reported on
+// stderr, even when the tool catches the error.
+import dgram from "node:dgram";
+import net from "node:net";
+
+const deny = (resource) => {
+  process.stderr.write(`lumina:access-denied ${JSON.stringify({ permission: "Network", resource })}\n`);
+  const error = new Error("Access to this API has been restricted");
+  error.code = "ERR_ACCESS_DENIED";
+  error.permission = "Network";
+  error.resource = resource;
+  return error;
+};
+
+const connect = net.Socket.prototype.connect;
+net.Socket.prototype.connect = function (...args) {
+  // net.connect passes its normalized arguments as a single array
+  const first = Array.isArray(args[0]) ? args[0][0] : args[0];
+  const options = typeof first === "object" && first !== null ? first : { port: first, host: args[1] };
+  if (options.path) {
+    // Unix sockets and named pipes are files
+    return connect.apply(this, args);
+  }
+  throw deny(`${options.host ?? "localhost"}:${options.port}`);
+};
+
+dgram.createSocket = () => {
+  throw deny("udp");
+};
@@ -0,0 +1,19 @@
+// Transpiles the .ts files given after the path of the typescript package to
+// .js files next to them, without type-checking. Only syntax errors fail.
+const fs = require("node:fs");
+const ts = require(process.argv[2]);
+
+for (const file of process.argv.slice(3)) {
+  const output = ts.transpileModule(fs.readFileSync(file, "utf8"), {
+    fileName: file,
+    reportDiagnostics: true,
+    compilerOptions: { module: ts.ModuleKind.ES2022, target: ts.ScriptTarget.ES2022 },
+  });
+  for (const diagnostic of output.diagnostics ?? []) {
+    const { line, character } = diagnostic.file.getLineAndCharacterOfPosition(diagnostic.start ?? 0);
+    const message = ts.flattenDiagnosticMessageText(diagnostic.messageText, "\n");
+    console.error(`${file}(${line + 1},${character + 1}): error TS${diagnostic.code}: ${message}`);
+    process.exitCode = 1;
+  }
+  fs.writeFileSync(file.replace(/\.ts$/, ".js"), output.outputText);
+}
@@ -0,0 +1,135 @@
+package infrastructure
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	typescriptdomain "lumina/backend/typescript_execution/domain"
+)
+
+// networkGuard refuses connections in runs without network access
+//
+//go:embed lumina_network_guard.mjs
+var networkGuard string
+
+// transpileScript turns the TypeScript of a restricted run into JavaScript
+//
+//go:embed lumina_transpile.cjs
+var transpileScript string
+
+const (
+	networkGuardFile    = "lumina_network_guard.mjs"
+	transpileScriptFile = "lumina_transpile.cjs"
+	// denialMarker prefixes the lines networkGuard writes to stderr
+	denialMarker = "lumina:access-denied "
+)
+
+// nodeDenialPattern matches the properties Node.js prints for an uncaught
+// ERR_ACCESS_DENIED error
+var nodeDenialPattern = regexp.MustCompile(`code: 'ERR_ACCESS_DENIED',\s*permission: '([^']*)',\s*resource: '([^']*)'`)
+
+var (
+	permissionFlagsOnce sync.Once
+	permissionFlags     []string
+	permissionFlagsErr  error
+)
+
+// nodePermissionFlags returns the flags enabling the permission model of the
+// installed Node.js: --permission since 22, --experimental-permission before
+func nodePermissionFlags() ([]string, error) {
+	permissionFlagsOnce.Do(func() {
+		for _, flag := range []string{"--permission", "--experimental-permission"} {
+			flags := []string{flag, "--disable-warning=ExperimentalWarning"}
+			if exec.Command("node", append(flags, "-e", "")...).Run() == nil {
+				permissionFlags = flags
+				return
+			}
+		}
+		permissionFlagsErr = fmt.Errorf("the installed Node.js has no permission model; restricted tools need Node.js 20.11 or later")
+	})
+	return permissionFlags, permissionFlagsErr
+}
+
+// nodePermissionArgs are the node arguments granting permissions to a run
+// in runDir; extraReads are further paths the run itself needs, such as its
+// npm packages. Writing a path implies reading it.
+func nodePermissionArgs(runDir string, extraReads []string, permissions typescriptdomain.Permissions) []string {
+	var args []string
+	for _, path := range append(append([]string{runDir}, extraReads...), append(permissions.ReadPaths, permissions.WritePaths...)...) {
+		args = append(args, "--allow-fs-read="+realPath(path))
+	}
+	for _, path := range append([]string{runDir}, permissions.WritePaths...) {
+		args = append(args, "--allow-fs-write="+realPath(path))
+	}
+	if permissions.ChildProcess {
+		args = append(args, "--allow-child-process", "--allow-worker")
+	}
+	if !permissions.Network {
+		args = append(args, "--import", "./"+networkGuardFile)
+	}
+	return args
+}
+
+// restrictedEnvVars are the variables of the Lumina process a restricted
+// node run inherits, so that API keys and other secrets stay out
+var restrictedEnvVars = []string{
+	"PATH", "HOME", "LANG", "LC_ALL", "TZ", "TMPDIR",
+	"SYSTEMROOT", "USERPROFILE", "TEMP", "TMP",
+}
+
+// restrictedEnv is the environment of a restricted run: restrictedEnvVars
+// and then extra
+func restrictedEnv(extra ...string) []string {
+	var env []string
+	for _, name := range restrictedEnvVars {
+		if value, ok := os.LookupEnv(name); ok {
+			env = append(env, name+"="+value)
+		}
+	}
+	return append(env, extra...)
+}
+
+// realPath resolves symlinks, since Node.js checks permissions on resolved
+// paths; paths that don't exist yet are kept as they are
+func realPath(path string) string {
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		return resolved
+	}
+	return path
+}
+
+// parseDenials finds the accesses refused to a run in its stderr and removes
+// the lines networkGuard wrote
+func parseDenials(stderr string) (string, []typescriptdomain.PermissionDenial) {
+	var denials []typescriptdomain.PermissionDenial
+	seen := make(map[typescriptdomain.PermissionDenial]bool)
+	add := func(denial typescriptdomain.PermissionDenial) {
+		if !seen[denial] {
+			seen[denial] = true
+			denials = append(denials, denial)
+		}
+	}
+
+	var kept []string
+	for _, line := range strings.SplitAfter(stderr, "\n") {
+		encoded, isMarker := strings.CutPrefix(line, denialMarker)
+		var denial typescriptdomain.PermissionDenial
+		if isMarker && json.Unmarshal([]byte(encoded), &denial) == nil {
+			add(denial)
+			continue
+		}
+		kept = append(kept, line)
+	}
+
+	for _, match := range nodeDenialPattern.FindAllStringSubmatch(stderr, -1) {
+		add(typescriptdomain.PermissionDenial{Permission: match[1], Resource: match[2]})
+	}
+	return strings.Join(kept, ""), denials
+}
@@ -0,0 +1,175 @@
+package infrastructure
+
+import (
+	"testing"
+
+	typescriptdomain "lumina/backend/typescript_execution/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNodePermissionArgs(t *testing.T) {
+	tests := []struct {
+		name        string
+		extraReads  []string
+		permissions typescriptdomain.Permissions
+		args        []string
+	}{
+		{
+			name:        "nothing granted",
+			permissions: typescriptdomain.Permissions{Restricted: true},
+			args: []string{
+				"--allow-fs-read=/run",
+				"--allow-fs-write=/run",
+				"--import", "./lumina_network_guard.mjs",
+			},
+		},
+		{
+			name:        "npm packages",
+			extraReads:  []string{"/cache/node_modules"},
+			permissions: typescriptdomain.Permissions{Restricted: true},
+			args: []string{
+				"--allow-fs-read=/run",
+				"--allow-fs-read=/cache/node_modules",
+				"--allow-fs-write=/run",
+				"--import", "./lumina_network_guard.mjs",
+			},
+		},
+		{
+			name: "paths",
+			permissions: typescriptdomain.Permissions{
+				Restricted: true,
+				ReadPaths:  []string{"/data/in"},
+				WritePaths: []string{"/data/out"},
+			},
+			args: []string{
+				"--allow-fs-read=/run",
+				"--allow-fs-read=/data/in",
+				"--allow-fs-read=/data/out",
+				"--allow-fs-write=/run",
+				"--allow-fs-write=/data/out",
+				"--import", "./lumina_network_guard.mjs",
+			},
+		},
+		{
+			name:        "network",
+			permissions: typescriptdomain.Permissions{Restricted: true, Network: true},
+			args: []string{
+				"--allow-fs-read=/run",
+				"--allow-fs-write=/run",
+			},
+		},
+		{
+			name:        "processes",
+			permissions: typescriptdomain.Permissions{Restricted: true, ChildProcess: true},
+			args: []string{
+				"--allow-fs-read=/run",
+				"--allow-fs-write=/run",
+				"--allow-child-process", "--allow-worker",
+				"--import", "./lumina_network_guard.mjs",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// When
+			args := nodePermissionArgs("/run", tt.extraReads, tt.permissions)
+
+			// Then
+			assert.Equal(t, tt.args, args)
+		})
+	}
+}
+
+// nodeReadDenial is what Node.js 20 prints for a read outside the granted paths
+const nodeReadDenial = `node:internal/fs/utils:347
+    throw err;
+    ^
+
+Error: Access to this API has been restricted
+    at Object.openSync (node:fs:581:3)
+    at Object.readFileSync (node:fs:457:35)
+    at file:///tmp/lumina-ts-exec/run_1/code.js:2:13 {
+  code: 'ERR_ACCESS_DENIED',
+  permission: 'FileSystemRead',
+  resource: '/etc/hostname'
+}
+
+Node.js v20.19.5
+`
+
+func TestParseDenials(t *testing.T) {
+	tests := []struct {
+		name    string
+		stderr  string
+		kept    string
+		denials []typescriptdomain.PermissionDenial
+	}{
+		{
+			name:   "no denials",
+			stderr: "warning: slow\n",
+			kept:   "warning: slow\n",
+		},
+		{
+			name:    "node error",
+			stderr:  nodeReadDenial,
+			kept:    nodeReadDenial,
+			denials: []typescriptdomain.PermissionDenial{{Permission: "FileSystemRead", Resource: "/etc/hostname"}},
+		},
+		{
+			name: "child process",
+			stderr: "Error: Access to this API has been restricted\n" +
+				"    at ChildProcess.spawn (node:internal/child_process:421:28) {\n" +
+				"  code: 'ERR_ACCESS_DENIED',\n" +
+				"  permission: 'ChildProcess',\n" +
+				"  resource: ''\n" +
+				"}\n",
+			kept: "Error: Access to this API has been restricted\n" +
+				"    at ChildProcess.spawn (node:internal/child_process:421:28) {\n" +
+				"  code: 'ERR_ACCESS_DENIED',\n" +
+				"  permission: 'ChildProcess',\n" +
+				"  resource: ''\n" +
+				"}\n",
+			denials: []typescriptdomain.PermissionDenial{{Permission: "ChildProcess", Resource: ""}},
+		},
+		{
+			name: "network guard lines are removed and repeats dropped",
+			stderr: "lumina:access-denied {\"permission\":\"Network\",\"resource\":\"example.com:443\"}\n" +
+				"fetch failed\n" +
+				"lumina:access-denied {\"permission\":\"Network\",\"resource\":\"example.com:443\"}\n",
+			kept:    "fetch failed\n",
+			denials: []typescriptdomain.PermissionDenial{{Permission: "Network", Resource: "example.com:443"}},
+		},
+		{
+			name:   "malformed guard line is kept",
+			stderr: "lumina:access-denied {oops\n",
+			kept:   "lumina:access-denied {oops\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// When
+			kept, denials := parseDenials(tt.stderr)
+
+			// Then
+			assert.Equal(t, tt.kept, kept)
+			assert.Equal(t, tt.denials, denials)
+		})
+	}
+}
+
+func TestRestrictedEnv(t *testing.T) {
+	// Given
+	t.Setenv("OPENAI_API_KEY", "sk-secret")
+	t.Setenv("LANG", "en_US.UTF-8")
+
+	// When
+	env := restrictedEnv("LUMINA_ARGS={}")
+
+	// Then secrets stay out and extra variables come last
+	assert.Contains(t, env, "LANG=en_US.UTF-8")
+	assert.NotContains(t, env, "OPENAI_API_KEY=sk-secret")
+	assert.Equal(t, "LUMINA_ARGS={}", env[len(env)-1])
+}
@@ -1,6 +1,7 @@
 package infrastructure
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
@@ -13,68 +14,176 @@ import (
 
 // NodeTypeScriptExecutor implements TypeScriptExecutor using Node.js
 type NodeTypeScriptExecutor struct {
-	tempDir string
+	tempDir  string
+	packages *NpmPackageCache
 }
 
 // NewNodeTypeScriptExecutor creates a new Node-based TypeScript executor
 func NewNodeTypeScriptExecutor() *NodeTypeScriptExecutor {
+	return NewNodeTypeScriptExecutorWithPackages(nil)
+}
+
+// NewNodeTypeScriptExecutorWithPackages creates an executor that installs the
+// npm packages of a run from packages; without a cache, runs needing
+// packages fail
+func NewNodeTypeScriptExecutorWithPackages(packages *NpmPackageCache) *NodeTypeScriptExecutor {
 	tempDir, err := os.MkdirTemp("", "lumina-ts-exec-*")
 	if err != nil {
 		tempDir = os.TempDir()
 	}
 
 	return &NodeTypeScriptExecutor{
-		tempDir: tempDir,
+		tempDir:  tempDir,
+		packages: packages,
 	}
 }
 
-// Execute compiles and runs TypeScript code using Node.js
-func (e *NodeTypeScriptExecutor) Execute(code string) (*typescriptdomain.ExecutionResult, error) {
-	// Create a temporary file for the TypeScript code
-	tsFile := filepath.Join(e.tempDir, fmt.Sprintf("code_%d.ts", time.Now().UnixNano()))
+// Execute compiles and runs TypeScript code using Node.js, with the
+// arguments passed as JSON in the LUMINA_ARGS environment variable. Each
+// module is written to its own file in the run directory and the imports of
+// its specifiers are rewritten to that file; the run directory's
+// node_modules is linked to the cached install of the packages. Restricted
+// code is transpiled to JavaScript and run by node under its permission
+// model, in the run directory.
+func (e *NodeTypeScriptExecutor) Execute(code string, opts typescriptdomain.ExecuteOptions) (*typescriptdomain.ExecutionResult, error) {
+	args := opts.Args
+	if args == nil {
+		args = map[string]interface{}{}
+	}
+	encodedArgs, err := json.Marshal(args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode arguments: %w", err)
+	}
+
+	// Create a directory for this run so its modules can't clash with another run
+	runDir, err := os.MkdirTemp(e.tempDir, fmt.Sprintf("run_%d_*", time.Now().UnixNano()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create run directory: %w", err)
+	}
+	defer os.RemoveAll(runDir)
+
+	var nodeModules string
+	var resolvedPackages map[string]string
+	if len(opts.Packages) > 0 {
+		if e.packages == nil {
+			return nil, fmt.Errorf("npm packages are not available: no package cache configured")
+		}
+
+		installed, resolved, err := e.packages.Provision(opts.Packages)
+		if err != nil {
+			return nil, fmt.Errorf("failed to install npm packages: %w", err)
+		}
+		if err := os.Symlink(installed, filepath.Join(runDir, "node_modules")); err != nil {
+			return nil, fmt.Errorf("failed to link npm packages: %w", err)
+		}
+		nodeModules = installed
+		resolvedPackages = resolved
+	}
+
+	linker := moduleLinker(opts.Modules)
+	sourceFiles := []string{"code.ts"}
+	for i, module := range opts.Modules {
+		moduleFile := filepath.Join(runDir, moduleFileName(i)+".ts")
+		if err := os.WriteFile(moduleFile, []byte(linker.Replace(module.Code)), 0644); err != nil {
+			return nil, fmt.Errorf("failed to write TypeScript module: %w", err)
+		}
+		sourceFiles = append(sourceFiles, moduleFileName(i)+".ts")
+	}
 
 	// Write the TypeScript code to the file
-	err := os.WriteFile(tsFile, []byte(code), 0644)
+	tsFile := filepath.Join(runDir, "code.ts")
+	err = os.WriteFile(tsFile, []byte(linker.Replace(code)), 0644)
 	if err != nil {
 		return nil, fmt.Errorf("failed to write TypeScript file: %w", err)
 	}
-	defer os.Remove(tsFile)
+
+	if opts.Permissions.Restricted {
+		var extraReads []string
+		if nodeModules != "" {
+			extraReads = append(extraReads, nodeModules)
+		}
+		result, err := e.runRestricted(runDir, sourceFiles, extraReads, string(encodedArgs), opts.Permissions)
+		if err != nil {
+			return nil, err
+		}
+		result.ResolvedPackages = resolvedPackages
+		return result, nil
+	}
 
 	// Prepare the Node.js execution command
 	// We'll use ts-node via npx to execute TypeScript directly
 	cmd := exec.Command("npx", "ts-node", "--esm", tsFile)
-	cmd.Dir = e.tempDir
-
-	// Capture stdout and stderr
-	var stdout, stderr strings.Builder
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
+	cmd.Dir = runDir
+	cmd.Env = append(os.Environ(), typescriptdomain.ArgumentsEnvVar+"="+string(encodedArgs))
 
-	// Execute the command
-	err = cmd.Run()
+	result := runProcess(cmd)
+	result.ResolvedPackages = resolvedPackages
+	return result, nil
+}
 
-	// Prepare the result
-	result := &typescriptdomain.ExecutionResult{
-		Output:   stdout.String(),
-		Error:    stderr.String(),
-		ExitCode: 0,
+// runRestricted transpiles the source files of runDir and runs code.js with
+// node, allowed only what permissions and extraReads grant
+func (e *NodeTypeScriptExecutor) runRestricted(runDir string, sourceFiles, extraReads []string, encodedArgs string, permissions typescriptdomain.Permissions) (*typescriptdomain.ExecutionResult, error) {
+	flags, err := nodePermissionFlags()
+	if err != nil {
+		return nil, err
 	}
-
+	if e.packages == nil {
+		return nil, fmt.Errorf("TypeScript compiler not available: no package cache configured")
+	}
+	compiler, _, err := e.packages.Provision([]typescriptdomain.Package{typescriptCompiler})
 	if err != nil {
-		if exitError, ok := err.(*exec.ExitError); ok {
-			result.ExitCode = exitError.ExitCode()
-		} else {
-			result.Error = fmt.Sprintf("Execution error: %v\n%s", err, result.Error)
-			result.ExitCode = 1
+		return nil, fmt.Errorf("failed to install the TypeScript compiler: %w", err)
+	}
+
+	files := map[string]string{
+		transpileScriptFile: transpileScript,
+		networkGuardFile:    networkGuard,
+		"package.json":      `{"type": "module"}`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(runDir, name), []byte(content), 0644); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", name, err)
 		}
-		result.Success = false
-	} else {
-		result.Success = true
 	}
 
+	// A syntax error is reported like a failed run
+	transpile := exec.Command("node", append([]string{transpileScriptFile, filepath.Join(compiler, "typescript")}, sourceFiles...)...)
+	transpile.Dir = runDir
+	if result := runProcess(transpile); !result.Success {
+		return result, nil
+	}
+
+	nodeArgs := append(append(flags, nodePermissionArgs(runDir, extraReads, permissions)...), "code.js")
+	cmd := exec.Command("node", nodeArgs...)
+	cmd.Dir = runDir
+	cmd.Env = restrictedEnv(typescriptdomain.ArgumentsEnvVar + "=" + encodedArgs)
+
+	result := runProcess(cmd)
+	result.Error, result.Denials = parseDenials(result.Error)
 	return result, nil
 }
 
+// moduleLinker rewrites quoted module specifiers to the files written for
+// them; ts-node resolves the .js extension to the .ts file
+func moduleLinker(modules []typescriptdomain.Module) *strings.Replacer {
+	var pairs []string
+	for i, module := range modules {
+		path := "./" + moduleFileName(i) + ".js"
+		for _, specifier := range module.Specifiers {
+			pairs = append(pairs,
+				`"`+specifier+`"`, `"`+path+`"`,
+				`'`+specifier+`'`, `'`+path+`'`,
+			)
+		}
+	}
+	return strings.NewReplacer(pairs...)
+}
+
+func moduleFileName(index int) string {
+	return fmt.Sprintf("module_%d", index)
+}
+
 // Cleanup cleans up temporary files
 func (e *NodeTypeScriptExecutor) Cleanup() {
 	if e.tempDir != "" {
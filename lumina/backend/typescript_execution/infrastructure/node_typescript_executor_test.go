@@ -0,0 +1,39 @@
+package infrastructure
+
+import (
+	"testing"
+
+	typescriptdomain "lumina/backend/typescript_execution/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestModuleLinker(t *testing.T) {
+	// Given two modules, one known by two specifiers
+	linker := moduleLinker([]typescriptdomain.Module{
+		{Specifiers: []string{"lumina:tools/json-helpers", "lumina:tools/JSON Helpers"}, Code: "export {}"},
+		{Specifiers: []string{"lumina:tools/slugify"}, Code: "export {}"},
+	})
+
+	tests := []struct {
+		name   string
+		code   string
+		linked string
+	}{
+		{"double quotes", `import { parse } from "lumina:tools/json-helpers";`, `import { parse } from "./module_0.js";`},
+		{"single quotes", `import { parse } from 'lumina:tools/JSON Helpers';`, `import { parse } from './module_0.js';`},
+		{"second module", `import slugify from "lumina:tools/slugify";`, `import slugify from "./module_1.js";`},
+		{"unknown tool", `import x from "lumina:tools/missing";`, `import x from "lumina:tools/missing";`},
+		{"prefix of a specifier", `const name = "lumina:tools/slug";`, `const name = "lumina:tools/slug";`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// When
+			linked := linker.Replace(tt.code)
+
+			// Then
+			assert.Equal(t, tt.linked, linked)
+		})
+	}
+}
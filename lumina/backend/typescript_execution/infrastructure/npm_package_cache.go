@@ -0,0 +1,196 @@
+package infrastructure
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	typescriptdomain "lumina/backend/typescript_execution/domain"
+)
+
+// installedManifest marks a finished install and records what it resolved to
+const installedManifest = "lumina-installed.json"
+
+// NpmPackageCache installs npm packages into directories addressed by a hash
+// of the requested set, so every run asking for the same packages shares one
+// node_modules. Downloads go through an npm cache kept in the same root, which
+// lets sets be installed again without network access.
+type NpmPackageCache struct {
+	root     string
+	registry string
+	offline  bool
+	mu       sync.Mutex
+}
+
+// NewNpmPackageCache creates a cache in root. registry, when set, is used
+// instead of the public registry (e.g. a local mirror); offline makes npm
+// install only from what is already cached or from file: tarballs.
+func NewNpmPackageCache(root, registry string, offline bool) *NpmPackageCache {
+	return &NpmPackageCache{
+		root:     root,
+		registry: registry,
+		offline:  offline,
+	}
+}
+
+// Provision returns a node_modules directory holding packages and the
+// version installed for each of them
+func (c *NpmPackageCache) Provision(packages []typescriptdomain.Package) (string, map[string]string, error) {
+	setDir := filepath.Join(c.root, "sets", packageSetKey(packages))
+	if resolved, err := readInstalled(setDir); err == nil {
+		return filepath.Join(setDir, "node_modules"), resolved, nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Another run may have installed the set while we waited
+	if resolved, err := readInstalled(setDir); err == nil {
+		return filepath.Join(setDir, "node_modules"), resolved, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(setDir), 0755); err != nil {
+		return "", nil, fmt.Errorf("failed to create package cache: %w", err)
+	}
+
+	// Install next to the set and move it in place once complete, so a
+	// failed install never leaves a half-filled set behind
+	stagingDir, err := os.MkdirTemp(filepath.Dir(setDir), "staging-*")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create package staging directory: %w", err)
+	}
+	defer os.RemoveAll(stagingDir)
+
+	resolved, err := c.install(stagingDir, packages)
+	if err != nil {
+		return "", nil, err
+	}
+
+	os.RemoveAll(setDir)
+	if err := os.Rename(stagingDir, setDir); err != nil {
+		return "", nil, fmt.Errorf("failed to store installed packages: %w", err)
+	}
+
+	// Later runs pinned to the resolved versions reuse this install
+	pinned := make([]typescriptdomain.Package, 0, len(packages))
+	for _, pkg := range packages {
+		if version, ok := resolved[pkg.Name]; ok && !strings.HasPrefix(pkg.Version, "file:") {
+			pkg.Version = version
+		}
+		pinned = append(pinned, pkg)
+	}
+	pinnedDir := filepath.Join(c.root, "sets", packageSetKey(pinned))
+	if pinnedDir != setDir {
+		if _, err := os.Lstat(pinnedDir); errors.Is(err, fs.ErrNotExist) {
+			os.Symlink(setDir, pinnedDir)
+		}
+	}
+
+	return filepath.Join(setDir, "node_modules"), resolved, nil
+}
+
+// install runs npm install for packages in dir and reads the installed versions
+func (c *NpmPackageCache) install(dir string, packages []typescriptdomain.Package) (map[string]string, error) {
+	dependencies := make(map[string]string, len(packages))
+	for _, pkg := range packages {
+		dependencies[pkg.Name] = pkg.Version
+	}
+
+	manifest, err := json.MarshalIndent(map[string]interface{}{
+		"name":         "lumina-tool-packages",
+		"private":      true,
+		"dependencies": dependencies,
+	}, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode package.json: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "package.json"), manifest, 0644); err != nil {
+		return nil, fmt.Errorf("failed to write package.json: %w", err)
+	}
+
+	// Install scripts are skipped: tools run with the user's permissions
+	args := []string{"install", "--no-audit", "--no-fund", "--ignore-scripts", "--prefer-offline",
+		"--cache", filepath.Join(c.root, "npm")}
+	if c.offline {
+		args = append(args, "--offline")
+	}
+	if c.registry != "" {
+		args = append(args, "--registry", c.registry)
+	}
+
+	cmd := exec.Command("npm", args...)
+	cmd.Dir = dir
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("npm install failed: %w\n%s", err, stderr.String())
+	}
+
+	resolved := make(map[string]string, len(packages))
+	for _, pkg := range packages {
+		version, err := installedVersion(dir, pkg.Name)
+		if err != nil {
+			return nil, err
+		}
+		resolved[pkg.Name] = version
+	}
+
+	encoded, err := json.Marshal(resolved)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode installed versions: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, installedManifest), encoded, 0644); err != nil {
+		return nil, fmt.Errorf("failed to write installed versions: %w", err)
+	}
+
+	return resolved, nil
+}
+
+func installedVersion(dir, name string) (string, error) {
+	content, err := os.ReadFile(filepath.Join(dir, "node_modules", filepath.FromSlash(name), "package.json"))
+	if err != nil {
+		return "", fmt.Errorf("package %s was not installed: %w", name, err)
+	}
+
+	var manifest struct {
+		Version string `json:"version"`
+	}
+	if err := json.Unmarshal(content, &manifest); err != nil {
+		return "", fmt.Errorf("failed to read version of %s: %w", name, err)
+	}
+	return manifest.Version, nil
+}
+
+func readInstalled(setDir string) (map[string]string, error) {
+	content, err := os.ReadFile(filepath.Join(setDir, installedManifest))
+	if err != nil {
+		return nil, err
+	}
+
+	var resolved map[string]string
+	if err := json.Unmarshal(content, &resolved); err != nil {
+		return nil, err
+	}
+	return resolved, nil
+}
+
+// packageSetKey is the content address of a set of packages
+func packageSetKey(packages []typescriptdomain.Package) string {
+	specs := make([]string, 0, len(packages))
+	for _, pkg := range packages {
+		specs = append(specs, pkg.Name+"@"+pkg.Version)
+	}
+	sort.Strings(specs)
+
+	sum := sha256.Sum256([]byte(strings.Join(specs, "\n")))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,90 @@
+package infrastructure
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	typescriptdomain "lumina/backend/typescript_execution/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writePackageTarball writes an npm package tarball with the given files
+// below package/ and returns its path
+func writePackageTarball(t *testing.T, files map[string]string) string {
+	path := filepath.Join(t.TempDir(), "package.tgz")
+	file, err := os.Create(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	compressed := gzip.NewWriter(file)
+	archive := tar.NewWriter(compressed)
+	for name, content := range files {
+		require.NoError(t, archive.WriteHeader(&tar.Header{Name: "package/" + name, Mode: 0644, Size: int64(len(content))}))
+		_, err := archive.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, archive.Close())
+	require.NoError(t, compressed.Close())
+	return path
+}
+
+func TestPackageSetKey(t *testing.T) {
+	zod := typescriptdomain.Package{Name: "zod", Version: "^3.23.0"}
+	dayjs := typescriptdomain.Package{Name: "dayjs", Version: "1.11.10"}
+
+	// Then the key depends on the set, not on its order
+	assert.Equal(t, packageSetKey([]typescriptdomain.Package{zod, dayjs}), packageSetKey([]typescriptdomain.Package{dayjs, zod}))
+	assert.NotEqual(t, packageSetKey([]typescriptdomain.Package{zod}), packageSetKey([]typescriptdomain.Package{zod, dayjs}))
+	assert.NotEqual(t, packageSetKey([]typescriptdomain.Package{zod}), packageSetKey([]typescriptdomain.Package{{Name: "zod", Version: "^3.24.0"}}))
+}
+
+func TestNpmPackageCache_Provision(t *testing.T) {
+	if _, err := exec.LookPath("npm"); err != nil {
+		t.Skip("npm is not installed")
+	}
+
+	// Given an offline cache and a package tarball
+	tarball := writePackageTarball(t, map[string]string{
+		"package.json": `{"name": "greet", "version": "1.2.3", "main": "index.js"}`,
+		"index.js":     `module.exports = () => "hi";`,
+	})
+	cache := NewNpmPackageCache(t.TempDir(), "", true)
+	packages := []typescriptdomain.Package{{Name: "greet", Version: "file:" + tarball}}
+
+	// When provisioning it twice
+	nodeModules, resolved, err := cache.Provision(packages)
+	require.NoError(t, err)
+	again, _, err := cache.Provision(packages)
+	require.NoError(t, err)
+
+	// Then it is installed once, with its version
+	assert.Equal(t, map[string]string{"greet": "1.2.3"}, resolved)
+	assert.FileExists(t, filepath.Join(nodeModules, "greet", "index.js"))
+	assert.Equal(t, nodeModules, again)
+}
+
+func TestNpmPackageCache_ProvisionFailure(t *testing.T) {
+	if _, err := exec.LookPath("npm"); err != nil {
+		t.Skip("npm is not installed")
+	}
+
+	// Given an offline cache and a tarball that does not exist
+	root := t.TempDir()
+	cache := NewNpmPackageCache(root, "", true)
+	missing := filepath.Join(t.TempDir(), "missing.tgz")
+
+	// When
+	_, _, err := cache.Provision([]typescriptdomain.Package{{Name: "missing", Version: "file:" + missing}})
+
+	// Then no half-installed set is left behind
+	require.Error(t, err)
+	sets, err := os.ReadDir(filepath.Join(root, "sets"))
+	require.NoError(t, err)
+	assert.Empty(t, sets)
+}
@@ -0,0 +1,70 @@
+package infrastructure_test
+
+import (
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	tooldomain "lumina/backend/tool/domain"
+	toolinfrastructure "lumina/backend/tool/infrastructure"
+	typescriptdomain "lumina/backend/typescript_execution/domain"
+	"lumina/backend/typescript_execution/infrastructure"
+)
+
+// stubTypeScript stands in for the compiler in restricted runs; the tested
+// code is already JavaScript, so it is passed through as it is
+var stubTypeScript = map[string]string{
+	"typescript/package.json": `{"name": "typescript", "version": "5.4.5", "main": "index.js"}`,
+	"typescript/index.js": `module.exports = {
+  ModuleKind: { ES2022: 7 },
+  ScriptTarget: { ES2022: 9 },
+  transpileModule: (source) => ({ outputText: source, diagnostics: [] }),
+  flattenDiagnosticMessageText: (message) => message,
+};`,
+}
+
+func TestRestrictedRun_UndeclaredRead(t *testing.T) {
+	if exec.Command("node", "--experimental-permission", "-e", "").Run() != nil &&
+		exec.Command("node", "--permission", "-e", "").Run() != nil {
+		t.Skip("node with a permission model is not installed")
+	}
+
+	// Given a runner for tools restricted to what they declare
+	root := t.TempDir()
+	require.NoError(t, infrastructure.SeedPackageSet(root, []typescriptdomain.Package{infrastructure.TypeScriptCompiler}, stubTypeScript))
+	executor := infrastructure.NewNodeTypeScriptExecutorWithPackages(infrastructure.NewNpmPackageCache(root, "", true))
+	defer executor.Cleanup()
+
+	dbFile := filepath.Join(t.TempDir(), "lumina.db")
+	tools, err := toolinfrastructure.NewSQLiteToolRepository(dbFile)
+	require.NoError(t, err)
+	defer tools.Close()
+	escalations, err := toolinfrastructure.NewSQLiteCapabilityEscalationRepository(dbFile)
+	require.NoError(t, err)
+	defer escalations.Close()
+
+	capabilities := tooldomain.NewCapabilityService(tools, escalations)
+	runner := tooldomain.NewCapabilityGuardedRunner(toolinfrastructure.NewTypeScriptToolRunner(executor, tools, nil), capabilities, nil)
+
+	// And a tool declaring no capabilities that reads a file
+	tool, err := tools.Save(tooldomain.NewTool("Hostname", `import { readFileSync } from "node:fs";
+console.log(readFileSync("/etc/hostname", "utf8"));`))
+	require.NoError(t, err)
+
+	// When
+	output, err := runner.Run(tool, nil)
+
+	// Then the read is refused and waits for the user as an escalation
+	require.NoError(t, err)
+	assert.NotEqual(t, 0, output.ExitCode)
+	assert.Contains(t, output.CapabilityRequests, tooldomain.CapabilityRequest{Kind: tooldomain.CapabilityFileRead, Resource: "/etc/hostname"})
+
+	pending, err := capabilities.Pending()
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	assert.Equal(t, tool.ID, pending[0].ToolID)
+	assert.Equal(t, tooldomain.CapabilityFileRead, pending[0].Kind)
+	assert.Equal(t, "/etc/hostname", pending[0].Resource)
+}
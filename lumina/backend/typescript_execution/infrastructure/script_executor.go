@@ -0,0 +1,180 @@
+package infrastructure
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode"
+
+	typescriptdomain "lumina/backend/typescript_execution/domain"
+)
+
+// goModule is the go.mod of the temporary module a Go snippet runs in
+const goModule = "module lumina.local/tool\n\ngo 1.21\n"
+
+// ScriptExecutor implements CodeExecutor by writing the code to a file in a
+// fresh run directory and running an interpreter or compiler on it
+type ScriptExecutor struct {
+	name     string
+	tempDir  string
+	fileName string
+	// command returns the program and its arguments for the code file
+	command func(file string) []string
+	// files are written to the run directory next to the code
+	files map[string]string
+}
+
+func newScriptExecutor(name, fileName string, files map[string]string, command func(file string) []string) *ScriptExecutor {
+	tempDir, err := os.MkdirTemp("", "lumina-"+name+"-exec-*")
+	if err != nil {
+		tempDir = os.TempDir()
+	}
+
+	return &ScriptExecutor{
+		name:     name,
+		tempDir:  tempDir,
+		fileName: fileName,
+		command:  command,
+		files:    files,
+	}
+}
+
+// NewShellExecutor creates an executor running POSIX shell scripts with sh
+func NewShellExecutor() *ScriptExecutor {
+	return newScriptExecutor("sh", "code.sh", nil, func(file string) []string {
+		return []string{"sh", file}
+	})
+}
+
+// NewPythonExecutor creates an executor running Python 3 code with python3
+func NewPythonExecutor() *ScriptExecutor {
+	return newScriptExecutor("python", "code.py", nil, func(file string) []string {
+		return []string{"python3", file}
+	})
+}
+
+// NewGoExecutor creates an executor running a Go main package with go run,
+// in a temporary module of its own; only the standard library is available
+func NewGoExecutor() *ScriptExecutor {
+	return newScriptExecutor("go", "main.go", map[string]string{"go.mod": goModule}, func(file string) []string {
+		return []string{"go", "run", file}
+	})
+}
+
+// Execute runs code with the arguments passed as JSON in LUMINA_ARGS and
+// one by one in LUMINA_ARG_<NAME> variables. File, network and process
+// access can't be enforced for an interpreter or compiler the way node
+// enforces it, so restricted runs fail with ErrRestrictionUnsupported.
+func (e *ScriptExecutor) Execute(code string, opts typescriptdomain.ExecuteOptions) (*typescriptdomain.ExecutionResult, error) {
+	if len(opts.Modules) > 0 || len(opts.Packages) > 0 {
+		return nil, fmt.Errorf("%w: modules and npm packages are TypeScript only", typescriptdomain.ErrUnsupportedLanguage)
+	}
+	if opts.Permissions.Restricted {
+		return nil, fmt.Errorf("%w for %s code", typescriptdomain.ErrRestrictionUnsupported, e.name)
+	}
+	env, err := argumentEnv(opts.Args)
+	if err != nil {
+		return nil, err
+	}
+
+	runDir, err := os.MkdirTemp(e.tempDir, fmt.Sprintf("run_%d_*", time.Now().UnixNano()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create run directory: %w", err)
+	}
+	defer os.RemoveAll(runDir)
+
+	for name, content := range e.files {
+		if err := os.WriteFile(filepath.Join(runDir, name), []byte(content), 0644); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", name, err)
+		}
+	}
+
+	codeFile := filepath.Join(runDir, e.fileName)
+	if err := os.WriteFile(codeFile, []byte(code), 0644); err != nil {
+		return nil, fmt.Errorf("failed to write code file: %w", err)
+	}
+
+	command := e.command(codeFile)
+	cmd := exec.Command(command[0], command[1:]...)
+	cmd.Dir = runDir
+	cmd.Env = append(os.Environ(), env...)
+	return runProcess(cmd), nil
+}
+
+// Cleanup cleans up temporary files
+func (e *ScriptExecutor) Cleanup() {
+	if e.tempDir != "" {
+		os.RemoveAll(e.tempDir)
+	}
+}
+
+// argumentEnv encodes args as environment variables: all of them as JSON in
+// LUMINA_ARGS, and each one in LUMINA_ARG_<NAME>, strings as they are and
+// other values as JSON
+func argumentEnv(args map[string]interface{}) ([]string, error) {
+	if args == nil {
+		args = map[string]interface{}{}
+	}
+	encodedArgs, err := json.Marshal(args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode arguments: %w", err)
+	}
+
+	env := []string{typescriptdomain.ArgumentsEnvVar + "=" + string(encodedArgs)}
+	for name, value := range args {
+		text, isText := value.(string)
+		if !isText {
+			encoded, err := json.Marshal(value)
+			if err != nil {
+				return nil, fmt.Errorf("failed to encode argument %s: %w", name, err)
+			}
+			text = string(encoded)
+		}
+		env = append(env, typescriptdomain.ArgumentEnvPrefix+argumentEnvName(name)+"="+text)
+	}
+	return env, nil
+}
+
+// argumentEnvName turns "max-items" into "MAX_ITEMS"
+func argumentEnvName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			return unicode.ToUpper(r)
+		}
+		return '_'
+	}, name)
+}
+
+// runProcess runs cmd and normalises what it printed and how it exited
+// into an ExecutionResult
+func runProcess(cmd *exec.Cmd) *typescriptdomain.ExecutionResult {
+	var stdout, stderr strings.Builder
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+
+	result := &typescriptdomain.ExecutionResult{
+		Output:   stdout.String(),
+		Error:    stderr.String(),
+		ExitCode: 0,
+	}
+
+	if err != nil {
+		if exitError, ok := err.(*exec.ExitError); ok {
+			result.ExitCode = exitError.ExitCode()
+		} else {
+			result.Error = fmt.Sprintf("Execution error: %v\n%s", err, result.Error)
+			result.ExitCode = 1
+		}
+		result.Success = false
+	} else {
+		result.Success = true
+	}
+
+	return result
+}
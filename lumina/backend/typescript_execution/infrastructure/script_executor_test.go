@@ -0,0 +1,113 @@
+package infrastructure
+
+import (
+	"errors"
+	"os/exec"
+	"testing"
+
+	typescriptdomain "lumina/backend/typescript_execution/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArgumentEnvName(t *testing.T) {
+	tests := []struct {
+		name    string
+		envName string
+	}{
+		{"city", "CITY"},
+		{"max-items", "MAX_ITEMS"},
+		{"maxItems", "MAXITEMS"},
+		{"page size 2", "PAGE_SIZE_2"},
+		{"café", "CAF_"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// When
+			envName := argumentEnvName(tt.name)
+
+			// Then
+			assert.Equal(t, tt.envName, envName)
+		})
+	}
+}
+
+func TestArgumentEnv(t *testing.T) {
+	// When
+	env, err := argumentEnv(map[string]interface{}{
+		"city":      "Paris",
+		"max-items": 3,
+		"tags":      []string{"a", "b"},
+	})
+
+	// Then strings are passed as they are and other values as JSON
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{
+		`LUMINA_ARGS={"city":"Paris","max-items":3,"tags":["a","b"]}`,
+		"LUMINA_ARG_CITY=Paris",
+		"LUMINA_ARG_MAX_ITEMS=3",
+		`LUMINA_ARG_TAGS=["a","b"]`,
+	}, env)
+}
+
+func TestArgumentEnv_NoArguments(t *testing.T) {
+	// When
+	env, err := argumentEnv(nil)
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, []string{"LUMINA_ARGS={}"}, env)
+}
+
+func TestScriptExecutor_Execute(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh is not installed")
+	}
+
+	// Given
+	executor := NewShellExecutor()
+	defer executor.Cleanup()
+
+	// When
+	result, err := executor.Execute(`echo "$LUMINA_ARG_CITY $LUMINA_ARGS"; echo oops >&2; exit 3`, typescriptdomain.ExecuteOptions{
+		Args: map[string]interface{}{"city": "Paris"},
+	})
+
+	// Then the output, errors and exit code of the script are returned
+	require.NoError(t, err)
+	assert.Equal(t, "Paris {\"city\":\"Paris\"}\n", result.Output)
+	assert.Equal(t, "oops\n", result.Error)
+	assert.Equal(t, 3, result.ExitCode)
+	assert.False(t, result.Success)
+}
+
+func TestScriptExecutor_ExecuteWithModules(t *testing.T) {
+	// Given
+	executor := NewPythonExecutor()
+	defer executor.Cleanup()
+
+	// When
+	_, err := executor.Execute("print(1)", typescriptdomain.ExecuteOptions{
+		Packages: []typescriptdomain.Package{{Name: "zod", Version: "^3.23.0"}},
+	})
+
+	// Then
+	assert.True(t, errors.Is(err, typescriptdomain.ErrUnsupportedLanguage))
+}
+
+func TestScriptExecutor_ExecuteRestricted(t *testing.T) {
+	// Given
+	executor := NewShellExecutor()
+	defer executor.Cleanup()
+
+	// When
+	result, err := executor.Execute("cat /etc/hostname", typescriptdomain.ExecuteOptions{
+		Permissions: typescriptdomain.Permissions{Restricted: true},
+	})
+
+	// Then the script is refused rather than run unconfined
+	assert.Nil(t, result)
+	assert.True(t, errors.Is(err, typescriptdomain.ErrRestrictionUnsupported))
+}
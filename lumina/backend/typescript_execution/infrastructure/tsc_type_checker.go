@@ -0,0 +1,166 @@
+package infrastructure
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	typescriptdomain "lumina/backend/typescript_execution/domain"
+)
+
+// typescriptCompiler is the compiler installed into the package cache for checks
+var typescriptCompiler = typescriptdomain.Package{Name: "typescript", Version: "^5.4.0"}
+
+// hostTypings declares the globals tools use without requiring @types/node
+//
+//go:embed lumina_host.d.ts
+var hostTypings string
+
+// tscDiagnosticPattern matches "code.ts(3,5): error TS2304: Cannot find name 'x'."
+var tscDiagnosticPattern = regexp.MustCompile(`^(.+?)\((\d+),(\d+)\): error (TS\d+): (.*)$`)
+
+// TscTypeChecker implements TypeChecker by running tsc --noEmit on the code
+// laid out the way NodeTypeScriptExecutor runs it
+type TscTypeChecker struct {
+	tempDir  string
+	packages *NpmPackageCache
+}
+
+// NewTscTypeChecker creates a checker that installs the compiler, and the npm
+// packages of the checked code, into packages
+func NewTscTypeChecker(packages *NpmPackageCache) *TscTypeChecker {
+	tempDir, err := os.MkdirTemp("", "lumina-ts-check-*")
+	if err != nil {
+		tempDir = os.TempDir()
+	}
+
+	return &TscTypeChecker{
+		tempDir:  tempDir,
+		packages: packages,
+	}
+}
+
+func (c *TscTypeChecker) Check(code string, modules []typescriptdomain.Module, packages []typescriptdomain.Package) ([]typescriptdomain.Diagnostic, error) {
+	if c.packages == nil {
+		return nil, fmt.Errorf("TypeScript compiler not available: no package cache configured")
+	}
+
+	checkDir, err := os.MkdirTemp(c.tempDir, fmt.Sprintf("check_%d_*", time.Now().UnixNano()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create check directory: %w", err)
+	}
+	defer os.RemoveAll(checkDir)
+
+	nodeModules, _, err := c.packages.Provision(append([]typescriptdomain.Package{typescriptCompiler}, packages...))
+	if err != nil {
+		return nil, fmt.Errorf("failed to install TypeScript compiler: %w", err)
+	}
+	if err := os.Symlink(nodeModules, filepath.Join(checkDir, "node_modules")); err != nil {
+		return nil, fmt.Errorf("failed to link npm packages: %w", err)
+	}
+
+	files := []string{"code.ts"}
+	linker := moduleLinker(modules)
+	for i, module := range modules {
+		name := moduleFileName(i) + ".ts"
+		if err := os.WriteFile(filepath.Join(checkDir, name), []byte(linker.Replace(module.Code)), 0644); err != nil {
+			return nil, fmt.Errorf("failed to write TypeScript module: %w", err)
+		}
+		files = append(files, name)
+	}
+	// Replacing specifiers in place keeps line and column numbers intact
+	if err := os.WriteFile(filepath.Join(checkDir, "code.ts"), []byte(linker.Replace(code)), 0644); err != nil {
+		return nil, fmt.Errorf("failed to write TypeScript file: %w", err)
+	}
+
+	// @types/node declares the same globals as the host typings
+	if !hasPackage(packages, "@types/node") {
+		if err := os.WriteFile(filepath.Join(checkDir, "lumina_host.d.ts"), []byte(hostTypings), 0644); err != nil {
+			return nil, fmt.Errorf("failed to write host typings: %w", err)
+		}
+		files = append(files, "lumina_host.d.ts")
+	}
+
+	args := append([]string{
+		filepath.Join(nodeModules, "typescript", "bin", "tsc"),
+		"--noEmit",
+		"--pretty", "false",
+		"--target", "es2022",
+		"--module", "es2022",
+		"--moduleResolution", "node",
+		"--lib", "es2022",
+		"--skipLibCheck",
+	}, files...)
+	cmd := exec.Command("node", args...)
+	cmd.Dir = checkDir
+
+	output, err := cmd.CombinedOutput()
+	diagnostics := parseTscOutput(string(output))
+	if err != nil {
+		if _, ok := err.(*exec.ExitError); !ok {
+			return nil, fmt.Errorf("failed to run TypeScript compiler: %w", err)
+		}
+		// A failing compiler without diagnostics is a broken setup, not broken code
+		if len(diagnostics) == 0 {
+			return nil, fmt.Errorf("TypeScript compiler failed: %s", strings.TrimSpace(string(output)))
+		}
+	}
+
+	return diagnostics, nil
+}
+
+// parseTscOutput returns the diagnostics reported for code.ts; indented
+// lines continue the message of the diagnostic before them
+func parseTscOutput(output string) []typescriptdomain.Diagnostic {
+	var diagnostics []typescriptdomain.Diagnostic
+	inCode := false
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimRight(line, "\r")
+		match := tscDiagnosticPattern.FindStringSubmatch(line)
+		if match == nil {
+			if inCode && strings.HasPrefix(line, " ") && len(diagnostics) > 0 {
+				last := &diagnostics[len(diagnostics)-1]
+				last.Message += "\n" + strings.TrimSpace(line)
+			}
+			continue
+		}
+
+		// Imported tools are checked when they are saved themselves
+		inCode = filepath.Base(match[1]) == "code.ts"
+		if !inCode {
+			continue
+		}
+
+		lineNumber, _ := strconv.Atoi(match[2])
+		column, _ := strconv.Atoi(match[3])
+		diagnostics = append(diagnostics, typescriptdomain.Diagnostic{
+			Line:    lineNumber,
+			Column:  column,
+			Code:    match[4],
+			Message: match[5],
+		})
+	}
+	return diagnostics
+}
+
+func hasPackage(packages []typescriptdomain.Package, name string) bool {
+	for _, p := range packages {
+		if p.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Cleanup removes the check directories
+func (c *TscTypeChecker) Cleanup() {
+	if c.tempDir != "" {
+		os.RemoveAll(c.tempDir)
+	}
+}
@@ -0,0 +1,99 @@
+package infrastructure
+
+import (
+	"os/exec"
+	"testing"
+
+	typescriptdomain "lumina/backend/typescript_execution/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTscOutput(t *testing.T) {
+	tests := []struct {
+		name        string
+		output      string
+		diagnostics []typescriptdomain.Diagnostic
+	}{
+		{
+			name:   "error in the code",
+			output: "code.ts(3,5): error TS2304: Cannot find name 'x'.\n",
+			diagnostics: []typescriptdomain.Diagnostic{
+				{Line: 3, Column: 5, Code: "TS2304", Message: "Cannot find name 'x'."},
+			},
+		},
+		{
+			name: "message continued on indented lines",
+			output: "code.ts(1,7): error TS2322: Type '{ a: string; }' is not assignable to type 'Options'.\r\n" +
+				"  Object literal may only specify known properties.\r\n",
+			diagnostics: []typescriptdomain.Diagnostic{
+				{Line: 1, Column: 7, Code: "TS2322", Message: "Type '{ a: string; }' is not assignable to type 'Options'.\nObject literal may only specify known properties."},
+			},
+		},
+		{
+			name: "errors in imported tools are left out",
+			output: "module_0.ts(2,1): error TS1005: ';' expected.\n" +
+				"  continued\n" +
+				"/tmp/check/code.ts(4,2): error TS2554: Expected 1 arguments, but got 0.\n",
+			diagnostics: []typescriptdomain.Diagnostic{
+				{Line: 4, Column: 2, Code: "TS2554", Message: "Expected 1 arguments, but got 0."},
+			},
+		},
+		{
+			name:   "no errors",
+			output: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// When
+			diagnostics := parseTscOutput(tt.output)
+
+			// Then
+			assert.Equal(t, tt.diagnostics, diagnostics)
+		})
+	}
+}
+
+// stubTsc stands in for the compiler: it reports one error in code.ts, and
+// another when the host typings are missing from the compiled files
+const stubTsc = `
+const files = process.argv.slice(2);
+if (!files.includes("lumina_host.d.ts")) console.log("code.ts(1,1): error TS9999: host typings missing");
+console.log("code.ts(2,7): error TS2322: Type 'string' is not assignable to type 'number'.");
+process.exit(2);
+`
+
+func TestTscTypeChecker_Check(t *testing.T) {
+	if _, err := exec.LookPath("node"); err != nil {
+		t.Skip("node is not installed")
+	}
+
+	// Given a package cache holding a stand-in compiler
+	root := t.TempDir()
+	require.NoError(t, SeedPackageSet(root, []typescriptdomain.Package{typescriptCompiler}, map[string]string{
+		"typescript/package.json": `{"name": "typescript", "version": "5.4.5"}`,
+		"typescript/bin/tsc":      stubTsc,
+	}))
+	checker := NewTscTypeChecker(NewNpmPackageCache(root, "", true))
+	defer checker.Cleanup()
+
+	// When
+	diagnostics, err := checker.Check("const n: number = 1;\nconst s: number = 'x';", nil, nil)
+
+	// Then the diagnostics of the code are returned
+	require.NoError(t, err)
+	assert.Equal(t, []typescriptdomain.Diagnostic{
+		{Line: 2, Column: 7, Code: "TS2322", Message: "Type 'string' is not assignable to type 'number'."},
+	}, diagnostics)
+}
+
+func TestTscTypeChecker_NoPackageCache(t *testing.T) {
+	// When
+	_, err := NewTscTypeChecker(nil).Check("const n = 1;", nil, nil)
+
+	// Then
+	assert.Error(t, err)
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	tooldomain "lumina/backend/tool/domain"
+	toolinfra "lumina/backend/tool/infrastructure"
+	typescriptinfra "lumina/backend/typescript_execution/infrastructure"
+)
+
+// Exit codes of the command line, besides the exit code of the tool itself
+const (
+	exitFailure = 1
+	exitUsage   = 2
+)
+
+// runUsage describes the run command
+const runUsage = `usage: lumina run <tool> [--arg name=value]...
+
+Runs a saved tool with the tool store of the desktop app, resolving its name
+as the app does from the current directory. The tool's output is printed on
+stdout and stderr and its exit code is the exit code of lumina.
+`
+
+// toolArguments collects repeated --arg name=value flags
+type toolArguments map[string]interface{}
+
+func (a toolArguments) String() string {
+	pairs := make([]string, 0, len(a))
+	for name, value := range a {
+		pairs = append(pairs, fmt.Sprintf("%s=%v", name, value))
+	}
+	return strings.Join(pairs, " ")
+}
+
+func (a toolArguments) Set(value string) error {
+	name, argument, found := strings.Cut(value, "=")
+	if !found || strings.TrimSpace(name) == "" {
+		return fmt.Errorf("expected name=value, got %q", value)
+	}
+	a[strings.TrimSpace(name)] = argument
+	return nil
+}
+
+// runCLI runs the command line given by args, the arguments after the
+// program name, and returns the exit code. ok is false when args do not
+// name a command, in which case the desktop app starts instead.
+func runCLI(args []string, stdout, stderr io.Writer) (code int, ok bool) {
+	if len(args) == 0 || args[0] != "run" {
+		return 0, false
+	}
+	return runTool(args[1:], stdout, stderr), true
+}
+
+// runTool implements `lumina run`
+func runTool(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("run", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() { fmt.Fprint(stderr, runUsage) }
+	arguments := toolArguments{}
+	flags.Var(arguments, "arg", "tool argument as name=value, repeatable")
+
+	// The tool name usually comes first, which the flag package would take
+	// as the end of the flags
+	var name string
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		name, args = args[0], args[1:]
+	}
+	if err := flags.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		return exitUsage
+	}
+	rest := flags.Args()
+	if name == "" && len(rest) > 0 {
+		name, rest = rest[0], rest[1:]
+	}
+	if name == "" || len(rest) > 0 {
+		flags.Usage()
+		return exitUsage
+	}
+
+	workingDir, err := os.Getwd()
+	if err != nil {
+		workingDir = "."
+	}
+	dbPath := getDBPath()
+
+	toolRepo, err := toolinfra.NewSQLiteToolRepository(dbPath)
+	if err != nil {
+		fmt.Fprintf(stderr, "lumina: could not open the tool store: %v\n", err)
+		return exitFailure
+	}
+	defer toolRepo.Close()
+	tools := tooldomain.NewScopedToolRepository(toolRepo, tooldomain.ToolScope{ProjectRoot: workingDir})
+
+	tool, err := tools.GetByName(name)
+	if err != nil {
+		fmt.Fprintf(stderr, "lumina: %s: %v\n", name, err)
+		return exitFailure
+	}
+
+	resolved, err := tool.ResolveArguments(arguments)
+	if err != nil {
+		fmt.Fprintf(stderr, "lumina: %s: %v\n", name, err)
+		return exitUsage
+	}
+
+	// Runs from the command line are recorded like runs from the app.
+	// runRecorder stays a true nil interface when the history is unavailable.
+	var runRecorder tooldomain.ToolRunRepository
+	if runRepo, err := toolinfra.NewSQLiteToolRunRepository(dbPath); err != nil {
+		fmt.Fprintf(stderr, "lumina: warning: runs will not be recorded: %v\n", err)
+	} else {
+		defer runRepo.Close()
+		runRecorder = runRepo
+	}
+
+	executor := typescriptinfra.NewNodeTypeScriptExecutorWithPackages(newPackageCache(dbPath))
+	var runner tooldomain.ToolRunner = toolinfra.NewTypeScriptToolRunnerWithExecutors(executor, newExecutorRegistry(executor), tools, runRecorder)
+
+	// Accesses beyond the declared capabilities are recorded for review in the app
+	if escalations, err := toolinfra.NewSQLiteCapabilityEscalationRepository(dbPath); err != nil {
+		fmt.Fprintf(stderr, "lumina: warning: refused accesses will not be recorded: %v\n", err)
+	} else {
+		defer escalations.Close()
+		runner = tooldomain.NewCapabilityGuardedRunner(runner, tooldomain.NewCapabilityService(tools, escalations), func(tool tooldomain.Tool, refused []tooldomain.CapabilityEscalation) {
+			for _, escalation := range refused {
+				fmt.Fprintf(stderr, "lumina: %s was refused %s, which it does not declare; recorded for review\n", tool.Name, escalation.Request())
+			}
+		})
+	}
+	output, err := runner.Run(tool, resolved)
+	if err != nil {
+		fmt.Fprintf(stderr, "lumina: %s: %v\n", name, err)
+		return exitFailure
+	}
+
+	fmt.Fprint(stdout, output.Output)
+	fmt.Fprint(stderr, output.Error)
+
+	// A failed run that reports no exit code of its own still fails the command
+	if !output.Success && output.ExitCode == 0 {
+		return exitFailure
+	}
+	return output.ExitCode
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	tooldomain "lumina/backend/tool/domain"
+	toolinfra "lumina/backend/tool/infrastructure"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// saveShellTool stores a shell tool greeting its name argument and exiting
+// with 3 in a tool store under a temporary home
+func saveShellTool(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	repo, err := toolinfra.NewSQLiteToolRepository(getDBPath())
+	require.NoError(t, err)
+	defer repo.Close()
+
+	tool, err := tooldomain.NewTool("Greet", `echo "hello $LUMINA_ARG_NAME"; exit 3`).WithLanguage(tooldomain.LanguageShell)
+	require.NoError(t, err)
+	tool.Parameters = []tooldomain.ToolParameter{{Name: "name", Type: tooldomain.ParameterString, Required: true}}
+	// A tool declaring no capabilities runs unrestricted
+	tool.Capabilities = nil
+	_, err = repo.Save(tool)
+	require.NoError(t, err)
+}
+
+func TestRunCLI_NotACommand(t *testing.T) {
+	// When
+	_, ok := runCLI([]string{"--debug"}, &bytes.Buffer{}, &bytes.Buffer{})
+
+	// Then the desktop app starts instead
+	assert.False(t, ok)
+}
+
+func TestRunTool_Arguments(t *testing.T) {
+	saveShellTool(t)
+
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"flags after the name", []string{"Greet", "--arg", "name=Ada"}},
+		{"flags before the name", []string{"--arg", "name=Ada", "Greet"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			var stdout, stderr bytes.Buffer
+
+			// When
+			code, ok := runCLI(append([]string{"run"}, tt.args...), &stdout, &stderr)
+
+			// Then the tool's output and exit code are passed through
+			require.True(t, ok)
+			assert.Equal(t, 3, code, stderr.String())
+			assert.Equal(t, "hello Ada\n", stdout.String())
+		})
+	}
+}
+
+func TestRunTool_Usage(t *testing.T) {
+	saveShellTool(t)
+
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"no tool", nil},
+		{"two tools", []string{"Greet", "Other"}},
+		{"argument without a value", []string{"Greet", "--arg", "name"}},
+		{"missing required argument", []string{"Greet"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			var stdout, stderr bytes.Buffer
+
+			// When
+			code := runTool(tt.args, &stdout, &stderr)
+
+			// Then
+			assert.Equal(t, exitUsage, code)
+			assert.Empty(t, stdout.String())
+			assert.NotEmpty(t, stderr.String())
+		})
+	}
+}
+
+func TestRunTool_RestrictedShellTool(t *testing.T) {
+	// Given a shell tool declaring capabilities, which can't be enforced
+	t.Setenv("HOME", t.TempDir())
+	repo, err := toolinfra.NewSQLiteToolRepository(getDBPath())
+	require.NoError(t, err)
+	tool, err := tooldomain.NewTool("List", "ls /").WithLanguage(tooldomain.LanguageShell)
+	require.NoError(t, err)
+	_, err = repo.Save(tool)
+	require.NoError(t, err)
+	require.NoError(t, repo.Close())
+	var stdout, stderr bytes.Buffer
+
+	// When
+	code := runTool([]string{"List"}, &stdout, &stderr)
+
+	// Then the run is refused and an unrestricted run is asked for
+	assert.Equal(t, exitFailure, code)
+	assert.Empty(t, stdout.String())
+	assert.Contains(t, stderr.String(), "List was refused unrestricted")
+}
+
+func TestRunTool_UnknownTool(t *testing.T) {
+	// Given
+	t.Setenv("HOME", t.TempDir())
+	var stderr bytes.Buffer
+
+	// When
+	code := runTool([]string{"Missing"}, &bytes.Buffer{}, &stderr)
+
+	// Then
+	assert.Equal(t, exitFailure, code)
+	assert.Contains(t, stderr.String(), "Missing")
+}
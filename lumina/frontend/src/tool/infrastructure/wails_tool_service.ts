@@ -1,9 +1,14 @@
-import { SaveTool, ListTools, GetTool } from '../../../wailsjs/go/main/App';
+import { SaveTool, ListTools, GetTool, GetToolByName } from '../../../wailsjs/go/main/App';
 import { Tool, ToolService } from '../domain/tool_service';
 
 export class WailsToolService implements ToolService {
   async saveTool(name: string, code: string): Promise<Tool> {
-    const savedTool = await SaveTool(name, code);
+    // Saving under the name of an existing tool updates it; an empty ID
+    // creates a new tool
+    const existing = await this.findToolByName(name);
+    const savedTool = existing
+      ? await SaveTool(existing.id, existing.version, name, code)
+      : await SaveTool('', 0, name, code);
 
     return {
       id: savedTool.id,
@@ -14,6 +19,17 @@ export class WailsToolService implements ToolService {
     };
   }
 
+  private async findToolByName(name: string) {
+    try {
+      return await GetToolByName(name);
+    } catch (error) {
+      if (String(error).includes('tool not found')) {
+        return undefined;
+      }
+      throw error;
+    }
+  }
+
   async listTools(): Promise<Tool[]> {
     const tools = await ListTools();
 
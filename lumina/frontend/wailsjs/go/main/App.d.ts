@@ -2,20 +2,166 @@
 // This file is automatically generated. DO NOT EDIT
 import {domain} from '../models';
 
+export function ApplyChanges(arg1:string,arg2:Array<domain.HunkSelection>):Promise<domain.UndoRecord>;
+
+export function CheckToolCode(arg1:string):Promise<Array<domain.ToolDiagnostic>>;
+
+export function CheckToolTypes(arg1:string):Promise<Array<domain.ToolDiagnostic>>;
+
+export function DeletePipeline(arg1:string):Promise<void>;
+
+export function DeleteTool(arg1:string):Promise<void>;
+
+export function DeleteToolExample(arg1:string):Promise<void>;
+
+export function DeleteToolTrigger(arg1:string):Promise<void>;
+
+export function DenyCapabilityEscalation(arg1:string):Promise<domain.CapabilityEscalation>;
+
+export function DiffToolRevisions(arg1:string,arg2:number,arg3:number):Promise<domain.RevisionDiff>;
+
+export function DiffToolRuns(arg1:string,arg2:string):Promise<domain.ToolRunDiff>;
+
+export function DuplicateTool(arg1:string,arg2:number,arg3:string):Promise<domain.Tool>;
+
 export function ExecuteTypeScript(arg1:string):Promise<domain.ExecutionResult>;
 
+export function ExportTools(arg1:Array<string>,arg2:string):Promise<domain.ToolBundleExport>;
+
+export function GenerateTool(arg1:string):Promise<domain.ToolGenerationResult>;
+
+export function GetChangeSet(arg1:string):Promise<domain.ChangeSet>;
+
 export function GetChatState():Promise<domain.ChatState>;
 
+export function GetCodebaseContextMode():Promise<string>;
+
 export function GetCurrentProjectTree():Promise<domain.Node>;
 
+export function GetGitContextOptions():Promise<domain.GitContextOptions>;
+
+export function GetLLMCall(arg1:string):Promise<domain.LLMCall>;
+
+export function GetPipeline(arg1:string):Promise<domain.Pipeline>;
+
+export function GetProjectNamespace():Promise<string>;
+
 export function GetTool(arg1:string):Promise<domain.Tool>;
 
 export function GetToolByName(arg1:string):Promise<domain.Tool>;
 
+export function GetToolDependencies(arg1:string):Promise<domain.ToolDependencyGraph>;
+
+export function GetToolRevision(arg1:string,arg2:number):Promise<domain.ToolRevision>;
+
+export function GetToolRun(arg1:string):Promise<domain.ToolRun>;
+
+export function GetToolTypeCheckPolicy():Promise<domain.TypeCheckPolicy>;
+
+export function GetToolUsage(arg1:string):Promise<domain.ToolUsage>;
+
+export function GrantCapabilityEscalation(arg1:string):Promise<domain.Tool>;
+
 export function Greet(arg1:string):Promise<string>;
 
+export function ImportTools(arg1:string,arg2:string,arg3:string):Promise<domain.ToolBundleImport>;
+
+export function ListCapabilityEscalations(arg1:string):Promise<Array<domain.CapabilityEscalation>>;
+
+export function ListChangeSets():Promise<Array<domain.ChangeSet>>;
+
+export function ListLLMCalls(arg1:number):Promise<Array<domain.LLMCall>>;
+
+export function ListPipelines():Promise<Array<domain.Pipeline>>;
+
+export function ListToolExamples(arg1:string):Promise<Array<domain.ToolExample>>;
+
+export function ListToolLanguages():Promise<Array<string>>;
+
+export function ListToolNamespaces():Promise<Array<string>>;
+
+export function ListToolRevisions(arg1:string):Promise<Array<domain.ToolRevision>>;
+
+export function ListToolRuns(arg1:domain.ToolRunQuery):Promise<Array<domain.ToolRun>>;
+
+export function ListToolTags():Promise<Array<string>>;
+
+export function ListToolTriggers(arg1:string):Promise<Array<domain.ToolTrigger>>;
+
+export function ListToolUsage():Promise<Record<string, domain.ToolUsage>>;
+
 export function ListTools():Promise<Array<domain.Tool>>;
 
-export function SaveTool(arg1:string,arg2:string):Promise<domain.Tool>;
+export function ListToolsByFrecency():Promise<Array<domain.Tool>>;
+
+export function ListToolsInNamespace(arg1:string):Promise<Array<domain.Tool>>;
+
+export function ListTrashedTools():Promise<Array<domain.Tool>>;
+
+export function ListUndoRecords(arg1:string):Promise<Array<domain.UndoRecord>>;
+
+export function MoveToolToNamespace(arg1:string,arg2:number,arg3:string):Promise<domain.Tool>;
+
+export function PublishTool(arg1:string,arg2:number):Promise<domain.Tool>;
+
+export function PurgeTool(arg1:string):Promise<void>;
+
+export function RebuildCodebaseIndex():Promise<domain.IndexStats>;
+
+export function RejectChanges(arg1:string,arg2:Array<domain.HunkSelection>):Promise<domain.ChangeSet>;
+
+export function RenameTool(arg1:string,arg2:number,arg3:string):Promise<domain.Tool>;
+
+export function ResolveToolSyncConflict(arg1:string,arg2:string):Promise<domain.ToolSyncReport>;
+
+export function RestoreTool(arg1:string):Promise<domain.Tool>;
+
+export function RevertTool(arg1:string,arg2:number):Promise<domain.Tool>;
+
+export function RunAllToolExamples():Promise<domain.ExampleSuiteReport>;
+
+export function RunPipeline(arg1:string,arg2:Record<string, any>):Promise<domain.PipelineRunResult>;
+
+export function RunTool(arg1:string,arg2:Record<string, any>):Promise<domain.ExecutionResult>;
+
+export function RunToolExamples(arg1:string):Promise<domain.ExampleSuiteReport>;
+
+export function RunToolTrigger(arg1:string):Promise<domain.TriggerResult>;
+
+export function SavePipeline(arg1:string,arg2:string,arg3:Array<domain.PipelineStep>):Promise<domain.Pipeline>;
+
+export function SaveTool(arg1:string,arg2:number,arg3:string,arg4:string):Promise<domain.Tool>;
+
+export function SaveToolExample(arg1:string,arg2:string,arg3:string,arg4:Record<string, any>,arg5:string,arg6:Array<domain.ExampleAssertion>):Promise<domain.ToolExample>;
+
+export function SaveToolTrigger(arg1:string,arg2:string,arg3:string,arg4:string,arg5:number,arg6:Record<string, any>,arg7:boolean):Promise<domain.ToolTrigger>;
+
+export function SearchCodebase(arg1:string,arg2:number):Promise<Array<domain.ScoredChunk>>;
+
+export function SearchTools(arg1:domain.ToolQuery):Promise<Array<domain.Tool>>;
+
+export function SearchToolsByFrecency(arg1:domain.ToolQuery):Promise<Array<domain.Tool>>;
 
 export function SendChatMessage(arg1:string):Promise<domain.ChatState>;
+
+export function SetCodebaseContextMode(arg1:string):Promise<void>;
+
+export function SetGitContextOptions(arg1:domain.GitContextOptions):Promise<domain.GitContextOptions>;
+
+export function SetToolCapabilities(arg1:string,arg2:number,arg3:domain.ToolCapabilities):Promise<domain.Tool>;
+
+export function SetToolDependencies(arg1:string,arg2:number,arg3:Array<domain.NpmDependency>):Promise<domain.Tool>;
+
+export function SetToolLanguage(arg1:string,arg2:number,arg3:string):Promise<domain.Tool>;
+
+export function SetToolMetadata(arg1:string,arg2:number,arg3:string,arg4:Array<string>):Promise<domain.Tool>;
+
+export function SetToolParameters(arg1:string,arg2:number,arg3:Array<domain.ToolParameter>):Promise<domain.Tool>;
+
+export function SetToolTypeCheckPolicy(arg1:string):Promise<void>;
+
+export function SyncTools():Promise<domain.ToolSyncReport>;
+
+export function UndoChanges(arg1:string):Promise<domain.ChangeSet>;
+
+export function UpdateTool(arg1:string,arg2:number,arg3:string,arg4:string):Promise<domain.Tool>;
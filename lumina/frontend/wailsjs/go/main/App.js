@@ -2,18 +2,94 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 
+export function ApplyChanges(arg1, arg2) {
+  return window['go']['main']['App']['ApplyChanges'](arg1, arg2);
+}
+
+export function CheckToolCode(arg1) {
+  return window['go']['main']['App']['CheckToolCode'](arg1);
+}
+
+export function CheckToolTypes(arg1) {
+  return window['go']['main']['App']['CheckToolTypes'](arg1);
+}
+
+export function DeletePipeline(arg1) {
+  return window['go']['main']['App']['DeletePipeline'](arg1);
+}
+
+export function DeleteTool(arg1) {
+  return window['go']['main']['App']['DeleteTool'](arg1);
+}
+
+export function DeleteToolExample(arg1) {
+  return window['go']['main']['App']['DeleteToolExample'](arg1);
+}
+
+export function DeleteToolTrigger(arg1) {
+  return window['go']['main']['App']['DeleteToolTrigger'](arg1);
+}
+
+export function DenyCapabilityEscalation(arg1) {
+  return window['go']['main']['App']['DenyCapabilityEscalation'](arg1);
+}
+
+export function DiffToolRevisions(arg1, arg2, arg3) {
+  return window['go']['main']['App']['DiffToolRevisions'](arg1, arg2, arg3);
+}
+
+export function DiffToolRuns(arg1, arg2) {
+  return window['go']['main']['App']['DiffToolRuns'](arg1, arg2);
+}
+
+export function DuplicateTool(arg1, arg2, arg3) {
+  return window['go']['main']['App']['DuplicateTool'](arg1, arg2, arg3);
+}
+
 export function ExecuteTypeScript(arg1) {
   return window['go']['main']['App']['ExecuteTypeScript'](arg1);
 }
 
+export function ExportTools(arg1, arg2) {
+  return window['go']['main']['App']['ExportTools'](arg1, arg2);
+}
+
+export function GenerateTool(arg1) {
+  return window['go']['main']['App']['GenerateTool'](arg1);
+}
+
+export function GetChangeSet(arg1) {
+  return window['go']['main']['App']['GetChangeSet'](arg1);
+}
+
 export function GetChatState() {
   return window['go']['main']['App']['GetChatState']();
 }
 
+export function GetCodebaseContextMode() {
+  return window['go']['main']['App']['GetCodebaseContextMode']();
+}
+
 export function GetCurrentProjectTree() {
   return window['go']['main']['App']['GetCurrentProjectTree']();
 }
 
+export function GetGitContextOptions() {
+  return window['go']['main']['App']['GetGitContextOptions']();
+}
+
+export function GetLLMCall(arg1) {
+  return window['go']['main']['App']['GetLLMCall'](arg1);
+}
+
+export function GetPipeline(arg1) {
+  return window['go']['main']['App']['GetPipeline'](arg1);
+}
+
+export function GetProjectNamespace() {
+  return window['go']['main']['App']['GetProjectNamespace']();
+}
+
 export function GetTool(arg1) {
   return window['go']['main']['App']['GetTool'](arg1);
 }
@@ -22,18 +98,234 @@ export function GetToolByName(arg1) {
   return window['go']['main']['App']['GetToolByName'](arg1);
 }
 
+export function GetToolDependencies(arg1) {
+  return window['go']['main']['App']['GetToolDependencies'](arg1);
+}
+
+export function GetToolRevision(arg1, arg2) {
+  return window['go']['main']['App']['GetToolRevision'](arg1, arg2);
+}
+
+export function GetToolRun(arg1) {
+  return window['go']['main']['App']['GetToolRun'](arg1);
+}
+
+export function GetToolTypeCheckPolicy() {
+  return window['go']['main']['App']['GetToolTypeCheckPolicy']();
+}
+
+export function GetToolUsage(arg1) {
+  return window['go']['main']['App']['GetToolUsage'](arg1);
+}
+
+export function GrantCapabilityEscalation(arg1) {
+  return window['go']['main']['App']['GrantCapabilityEscalation'](arg1);
+}
+
 export function Greet(arg1) {
   return window['go']['main']['App']['Greet'](arg1);
 }
 
+export function ImportTools(arg1, arg2, arg3) {
+  return window['go']['main']['App']['ImportTools'](arg1, arg2, arg3);
+}
+
+export function ListCapabilityEscalations(arg1) {
+  return window['go']['main']['App']['ListCapabilityEscalations'](arg1);
+}
+
+export function ListChangeSets() {
+  return window['go']['main']['App']['ListChangeSets']();
+}
+
+export function ListLLMCalls(arg1) {
+  return window['go']['main']['App']['ListLLMCalls'](arg1);
+}
+
+export function ListPipelines() {
+  return window['go']['main']['App']['ListPipelines']();
+}
+
+export function ListToolExamples(arg1) {
+  return window['go']['main']['App']['ListToolExamples'](arg1);
+}
+
+export function ListToolLanguages() {
+  return window['go']['main']['App']['ListToolLanguages']();
+}
+
+export function ListToolNamespaces() {
+  return window['go']['main']['App']['ListToolNamespaces']();
+}
+
+export function ListToolRevisions(arg1) {
+  return window['go']['main']['App']['ListToolRevisions'](arg1);
+}
+
+export function ListToolRuns(arg1) {
+  return window['go']['main']['App']['ListToolRuns'](arg1);
+}
+
+export function ListToolTags() {
+  return window['go']['main']['App']['ListToolTags']();
+}
+
+export function ListToolTriggers(arg1) {
+  return window['go']['main']['App']['ListToolTriggers'](arg1);
+}
+
+export function ListToolUsage() {
+  return window['go']['main']['App']['ListToolUsage']();
+}
+
 export function ListTools() {
   return window['go']['main']['App']['ListTools']();
 }
 
-export function SaveTool(arg1, arg2) {
-  return window['go']['main']['App']['SaveTool'](arg1, arg2);
+export function ListToolsByFrecency() {
+  return window['go']['main']['App']['ListToolsByFrecency']();
+}
+
+export function ListToolsInNamespace(arg1) {
+  return window['go']['main']['App']['ListToolsInNamespace'](arg1);
+}
+
+export function ListTrashedTools() {
+  return window['go']['main']['App']['ListTrashedTools']();
+}
+
+export function ListUndoRecords(arg1) {
+  return window['go']['main']['App']['ListUndoRecords'](arg1);
+}
+
+export function MoveToolToNamespace(arg1, arg2, arg3) {
+  return window['go']['main']['App']['MoveToolToNamespace'](arg1, arg2, arg3);
+}
+
+export function PublishTool(arg1, arg2) {
+  return window['go']['main']['App']['PublishTool'](arg1, arg2);
+}
+
+export function PurgeTool(arg1) {
+  return window['go']['main']['App']['PurgeTool'](arg1);
+}
+
+export function RebuildCodebaseIndex() {
+  return window['go']['main']['App']['RebuildCodebaseIndex']();
+}
+
+export function RejectChanges(arg1, arg2) {
+  return window['go']['main']['App']['RejectChanges'](arg1, arg2);
+}
+
+export function RenameTool(arg1, arg2, arg3) {
+  return window['go']['main']['App']['RenameTool'](arg1, arg2, arg3);
+}
+
+export function ResolveToolSyncConflict(arg1, arg2) {
+  return window['go']['main']['App']['ResolveToolSyncConflict'](arg1, arg2);
+}
+
+export function RestoreTool(arg1) {
+  return window['go']['main']['App']['RestoreTool'](arg1);
+}
+
+export function RevertTool(arg1, arg2) {
+  return window['go']['main']['App']['RevertTool'](arg1, arg2);
+}
+
+export function RunAllToolExamples() {
+  return window['go']['main']['App']['RunAllToolExamples']();
+}
+
+export function RunPipeline(arg1, arg2) {
+  return window['go']['main']['App']['RunPipeline'](arg1, arg2);
+}
+
+export function RunTool(arg1, arg2) {
+  return window['go']['main']['App']['RunTool'](arg1, arg2);
+}
+
+export function RunToolExamples(arg1) {
+  return window['go']['main']['App']['RunToolExamples'](arg1);
+}
+
+export function RunToolTrigger(arg1) {
+  return window['go']['main']['App']['RunToolTrigger'](arg1);
+}
+
+export function SavePipeline(arg1, arg2, arg3) {
+  return window['go']['main']['App']['SavePipeline'](arg1, arg2, arg3);
+}
+
+export function SaveTool(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['SaveTool'](arg1, arg2, arg3, arg4);
+}
+
+export function SaveToolExample(arg1, arg2, arg3, arg4, arg5, arg6) {
+  return window['go']['main']['App']['SaveToolExample'](arg1, arg2, arg3, arg4, arg5, arg6);
+}
+
+export function SaveToolTrigger(arg1, arg2, arg3, arg4, arg5, arg6, arg7) {
+  return window['go']['main']['App']['SaveToolTrigger'](arg1, arg2, arg3, arg4, arg5, arg6, arg7);
+}
+
+export function SearchCodebase(arg1, arg2) {
+  return window['go']['main']['App']['SearchCodebase'](arg1, arg2);
+}
+
+export function SearchTools(arg1) {
+  return window['go']['main']['App']['SearchTools'](arg1);
+}
+
+export function SearchToolsByFrecency(arg1) {
+  return window['go']['main']['App']['SearchToolsByFrecency'](arg1);
 }
 
 export function SendChatMessage(arg1) {
   return window['go']['main']['App']['SendChatMessage'](arg1);
 }
+
+export function SetCodebaseContextMode(arg1) {
+  return window['go']['main']['App']['SetCodebaseContextMode'](arg1);
+}
+
+export function SetGitContextOptions(arg1) {
+  return window['go']['main']['App']['SetGitContextOptions'](arg1);
+}
+
+export function SetToolCapabilities(arg1, arg2, arg3) {
+  return window['go']['main']['App']['SetToolCapabilities'](arg1, arg2, arg3);
+}
+
+export function SetToolDependencies(arg1, arg2, arg3) {
+  return window['go']['main']['App']['SetToolDependencies'](arg1, arg2, arg3);
+}
+
+export function SetToolLanguage(arg1, arg2, arg3) {
+  return window['go']['main']['App']['SetToolLanguage'](arg1, arg2, arg3);
+}
+
+export function SetToolMetadata(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['SetToolMetadata'](arg1, arg2, arg3, arg4);
+}
+
+export function SetToolParameters(arg1, arg2, arg3) {
+  return window['go']['main']['App']['SetToolParameters'](arg1, arg2, arg3);
+}
+
+export function SetToolTypeCheckPolicy(arg1) {
+  return window['go']['main']['App']['SetToolTypeCheckPolicy'](arg1);
+}
+
+export function SyncTools() {
+  return window['go']['main']['App']['SyncTools']();
+}
+
+export function UndoChanges(arg1) {
+  return window['go']['main']['App']['UndoChanges'](arg1);
+}
+
+export function UpdateTool(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['UpdateTool'](arg1, arg2, arg3, arg4);
+}
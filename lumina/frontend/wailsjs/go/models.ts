@@ -1,29 +1,1166 @@
 export namespace domain {
 	
+	export class CapabilityEscalation {
+	    id: string;
+	    tool_id: string;
+	    tool_name: string;
+	    kind: string;
+	    resource: string;
+	    status: string;
+	    // Go type: time
+	    created_at: any;
+	    // Go type: time
+	    decided_at?: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new CapabilityEscalation(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.tool_id = source["tool_id"];
+	        this.tool_name = source["tool_name"];
+	        this.kind = source["kind"];
+	        this.resource = source["resource"];
+	        this.status = source["status"];
+	        this.created_at = this.convertValues(source["created_at"], null);
+	        this.decided_at = this.convertValues(source["decided_at"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class CapabilityRequest {
+	    kind: string;
+	    resource: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new CapabilityRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.kind = source["kind"];
+	        this.resource = source["resource"];
+	    }
+	}
+	export class DiffLine {
+	    kind: string;
+	    text: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new DiffLine(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.kind = source["kind"];
+	        this.text = source["text"];
+	    }
+	}
+	export class Hunk {
+	    oldStart: number;
+	    oldLines: number;
+	    newStart: number;
+	    newLines: number;
+	    lines: DiffLine[];
+	    status: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Hunk(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.oldStart = source["oldStart"];
+	        this.oldLines = source["oldLines"];
+	        this.newStart = source["newStart"];
+	        this.newLines = source["newLines"];
+	        this.lines = this.convertValues(source["lines"], DiffLine);
+	        this.status = source["status"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class FileChange {
+	    path: string;
+	    kind: string;
+	    hunks: Hunk[];
+	    newContent?: string;
+	    baseHash?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new FileChange(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.kind = source["kind"];
+	        this.hunks = this.convertValues(source["hunks"], Hunk);
+	        this.newContent = source["newContent"];
+	        this.baseHash = source["baseHash"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ChangeSet {
+	    id: string;
+	    messageIndex: number;
+	    files: FileChange[];
+	    // Go type: time
+	    createdAt: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new ChangeSet(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.messageIndex = source["messageIndex"];
+	        this.files = this.convertValues(source["files"], FileChange);
+	        this.createdAt = this.convertValues(source["createdAt"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ContextChunk {
+	    path: string;
+	    startLine: number;
+	    endLine: number;
+	    score: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ContextChunk(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.startLine = source["startLine"];
+	        this.endLine = source["endLine"];
+	        this.score = source["score"];
+	    }
+	}
 	export class Message {
 	    role: string;
 	    content: string;
+	    contextChunks?: ContextChunk[];
+	
+	    static createFrom(source: any = {}) {
+	        return new Message(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.role = source["role"];
+	        this.content = source["content"];
+	        this.contextChunks = this.convertValues(source["contextChunks"], ContextChunk);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ChatState {
+	    messages: Message[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ChatState(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.messages = this.convertValues(source["messages"], Message);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	
+	export class ExampleAssertion {
+	    kind: string;
+	    value: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ExampleAssertion(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.kind = source["kind"];
+	        this.value = source["value"];
+	    }
+	}
+	export class ToolRunOutput {
+	    output: string;
+	    error: string;
+	    exit_code: number;
+	    success: boolean;
+	    duration_ms: number;
+	    capability_requests?: CapabilityRequest[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ToolRunOutput(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.output = source["output"];
+	        this.error = source["error"];
+	        this.exit_code = source["exit_code"];
+	        this.success = source["success"];
+	        this.duration_ms = source["duration_ms"];
+	        this.capability_requests = this.convertValues(source["capability_requests"], CapabilityRequest);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ExampleResult {
+	    example_id: string;
+	    example_name: string;
+	    tool_id: string;
+	    tool_name: string;
+	    passed: boolean;
+	    failures: string[];
+	    output: ToolRunOutput;
+	
+	    static createFrom(source: any = {}) {
+	        return new ExampleResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.example_id = source["example_id"];
+	        this.example_name = source["example_name"];
+	        this.tool_id = source["tool_id"];
+	        this.tool_name = source["tool_name"];
+	        this.passed = source["passed"];
+	        this.failures = source["failures"];
+	        this.output = this.convertValues(source["output"], ToolRunOutput);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ExampleSuiteReport {
+	    results: ExampleResult[];
+	    passed: number;
+	    failed: number;
+	    duration_ms: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ExampleSuiteReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.results = this.convertValues(source["results"], ExampleResult);
+	        this.passed = source["passed"];
+	        this.failed = source["failed"];
+	        this.duration_ms = source["duration_ms"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class PermissionDenial {
+	    permission: string;
+	    resource: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new PermissionDenial(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.permission = source["permission"];
+	        this.resource = source["resource"];
+	    }
+	}
+	export class ExecutionResult {
+	    output: string;
+	    error: string;
+	    success: boolean;
+	    exitCode: number;
+	    resolvedPackages?: Record<string, string>;
+	    denials?: PermissionDenial[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ExecutionResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.output = source["output"];
+	        this.error = source["error"];
+	        this.success = source["success"];
+	        this.exitCode = source["exitCode"];
+	        this.resolvedPackages = source["resolvedPackages"];
+	        this.denials = this.convertValues(source["denials"], PermissionDenial);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class FileSnapshot {
+	    path: string;
+	    existedBefore: boolean;
+	    before: string;
+	    existsAfter: boolean;
+	    after: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new FileSnapshot(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.existedBefore = source["existedBefore"];
+	        this.before = source["before"];
+	        this.existsAfter = source["existsAfter"];
+	        this.after = source["after"];
+	    }
+	}
+	export class GitContextOptions {
+	    enabled: boolean;
+	    commitCount: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new GitContextOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.enabled = source["enabled"];
+	        this.commitCount = source["commitCount"];
+	    }
+	}
+	
+	export class HunkRef {
+	    path: string;
+	    index: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new HunkRef(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.index = source["index"];
+	    }
+	}
+	export class HunkSelection {
+	    path: string;
+	    hunks: number[];
+	
+	    static createFrom(source: any = {}) {
+	        return new HunkSelection(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.hunks = source["hunks"];
+	    }
+	}
+	export class IndexStats {
+	    chunkCount: number;
+	    fileCount: number;
+	    averageLength: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new IndexStats(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.chunkCount = source["chunkCount"];
+	        this.fileCount = source["fileCount"];
+	        this.averageLength = source["averageLength"];
+	    }
+	}
+	export class LLMCall {
+	    id: string;
+	    provider: string;
+	    model: string;
+	    method: string;
+	    url: string;
+	    requestHeaders: Record<string, string>;
+	    requestBody: string;
+	    statusCode: number;
+	    responseBody: string;
+	    error: string;
+	    latencyMs: number;
+	    // Go type: time
+	    startedAt: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new LLMCall(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.provider = source["provider"];
+	        this.model = source["model"];
+	        this.method = source["method"];
+	        this.url = source["url"];
+	        this.requestHeaders = source["requestHeaders"];
+	        this.requestBody = source["requestBody"];
+	        this.statusCode = source["statusCode"];
+	        this.responseBody = source["responseBody"];
+	        this.error = source["error"];
+	        this.latencyMs = source["latencyMs"];
+	        this.startedAt = this.convertValues(source["startedAt"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class Node {
+	    name: string;
+	    type: string;
+	    children: Node[];
+	
+	    static createFrom(source: any = {}) {
+	        return new Node(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.type = source["type"];
+	        this.children = this.convertValues(source["children"], Node);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class NpmDependency {
+	    name: string;
+	    version: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new NpmDependency(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.version = source["version"];
+	    }
+	}
+	
+	export class PipelineStep {
+	    tool_id: string;
+	    arguments: Record<string, any>;
+	    input_parameter: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new PipelineStep(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.tool_id = source["tool_id"];
+	        this.arguments = source["arguments"];
+	        this.input_parameter = source["input_parameter"];
+	    }
+	}
+	export class Pipeline {
+	    id: string;
+	    name: string;
+	    steps: PipelineStep[];
+	    // Go type: time
+	    created_at: any;
+	    // Go type: time
+	    updated_at: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new Pipeline(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.steps = this.convertValues(source["steps"], PipelineStep);
+	        this.created_at = this.convertValues(source["created_at"], null);
+	        this.updated_at = this.convertValues(source["updated_at"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class PipelineStepResult {
+	    index: number;
+	    tool_id: string;
+	    tool_name: string;
+	    arguments: Record<string, any>;
+	    output: ToolRunOutput;
+	    value: any;
+	    success: boolean;
+	    error: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new PipelineStepResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.index = source["index"];
+	        this.tool_id = source["tool_id"];
+	        this.tool_name = source["tool_name"];
+	        this.arguments = source["arguments"];
+	        this.output = this.convertValues(source["output"], ToolRunOutput);
+	        this.value = source["value"];
+	        this.success = source["success"];
+	        this.error = source["error"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class PipelineRunResult {
+	    pipeline_id: string;
+	    steps: PipelineStepResult[];
+	    success: boolean;
+	    failed_step: number;
+	    output: string;
+	    duration_ms: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new PipelineRunResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.pipeline_id = source["pipeline_id"];
+	        this.steps = this.convertValues(source["steps"], PipelineStepResult);
+	        this.success = source["success"];
+	        this.failed_step = source["failed_step"];
+	        this.output = source["output"];
+	        this.duration_ms = source["duration_ms"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	
+	export class RevisionDiffLine {
+	    kind: string;
+	    text: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new RevisionDiffLine(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.kind = source["kind"];
+	        this.text = source["text"];
+	    }
+	}
+	export class RevisionDiff {
+	    tool_id: string;
+	    from_revision: number;
+	    to_revision: number;
+	    lines: RevisionDiffLine[];
+	    added: number;
+	    removed: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new RevisionDiff(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.tool_id = source["tool_id"];
+	        this.from_revision = source["from_revision"];
+	        this.to_revision = source["to_revision"];
+	        this.lines = this.convertValues(source["lines"], RevisionDiffLine);
+	        this.added = source["added"];
+	        this.removed = source["removed"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class ScoredChunk {
+	    id: string;
+	    path: string;
+	    startLine: number;
+	    endLine: number;
+	    content: string;
+	    score: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ScoredChunk(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.path = source["path"];
+	        this.startLine = source["startLine"];
+	        this.endLine = source["endLine"];
+	        this.content = source["content"];
+	        this.score = source["score"];
+	    }
+	}
+	export class ToolCapabilities {
+	    read_paths: string[];
+	    write_paths: string[];
+	    network: boolean;
+	    subprocess: boolean;
+	    unrestricted?: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new ToolCapabilities(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.read_paths = source["read_paths"];
+	        this.write_paths = source["write_paths"];
+	        this.network = source["network"];
+	        this.subprocess = source["subprocess"];
+	        this.unrestricted = source["unrestricted"];
+	    }
+	}
+	export class ToolParameter {
+	    name: string;
+	    type: string;
+	    description: string;
+	    default?: any;
+	    required: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new ToolParameter(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.type = source["type"];
+	        this.description = source["description"];
+	        this.default = source["default"];
+	        this.required = source["required"];
+	    }
+	}
+	export class Tool {
+	    id: string;
+	    name: string;
+	    code: string;
+	    description: string;
+	    tags: string[];
+	    // Go type: time
+	    created_at: any;
+	    // Go type: time
+	    updated_at: any;
+	    parameters: ToolParameter[];
+	    dependencies: NpmDependency[];
+	    // Go type: time
+	    deleted_at?: any;
+	    namespace: string;
+	    language: string;
+	    capabilities?: ToolCapabilities;
+	    draft: boolean;
+	    version: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Tool(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.code = source["code"];
+	        this.description = source["description"];
+	        this.tags = source["tags"];
+	        this.created_at = this.convertValues(source["created_at"], null);
+	        this.updated_at = this.convertValues(source["updated_at"], null);
+	        this.parameters = this.convertValues(source["parameters"], ToolParameter);
+	        this.dependencies = this.convertValues(source["dependencies"], NpmDependency);
+	        this.deleted_at = this.convertValues(source["deleted_at"], null);
+	        this.namespace = source["namespace"];
+	        this.language = source["language"];
+	        this.capabilities = this.convertValues(source["capabilities"], ToolCapabilities);
+	        this.draft = source["draft"];
+	        this.version = source["version"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ToolBundleExport {
+	    path: string;
+	    tools: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ToolBundleExport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.tools = source["tools"];
+	    }
+	}
+	export class ToolBundleImport {
+	    namespace: string;
+	    imported: string[];
+	    renamed: Record<string, string>;
+	    overwritten: string[];
+	    skipped: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ToolBundleImport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.namespace = source["namespace"];
+	        this.imported = source["imported"];
+	        this.renamed = source["renamed"];
+	        this.overwritten = source["overwritten"];
+	        this.skipped = source["skipped"];
+	    }
+	}
+	
+	export class ToolDependency {
+	    tool_id: string;
+	    name: string;
+	    specifiers: string[];
+	    imports: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ToolDependency(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.tool_id = source["tool_id"];
+	        this.name = source["name"];
+	        this.specifiers = source["specifiers"];
+	        this.imports = source["imports"];
+	    }
+	}
+	export class ToolDependencyGraph {
+	    root_id: string;
+	    nodes: ToolDependency[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ToolDependencyGraph(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.root_id = source["root_id"];
+	        this.nodes = this.convertValues(source["nodes"], ToolDependency);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ToolDiagnostic {
+	    line: number;
+	    column: number;
+	    code: string;
+	    message: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ToolDiagnostic(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.line = source["line"];
+	        this.column = source["column"];
+	        this.code = source["code"];
+	        this.message = source["message"];
+	    }
+	}
+	export class ToolExample {
+	    id: string;
+	    tool_id: string;
+	    name: string;
+	    arguments: Record<string, any>;
+	    expected_output: string;
+	    assertions: ExampleAssertion[];
+	    // Go type: time
+	    created_at: any;
+	    // Go type: time
+	    updated_at: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new ToolExample(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.tool_id = source["tool_id"];
+	        this.name = source["name"];
+	        this.arguments = source["arguments"];
+	        this.expected_output = source["expected_output"];
+	        this.assertions = this.convertValues(source["assertions"], ExampleAssertion);
+	        this.created_at = this.convertValues(source["created_at"], null);
+	        this.updated_at = this.convertValues(source["updated_at"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ToolGenerationAttempt {
+	    attempt: number;
+	    passed: boolean;
+	    failures: string[];
+	    output: ToolRunOutput;
 	
 	    static createFrom(source: any = {}) {
-	        return new Message(source);
+	        return new ToolGenerationAttempt(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.role = source["role"];
-	        this.content = source["content"];
+	        this.attempt = source["attempt"];
+	        this.passed = source["passed"];
+	        this.failures = source["failures"];
+	        this.output = this.convertValues(source["output"], ToolRunOutput);
 	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
 	}
-	export class ChatState {
-	    messages: Message[];
+	export class ToolGenerationResult {
+	    tool: Tool;
+	    example: ToolExample;
+	    attempts: ToolGenerationAttempt[];
 	
 	    static createFrom(source: any = {}) {
-	        return new ChatState(source);
+	        return new ToolGenerationResult(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.messages = this.convertValues(source["messages"], Message);
+	        this.tool = this.convertValues(source["tool"], Tool);
+	        this.example = this.convertValues(source["example"], ToolExample);
+	        this.attempts = this.convertValues(source["attempts"], ToolGenerationAttempt);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -44,39 +1181,240 @@ export namespace domain {
 		    return a;
 		}
 	}
-	export class ExecutionResult {
+	
+	export class ToolQuery {
+	    text: string;
+	    tag: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ToolQuery(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.text = source["text"];
+	        this.tag = source["tag"];
+	    }
+	}
+	export class ToolRevision {
+	    tool_id: string;
+	    revision: number;
+	    name: string;
+	    code: string;
+	    parameters: ToolParameter[];
+	    dependencies: NpmDependency[];
+	    resolved_versions: Record<string, string>;
+	    message: string;
+	    // Go type: time
+	    created_at: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new ToolRevision(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.tool_id = source["tool_id"];
+	        this.revision = source["revision"];
+	        this.name = source["name"];
+	        this.code = source["code"];
+	        this.parameters = this.convertValues(source["parameters"], ToolParameter);
+	        this.dependencies = this.convertValues(source["dependencies"], NpmDependency);
+	        this.resolved_versions = source["resolved_versions"];
+	        this.message = source["message"];
+	        this.created_at = this.convertValues(source["created_at"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ToolRun {
+	    id: string;
+	    tool_id: string;
+	    tool_name: string;
+	    revision: number;
+	    code: string;
+	    arguments: Record<string, any>;
 	    output: string;
 	    error: string;
+	    exit_code: number;
 	    success: boolean;
-	    exitCode: number;
+	    duration_ms: number;
+	    // Go type: time
+	    started_at: any;
 	
 	    static createFrom(source: any = {}) {
-	        return new ExecutionResult(source);
+	        return new ToolRun(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.tool_id = source["tool_id"];
+	        this.tool_name = source["tool_name"];
+	        this.revision = source["revision"];
+	        this.code = source["code"];
+	        this.arguments = source["arguments"];
 	        this.output = source["output"];
 	        this.error = source["error"];
+	        this.exit_code = source["exit_code"];
 	        this.success = source["success"];
-	        this.exitCode = source["exitCode"];
+	        this.duration_ms = source["duration_ms"];
+	        this.started_at = this.convertValues(source["started_at"], null);
 	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
 	}
+	export class ToolRunDiff {
+	    from_run_id: string;
+	    to_run_id: string;
+	    lines: RevisionDiffLine[];
+	    added: number;
+	    removed: number;
 	
-	export class Node {
+	    static createFrom(source: any = {}) {
+	        return new ToolRunDiff(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.from_run_id = source["from_run_id"];
+	        this.to_run_id = source["to_run_id"];
+	        this.lines = this.convertValues(source["lines"], RevisionDiffLine);
+	        this.added = source["added"];
+	        this.removed = source["removed"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class ToolRunQuery {
+	    tool_id: string;
+	    adhoc_only: boolean;
+	    // Go type: time
+	    since: any;
+	    // Go type: time
+	    until: any;
+	    limit: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ToolRunQuery(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.tool_id = source["tool_id"];
+	        this.adhoc_only = source["adhoc_only"];
+	        this.since = this.convertValues(source["since"], null);
+	        this.until = this.convertValues(source["until"], null);
+	        this.limit = source["limit"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ToolSyncConflict {
+	    tool_id: string;
 	    name: string;
-	    type: string;
-	    children: Node[];
+	    path: string;
+	    reason: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new Node(source);
+	        return new ToolSyncConflict(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.tool_id = source["tool_id"];
 	        this.name = source["name"];
-	        this.type = source["type"];
-	        this.children = this.convertValues(source["children"], Node);
+	        this.path = source["path"];
+	        this.reason = source["reason"];
+	    }
+	}
+	export class ToolSyncReport {
+	    imported: string[];
+	    exported: string[];
+	    updated_tools: string[];
+	    updated_files: string[];
+	    trashed_tools: string[];
+	    deleted_files: string[];
+	    conflicts: ToolSyncConflict[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ToolSyncReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.imported = source["imported"];
+	        this.exported = source["exported"];
+	        this.updated_tools = source["updated_tools"];
+	        this.updated_files = source["updated_files"];
+	        this.trashed_tools = source["trashed_tools"];
+	        this.deleted_files = source["deleted_files"];
+	        this.conflicts = this.convertValues(source["conflicts"], ToolSyncConflict);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -97,24 +1435,39 @@ export namespace domain {
 		    return a;
 		}
 	}
-	export class Tool {
+	export class ToolTrigger {
 	    id: string;
-	    name: string;
-	    code: string;
+	    tool_id: string;
+	    kind: string;
+	    glob: string;
+	    interval_seconds: number;
+	    arguments: Record<string, any>;
+	    enabled: boolean;
+	    // Go type: time
+	    last_run_at?: any;
+	    last_output: string;
+	    last_exit_code: number;
 	    // Go type: time
 	    created_at: any;
 	    // Go type: time
 	    updated_at: any;
 	
 	    static createFrom(source: any = {}) {
-	        return new Tool(source);
+	        return new ToolTrigger(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.id = source["id"];
-	        this.name = source["name"];
-	        this.code = source["code"];
+	        this.tool_id = source["tool_id"];
+	        this.kind = source["kind"];
+	        this.glob = source["glob"];
+	        this.interval_seconds = source["interval_seconds"];
+	        this.arguments = source["arguments"];
+	        this.enabled = source["enabled"];
+	        this.last_run_at = this.convertValues(source["last_run_at"], null);
+	        this.last_output = source["last_output"];
+	        this.last_exit_code = source["last_exit_code"];
 	        this.created_at = this.convertValues(source["created_at"], null);
 	        this.updated_at = this.convertValues(source["updated_at"], null);
 	    }
@@ -137,6 +1490,138 @@ export namespace domain {
 		    return a;
 		}
 	}
+	export class ToolUsage {
+	    tool_id: string;
+	    run_count: number;
+	    failure_count: number;
+	    failure_rate: number;
+	    average_duration_ms: number;
+	    // Go type: time
+	    last_run_at?: any;
+	    recent_runs: time.Time[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ToolUsage(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.tool_id = source["tool_id"];
+	        this.run_count = source["run_count"];
+	        this.failure_count = source["failure_count"];
+	        this.failure_rate = source["failure_rate"];
+	        this.average_duration_ms = source["average_duration_ms"];
+	        this.last_run_at = this.convertValues(source["last_run_at"], null);
+	        this.recent_runs = this.convertValues(source["recent_runs"], time.Time);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class TriggerResult {
+	    trigger_id: string;
+	    tool_id: string;
+	    tool_name: string;
+	    reason: string;
+	    changed_files: string[];
+	    output: ToolRunOutput;
+	    changed: boolean;
+	    error: string;
+	    // Go type: time
+	    ran_at: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new TriggerResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.trigger_id = source["trigger_id"];
+	        this.tool_id = source["tool_id"];
+	        this.tool_name = source["tool_name"];
+	        this.reason = source["reason"];
+	        this.changed_files = source["changed_files"];
+	        this.output = this.convertValues(source["output"], ToolRunOutput);
+	        this.changed = source["changed"];
+	        this.error = source["error"];
+	        this.ran_at = this.convertValues(source["ran_at"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class UndoRecord {
+	    id: string;
+	    changeSetId: string;
+	    hunks: HunkRef[];
+	    files: FileSnapshot[];
+	    // Go type: time
+	    appliedAt: any;
+	    // Go type: time
+	    undoneAt?: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new UndoRecord(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.changeSetId = source["changeSetId"];
+	        this.hunks = this.convertValues(source["hunks"], HunkRef);
+	        this.files = this.convertValues(source["files"], FileSnapshot);
+	        this.appliedAt = this.convertValues(source["appliedAt"], null);
+	        this.undoneAt = this.convertValues(source["undoneAt"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
 
 }
 